@@ -1,3 +1,14 @@
 module github.com/NickDiPreta1/toolhub
 
 go 1.25.1
+
+require (
+	github.com/BurntSushi/toml v1.6.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	github.com/yuin/goldmark v1.8.2
+	golang.org/x/net v0.47.0
+	golang.org/x/text v0.40.0
+)
@@ -0,0 +1,169 @@
+package codegen
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGoStructs(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		rootName string
+		expected string
+	}{
+		{
+			name:     "scalar fields",
+			input:    `{"id":1,"name":"ann","score":9.5,"active":true}`,
+			expected: "type Root struct {\n\tActive bool    `json:\"active\"`\n\tID     int64   `json:\"id\"`\n\tName   string  `json:\"name\"`\n\tScore  float64 `json:\"score\"`\n}",
+		},
+		{
+			name:     "custom root name and initialisms",
+			input:    `{"user_id":1,"avatarUrl":"x"}`,
+			rootName: "user profile",
+			expected: "type UserProfile struct {\n\tAvatarURL string `json:\"avatarUrl\"`\n\tUserID    int64  `json:\"user_id\"`\n}",
+		},
+		{
+			name:     "optional fields across samples",
+			input:    `{"id":1,"note":"a"} {"id":2}`,
+			expected: "type Root struct {\n\tID   int64  `json:\"id\"`\n\tNote string `json:\"note,omitempty\"`\n}",
+		},
+		{
+			name:     "int and float merge to float64",
+			input:    `{"n":1} {"n":1.5}`,
+			expected: "type Root struct {\n\tN float64 `json:\"n\"`\n}",
+		},
+		{
+			name:     "null makes pointer",
+			input:    `{"n":null} {"n":"x"}`,
+			expected: "type Root struct {\n\tN *string `json:\"n\"`\n}",
+		},
+		{
+			name:     "time detection",
+			input:    `{"at":"2024-01-01T10:00:00Z"}`,
+			expected: "import \"time\"\n\ntype Root struct {\n\tAt time.Time `json:\"at\"`\n}",
+		},
+		{
+			name:     "nested object and array of objects",
+			input:    `{"address":{"city":"x"},"items":[{"sku":"a"}]}`,
+			expected: "type Root struct {\n\tAddress Address `json:\"address\"`\n\tItems   []Item  `json:\"items\"`\n}\n\ntype Address struct {\n\tCity string `json:\"city\"`\n}\n\ntype Item struct {\n\tSku string `json:\"sku\"`\n}",
+		},
+		{
+			name:     "mixed types become any",
+			input:    `{"v":1} {"v":"x"}`,
+			expected: "type Root struct {\n\tV any `json:\"v\"`\n}",
+		},
+		{
+			name:     "top-level array",
+			input:    `[{"id":1}]`,
+			expected: "type Root []RootItem\n\ntype RootItem struct {\n\tID int64 `json:\"id\"`\n}",
+		},
+		{
+			name:     "top-level array with a field named like the root",
+			input:    `[{"root":{"a":1}}]`,
+			expected: "type Root []RootItem\n\ntype RootItem struct {\n\tRoot RootItemRoot `json:\"root\"`\n}\n\ntype RootItemRoot struct {\n\tA int64 `json:\"a\"`\n}",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			typ, err := Infer(tt.input)
+			if err != nil {
+				t.Fatalf("Infer(%q) unexpected error: %v", tt.input, err)
+			}
+
+			got, err := GoStructs(typ, tt.rootName)
+			if err != nil {
+				t.Fatalf("GoStructs unexpected error: %v", err)
+			}
+
+			if got != tt.expected {
+				t.Errorf("GoStructs(%q)\ngot:\n%s\n\nwant:\n%s", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestTypeScript(t *testing.T) {
+	typ, err := Infer(`{"id":1,"tags":["a"],"meta":null,"bad-key":true} {"id":2,"tags":[],"meta":{"k":"v"}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := TypeScript(typ, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "export interface Root {\n  \"bad-key\"?: boolean;\n  id: number;\n  meta: Meta | null;\n  tags: string[];\n}\n\nexport interface Meta {\n  k: string;\n}"
+	if got != expected {
+		t.Errorf("TypeScript\ngot:\n%s\n\nwant:\n%s", got, expected)
+	}
+}
+
+func TestTypeScriptTopLevelArray(t *testing.T) {
+	typ, err := Infer(`[{"root":{"a":1}}]`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := TypeScript(typ, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "export type Root = RootItem[];\n\nexport interface RootItem {\n  root: RootItemRoot;\n}\n\nexport interface RootItemRoot {\n  a: number;\n}"
+	if got != expected {
+		t.Errorf("TypeScript\ngot:\n%s\n\nwant:\n%s", got, expected)
+	}
+}
+
+func TestJSONSchema(t *testing.T) {
+	typ, err := Infer(`{"id":1,"day":"2024-01-01"} {"id":2}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := JSONSchema(typ, "event")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{
+		`"$schema": "https://json-schema.org/draft/2020-12/schema"`,
+		`"title": "Event"`,
+		`"format": "date"`,
+		`"type": "integer"`,
+		"\"required\": [\n    \"id\"\n  ]",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("JSONSchema output missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestInferErrors(t *testing.T) {
+	for _, input := range []string{"", "   ", `{"a":`, `not json`} {
+		if _, err := Infer(input); err == nil {
+			t.Errorf("Infer(%q) expected error but got nil", input)
+		}
+	}
+}
+
+func TestGoName(t *testing.T) {
+	tests := map[string]string{
+		"id":           "ID",
+		"user_name":    "UserName",
+		"HTTPServer":   "HTTPServer",
+		"createdAt":    "CreatedAt",
+		"api-key":      "APIKey",
+		"2fa":          "X2fa",
+		"already_Good": "AlreadyGood",
+	}
+
+	for input, want := range tests {
+		if got := goName(input); got != want {
+			t.Errorf("goName(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
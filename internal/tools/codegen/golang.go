@@ -0,0 +1,212 @@
+package codegen
+
+import (
+	"fmt"
+	"go/format"
+	"sort"
+	"strings"
+)
+
+// DefaultRootName names the top-level type when the caller gives none.
+const DefaultRootName = "Root"
+
+// rootTypeName turns the caller's root name into an identifier, falling back
+// to DefaultRootName.
+func rootTypeName(name string) string {
+	if strings.TrimSpace(name) == "" {
+		return DefaultRootName
+	}
+
+	return goName(name)
+}
+
+// namedType is an object type that gets its own declaration.
+type namedType struct {
+	Name string
+	Type *Type
+}
+
+// collectTypes assigns a unique name to every object type reachable from
+// root, in declaration order (root first, then nested types by field name).
+func collectTypes(root *Type, rootName string) []namedType {
+	var out []namedType
+	used := map[string]bool{}
+	// A root that is not an object is declared as an alias under rootName,
+	// so nested types must not take that name.
+	if root != nil && root.Kind != KindObject {
+		used[rootName] = true
+	}
+
+	var visit func(t *Type, name, parent string)
+	visit = func(t *Type, name, parent string) {
+		if t == nil {
+			return
+		}
+		switch t.Kind {
+		case KindArray:
+			visit(t.Elem, singular(name), parent)
+		case KindObject:
+			unique := name
+			if used[unique] {
+				unique = parent + name
+			}
+			for i := 2; used[unique]; i++ {
+				unique = fmt.Sprintf("%s%d", name, i)
+			}
+			used[unique] = true
+			out = append(out, namedType{Name: unique, Type: t})
+
+			for _, key := range fieldKeys(t) {
+				visit(t.Fields[key].Type, goName(key), unique)
+			}
+		}
+	}
+	visit(root, rootName, "")
+
+	return out
+}
+
+// GoStructs renders t as Go type declarations with json struct tags.
+func GoStructs(t *Type, rootName string) (string, error) {
+	if t == nil {
+		return "", fmt.Errorf("no JSON samples to generate from")
+	}
+	rootName = rootTypeName(rootName)
+
+	types := collectTypes(t, rootName)
+	names := typeNames(types)
+
+	var b strings.Builder
+	if usesTime(t) {
+		b.WriteString("import \"time\"\n\n")
+	}
+
+	if t.Kind != KindObject {
+		fmt.Fprintf(&b, "type %s %s\n\n", rootName, goType(t, names))
+	}
+
+	for _, nt := range types {
+		fmt.Fprintf(&b, "type %s struct {\n", nt.Name)
+
+		usedFields := map[string]bool{}
+		for _, key := range fieldKeys(nt.Type) {
+			field := nt.Type.Fields[key]
+
+			fieldName := goName(key)
+			for i := 2; usedFields[fieldName]; i++ {
+				fieldName = fmt.Sprintf("%s%d", goName(key), i)
+			}
+			usedFields[fieldName] = true
+
+			typ := goType(field.Type, names)
+			tag := key
+			if field.Optional {
+				tag += ",omitempty"
+				if field.Type.Kind == KindObject && !strings.HasPrefix(typ, "*") {
+					typ = "*" + typ
+				}
+			}
+			fmt.Fprintf(&b, "\t%s %s `json:%q`\n", fieldName, typ, tag)
+		}
+
+		b.WriteString("}\n\n")
+	}
+
+	src, err := format.Source([]byte(b.String()))
+	if err != nil {
+		return "", fmt.Errorf("error formatting generated Go: %w", err)
+	}
+
+	return strings.TrimSpace(string(src)), nil
+}
+
+// goType returns the Go type expression for t.
+func goType(t *Type, names map[*Type]string) string {
+	if t == nil {
+		return "any"
+	}
+
+	var base string
+	switch t.Kind {
+	case KindNull, KindAny:
+		return "any"
+	case KindBool:
+		base = "bool"
+	case KindInt:
+		base = "int64"
+	case KindFloat:
+		base = "float64"
+	case KindString:
+		base = "string"
+	case KindTime:
+		base = "time.Time"
+	case KindObject:
+		base = names[t]
+	case KindArray:
+		// A nil slice already represents null.
+		return "[]" + goType(t.Elem, names)
+	}
+
+	if t.Nullable {
+		return "*" + base
+	}
+
+	return base
+}
+
+// usesTime reports whether any type reachable from t is KindTime.
+func usesTime(t *Type) bool {
+	if t == nil {
+		return false
+	}
+	switch t.Kind {
+	case KindTime:
+		return true
+	case KindArray:
+		return usesTime(t.Elem)
+	case KindObject:
+		for _, f := range t.Fields {
+			if usesTime(f.Type) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// typeNames indexes the collected declarations by type.
+func typeNames(types []namedType) map[*Type]string {
+	names := make(map[*Type]string, len(types))
+	for _, nt := range types {
+		names[nt.Type] = nt.Name
+	}
+
+	return names
+}
+
+// fieldKeys returns the JSON names of t's fields in lexical order.
+func fieldKeys(t *Type) []string {
+	keys := make([]string, 0, len(t.Fields))
+	for k := range t.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// singular derives an element type name from a plural field name, so the
+// elements of "Items" are called "Item".
+func singular(name string) string {
+	switch {
+	case strings.HasSuffix(name, "ies") && len(name) > 4:
+		return strings.TrimSuffix(name, "ies") + "y"
+	case strings.HasSuffix(name, "ss"), strings.HasSuffix(name, "us"):
+		return name
+	case strings.HasSuffix(name, "s") && len(name) > 3:
+		return strings.TrimSuffix(name, "s")
+	default:
+		return name + "Item"
+	}
+}
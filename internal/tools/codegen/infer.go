@@ -0,0 +1,176 @@
+// Package codegen generates Go structs, TypeScript interfaces and JSON Schema
+// documents from one or more JSON sample payloads.
+package codegen
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/NickDiPreta1/toolhub/internal/tools/jsonutil"
+)
+
+// Kind classifies an inferred JSON type.
+type Kind int
+
+const (
+	KindNull Kind = iota
+	KindBool
+	KindInt
+	KindFloat
+	KindString
+	KindTime
+	KindObject
+	KindArray
+	KindAny
+)
+
+// Type is the shape inferred from one or more JSON values.
+type Type struct {
+	Kind     Kind
+	Nullable bool
+	// Fields holds object members, keyed by JSON name.
+	Fields map[string]*Field
+	// Elem is the element type of an array; nil for an empty array.
+	Elem *Type
+	// Format records a detected string format such as "date".
+	Format string
+}
+
+// Field is an object member and whether every sample contained it.
+type Field struct {
+	Type *Type
+	// Optional is true when the field was missing from at least one sample.
+	Optional bool
+}
+
+// timeLayouts are the string formats promoted to KindTime. Only layouts that
+// encoding/json can decode into time.Time are listed here.
+var timeLayouts = []string{time.RFC3339Nano, time.RFC3339}
+
+// Infer parses input as one or more JSON samples and merges them into a
+// single Type.
+func Infer(input string) (*Type, error) {
+	samples, err := jsonutil.ParseAll(input)
+	if err != nil {
+		return nil, err
+	}
+
+	var merged *Type
+	for _, sample := range samples {
+		merged = merge(merged, typeOf(sample))
+	}
+
+	return merged, nil
+}
+
+// typeOf infers the Type of a single decoded JSON value.
+func typeOf(value any) *Type {
+	switch v := value.(type) {
+	case nil:
+		return &Type{Kind: KindNull, Nullable: true}
+	case bool:
+		return &Type{Kind: KindBool}
+	case json.Number:
+		if _, err := v.Int64(); err == nil && !strings.ContainsAny(v.String(), ".eE") {
+			return &Type{Kind: KindInt}
+		}
+		return &Type{Kind: KindFloat}
+	case string:
+		for _, layout := range timeLayouts {
+			if _, err := time.Parse(layout, v); err == nil {
+				return &Type{Kind: KindTime, Format: "date-time"}
+			}
+		}
+		if _, err := time.Parse(time.DateOnly, v); err == nil {
+			return &Type{Kind: KindString, Format: "date"}
+		}
+		return &Type{Kind: KindString}
+	case []any:
+		t := &Type{Kind: KindArray}
+		for _, elem := range v {
+			t.Elem = merge(t.Elem, typeOf(elem))
+		}
+		return t
+	case map[string]any:
+		t := &Type{Kind: KindObject, Fields: map[string]*Field{}}
+		for k, child := range v {
+			t.Fields[k] = &Field{Type: typeOf(child)}
+		}
+		return t
+	default:
+		return &Type{Kind: KindAny}
+	}
+}
+
+// merge combines two inferred types into one that accepts both.
+func merge(a, b *Type) *Type {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+
+	nullable := a.Nullable || b.Nullable
+	if a.Kind == KindNull {
+		out := *b
+		out.Nullable = true
+		return &out
+	}
+	if b.Kind == KindNull {
+		out := *a
+		out.Nullable = true
+		return &out
+	}
+
+	switch {
+	case a.Kind == b.Kind:
+		out := &Type{Kind: a.Kind, Nullable: nullable}
+		if a.Format == b.Format {
+			out.Format = a.Format
+		}
+		switch a.Kind {
+		case KindObject:
+			out.Fields = mergeFields(a.Fields, b.Fields)
+		case KindArray:
+			out.Elem = merge(a.Elem, b.Elem)
+		}
+		return out
+	case isNumber(a.Kind) && isNumber(b.Kind):
+		return &Type{Kind: KindFloat, Nullable: nullable}
+	case isText(a.Kind) && isText(b.Kind):
+		return &Type{Kind: KindString, Nullable: nullable}
+	default:
+		return &Type{Kind: KindAny, Nullable: nullable}
+	}
+}
+
+// mergeFields unions two field sets, marking fields missing from either side
+// as optional.
+func mergeFields(a, b map[string]*Field) map[string]*Field {
+	out := make(map[string]*Field, len(a))
+	for name, fa := range a {
+		fb, ok := b[name]
+		if !ok {
+			out[name] = &Field{Type: fa.Type, Optional: true}
+			continue
+		}
+		out[name] = &Field{Type: merge(fa.Type, fb.Type), Optional: fa.Optional || fb.Optional}
+	}
+	for name, fb := range b {
+		if _, ok := a[name]; !ok {
+			out[name] = &Field{Type: fb.Type, Optional: true}
+		}
+	}
+
+	return out
+}
+
+func isNumber(k Kind) bool {
+	return k == KindInt || k == KindFloat
+}
+
+func isText(k Kind) bool {
+	return k == KindString || k == KindTime
+}
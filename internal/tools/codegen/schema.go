@@ -0,0 +1,88 @@
+package codegen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// schemaDialect is the JSON Schema draft declared by generated documents.
+const schemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// JSONSchema renders t as a JSON Schema document. Fields present in every
+// sample are listed as required.
+func JSONSchema(t *Type, rootName string) (string, error) {
+	if t == nil {
+		return "", fmt.Errorf("no JSON samples to generate from")
+	}
+
+	doc := schemaFor(t)
+	doc["$schema"] = schemaDialect
+	doc["title"] = rootTypeName(rootName)
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return "", fmt.Errorf("error encoding schema: %w", err)
+	}
+
+	return string(bytes.TrimSpace(buf.Bytes())), nil
+}
+
+// schemaFor builds the schema object describing t.
+func schemaFor(t *Type) map[string]any {
+	s := map[string]any{}
+	if t == nil {
+		return s
+	}
+
+	var typ string
+	switch t.Kind {
+	case KindNull:
+		s["type"] = "null"
+		return s
+	case KindAny:
+		return s
+	case KindBool:
+		typ = "boolean"
+	case KindInt:
+		typ = "integer"
+	case KindFloat:
+		typ = "number"
+	case KindString, KindTime:
+		typ = "string"
+		if t.Format != "" {
+			s["format"] = t.Format
+		}
+	case KindObject:
+		typ = "object"
+		props := map[string]any{}
+		var required []string
+		for _, key := range fieldKeys(t) {
+			field := t.Fields[key]
+			props[key] = schemaFor(field.Type)
+			if !field.Optional {
+				required = append(required, key)
+			}
+		}
+		s["properties"] = props
+		if len(required) > 0 {
+			s["required"] = required
+		}
+	case KindArray:
+		typ = "array"
+		if t.Elem != nil {
+			s["items"] = schemaFor(t.Elem)
+		}
+	}
+
+	if t.Nullable {
+		s["type"] = []string{typ, "null"}
+	} else {
+		s["type"] = typ
+	}
+
+	return s
+}
@@ -0,0 +1,80 @@
+package codegen
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// TypeScript renders t as exported TypeScript interfaces. Times are typed as
+// string because JSON carries them as ISO 8601 text.
+func TypeScript(t *Type, rootName string) (string, error) {
+	if t == nil {
+		return "", fmt.Errorf("no JSON samples to generate from")
+	}
+	rootName = rootTypeName(rootName)
+
+	types := collectTypes(t, rootName)
+	names := typeNames(types)
+
+	var blocks []string
+	if t.Kind != KindObject {
+		blocks = append(blocks, fmt.Sprintf("export type %s = %s;", rootName, tsType(t, names)))
+	}
+
+	for _, nt := range types {
+		var b strings.Builder
+		fmt.Fprintf(&b, "export interface %s {\n", nt.Name)
+		for _, key := range fieldKeys(nt.Type) {
+			field := nt.Type.Fields[key]
+
+			prop := key
+			if !isTSIdentifier(key) {
+				prop = strconv.Quote(key)
+			}
+			if field.Optional {
+				prop += "?"
+			}
+			fmt.Fprintf(&b, "  %s: %s;\n", prop, tsType(field.Type, names))
+		}
+		b.WriteString("}")
+		blocks = append(blocks, b.String())
+	}
+
+	return strings.Join(blocks, "\n\n"), nil
+}
+
+// tsType returns the TypeScript type expression for t.
+func tsType(t *Type, names map[*Type]string) string {
+	if t == nil {
+		return "unknown"
+	}
+
+	var base string
+	switch t.Kind {
+	case KindNull:
+		return "null"
+	case KindAny:
+		return "unknown"
+	case KindBool:
+		base = "boolean"
+	case KindInt, KindFloat:
+		base = "number"
+	case KindString, KindTime:
+		base = "string"
+	case KindObject:
+		base = names[t]
+	case KindArray:
+		elem := tsType(t.Elem, names)
+		if strings.Contains(elem, " ") {
+			elem = "(" + elem + ")"
+		}
+		base = elem + "[]"
+	}
+
+	if t.Nullable {
+		return base + " | null"
+	}
+
+	return base
+}
@@ -0,0 +1,244 @@
+// Package csvutil reads, validates, reshapes and re-encodes delimited text.
+// Unlike encoding/csv it accepts any delimiter and quote character, and it
+// remembers the line each record starts on so problems can be reported
+// against the original input.
+package csvutil
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Dialect describes how a file is delimited.
+type Dialect struct {
+	Delimiter rune
+	// Quote encloses fields containing the delimiter or line breaks and is
+	// doubled to escape itself. Zero disables quoting.
+	Quote rune
+	// Header is true when the first record names the columns.
+	Header bool
+}
+
+// Errors wrapped by ParseError.
+var (
+	ErrBareQuote    = errors.New("quote character in unquoted field")
+	ErrQuote        = errors.New("unexpected text after closing quote")
+	ErrUnterminated = errors.New("quoted field is never closed")
+)
+
+// ParseError reports malformed quoting at a 1-based line and column.
+type ParseError struct {
+	Line   int
+	Column int
+	Err    error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("line %d, column %d: %v", e.Line, e.Column, e.Err)
+}
+
+func (e *ParseError) Unwrap() error { return e.Err }
+
+// Table is parsed delimited data.
+type Table struct {
+	// Header names the columns. Without a header row it holds generated
+	// names (column1, column2, ...) and HasHeader is false.
+	Header    []string
+	HasHeader bool
+	Rows      [][]string
+	// Lines holds the input line each row starts on.
+	Lines []int
+}
+
+// Width returns the number of columns named by the header.
+func (t *Table) Width() int {
+	return len(t.Header)
+}
+
+// Column returns every row's value in column i, or "" for short rows.
+func (t *Table) Column(i int) []string {
+	values := make([]string, len(t.Rows))
+	for r, row := range t.Rows {
+		if i < len(row) {
+			values[r] = row[i]
+		}
+	}
+	return values
+}
+
+// Parse splits input into records using d. Blank lines are skipped and a
+// leading byte order mark is ignored. Rows may have differing field counts;
+// Validate reports them.
+func Parse(input string, d Dialect) (*Table, error) {
+	if d.Delimiter == 0 || d.Delimiter == '\n' || d.Delimiter == '\r' || d.Delimiter == utf8.RuneError {
+		return nil, fmt.Errorf("invalid delimiter %q", d.Delimiter)
+	}
+	if d.Quote == d.Delimiter || d.Quote == '\n' || d.Quote == '\r' {
+		return nil, fmt.Errorf("invalid quote character %q", d.Quote)
+	}
+
+	p := &parser{input: strings.TrimPrefix(input, "\ufeff"), d: d, line: 1}
+	var records [][]string
+	var lines []int
+	for {
+		p.skipBlankLines()
+		if p.pos >= len(p.input) {
+			break
+		}
+		start := p.line
+		record, err := p.readRecord()
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+		lines = append(lines, start)
+	}
+
+	t := &Table{HasHeader: d.Header}
+	if d.Header && len(records) > 0 {
+		t.Header = records[0]
+		records, lines = records[1:], lines[1:]
+	} else {
+		width := 0
+		for _, r := range records {
+			width = max(width, len(r))
+		}
+		t.Header = GeneratedNames(width)
+	}
+	t.Rows = records
+	t.Lines = lines
+	return t, nil
+}
+
+// GeneratedNames returns column1 through columnN.
+func GeneratedNames(n int) []string {
+	names := make([]string, n)
+	for i := range names {
+		names[i] = "column" + strconv.Itoa(i+1)
+	}
+	return names
+}
+
+type parser struct {
+	input     string
+	d         Dialect
+	pos       int
+	line      int
+	lineStart int
+}
+
+func (p *parser) skipBlankLines() {
+	for p.pos < len(p.input) {
+		switch {
+		case p.input[p.pos] == '\n':
+			p.newline(p.pos + 1)
+		case strings.HasPrefix(p.input[p.pos:], "\r\n"):
+			p.newline(p.pos + 2)
+		default:
+			return
+		}
+	}
+}
+
+// newline moves past a line break that ends just before next.
+func (p *parser) newline(next int) {
+	p.pos = next
+	p.line++
+	p.lineStart = next
+}
+
+func (p *parser) column(pos int) int {
+	return utf8.RuneCountInString(p.input[p.lineStart:pos]) + 1
+}
+
+// readRecord reads fields up to the end of the line, or of the input.
+func (p *parser) readRecord() ([]string, error) {
+	var fields []string
+	for {
+		var field string
+		var err error
+		if p.d.Quote != 0 && strings.HasPrefix(p.input[p.pos:], string(p.d.Quote)) {
+			field, err = p.readQuoted()
+		} else {
+			field, err = p.readUnquoted()
+		}
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, field)
+
+		rest := p.input[p.pos:]
+		switch {
+		case rest == "":
+			return fields, nil
+		case strings.HasPrefix(rest, string(p.d.Delimiter)):
+			p.pos += utf8.RuneLen(p.d.Delimiter)
+		case rest[0] == '\n':
+			p.newline(p.pos + 1)
+			return fields, nil
+		case strings.HasPrefix(rest, "\r\n"):
+			p.newline(p.pos + 2)
+			return fields, nil
+		default:
+			return nil, &ParseError{Line: p.line, Column: p.column(p.pos), Err: ErrQuote}
+		}
+	}
+}
+
+func (p *parser) readUnquoted() (string, error) {
+	rest := p.input[p.pos:]
+	end := len(rest)
+	if i := strings.IndexRune(rest, p.d.Delimiter); i >= 0 {
+		end = i
+	}
+	if i := strings.IndexByte(rest[:end], '\n'); i >= 0 {
+		end = i
+		if i > 0 && rest[i-1] == '\r' {
+			end = i - 1
+		}
+	}
+
+	field := rest[:end]
+	if p.d.Quote != 0 {
+		if i := strings.IndexRune(field, p.d.Quote); i >= 0 {
+			return "", &ParseError{Line: p.line, Column: p.column(p.pos + i), Err: ErrBareQuote}
+		}
+	}
+	p.pos += end
+	return field, nil
+}
+
+func (p *parser) readQuoted() (string, error) {
+	quote := string(p.d.Quote)
+	line, col := p.line, p.column(p.pos)
+	p.pos += len(quote)
+
+	var b strings.Builder
+	for {
+		rest := p.input[p.pos:]
+		i := strings.Index(rest, quote)
+		if i < 0 {
+			return "", &ParseError{Line: line, Column: col, Err: ErrUnterminated}
+		}
+		chunk := rest[:i]
+		b.WriteString(chunk)
+		// Keep line numbers right across line breaks inside the field.
+		for j := strings.IndexByte(chunk, '\n'); j >= 0; j = strings.IndexByte(chunk, '\n') {
+			p.line++
+			p.lineStart = p.pos + j + 1
+			p.pos += j + 1
+			chunk = chunk[j+1:]
+		}
+		p.pos += len(chunk) + len(quote)
+
+		if strings.HasPrefix(p.input[p.pos:], quote) {
+			b.WriteString(quote)
+			p.pos += len(quote)
+			continue
+		}
+		return strings.ReplaceAll(b.String(), "\r\n", "\n"), nil
+	}
+}
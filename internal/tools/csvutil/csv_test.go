@@ -0,0 +1,120 @@
+package csvutil
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		dialect    Dialect
+		wantHeader []string
+		wantRows   [][]string
+		wantLines  []int
+	}{
+		{
+			name:       "header and rows",
+			input:      "id,name\n1,Ada\n2,Grace\n",
+			dialect:    Dialect{Delimiter: ',', Quote: '"', Header: true},
+			wantHeader: []string{"id", "name"},
+			wantRows:   [][]string{{"1", "Ada"}, {"2", "Grace"}},
+			wantLines:  []int{2, 3},
+		},
+		{
+			name:       "quoted fields with delimiters, escaped quotes and line breaks",
+			input:      "a,b\r\n\"x,y\",\"say \"\"hi\"\"\"\r\n\"two\r\nlines\",z\r\nlast,row",
+			dialect:    Dialect{Delimiter: ',', Quote: '"', Header: true},
+			wantHeader: []string{"a", "b"},
+			wantRows:   [][]string{{"x,y", `say "hi"`}, {"two\nlines", "z"}, {"last", "row"}},
+			wantLines:  []int{2, 3, 5},
+		},
+		{
+			name:       "blank lines skipped and BOM ignored",
+			input:      "\ufeffa;b\n\n1;2\n\n\n3;4",
+			dialect:    Dialect{Delimiter: ';', Quote: '"', Header: true},
+			wantHeader: []string{"a", "b"},
+			wantRows:   [][]string{{"1", "2"}, {"3", "4"}},
+			wantLines:  []int{3, 6},
+		},
+		{
+			name:       "single quotes and ragged rows without header",
+			input:      "'a|b'|c\nd\n",
+			dialect:    Dialect{Delimiter: '|', Quote: '\''},
+			wantHeader: []string{"column1", "column2"},
+			wantRows:   [][]string{{"a|b", "c"}, {"d"}},
+			wantLines:  []int{1, 2},
+		},
+		{
+			name:       "quoting disabled",
+			input:      "a\t\"b\"\n",
+			dialect:    Dialect{Delimiter: '\t'},
+			wantHeader: []string{"column1", "column2"},
+			wantRows:   [][]string{{"a", `"b"`}},
+			wantLines:  []int{1},
+		},
+		{
+			name:       "empty fields",
+			input:      ",\n,x,",
+			dialect:    Dialect{Delimiter: ',', Quote: '"'},
+			wantHeader: []string{"column1", "column2", "column3"},
+			wantRows:   [][]string{{"", ""}, {"", "x", ""}},
+			wantLines:  []int{1, 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			table, err := Parse(tt.input, tt.dialect)
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			if !reflect.DeepEqual(table.Header, tt.wantHeader) {
+				t.Errorf("Header = %q, want %q", table.Header, tt.wantHeader)
+			}
+			if !reflect.DeepEqual(table.Rows, tt.wantRows) {
+				t.Errorf("Rows = %q, want %q", table.Rows, tt.wantRows)
+			}
+			if !reflect.DeepEqual(table.Lines, tt.wantLines) {
+				t.Errorf("Lines = %v, want %v", table.Lines, tt.wantLines)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		wantErr    error
+		wantLine   int
+		wantColumn int
+	}{
+		{"bare quote", "a,b\nc,d\"e\n", ErrBareQuote, 2, 4},
+		{"text after closing quote", "a,\"b\"c\n", ErrQuote, 1, 6},
+		{"unterminated", "a,b\n\"c,d\ne,f\n", ErrUnterminated, 2, 1},
+		{"line counted inside quoted field", "\"a\nb\",c\"d\n", ErrBareQuote, 2, 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.input, Dialect{Delimiter: ',', Quote: '"'})
+			var perr *ParseError
+			if !errors.As(err, &perr) || !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if perr.Line != tt.wantLine || perr.Column != tt.wantColumn {
+				t.Errorf("position = %d:%d, want %d:%d", perr.Line, perr.Column, tt.wantLine, tt.wantColumn)
+			}
+		})
+	}
+
+	if _, err := Parse("a", Dialect{Delimiter: ',', Quote: ','}); err == nil {
+		t.Error("quote equal to delimiter accepted")
+	}
+	if _, err := Parse("a", Dialect{}); err == nil {
+		t.Error("missing delimiter accepted")
+	}
+}
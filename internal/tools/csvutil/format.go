@@ -0,0 +1,305 @@
+package csvutil
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Select returns a table holding only the columns listed in spec, in that
+// order. spec is a comma-separated list of columns named by header or
+// 1-based position, each optionally renamed with =, as in
+// "id, name=full_name, 4". An empty spec keeps every column.
+func Select(t *Table, spec string) (*Table, error) {
+	if strings.TrimSpace(spec) == "" {
+		return t, nil
+	}
+
+	var cols []int
+	var header []string
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		name, rename, renamed := strings.Cut(item, "=")
+		col, err := findColumn(t, strings.TrimSpace(name))
+		if err != nil {
+			return nil, err
+		}
+		cols = append(cols, col)
+		if renamed {
+			header = append(header, strings.TrimSpace(rename))
+		} else {
+			header = append(header, t.Header[col])
+		}
+	}
+
+	out := &Table{Header: header, HasHeader: t.HasHeader, Lines: t.Lines}
+	out.Rows = make([][]string, len(t.Rows))
+	for r, row := range t.Rows {
+		selected := make([]string, len(cols))
+		for i, col := range cols {
+			if col < len(row) {
+				selected[i] = row[col]
+			}
+		}
+		out.Rows[r] = selected
+	}
+	return out, nil
+}
+
+// QuoteMode controls which fields Write quotes.
+type QuoteMode string
+
+const (
+	// QuoteMinimal quotes only fields that need it.
+	QuoteMinimal QuoteMode = "minimal"
+	QuoteAll     QuoteMode = "all"
+	// QuoteNonNumeric quotes every field that is not a number.
+	QuoteNonNumeric QuoteMode = "nonnumeric"
+	// QuoteNone never quotes, and fails on fields that would need it.
+	QuoteNone QuoteMode = "none"
+)
+
+// QuoteModes lists every quote mode in display order.
+var QuoteModes = []QuoteMode{QuoteMinimal, QuoteAll, QuoteNonNumeric, QuoteNone}
+
+// WriteOptions configures Write.
+type WriteOptions struct {
+	Delimiter rune
+	Quote     rune
+	Mode      QuoteMode
+}
+
+// Write encodes t as delimited text, with a header row when t has one.
+// Lines end in \n.
+func Write(w io.Writer, t *Table, opts WriteOptions) error {
+	if opts.Delimiter == 0 || opts.Delimiter == '\n' || opts.Delimiter == '\r' {
+		return fmt.Errorf("invalid delimiter %q", opts.Delimiter)
+	}
+	switch opts.Mode {
+	case QuoteMinimal, QuoteAll, QuoteNonNumeric:
+		if opts.Quote == 0 || opts.Quote == opts.Delimiter || opts.Quote == '\n' || opts.Quote == '\r' {
+			return fmt.Errorf("invalid quote character %q", opts.Quote)
+		}
+	case QuoteNone:
+	default:
+		return fmt.Errorf("unknown quote mode %q", opts.Mode)
+	}
+
+	bw := bufio.NewWriter(w)
+	if t.HasHeader {
+		if err := writeRecord(bw, t.Header, opts, 0); err != nil {
+			return err
+		}
+	}
+	for r, row := range t.Rows {
+		if err := writeRecord(bw, row, opts, t.Lines[r]); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+func writeRecord(w *bufio.Writer, record []string, opts WriteOptions, line int) error {
+	quote := string(opts.Quote)
+	for i, field := range record {
+		if i > 0 {
+			w.WriteRune(opts.Delimiter)
+		}
+
+		special := strings.ContainsRune(field, opts.Delimiter) || strings.ContainsAny(field, "\r\n") ||
+			(opts.Quote != 0 && strings.ContainsRune(field, opts.Quote))
+		var quoted bool
+		switch opts.Mode {
+		case QuoteAll:
+			quoted = true
+		case QuoteNonNumeric:
+			quoted = special || !TypeFloat.Matches(field)
+		case QuoteNone:
+			if special {
+				if line == 0 {
+					return fmt.Errorf("header field %d needs quoting, which is turned off", i+1)
+				}
+				return fmt.Errorf("line %d: field %d needs quoting, which is turned off", line, i+1)
+			}
+		default:
+			quoted = special || field != strings.TrimSpace(field)
+		}
+
+		if quoted {
+			w.WriteString(quote)
+			w.WriteString(strings.ReplaceAll(field, quote, quote+quote))
+			w.WriteString(quote)
+		} else {
+			w.WriteString(field)
+		}
+	}
+	_, err := w.WriteString("\n")
+	return err
+}
+
+// ToJSON returns t as an indented JSON array with one object per row, keyed
+// by column name in column order. When infer is set, cells holding
+// numbers, true or false are written as JSON numbers and booleans and
+// empty cells as null; otherwise every value is a string.
+func ToJSON(t *Table, infer bool) (string, error) {
+	keys := uniqueKeys(t.Header)
+
+	var buf bytes.Buffer
+	buf.WriteByte('[')
+	for r, row := range t.Rows {
+		if r > 0 {
+			buf.WriteByte(',')
+		}
+		buf.WriteByte('{')
+		for i, cell := range row {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			key := "column" + strconv.Itoa(i+1)
+			if i < len(keys) {
+				key = keys[i]
+			}
+			k, err := json.Marshal(key)
+			if err != nil {
+				return "", err
+			}
+			buf.Write(k)
+			buf.WriteByte(':')
+
+			v, err := json.Marshal(cellValue(cell, infer))
+			if err != nil {
+				return "", err
+			}
+			buf.Write(v)
+		}
+		buf.WriteByte('}')
+	}
+	buf.WriteByte(']')
+
+	var out bytes.Buffer
+	if err := json.Indent(&out, buf.Bytes(), "", "  "); err != nil {
+		return "", err
+	}
+	return out.String(), nil
+}
+
+// cellValue converts cell to the JSON value ToJSON writes. Numbers convert
+// only when lossless, so "007" and "1e3" stay strings.
+func cellValue(cell string, infer bool) any {
+	if !infer {
+		return cell
+	}
+	switch cell {
+	case "":
+		return nil
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+	if i, err := strconv.ParseInt(cell, 10, 64); err == nil && strconv.FormatInt(i, 10) == cell {
+		return json.Number(cell)
+	}
+	if f, err := strconv.ParseFloat(cell, 64); err == nil && strconv.FormatFloat(f, 'f', -1, 64) == cell {
+		return json.Number(cell)
+	}
+	return cell
+}
+
+// uniqueKeys names empty headers by position and suffixes repeated ones
+// with _2, _3 and so on, so every row object has distinct keys.
+func uniqueKeys(header []string) []string {
+	keys := make([]string, len(header))
+	seen := map[string]bool{}
+	for i, h := range header {
+		if strings.TrimSpace(h) == "" {
+			h = "column" + strconv.Itoa(i+1)
+		}
+		key := h
+		for n := 2; seen[key]; n++ {
+			key = h + "_" + strconv.Itoa(n)
+		}
+		seen[key] = true
+		keys[i] = key
+	}
+	return keys
+}
+
+// ToMarkdown returns t as a GitHub-flavoured Markdown table with padded
+// columns. Numeric columns are right-aligned; pipes are escaped and line
+// breaks inside cells become <br>.
+func ToMarkdown(t *Table) string {
+	width := t.Width()
+	for _, row := range t.Rows {
+		width = max(width, len(row))
+	}
+	if width == 0 {
+		return ""
+	}
+
+	header := append(t.Header[:len(t.Header):len(t.Header)], GeneratedNames(width)[len(t.Header):]...)
+	cells := make([][]string, 0, len(t.Rows)+1)
+	for _, row := range append([][]string{header}, t.Rows...) {
+		escaped := make([]string, width)
+		for i := range escaped {
+			if i < len(row) {
+				escaped[i] = markdownCell(row[i])
+			}
+		}
+		cells = append(cells, escaped)
+	}
+
+	widths := make([]int, width)
+	right := make([]bool, width)
+	for i := range widths {
+		widths[i] = 3
+		for _, row := range cells {
+			widths[i] = max(widths[i], utf8.RuneCountInString(row[i]))
+		}
+		right[i] = i < t.Width() && InferType(t.Column(i)).Numeric()
+	}
+
+	var b strings.Builder
+	writeRow := func(row []string) {
+		b.WriteString("|")
+		for i, cell := range row {
+			pad := strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell))
+			if right[i] {
+				b.WriteString(" " + pad + cell + " |")
+			} else {
+				b.WriteString(" " + cell + pad + " |")
+			}
+		}
+		b.WriteString("\n")
+	}
+
+	writeRow(cells[0])
+	b.WriteString("|")
+	for i, w := range widths {
+		if right[i] {
+			b.WriteString(" " + strings.Repeat("-", w-1) + ": |")
+		} else {
+			b.WriteString(" " + strings.Repeat("-", w) + " |")
+		}
+	}
+	b.WriteString("\n")
+	for _, row := range cells[1:] {
+		writeRow(row)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func markdownCell(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, "|", `\|`)
+	s = strings.ReplaceAll(s, "\r\n", "<br>")
+	return strings.ReplaceAll(s, "\n", "<br>")
+}
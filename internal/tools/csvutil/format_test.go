@@ -0,0 +1,128 @@
+package csvutil
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func sample(t *testing.T) *Table {
+	t.Helper()
+	table, err := Parse("id,name,score\n1,\"Lovelace, Ada\",9.5\n2,Grace,\n", Dialect{Delimiter: ',', Quote: '"', Header: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return table
+}
+
+func TestSelect(t *testing.T) {
+	table, err := Select(sample(t), "score, 2=full_name")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(table.Header, "|") != "score|full_name" {
+		t.Errorf("Header = %q", table.Header)
+	}
+	if strings.Join(table.Rows[0], "|") != "9.5|Lovelace, Ada" || strings.Join(table.Rows[1], "|") != "|Grace" {
+		t.Errorf("Rows = %q", table.Rows)
+	}
+
+	if _, err := Select(sample(t), "id, nope"); err == nil || !strings.Contains(err.Error(), `unknown column "nope"`) {
+		t.Errorf("unknown column error = %v", err)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	tests := []struct {
+		name string
+		opts WriteOptions
+		want string
+	}{
+		{
+			name: "minimal",
+			opts: WriteOptions{Delimiter: ',', Quote: '"', Mode: QuoteMinimal},
+			want: "id,name,score\n1,\"Lovelace, Ada\",9.5\n2,Grace,\n",
+		},
+		{
+			name: "all with single quotes",
+			opts: WriteOptions{Delimiter: ';', Quote: '\'', Mode: QuoteAll},
+			want: "'id';'name';'score'\n'1';'Lovelace, Ada';'9.5'\n'2';'Grace';''\n",
+		},
+		{
+			name: "non-numeric",
+			opts: WriteOptions{Delimiter: '\t', Quote: '"', Mode: QuoteNonNumeric},
+			want: "\"id\"\t\"name\"\t\"score\"\n1\t\"Lovelace, Ada\"\t9.5\n2\t\"Grace\"\t\"\"\n",
+		},
+		{
+			name: "none",
+			opts: WriteOptions{Delimiter: '|', Mode: QuoteNone},
+			want: "id|name|score\n1|Lovelace, Ada|9.5\n2|Grace|\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Write(&buf, sample(t), tt.opts); err != nil {
+				t.Fatal(err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("got\n%s\nwant\n%s", buf.String(), tt.want)
+			}
+		})
+	}
+
+	err := Write(&bytes.Buffer{}, sample(t), WriteOptions{Delimiter: ',', Mode: QuoteNone})
+	if err == nil || !strings.Contains(err.Error(), "line 2: field 2 needs quoting") {
+		t.Errorf("QuoteNone error = %v", err)
+	}
+	if err := Write(&bytes.Buffer{}, sample(t), WriteOptions{Delimiter: ',', Quote: '"', Mode: "sometimes"}); err == nil {
+		t.Error("unknown quote mode accepted")
+	}
+}
+
+func TestToJSON(t *testing.T) {
+	got, err := ToJSON(sample(t), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `[
+  {
+    "id": 1,
+    "name": "Lovelace, Ada",
+    "score": 9.5
+  },
+  {
+    "id": 2,
+    "name": "Grace",
+    "score": null
+  }
+]`
+	if got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+
+	table := &Table{Header: []string{"a", "a", ""}, Rows: [][]string{{"007", "true", "x", "extra"}}, Lines: []int{2}}
+	got, err = ToJSON(table, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(got, `"a": "007"`) || !strings.Contains(got, `"a_2": "true"`) ||
+		!strings.Contains(got, `"column3": "x"`) || !strings.Contains(got, `"column4": "extra"`) {
+		t.Errorf("keys not made unique:\n%s", got)
+	}
+}
+
+func TestToMarkdown(t *testing.T) {
+	table := sample(t)
+	table.Rows = append(table.Rows, []string{"3", "pipe | and\nbreak", "10"})
+	table.Lines = append(table.Lines, 4)
+
+	want := "|  id | name                 | score |\n" +
+		"| --: | -------------------- | ----: |\n" +
+		"|   1 | Lovelace, Ada        |   9.5 |\n" +
+		"|   2 | Grace                |       |\n" +
+		"|   3 | pipe \\| and<br>break |    10 |"
+	if got := ToMarkdown(table); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
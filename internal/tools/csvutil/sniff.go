@@ -0,0 +1,148 @@
+package csvutil
+
+import (
+	"errors"
+	"strings"
+)
+
+// sniffLines is how many lines of input Sniff examines.
+const sniffLines = 50
+
+// Delimiters lists the delimiters Sniff tries, in order of preference.
+var Delimiters = []rune{',', ';', '\t', '|', ':'}
+
+// Sniff guesses the dialect of input from its first lines. The delimiter
+// is the candidate that splits the most records into the same number of
+// fields; the quote is ' only when more fields are enclosed in ' than in ";
+// and a header is assumed unless the first record looks like data.
+func Sniff(input string) Dialect {
+	sample := sampleLines(strings.TrimPrefix(input, "\ufeff"), sniffLines)
+	best := Dialect{Delimiter: ',', Quote: '"'}
+	bestScore := -1.0
+
+	for _, delim := range Delimiters {
+		quote := sniffQuote(sample, delim)
+		t, err := parseSample(sample, Dialect{Delimiter: delim, Quote: quote})
+		if err != nil || len(t.Rows) == 0 {
+			continue
+		}
+		if score := consistency(t.Rows); score > bestScore {
+			best = Dialect{Delimiter: delim, Quote: quote}
+			bestScore = score
+		}
+	}
+
+	if t, err := parseSample(sample, best); err == nil {
+		best.Header = sniffHeader(t.Rows)
+	}
+	return best
+}
+
+// sampleLines returns the first n lines of input.
+func sampleLines(input string, n int) string {
+	end := 0
+	for i := 0; i < n && end < len(input); i++ {
+		j := strings.IndexByte(input[end:], '\n')
+		if j < 0 {
+			return input
+		}
+		end += j + 1
+	}
+	return input[:end]
+}
+
+// parseSample parses sample with d, dropping a final record whose quoted
+// field was cut off by the end of the sample.
+func parseSample(sample string, d Dialect) (*Table, error) {
+	t, err := Parse(sample, d)
+	var perr *ParseError
+	if errors.As(err, &perr) && errors.Is(err, ErrUnterminated) && perr.Line > 1 {
+		return Parse(sampleLines(sample, perr.Line-1), d)
+	}
+	return t, err
+}
+
+// consistency scores how well rows agree on a field count greater than
+// one: the fraction of rows with the most common count, plus a small
+// bonus for wider rows to break ties.
+func consistency(rows [][]string) float64 {
+	counts := map[int]int{}
+	for _, r := range rows {
+		counts[len(r)]++
+	}
+	mode, n := 0, 0
+	for width, c := range counts {
+		if c > n || c == n && width > mode {
+			mode, n = width, c
+		}
+	}
+	if mode < 2 {
+		return 0
+	}
+	return float64(n)/float64(len(rows)) + float64(mode)/1000
+}
+
+// sniffQuote counts fields enclosed in each quote character.
+func sniffQuote(sample string, delim rune) rune {
+	double, single := 0, 0
+	for _, line := range strings.Split(sample, "\n") {
+		for _, field := range strings.Split(strings.TrimSuffix(line, "\r"), string(delim)) {
+			field = strings.TrimSpace(field)
+			if len(field) < 2 {
+				continue
+			}
+			switch {
+			case field[0] == '"' && field[len(field)-1] == '"':
+				double++
+			case field[0] == '\'' && field[len(field)-1] == '\'':
+				single++
+			}
+		}
+	}
+	if single > double {
+		return '\''
+	}
+	return '"'
+}
+
+// sniffHeader votes column by column: a column whose values share a type
+// the first cell does not fit votes for a header, and one whose first cell
+// fits that type votes against. Without votes, a first row of distinct,
+// non-empty cells is taken as a header.
+func sniffHeader(rows [][]string) bool {
+	if len(rows) < 2 {
+		return len(rows) == 1
+	}
+	first, body := rows[0], rows[1:]
+
+	votes := 0
+	for i, cell := range first {
+		values := make([]string, 0, len(body))
+		for _, r := range body {
+			if i < len(r) {
+				values = append(values, r[i])
+			}
+		}
+		typ := InferType(values)
+		if typ == TypeString {
+			continue
+		}
+		if cell != "" && !typ.Matches(cell) {
+			votes++
+		} else {
+			votes--
+		}
+	}
+	if votes != 0 {
+		return votes > 0
+	}
+
+	seen := map[string]bool{}
+	for _, cell := range first {
+		if strings.TrimSpace(cell) == "" || seen[cell] {
+			return false
+		}
+		seen[cell] = true
+	}
+	return true
+}
@@ -0,0 +1,53 @@
+package csvutil
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSniff(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  Dialect
+	}{
+		{
+			name:  "comma with header",
+			input: "id,name,joined\n1,Ada,2024-01-02\n2,Grace,2024-02-03\n",
+			want:  Dialect{Delimiter: ',', Quote: '"', Header: true},
+		},
+		{
+			name:  "semicolon with decimal commas",
+			input: "name;price\n\"Widget\";1,50\n\"Gadget, large\";12,00\n",
+			want:  Dialect{Delimiter: ';', Quote: '"', Header: true},
+		},
+		{
+			name:  "tab without header",
+			input: "1\t2.5\ttrue\n2\t3.5\tfalse\n3\t4\ttrue\n",
+			want:  Dialect{Delimiter: '\t', Quote: '"', Header: false},
+		},
+		{
+			name:  "pipe with single quotes",
+			input: "'a'|'b'\n'x|y'|'z'\n",
+			want:  Dialect{Delimiter: '|', Quote: '\'', Header: true},
+		},
+		{
+			name:  "numeric header row is data",
+			input: "10,20\n30,40\n",
+			want:  Dialect{Delimiter: ',', Quote: '"', Header: false},
+		},
+		{
+			name:  "quoted field cut off by the sample",
+			input: strings.Repeat("a,b\n", sniffLines-1) + "\"multi\nline\",c\n",
+			want:  Dialect{Delimiter: ',', Quote: '"', Header: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Sniff(tt.input); got != tt.want {
+				t.Errorf("Sniff = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,218 @@
+package csvutil
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ColumnType is the kind of value a column holds.
+type ColumnType string
+
+const (
+	TypeString ColumnType = "string"
+	TypeInt    ColumnType = "int"
+	TypeFloat  ColumnType = "float"
+	TypeBool   ColumnType = "bool"
+	TypeDate   ColumnType = "date"
+)
+
+// ColumnTypes lists every type, from narrowest to widest as InferType
+// tries them.
+var ColumnTypes = []ColumnType{TypeBool, TypeInt, TypeFloat, TypeDate, TypeString}
+
+// DateLayouts are the date and timestamp layouts TypeDate accepts.
+var DateLayouts = []string{
+	"2006-01-02",
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"01/02/2006",
+}
+
+// ParseColumnType returns the ColumnType matching name, ignoring case.
+func ParseColumnType(name string) (ColumnType, error) {
+	t := ColumnType(strings.ToLower(strings.TrimSpace(name)))
+	for _, known := range ColumnTypes {
+		if t == known {
+			return t, nil
+		}
+	}
+	return "", fmt.Errorf("unknown column type %q", name)
+}
+
+// Matches reports whether value is a valid t. Every value is a string, and
+// every int is a float.
+func (t ColumnType) Matches(value string) bool {
+	value = strings.TrimSpace(value)
+	switch t {
+	case TypeInt:
+		_, err := strconv.ParseInt(value, 10, 64)
+		return err == nil
+	case TypeFloat:
+		_, err := strconv.ParseFloat(value, 64)
+		return err == nil
+	case TypeBool:
+		switch strings.ToLower(value) {
+		case "true", "false", "yes", "no":
+			return true
+		}
+		return false
+	case TypeDate:
+		for _, layout := range DateLayouts {
+			if _, err := time.Parse(layout, value); err == nil {
+				return true
+			}
+		}
+		return false
+	default:
+		return true
+	}
+}
+
+// Numeric reports whether t holds numbers.
+func (t ColumnType) Numeric() bool {
+	return t == TypeInt || t == TypeFloat
+}
+
+// InferType returns the narrowest type that every non-empty value matches,
+// or TypeString when there are no non-empty values.
+func InferType(values []string) ColumnType {
+	for _, t := range ColumnTypes {
+		seen := false
+		ok := true
+		for _, v := range values {
+			if strings.TrimSpace(v) == "" {
+				continue
+			}
+			seen = true
+			if !t.Matches(v) {
+				ok = false
+				break
+			}
+		}
+		if !seen {
+			return TypeString
+		}
+		if ok {
+			return t
+		}
+	}
+	return TypeString
+}
+
+// InferTypes returns the inferred type of every column of t.
+func InferTypes(t *Table) []ColumnType {
+	types := make([]ColumnType, t.Width())
+	for i := range types {
+		types[i] = InferType(t.Column(i))
+	}
+	return types
+}
+
+// Rule is what Validate expects of one column.
+type Rule struct {
+	Type     ColumnType
+	Required bool
+}
+
+// ParseRules reads a comma-separated list of column:type pairs, such as
+// "id:int!, price:float, 4:date", into one rule per column of t. Columns are
+// named by header or 1-based position; a trailing ! marks the column
+// required. Unlisted columns accept any value.
+func ParseRules(t *Table, spec string) ([]Rule, error) {
+	rules := make([]Rule, t.Width())
+	for i := range rules {
+		rules[i].Type = TypeString
+	}
+
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		i := strings.LastIndex(item, ":")
+		if i < 0 {
+			return nil, fmt.Errorf("rule %q must look like column:type", item)
+		}
+		col, err := findColumn(t, strings.TrimSpace(item[:i]))
+		if err != nil {
+			return nil, err
+		}
+		typeName := strings.TrimSpace(item[i+1:])
+		required := strings.HasSuffix(typeName, "!")
+		typ, err := ParseColumnType(strings.TrimSuffix(typeName, "!"))
+		if err != nil {
+			return nil, err
+		}
+		rules[col] = Rule{Type: typ, Required: required}
+	}
+	return rules, nil
+}
+
+// Issue is one problem Validate found.
+type Issue struct {
+	Line int
+	// Column is the header name, or empty for whole-row problems.
+	Column  string
+	Message string
+}
+
+func (i Issue) String() string {
+	if i.Column == "" {
+		return fmt.Sprintf("line %d: %s", i.Line, i.Message)
+	}
+	return fmt.Sprintf("line %d, column %q: %s", i.Line, i.Column, i.Message)
+}
+
+// Validate checks that every row has one field per column and that each
+// field satisfies its column's rule. It returns at most limit issues, or
+// all of them when limit is zero or less, and the total number found.
+func Validate(t *Table, rules []Rule, limit int) ([]Issue, int) {
+	var issues []Issue
+	total := 0
+	report := func(issue Issue) {
+		total++
+		if limit <= 0 || len(issues) < limit {
+			issues = append(issues, issue)
+		}
+	}
+
+	width := t.Width()
+	for r, row := range t.Rows {
+		line := t.Lines[r]
+		if len(row) != width {
+			report(Issue{Line: line, Message: fmt.Sprintf("expected %d fields, got %d", width, len(row))})
+		}
+		for i, rule := range rules {
+			value := ""
+			if i < len(row) {
+				value = row[i]
+			}
+			switch {
+			case strings.TrimSpace(value) == "":
+				if rule.Required {
+					report(Issue{Line: line, Column: t.Header[i], Message: "value is required"})
+				}
+			case !rule.Type.Matches(value):
+				report(Issue{Line: line, Column: t.Header[i], Message: fmt.Sprintf("%q is not a valid %s", value, rule.Type)})
+			}
+		}
+	}
+	return issues, total
+}
+
+// findColumn returns the index of the column named name, or at the 1-based
+// position name gives.
+func findColumn(t *Table, name string) (int, error) {
+	for i, h := range t.Header {
+		if h == name {
+			return i, nil
+		}
+	}
+	if n, err := strconv.Atoi(name); err == nil && n >= 1 && n <= t.Width() {
+		return n - 1, nil
+	}
+	return 0, fmt.Errorf("unknown column %q", name)
+}
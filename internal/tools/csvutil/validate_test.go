@@ -0,0 +1,81 @@
+package csvutil
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestInferType(t *testing.T) {
+	tests := []struct {
+		values []string
+		want   ColumnType
+	}{
+		{[]string{"1", "-2", ""}, TypeInt},
+		{[]string{"1", "2.5"}, TypeFloat},
+		{[]string{"true", "No", " yes "}, TypeBool},
+		{[]string{"2024-01-02", "2024-01-02T10:00:00Z", "03/04/2024"}, TypeDate},
+		{[]string{"1", "x"}, TypeString},
+		{[]string{"", " "}, TypeString},
+		{nil, TypeString},
+	}
+	for _, tt := range tests {
+		if got := InferType(tt.values); got != tt.want {
+			t.Errorf("InferType(%q) = %s, want %s", tt.values, got, tt.want)
+		}
+	}
+}
+
+func TestParseRules(t *testing.T) {
+	table := &Table{Header: []string{"id", "price", "note:text"}}
+
+	rules, err := ParseRules(table, "id:int!, 2:float, note:text:string")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Rule{{TypeInt, true}, {TypeFloat, false}, {TypeString, false}}
+	if !reflect.DeepEqual(rules, want) {
+		t.Errorf("rules = %+v, want %+v", rules, want)
+	}
+
+	for spec, wantErr := range map[string]string{
+		"id":          "must look like column:type",
+		"missing:int": `unknown column "missing"`,
+		"4:int":       `unknown column "4"`,
+		"id:money":    `unknown column type "money"`,
+	} {
+		if _, err := ParseRules(table, spec); err == nil || !strings.Contains(err.Error(), wantErr) {
+			t.Errorf("ParseRules(%q) error = %v, want %q", spec, err, wantErr)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	table, err := Parse("id,price,active\n1,9.99,true\nx,1,maybe\n3\n4,,false,extra\n", Dialect{Delimiter: ',', Quote: '"', Header: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rules := []Rule{{TypeInt, true}, {TypeFloat, true}, {TypeBool, false}}
+
+	issues, total := Validate(table, rules, 0)
+	var got []string
+	for _, issue := range issues {
+		got = append(got, issue.String())
+	}
+	want := []string{
+		`line 3, column "id": "x" is not a valid int`,
+		`line 3, column "active": "maybe" is not a valid bool`,
+		`line 4: expected 3 fields, got 1`,
+		`line 4, column "price": value is required`,
+		`line 5: expected 3 fields, got 4`,
+		`line 5, column "price": value is required`,
+	}
+	if !reflect.DeepEqual(got, want) || total != len(want) {
+		t.Errorf("issues (%d) =\n%s\nwant\n%s", total, strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	issues, total = Validate(table, rules, 2)
+	if len(issues) != 2 || total != len(want) {
+		t.Errorf("limited: %d issues, total %d; want 2 and %d", len(issues), total, len(want))
+	}
+}
@@ -0,0 +1,267 @@
+// Package dataconvert converts structured data between JSON, YAML, TOML,
+// XML and CSV. Every conversion decodes the input into a generic Go value
+// (maps, slices and scalars) and then encodes that value in the target format.
+package dataconvert
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Format identifies a supported data format.
+type Format string
+
+const (
+	JSON Format = "json"
+	YAML Format = "yaml"
+	TOML Format = "toml"
+	XML  Format = "xml"
+	CSV  Format = "csv"
+)
+
+// Formats lists every supported format in display order.
+var Formats = []Format{JSON, YAML, TOML, XML, CSV}
+
+// ParseFormat returns the Format matching name, ignoring case.
+func ParseFormat(name string) (Format, error) {
+	f := Format(strings.ToLower(strings.TrimSpace(name)))
+	for _, known := range Formats {
+		if f == known {
+			return f, nil
+		}
+	}
+
+	return "", fmt.Errorf("unsupported format %q", name)
+}
+
+// Convert decodes input as the from format and re-encodes it as the to format.
+func Convert(input string, from, to Format) (string, error) {
+	if strings.TrimSpace(input) == "" {
+		return "", fmt.Errorf("input cannot be empty")
+	}
+
+	value, err := Decode(input, from)
+	if err != nil {
+		return "", err
+	}
+
+	return Encode(value, to)
+}
+
+// Decode parses input in the given format into a generic value.
+func Decode(input string, from Format) (any, error) {
+	switch from {
+	case JSON:
+		return decodeJSON(input)
+	case YAML:
+		return decodeYAML(input)
+	case TOML:
+		return decodeTOML(input)
+	case XML:
+		return decodeXML(input)
+	case CSV:
+		return decodeCSV(input)
+	default:
+		return nil, fmt.Errorf("unsupported format %q", from)
+	}
+}
+
+// Encode renders a generic value in the given format.
+func Encode(value any, to Format) (string, error) {
+	switch to {
+	case JSON:
+		return encodeJSON(value)
+	case YAML:
+		return encodeYAML(value)
+	case TOML:
+		return encodeTOML(value)
+	case XML:
+		return encodeXML(value)
+	case CSV:
+		return encodeCSV(value)
+	default:
+		return "", fmt.Errorf("unsupported format %q", to)
+	}
+}
+
+func decodeJSON(input string) (any, error) {
+	dec := json.NewDecoder(strings.NewReader(input))
+	dec.UseNumber()
+
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	if dec.More() {
+		return nil, fmt.Errorf("invalid JSON: unexpected data after top-level value")
+	}
+
+	return normalize(v), nil
+}
+
+func encodeJSON(value any) (string, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(value); err != nil {
+		return "", fmt.Errorf("error encoding JSON: %w", err)
+	}
+
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
+
+func decodeYAML(input string) (any, error) {
+	var v any
+	if err := yaml.Unmarshal([]byte(input), &v); err != nil {
+		return nil, fmt.Errorf("invalid YAML: %w", err)
+	}
+
+	return normalize(v), nil
+}
+
+func encodeYAML(value any) (string, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(value); err != nil {
+		return "", fmt.Errorf("error encoding YAML: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return "", fmt.Errorf("error encoding YAML: %w", err)
+	}
+
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
+
+func decodeTOML(input string) (any, error) {
+	var v map[string]any
+	if _, err := toml.Decode(input, &v); err != nil {
+		return nil, fmt.Errorf("invalid TOML: %w", err)
+	}
+
+	return normalize(v), nil
+}
+
+func encodeTOML(value any) (string, error) {
+	// TOML documents are always tables, so only objects can be encoded.
+	if _, ok := value.(map[string]any); !ok {
+		return "", fmt.Errorf("TOML requires a top-level object, got %s", kindOf(value))
+	}
+	if err := checkTOMLNulls(value, ""); err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	enc := toml.NewEncoder(&buf)
+	enc.Indent = ""
+	if err := enc.Encode(value); err != nil {
+		return "", fmt.Errorf("error encoding TOML: %w", err)
+	}
+
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
+
+// checkTOMLNulls reports the first null value, which TOML cannot represent.
+func checkTOMLNulls(value any, path string) error {
+	switch v := value.(type) {
+	case nil:
+		return fmt.Errorf("TOML cannot represent null (at %q)", path)
+	case map[string]any:
+		for k, child := range v {
+			if err := checkTOMLNulls(child, joinPath(path, k)); err != nil {
+				return err
+			}
+		}
+	case []any:
+		for i, child := range v {
+			if err := checkTOMLNulls(child, joinPath(path, fmt.Sprint(i))); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// normalize converts decoder-specific types into the small set of types the
+// encoders share: map[string]any, []any, string, bool, int64, float64 and nil.
+func normalize(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for k, child := range v {
+			out[k] = normalize(child)
+		}
+		return out
+	case map[any]any:
+		out := make(map[string]any, len(v))
+		for k, child := range v {
+			out[fmt.Sprint(k)] = normalize(child)
+		}
+		return out
+	case []map[string]any:
+		out := make([]any, len(v))
+		for i, child := range v {
+			out[i] = normalize(child)
+		}
+		return out
+	case []any:
+		out := make([]any, len(v))
+		for i, child := range v {
+			out[i] = normalize(child)
+		}
+		return out
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	case int:
+		return int64(v)
+	case uint64:
+		if v <= math.MaxInt64 {
+			return int64(v)
+		}
+		return float64(v)
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	default:
+		return v
+	}
+}
+
+// kindOf names the JSON kind of a generic value for error messages.
+func kindOf(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	default:
+		return "number"
+	}
+}
+
+// joinPath appends key to a dotted path.
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+
+	return path + "." + key
+}
@@ -0,0 +1,208 @@
+package dataconvert
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		from        Format
+		to          Format
+		expected    string
+		expectError bool
+	}{
+		// JSON <-> YAML
+		{
+			name:     "json to yaml",
+			input:    `{"name":"toolhub","port":4000,"tags":["go","web"]}`,
+			from:     JSON,
+			to:       YAML,
+			expected: "name: toolhub\nport: 4000\ntags:\n  - go\n  - web",
+		},
+		{
+			name:     "yaml to json",
+			input:    "name: toolhub\nport: 4000\ndebug: false\n",
+			from:     YAML,
+			to:       JSON,
+			expected: "{\n  \"debug\": false,\n  \"name\": \"toolhub\",\n  \"port\": 4000\n}",
+		},
+
+		// JSON <-> TOML
+		{
+			name:     "json to toml",
+			input:    `{"title":"demo","server":{"port":8080}}`,
+			from:     JSON,
+			to:       TOML,
+			expected: "title = \"demo\"\n\n[server]\nport = 8080",
+		},
+		{
+			name:     "toml to json",
+			input:    "title = \"demo\"\n[server]\nport = 8080\n",
+			from:     TOML,
+			to:       JSON,
+			expected: "{\n  \"server\": {\n    \"port\": 8080\n  },\n  \"title\": \"demo\"\n}",
+		},
+		{
+			name:        "toml rejects top-level array",
+			input:       `[1,2]`,
+			from:        JSON,
+			to:          TOML,
+			expectError: true,
+		},
+		{
+			name:        "toml rejects null",
+			input:       `{"a":null}`,
+			from:        JSON,
+			to:          TOML,
+			expectError: true,
+		},
+
+		// JSON <-> XML
+		{
+			name:     "json to xml with attributes",
+			input:    `{"book":{"@id":"42","title":"Go","tag":["a","b"]}}`,
+			from:     JSON,
+			to:       XML,
+			expected: "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n<book id=\"42\">\n  <tag>a</tag>\n  <tag>b</tag>\n  <title>Go</title>\n</book>",
+		},
+		{
+			name:     "xml to json with attributes and text",
+			input:    `<book id="42"><title lang="en">Go</title><tag>a</tag><tag>b</tag></book>`,
+			from:     XML,
+			to:       JSON,
+			expected: "{\n  \"book\": {\n    \"@id\": \"42\",\n    \"tag\": [\n      \"a\",\n      \"b\"\n    ],\n    \"title\": {\n      \"#text\": \"Go\",\n      \"@lang\": \"en\"\n    }\n  }\n}",
+		},
+		{
+			name:     "json array to xml wraps in root",
+			input:    `[1,2]`,
+			from:     JSON,
+			to:       XML,
+			expected: "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n<root>\n  <item>1</item>\n  <item>2</item>\n</root>",
+		},
+		{
+			name:        "invalid xml name",
+			input:       `{"1bad":"x"}`,
+			from:        JSON,
+			to:          XML,
+			expectError: true,
+		},
+
+		// JSON <-> CSV
+		{
+			name:     "json to csv infers header and flattens",
+			input:    `[{"id":1,"user":{"name":"ann"}},{"id":2,"tags":["x","y"]}]`,
+			from:     JSON,
+			to:       CSV,
+			expected: "id,tags.0,tags.1,user.name\n1,,,ann\n2,x,y,",
+		},
+		{
+			name:     "csv to json unflattens",
+			input:    "id,user.name,tags.0,tags.1,zip\n1,ann,x,y,007\n",
+			from:     CSV,
+			to:       JSON,
+			expected: "[\n  {\n    \"id\": 1,\n    \"tags\": [\n      \"x\",\n      \"y\"\n    ],\n    \"user\": {\n      \"name\": \"ann\"\n    },\n    \"zip\": \"007\"\n  }\n]",
+		},
+		{
+			name:        "csv rejects scalar array",
+			input:       `[1,2]`,
+			from:        JSON,
+			to:          CSV,
+			expectError: true,
+		},
+
+		// Errors
+		{
+			name:        "empty input",
+			input:       "  ",
+			from:        JSON,
+			to:          YAML,
+			expectError: true,
+		},
+		{
+			name:        "invalid json",
+			input:       `{"a":`,
+			from:        JSON,
+			to:          YAML,
+			expectError: true,
+		},
+		{
+			name:        "trailing json data",
+			input:       `{} {}`,
+			from:        JSON,
+			to:          YAML,
+			expectError: true,
+		},
+		{
+			name:        "invalid yaml",
+			input:       "a: [1, 2",
+			from:        YAML,
+			to:          JSON,
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Convert(tt.input, tt.from, tt.to)
+
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Convert(%q) expected error but got nil", tt.input)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Convert(%q) unexpected error: %v", tt.input, err)
+			}
+
+			if result != tt.expected {
+				t.Errorf("Convert(%q)\ngot:\n%s\n\nwant:\n%s", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+// TestConvertRoundTrip checks that converting JSON to each format and back
+// preserves the document.
+func TestConvertRoundTrip(t *testing.T) {
+	input := `{"service":{"name":"api","port":8080,"ratio":0.5,"enabled":true,"hosts":["a","b"]}}`
+
+	for _, format := range []Format{YAML, TOML} {
+		t.Run(string(format), func(t *testing.T) {
+			converted, err := Convert(input, JSON, format)
+			if err != nil {
+				t.Fatalf("Convert to %s failed: %v", format, err)
+			}
+
+			back, err := Convert(converted, format, JSON)
+			if err != nil {
+				t.Fatalf("Convert from %s failed: %v", format, err)
+			}
+
+			var want, got any
+			if err := json.Unmarshal([]byte(input), &want); err != nil {
+				t.Fatal(err)
+			}
+			if err := json.Unmarshal([]byte(back), &got); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(want, got) {
+				t.Errorf("round trip through %s changed the document:\nwant: %v\ngot:  %v", format, want, got)
+			}
+		})
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	if f, err := ParseFormat(" YAML "); err != nil || f != YAML {
+		t.Errorf("ParseFormat(\" YAML \") = %q, %v; want %q", f, err, YAML)
+	}
+	if _, err := ParseFormat("ini"); err == nil {
+		t.Error("ParseFormat(\"ini\") expected error but got nil")
+	}
+}
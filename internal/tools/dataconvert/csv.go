@@ -0,0 +1,191 @@
+package dataconvert
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// KeySeparator joins nested keys when objects are flattened into CSV columns,
+// so {"user":{"name":"a"}} becomes the column "user.name".
+const KeySeparator = "."
+
+func encodeCSV(value any) (string, error) {
+	var records []any
+	switch v := value.(type) {
+	case []any:
+		records = v
+	case map[string]any:
+		records = []any{v}
+	default:
+		return "", fmt.Errorf("CSV requires an array of objects, got %s", kindOf(value))
+	}
+
+	rows := make([]map[string]string, 0, len(records))
+	seen := map[string]bool{}
+	var header []string
+
+	for i, rec := range records {
+		obj, ok := rec.(map[string]any)
+		if !ok {
+			return "", fmt.Errorf("CSV requires an array of objects, element %d is %s", i, kindOf(rec))
+		}
+
+		row := map[string]string{}
+		flatten(obj, "", row)
+		for k := range row {
+			if !seen[k] {
+				seen[k] = true
+				header = append(header, k)
+			}
+		}
+		rows = append(rows, row)
+	}
+	sort.Strings(header)
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(header); err != nil {
+		return "", fmt.Errorf("error encoding CSV: %w", err)
+	}
+	for _, row := range rows {
+		line := make([]string, len(header))
+		for i, k := range header {
+			line[i] = row[k]
+		}
+		if err := w.Write(line); err != nil {
+			return "", fmt.Errorf("error encoding CSV: %w", err)
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return "", fmt.Errorf("error encoding CSV: %w", err)
+	}
+
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
+
+// flatten writes every leaf of value into row, keyed by its dotted path.
+// Array elements use their index as the path segment.
+func flatten(value any, prefix string, row map[string]string) {
+	switch v := value.(type) {
+	case map[string]any:
+		for k, child := range v {
+			flatten(child, joinPath(prefix, k), row)
+		}
+	case []any:
+		for i, child := range v {
+			flatten(child, joinPath(prefix, strconv.Itoa(i)), row)
+		}
+	default:
+		row[prefix] = scalarString(v)
+	}
+}
+
+func decodeCSV(input string) (any, error) {
+	r := csv.NewReader(strings.NewReader(input))
+	r.TrimLeadingSpace = true
+
+	lines, err := r.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV: %w", err)
+	}
+	if len(lines) == 0 {
+		return nil, fmt.Errorf("invalid CSV: missing header row")
+	}
+
+	header := lines[0]
+	records := make([]any, 0, len(lines)-1)
+	for _, line := range lines[1:] {
+		obj := map[string]any{}
+		for i, cell := range line {
+			// Empty cells are treated as absent so sparse records round-trip.
+			if cell == "" {
+				continue
+			}
+			if err := setPath(obj, strings.Split(header[i], KeySeparator), inferScalar(cell)); err != nil {
+				return nil, fmt.Errorf("invalid CSV: column %q: %w", header[i], err)
+			}
+		}
+		records = append(records, rebuildArrays(obj))
+	}
+
+	return records, nil
+}
+
+// setPath stores value in obj under the nested path, creating objects as needed.
+func setPath(obj map[string]any, path []string, value any) error {
+	for _, key := range path[:len(path)-1] {
+		next, ok := obj[key]
+		if !ok {
+			child := map[string]any{}
+			obj[key] = child
+			obj = child
+			continue
+		}
+		child, ok := next.(map[string]any)
+		if !ok {
+			return fmt.Errorf("%q is both a value and an object", key)
+		}
+		obj = child
+	}
+
+	last := path[len(path)-1]
+	if _, exists := obj[last]; exists {
+		return fmt.Errorf("duplicate column")
+	}
+	obj[last] = value
+
+	return nil
+}
+
+// rebuildArrays turns objects whose keys are exactly 0..n-1 back into arrays.
+func rebuildArrays(value any) any {
+	obj, ok := value.(map[string]any)
+	if !ok {
+		return value
+	}
+
+	for k, child := range obj {
+		obj[k] = rebuildArrays(child)
+	}
+
+	if len(obj) == 0 {
+		return obj
+	}
+	list := make([]any, len(obj))
+	for k, child := range obj {
+		i, err := strconv.Atoi(k)
+		if err != nil || i < 0 || i >= len(obj) || strconv.Itoa(i) != k {
+			return obj
+		}
+		list[i] = child
+	}
+
+	return list
+}
+
+// inferScalar converts a CSV cell to a bool or number when the conversion is
+// lossless, so "42" becomes 42 but "007" stays a string.
+func inferScalar(cell string) any {
+	switch cell {
+	case "true":
+		return true
+	case "false":
+		return false
+	case "null":
+		return nil
+	}
+
+	if i, err := strconv.ParseInt(cell, 10, 64); err == nil && strconv.FormatInt(i, 10) == cell {
+		return i
+	}
+	if f, err := strconv.ParseFloat(cell, 64); err == nil && strconv.FormatFloat(f, 'f', -1, 64) == cell {
+		return f
+	}
+
+	return cell
+}
@@ -0,0 +1,260 @@
+package dataconvert
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// XML has no native notion of objects, arrays or attributes-vs-children, so
+// conversions follow the common "badgerfish-lite" convention:
+//   - attributes become keys prefixed with AttrPrefix ("@id")
+//   - text content next to attributes or children is stored under TextKey
+//   - repeated child elements become arrays
+//   - an element with only text becomes a plain string
+const (
+	AttrPrefix = "@"
+	TextKey    = "#text"
+)
+
+// defaultRoot wraps values that do not name a single root element.
+const defaultRoot = "root"
+
+// arrayItem names elements produced from array entries that have no key.
+const arrayItem = "item"
+
+func decodeXML(input string) (any, error) {
+	dec := xml.NewDecoder(strings.NewReader(input))
+
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("invalid XML: no root element")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid XML: %w", err)
+		}
+
+		if start, ok := tok.(xml.StartElement); ok {
+			value, err := decodeXMLElement(dec, start)
+			if err != nil {
+				return nil, fmt.Errorf("invalid XML: %w", err)
+			}
+			return map[string]any{start.Name.Local: value}, nil
+		}
+	}
+}
+
+// decodeXMLElement consumes tokens up to the end of start and returns its value.
+func decodeXMLElement(dec *xml.Decoder, start xml.StartElement) (any, error) {
+	obj := map[string]any{}
+	for _, attr := range start.Attr {
+		if attr.Name.Space == "xmlns" || attr.Name.Local == "xmlns" {
+			continue
+		}
+		obj[AttrPrefix+attr.Name.Local] = attr.Value
+	}
+
+	var text strings.Builder
+	hasChildren := false
+
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			child, err := decodeXMLElement(dec, t)
+			if err != nil {
+				return nil, err
+			}
+			hasChildren = true
+			addXMLChild(obj, t.Name.Local, child)
+		case xml.CharData:
+			text.Write(t)
+		case xml.EndElement:
+			content := strings.TrimSpace(text.String())
+			if len(obj) == 0 && !hasChildren {
+				return content, nil
+			}
+			if content != "" {
+				obj[TextKey] = content
+			}
+			return obj, nil
+		}
+	}
+}
+
+// addXMLChild stores a child value, turning repeated names into arrays.
+func addXMLChild(obj map[string]any, name string, child any) {
+	existing, ok := obj[name]
+	if !ok {
+		obj[name] = child
+		return
+	}
+
+	if list, ok := existing.([]any); ok {
+		obj[name] = append(list, child)
+		return
+	}
+
+	obj[name] = []any{existing, child}
+}
+
+func encodeXML(value any) (string, error) {
+	rootName := defaultRoot
+	rootValue := value
+	if obj, ok := value.(map[string]any); ok && len(obj) == 1 {
+		for k, v := range obj {
+			if _, isList := v.([]any); !isList && !strings.HasPrefix(k, AttrPrefix) && k != TextKey {
+				rootName, rootValue = k, v
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+
+	enc := xml.NewEncoder(&buf)
+	enc.Indent("", "  ")
+	if err := encodeXMLElement(enc, rootName, rootValue); err != nil {
+		return "", err
+	}
+	if err := enc.Flush(); err != nil {
+		return "", fmt.Errorf("error encoding XML: %w", err)
+	}
+
+	return buf.String(), nil
+}
+
+// encodeXMLElement writes value as one element named name.
+func encodeXMLElement(enc *xml.Encoder, name string, value any) error {
+	if !isXMLName(name) {
+		return fmt.Errorf("%q is not a valid XML element name", name)
+	}
+
+	start := xml.StartElement{Name: xml.Name{Local: name}}
+
+	switch v := value.(type) {
+	case map[string]any:
+		keys := sortedKeys(v)
+		var children []string
+		var text string
+		for _, k := range keys {
+			switch {
+			case strings.HasPrefix(k, AttrPrefix):
+				attr := strings.TrimPrefix(k, AttrPrefix)
+				if !isXMLName(attr) {
+					return fmt.Errorf("%q is not a valid XML attribute name", attr)
+				}
+				start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: attr}, Value: scalarString(v[k])})
+			case k == TextKey:
+				text = scalarString(v[k])
+			default:
+				children = append(children, k)
+			}
+		}
+
+		if err := enc.EncodeToken(start); err != nil {
+			return err
+		}
+		if text != "" {
+			if err := enc.EncodeToken(xml.CharData(text)); err != nil {
+				return err
+			}
+		}
+		for _, k := range children {
+			if list, ok := v[k].([]any); ok {
+				for _, item := range list {
+					if err := encodeXMLElement(enc, k, item); err != nil {
+						return err
+					}
+				}
+				continue
+			}
+			if err := encodeXMLElement(enc, k, v[k]); err != nil {
+				return err
+			}
+		}
+		return enc.EncodeToken(start.End())
+
+	case []any:
+		if err := enc.EncodeToken(start); err != nil {
+			return err
+		}
+		for _, item := range v {
+			if err := encodeXMLElement(enc, arrayItem, item); err != nil {
+				return err
+			}
+		}
+		return enc.EncodeToken(start.End())
+
+	case nil:
+		if err := enc.EncodeToken(start); err != nil {
+			return err
+		}
+		return enc.EncodeToken(start.End())
+
+	default:
+		if err := enc.EncodeToken(start); err != nil {
+			return err
+		}
+		if err := enc.EncodeToken(xml.CharData(scalarString(v))); err != nil {
+			return err
+		}
+		return enc.EncodeToken(start.End())
+	}
+}
+
+// isXMLName reports whether s is usable as an unprefixed XML name.
+func isXMLName(s string) bool {
+	if s == "" || strings.HasPrefix(strings.ToLower(s), "xml") {
+		return false
+	}
+	for i, r := range s {
+		switch {
+		case r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r > 0x7f:
+		case i > 0 && (r == '-' || r == '.' || r >= '0' && r <= '9'):
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+// scalarString formats a scalar value as text.
+func scalarString(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// sortedKeys returns the keys of m in lexical order.
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -1,6 +1,10 @@
 package encodingutil
 
-import "encoding/base64"
+import (
+	"encoding/base64"
+	"strings"
+	"unicode"
+)
 
 // Encode returns the base64 encoding of the input string.
 func Encode(input string) string {
@@ -16,3 +20,22 @@ func Decode(input string) (string, error) {
 
 	return string(decodedBytes), nil
 }
+
+// DecodeBytes decodes base64 written with either the standard or the
+// URL-safe alphabet, with or without padding. Whitespace, such as the line
+// breaks of wrapped output, is ignored.
+func DecodeBytes(input string) ([]byte, error) {
+	input = strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, input)
+	input = strings.TrimRight(input, "=")
+
+	enc := base64.RawStdEncoding
+	if strings.ContainsAny(input, "-_") {
+		enc = base64.RawURLEncoding
+	}
+	return enc.DecodeString(input)
+}
@@ -1,6 +1,9 @@
 package encodingutil
 
-import "testing"
+import (
+	"bytes"
+	"testing"
+)
 
 func TestEncode(t *testing.T) {
 	tests := []struct {
@@ -239,3 +242,22 @@ func TestEncodeDecodeRountTrip(t *testing.T) {
 		})
 	}
 }
+
+func TestDecodeBytes(t *testing.T) {
+	want := []byte{0xfb, 0xff, 0xbf, 'h', 'i'}
+	for _, in := range []string{
+		"+/+/aGk=",
+		"+/+/aGk",
+		"-_-_aGk",
+		"+/+/\naGk=\n",
+		"  -_-_ aGk= ",
+	} {
+		got, err := DecodeBytes(in)
+		if err != nil || !bytes.Equal(got, want) {
+			t.Errorf("DecodeBytes(%q) = % x, %v; want % x", in, got, err, want)
+		}
+	}
+	if _, err := DecodeBytes("not base64!"); err == nil {
+		t.Error("DecodeBytes accepted invalid input")
+	}
+}
@@ -0,0 +1,333 @@
+package encodingutil
+
+import (
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/flate"
+	"compress/gzip"
+	"compress/lzw"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Codec names a compression format.
+type Codec string
+
+const (
+	// CodecAuto detects gzip, zlib and bzip2 input when decompressing.
+	CodecAuto    Codec = "auto"
+	CodecGzip    Codec = "gzip"
+	CodecZlib    Codec = "zlib"
+	CodecDeflate Codec = "deflate"
+	CodecBzip2   Codec = "bzip2"
+	// CodecLZWLSB is LZW with least-significant-bit-first codes, as in GIF.
+	CodecLZWLSB Codec = "lzw-lsb"
+	// CodecLZWMSB is LZW with most-significant-bit-first codes, as in TIFF
+	// and PDF.
+	CodecLZWMSB Codec = "lzw-msb"
+)
+
+// CodecOption describes a selectable codec for the UI.
+type CodecOption struct {
+	Codec Codec
+	Name  string
+	// Compress is false for codecs the standard library can only decode.
+	Compress bool
+	// Leveled is true for codecs that take a compression level.
+	Leveled bool
+}
+
+// Codecs lists the supported codecs in display order.
+var Codecs = []CodecOption{
+	{CodecAuto, "Detect (decompress only)", false, false},
+	{CodecGzip, "gzip", true, true},
+	{CodecZlib, "zlib", true, true},
+	{CodecDeflate, "Raw deflate", true, true},
+	{CodecBzip2, "bzip2 (decompress only)", false, false},
+	{CodecLZWLSB, "LZW, LSB first (GIF)", true, false},
+	{CodecLZWMSB, "LZW, MSB first (TIFF, PDF)", true, false},
+}
+
+// Compression levels accepted by the deflate-based codecs, from
+// compress/flate.
+const (
+	MinLevel     = flate.HuffmanOnly
+	MaxLevel     = flate.BestCompression
+	DefaultLevel = flate.DefaultCompression
+)
+
+// ErrOutputTooLarge is returned when decompressed data exceeds the limit
+// given to Decompress.
+var ErrOutputTooLarge = errors.New("decompressed data exceeds the size limit")
+
+// CompressOptions configures Compress.
+type CompressOptions struct {
+	Codec Codec
+	// Level is a compress/flate level for gzip, zlib and deflate.
+	Level int
+	// Name and ModTime are stored in the gzip header when set.
+	Name    string
+	ModTime time.Time
+}
+
+// GzipInfo is the header metadata of a gzip stream.
+type GzipInfo struct {
+	Name    string
+	Comment string
+	ModTime time.Time
+	OS      byte
+	OSName  string
+	// ExtraFlags describes the XFL byte, which hints at the level used.
+	ExtraFlags string
+	ExtraLen   int
+	// Members counts the concatenated gzip members; the other fields
+	// describe the first.
+	Members int
+}
+
+// CompressStats reports the sizes on both sides of a run.
+type CompressStats struct {
+	Codec        Codec
+	Compressed   int64
+	Uncompressed int64
+	Gzip         *GzipInfo
+}
+
+// Ratio returns how many times larger the data is uncompressed.
+func (s CompressStats) Ratio() float64 {
+	if s.Compressed == 0 {
+		return 0
+	}
+	return float64(s.Uncompressed) / float64(s.Compressed)
+}
+
+// Savings returns the fraction of the uncompressed size saved, which is
+// negative when compression made the data larger.
+func (s CompressStats) Savings() float64 {
+	if s.Uncompressed == 0 {
+		return 0
+	}
+	return 1 - float64(s.Compressed)/float64(s.Uncompressed)
+}
+
+// Compress reads r and writes it to w compressed with opts.Codec.
+func Compress(w io.Writer, r io.Reader, opts CompressOptions) (CompressStats, error) {
+	stats := CompressStats{Codec: opts.Codec}
+	out := &countingWriter{w: w}
+
+	var zw io.WriteCloser
+	var err error
+	switch opts.Codec {
+	case CodecGzip, CodecZlib, CodecDeflate:
+		if opts.Level < MinLevel || opts.Level > MaxLevel {
+			return stats, fmt.Errorf("compression level must be between %d and %d", MinLevel, MaxLevel)
+		}
+		switch opts.Codec {
+		case CodecGzip:
+			var gw *gzip.Writer
+			gw, err = gzip.NewWriterLevel(out, opts.Level)
+			if err == nil {
+				gw.Name = opts.Name
+				gw.ModTime = opts.ModTime
+			}
+			zw = gw
+		case CodecZlib:
+			zw, err = zlib.NewWriterLevel(out, opts.Level)
+		default:
+			zw, err = flate.NewWriter(out, opts.Level)
+		}
+	case CodecLZWLSB:
+		zw = lzw.NewWriter(out, lzw.LSB, 8)
+	case CodecLZWMSB:
+		zw = lzw.NewWriter(out, lzw.MSB, 8)
+	case CodecBzip2, CodecAuto:
+		return stats, fmt.Errorf("%s can only be decompressed", opts.Codec)
+	default:
+		return stats, fmt.Errorf("unsupported codec %q", opts.Codec)
+	}
+	if err != nil {
+		return stats, err
+	}
+
+	n, err := io.Copy(zw, r)
+	stats.Uncompressed = n
+	if err != nil {
+		return stats, err
+	}
+	if err := zw.Close(); err != nil {
+		return stats, err
+	}
+	stats.Compressed = out.n
+	return stats, nil
+}
+
+// Decompress reads compressed data from r and writes at most limit bytes
+// of output to w; larger output fails with ErrOutputTooLarge. CodecAuto
+// picks the codec from the leading bytes.
+func Decompress(w io.Writer, r io.Reader, codec Codec, limit int64) (CompressStats, error) {
+	in := &countingReader{r: r}
+	br := bufio.NewReader(in)
+
+	if codec == CodecAuto {
+		head, _ := br.Peek(4)
+		codec = DetectCodec(head)
+		if codec == "" {
+			return CompressStats{Codec: CodecAuto}, errors.New("could not detect the compression format; choose one")
+		}
+	}
+	stats := CompressStats{Codec: codec}
+	out := &limitWriter{w: w, remaining: limit}
+
+	var err error
+	switch codec {
+	case CodecGzip:
+		stats.Gzip, err = gunzip(out, br)
+	case CodecZlib:
+		var zr io.ReadCloser
+		if zr, err = zlib.NewReader(br); err == nil {
+			defer zr.Close()
+			_, err = io.Copy(out, zr)
+		}
+	case CodecDeflate:
+		_, err = io.Copy(out, flate.NewReader(br))
+	case CodecBzip2:
+		_, err = io.Copy(out, bzip2.NewReader(br))
+	case CodecLZWLSB:
+		_, err = io.Copy(out, lzw.NewReader(br, lzw.LSB, 8))
+	case CodecLZWMSB:
+		_, err = io.Copy(out, lzw.NewReader(br, lzw.MSB, 8))
+	default:
+		return stats, fmt.Errorf("unsupported codec %q", codec)
+	}
+
+	// Count only the compressed bytes actually consumed.
+	stats.Compressed = in.n - int64(br.Buffered())
+	stats.Uncompressed = out.n
+	if err != nil && !errors.Is(err, ErrOutputTooLarge) {
+		err = fmt.Errorf("invalid %s data: %w", codec, err)
+	}
+	return stats, err
+}
+
+// gunzip decompresses every member of a gzip stream and returns the
+// first member's header.
+func gunzip(w io.Writer, br *bufio.Reader) (*GzipInfo, error) {
+	raw, _ := br.Peek(10)
+	zr, err := gzip.NewReader(br)
+	if err != nil {
+		return nil, err
+	}
+	info := &GzipInfo{
+		Name:     zr.Name,
+		Comment:  zr.Comment,
+		ModTime:  zr.ModTime,
+		OS:       zr.OS,
+		OSName:   gzipOSName(zr.OS),
+		ExtraLen: len(zr.Extra),
+	}
+	if len(raw) == 10 {
+		info.ExtraFlags = gzipExtraFlags(raw[8])
+	}
+
+	zr.Multistream(false)
+	for {
+		info.Members++
+		if _, err := io.Copy(w, zr); err != nil {
+			return info, err
+		}
+		if err := zr.Reset(br); err == io.EOF {
+			return info, nil
+		} else if err != nil {
+			return info, err
+		}
+		zr.Multistream(false)
+	}
+}
+
+// DetectCodec recognises gzip, zlib and bzip2 data by its leading bytes.
+// It returns "" for anything else; raw deflate and LZW have no signature.
+func DetectCodec(head []byte) Codec {
+	switch {
+	case bytes.HasPrefix(head, []byte{0x1f, 0x8b}):
+		return CodecGzip
+	case bytes.HasPrefix(head, []byte("BZh")):
+		return CodecBzip2
+	case len(head) >= 2 && head[0]&0x0f == 8 && head[0]>>4 <= 7 && (uint16(head[0])<<8|uint16(head[1]))%31 == 0:
+		// A zlib header: deflate method with a valid check value.
+		return CodecZlib
+	default:
+		return ""
+	}
+}
+
+var gzipOSNames = map[byte]string{
+	0: "FAT (MS-DOS, Windows)", 1: "Amiga", 2: "VMS", 3: "Unix", 4: "VM/CMS",
+	5: "Atari TOS", 6: "HPFS (OS/2)", 7: "Macintosh", 8: "Z-System", 9: "CP/M",
+	10: "TOPS-20", 11: "NTFS", 12: "QDOS", 13: "Acorn RISC OS", 255: "unknown",
+}
+
+func gzipOSName(os byte) string {
+	if name, ok := gzipOSNames[os]; ok {
+		return name
+	}
+	return fmt.Sprintf("unassigned (%d)", os)
+}
+
+func gzipExtraFlags(xfl byte) string {
+	switch xfl {
+	case 0:
+		return "none"
+	case 2:
+		return "maximum compression"
+	case 4:
+		return "fastest compression"
+	default:
+		return fmt.Sprintf("0x%02x", xfl)
+	}
+}
+
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// limitWriter passes through at most remaining bytes, then fails.
+type limitWriter struct {
+	w         io.Writer
+	remaining int64
+	n         int64
+}
+
+func (l *limitWriter) Write(p []byte) (int, error) {
+	if int64(len(p)) > l.remaining {
+		n, _ := l.w.Write(p[:l.remaining])
+		l.n += int64(n)
+		l.remaining = 0
+		return n, ErrOutputTooLarge
+	}
+	n, err := l.w.Write(p)
+	l.n += int64(n)
+	l.remaining -= int64(n)
+	return n, err
+}
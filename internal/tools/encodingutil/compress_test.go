@@ -0,0 +1,181 @@
+package encodingutil
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCompressRoundTrip(t *testing.T) {
+	input := strings.Repeat("the quick brown fox jumps over the lazy dog\n", 200)
+
+	for _, c := range Codecs {
+		if !c.Compress {
+			continue
+		}
+		for _, level := range []int{MinLevel, 1, DefaultLevel, MaxLevel} {
+			if !c.Leveled && level != DefaultLevel {
+				continue
+			}
+			var compressed bytes.Buffer
+			stats, err := Compress(&compressed, strings.NewReader(input), CompressOptions{Codec: c.Codec, Level: level})
+			if err != nil {
+				t.Fatalf("%s level %d: Compress: %v", c.Codec, level, err)
+			}
+			if stats.Uncompressed != int64(len(input)) || stats.Compressed != int64(compressed.Len()) {
+				t.Errorf("%s level %d: stats %+v, want %d -> %d", c.Codec, level, stats, len(input), compressed.Len())
+			}
+			if stats.Ratio() < 1.5 || stats.Savings() < 0.3 {
+				t.Errorf("%s level %d: ratio %.2f, savings %.2f on repetitive text", c.Codec, level, stats.Ratio(), stats.Savings())
+			}
+
+			var out bytes.Buffer
+			dstats, err := Decompress(&out, &compressed, c.Codec, 1<<20)
+			if err != nil {
+				t.Fatalf("%s level %d: Decompress: %v", c.Codec, level, err)
+			}
+			if out.String() != input {
+				t.Errorf("%s level %d: round trip changed the data", c.Codec, level)
+			}
+			if dstats.Compressed != stats.Compressed || dstats.Uncompressed != stats.Uncompressed {
+				t.Errorf("%s level %d: decompress stats %+v, compress stats %+v", c.Codec, level, dstats, stats)
+			}
+		}
+	}
+}
+
+func TestCompressErrors(t *testing.T) {
+	tests := []struct {
+		opts    CompressOptions
+		wantErr string
+	}{
+		{CompressOptions{Codec: CodecGzip, Level: 10}, "level must be between"},
+		{CompressOptions{Codec: CodecDeflate, Level: -3}, "level must be between"},
+		{CompressOptions{Codec: CodecBzip2}, "can only be decompressed"},
+		{CompressOptions{Codec: CodecAuto}, "can only be decompressed"},
+		{CompressOptions{Codec: "brotli"}, "unsupported codec"},
+	}
+	for _, tt := range tests {
+		_, err := Compress(&bytes.Buffer{}, strings.NewReader("x"), tt.opts)
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("Compress(%+v) error = %v, want %q", tt.opts, err, tt.wantErr)
+		}
+	}
+}
+
+func TestDecompressGzipHeader(t *testing.T) {
+	mtime := time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC)
+	var buf bytes.Buffer
+	if _, err := Compress(&buf, strings.NewReader("first "), CompressOptions{
+		Codec: CodecGzip, Level: MaxLevel, Name: "report.csv", ModTime: mtime,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	// A second member, as produced by concatenating gzip files.
+	zw := gzip.NewWriter(&buf)
+	zw.Write([]byte("second"))
+	zw.Close()
+
+	var out bytes.Buffer
+	stats, err := Decompress(&out, &buf, CodecAuto, 1<<20)
+	if err != nil {
+		t.Fatalf("Decompress: %v", err)
+	}
+	if out.String() != "first second" {
+		t.Errorf("output = %q", out.String())
+	}
+	if stats.Codec != CodecGzip {
+		t.Errorf("codec = %q, want gzip", stats.Codec)
+	}
+
+	g := stats.Gzip
+	if g == nil {
+		t.Fatal("no gzip header info")
+	}
+	if g.Name != "report.csv" || !g.ModTime.Equal(mtime) || g.Members != 2 {
+		t.Errorf("header = %+v", g)
+	}
+	if g.OS != 255 || g.OSName != "unknown" {
+		t.Errorf("OS = %d %q, want 255 unknown", g.OS, g.OSName)
+	}
+	if g.ExtraFlags != "maximum compression" {
+		t.Errorf("ExtraFlags = %q", g.ExtraFlags)
+	}
+}
+
+func TestDecompressBzip2(t *testing.T) {
+	// "hello bzip2\n" compressed with bzip2 -9.
+	data, _ := base64.StdEncoding.DecodeString("QlpoOTFBWSZTWatrofEAAALZgAAQQAAQABJkwBAgADEA000EAB6j705RogeLuSKcKEhVtdD4gA==")
+
+	var out bytes.Buffer
+	stats, err := Decompress(&out, bytes.NewReader(data), CodecAuto, 1<<20)
+	if err != nil {
+		t.Fatalf("Decompress: %v", err)
+	}
+	if out.String() != "hello bzip2\n" || stats.Codec != CodecBzip2 {
+		t.Errorf("output = %q, codec %q", out.String(), stats.Codec)
+	}
+}
+
+func TestDecompressLimit(t *testing.T) {
+	var buf bytes.Buffer
+	Compress(&buf, bytes.NewReader(make([]byte, 1<<20)), CompressOptions{Codec: CodecZlib, Level: DefaultLevel})
+
+	var out bytes.Buffer
+	stats, err := Decompress(&out, &buf, CodecZlib, 1000)
+	if !errors.Is(err, ErrOutputTooLarge) {
+		t.Fatalf("error = %v, want ErrOutputTooLarge", err)
+	}
+	if out.Len() != 1000 || stats.Uncompressed != 1000 {
+		t.Errorf("wrote %d bytes, stats %d; want 1000", out.Len(), stats.Uncompressed)
+	}
+}
+
+func TestDecompressErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		codec   Codec
+		data    []byte
+		wantErr string
+	}{
+		{"undetectable", CodecAuto, []byte("plain text"), "could not detect"},
+		{"bad gzip", CodecGzip, []byte("plain text"), "invalid gzip data"},
+		{"truncated gzip", CodecGzip, []byte{0x1f, 0x8b, 8, 0, 0, 0, 0, 0, 0, 3, 1}, "invalid gzip data"},
+		{"bad zlib", CodecZlib, []byte{0x78, 0x9c, 0xff}, "invalid zlib data"},
+		{"bad bzip2", CodecBzip2, []byte("BZh9junk"), "invalid bzip2 data"},
+		{"unknown codec", "brotli", []byte("x"), "unsupported codec"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Decompress(&bytes.Buffer{}, bytes.NewReader(tt.data), tt.codec, 1<<20)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDetectCodec(t *testing.T) {
+	tests := []struct {
+		head []byte
+		want Codec
+	}{
+		{[]byte{0x1f, 0x8b, 0x08}, CodecGzip},
+		{[]byte("BZh9"), CodecBzip2},
+		{[]byte{0x78, 0x9c}, CodecZlib},
+		{[]byte{0x78, 0x01}, CodecZlib},
+		{[]byte{0x78, 0xda}, CodecZlib},
+		{[]byte{0x78, 0x9d}, ""},
+		{[]byte("{}"), ""},
+		{nil, ""},
+	}
+	for _, tt := range tests {
+		if got := DetectCodec(tt.head); got != tt.want {
+			t.Errorf("DetectCodec(% x) = %q, want %q", tt.head, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,461 @@
+package fakedata
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"net/netip"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Field is one column of a record template.
+type Field struct {
+	Name string
+	Type string
+	Args []string
+	gen  generator
+}
+
+// generator produces one value for a record: a string, int64, float64 or
+// bool.
+type generator func(rec *record) any
+
+// record is the state shared by the fields of one record, so that name,
+// email and username describe the same person and city, state and address
+// the same place.
+type record struct {
+	rng    *rand.Rand
+	index  int
+	person *person
+	place  *place
+}
+
+type person struct {
+	first, last string
+}
+
+type place struct {
+	city, state, zip string
+}
+
+func (rec *record) who() *person {
+	if rec.person == nil {
+		rec.person = &person{
+			first: pick(rec.rng, firstNames),
+			last:  pick(rec.rng, lastNames),
+		}
+	}
+	return rec.person
+}
+
+func (rec *record) where() *place {
+	if rec.place == nil {
+		c := pick(rec.rng, cities)
+		rec.place = &place{
+			city:  c.name,
+			state: c.state,
+			zip:   fmt.Sprintf("%05d", 10000+rec.rng.Intn(89999)),
+		}
+	}
+	return rec.place
+}
+
+// FieldType describes a template type for the UI.
+type FieldType struct {
+	Name    string
+	Args    string
+	Example string
+}
+
+// FieldTypes lists the supported template types in display order.
+var FieldTypes = []FieldType{
+	{"seq", "", "1"},
+	{"uuid", "", "3f0c5d2e-8b1a-4c7e-9d2f-6a4b8c0e1f23"},
+	{"firstName", "", "Ada"},
+	{"lastName", "", "Lovelace"},
+	{"name", "", "Ada Lovelace"},
+	{"username", "", "ada.lovelace42"},
+	{"email", "", "ada.lovelace@example.com"},
+	{"phone", "", "+1-415-555-0142"},
+	{"company", "", "Lovelace Group"},
+	{"street", "", "42 Maple Avenue"},
+	{"city", "", "Portland"},
+	{"state", "", "OR"},
+	{"zip", "", "97205"},
+	{"country", "", "Canada"},
+	{"address", "", "42 Maple Avenue, Portland, OR 97205"},
+	{"url", "", "https://lovelace.example.com"},
+	{"ipv4", "", "203.0.113.7"},
+	{"ipv6", "", "2001:db8::1"},
+	{"timestamp", "from, to", "2023-04-05T06:07:08Z"},
+	{"date", "from, to", "2023-04-05"},
+	{"unix", "from, to", "1680674828"},
+	{"int", "min, max", "17"},
+	{"float", "min, max, decimals", "0.42"},
+	{"bool", "", "true"},
+	{"oneof", "a, b, ...", "b"},
+	{"word", "", "lorem"},
+	{"words", "n", "lorem ipsum dolor"},
+	{"sentence", "", "Lorem ipsum dolor sit amet."},
+	{"paragraph", "", "Lorem ipsum dolor sit amet. ..."},
+}
+
+// Default time range for timestamp, date and unix fields.
+var (
+	defaultFrom = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	defaultTo   = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+)
+
+// ParseTemplate parses a record template with one field per line, written as
+// "name: type" or "name: type(arg, arg)". Blank lines and lines starting with
+// "#" are ignored. Type names are case-insensitive.
+func ParseTemplate(s string) ([]Field, error) {
+	var fields []Field
+	seen := map[string]bool{}
+
+	for i, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		name, spec, ok := strings.Cut(line, ":")
+		name, spec = strings.TrimSpace(name), strings.TrimSpace(spec)
+		if !ok || name == "" || spec == "" {
+			return nil, fmt.Errorf("line %d: expected \"name: type\", got %q", i+1, line)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("line %d: duplicate field %q", i+1, name)
+		}
+		seen[name] = true
+
+		f, err := parseField(name, spec)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		fields = append(fields, f)
+	}
+
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("template has no fields")
+	}
+	return fields, nil
+}
+
+func parseField(name, spec string) (Field, error) {
+	f := Field{Name: name, Type: spec}
+
+	if open := strings.IndexByte(spec, '('); open >= 0 {
+		if !strings.HasSuffix(spec, ")") {
+			return f, fmt.Errorf("missing \")\" in %q", spec)
+		}
+		f.Type = strings.TrimSpace(spec[:open])
+		for _, arg := range strings.Split(spec[open+1:len(spec)-1], ",") {
+			if arg = strings.TrimSpace(arg); arg != "" {
+				f.Args = append(f.Args, arg)
+			}
+		}
+	}
+
+	gen, err := newGenerator(strings.ToLower(f.Type), f.Args)
+	if err != nil {
+		return f, err
+	}
+	f.gen = gen
+
+	return f, nil
+}
+
+func newGenerator(typ string, args []string) (generator, error) {
+	if limit := maxArgs(typ); len(args) > limit {
+		return nil, fmt.Errorf("%s takes at most %d arguments, got %d", typ, limit, len(args))
+	}
+
+	switch typ {
+	case "seq":
+		return func(rec *record) any { return int64(rec.index + 1) }, nil
+	case "uuid":
+		return func(rec *record) any { return fakeUUID(rec.rng) }, nil
+	case "firstname":
+		return func(rec *record) any { return rec.who().first }, nil
+	case "lastname":
+		return func(rec *record) any { return rec.who().last }, nil
+	case "name":
+		return func(rec *record) any { p := rec.who(); return p.first + " " + p.last }, nil
+	case "username":
+		return func(rec *record) any {
+			p := rec.who()
+			return fmt.Sprintf("%s.%s%d", strings.ToLower(p.first), strings.ToLower(p.last), rec.rng.Intn(100))
+		}, nil
+	case "email":
+		return func(rec *record) any {
+			p := rec.who()
+			return fmt.Sprintf("%s.%s@%s", strings.ToLower(p.first), strings.ToLower(p.last), pick(rec.rng, emailDomains))
+		}, nil
+	case "phone":
+		// 555-01XX numbers are reserved for fiction.
+		return func(rec *record) any {
+			return fmt.Sprintf("+1-%d-555-01%02d", 200+rec.rng.Intn(800), rec.rng.Intn(100))
+		}, nil
+	case "company":
+		return func(rec *record) any { return pick(rec.rng, lastNames) + " " + pick(rec.rng, companySuffixes) }, nil
+	case "street":
+		return func(rec *record) any { return fakeStreet(rec.rng) }, nil
+	case "city":
+		return func(rec *record) any { return rec.where().city }, nil
+	case "state":
+		return func(rec *record) any { return rec.where().state }, nil
+	case "zip":
+		return func(rec *record) any { return rec.where().zip }, nil
+	case "country":
+		return func(rec *record) any { return pick(rec.rng, countries) }, nil
+	case "address":
+		return func(rec *record) any {
+			p := rec.where()
+			return fmt.Sprintf("%s, %s, %s %s", fakeStreet(rec.rng), p.city, p.state, p.zip)
+		}, nil
+	case "url":
+		return func(rec *record) any {
+			return fmt.Sprintf("https://%s.%s/%s", strings.ToLower(pick(rec.rng, lastNames)), pick(rec.rng, emailDomains), pick(rec.rng, loremWords))
+		}, nil
+	case "ipv4":
+		return func(rec *record) any {
+			return fmt.Sprintf("%d.%d.%d.%d", 1+rec.rng.Intn(223), rec.rng.Intn(256), rec.rng.Intn(256), 1+rec.rng.Intn(254))
+		}, nil
+	case "ipv6":
+		return func(rec *record) any {
+			var b [16]byte
+			rec.rng.Read(b[:])
+			return netip.AddrFrom16(b).String()
+		}, nil
+	case "timestamp", "date", "unix":
+		return timeGenerator(typ, args)
+	case "int":
+		return intGenerator(args)
+	case "float":
+		return floatGenerator(args)
+	case "bool":
+		return func(rec *record) any { return rec.rng.Intn(2) == 1 }, nil
+	case "oneof":
+		if len(args) == 0 {
+			return nil, fmt.Errorf("oneof needs at least one choice")
+		}
+		return func(rec *record) any { return pick(rec.rng, args) }, nil
+	case "word":
+		return func(rec *record) any { return pick(rec.rng, loremWords) }, nil
+	case "words":
+		n := 3
+		if len(args) == 1 {
+			v, err := strconv.Atoi(args[0])
+			if err != nil || v < 1 || v > 100 {
+				return nil, fmt.Errorf("words count must be between 1 and 100")
+			}
+			n = v
+		}
+		return func(rec *record) any {
+			g := &loremGen{rng: rec.rng}
+			words := make([]string, n)
+			for i := range words {
+				words[i] = g.word()
+			}
+			return strings.Join(words, " ")
+		}, nil
+	case "sentence":
+		return func(rec *record) any { return (&loremGen{rng: rec.rng}).sentence() }, nil
+	case "paragraph":
+		return func(rec *record) any { return (&loremGen{rng: rec.rng}).paragraph() }, nil
+	default:
+		return nil, fmt.Errorf("unknown type %q", typ)
+	}
+}
+
+// maxArgs returns how many arguments typ accepts.
+func maxArgs(typ string) int {
+	switch typ {
+	case "oneof":
+		return math.MaxInt
+	case "float":
+		return 3
+	case "int", "timestamp", "date", "unix":
+		return 2
+	case "words":
+		return 1
+	default:
+		return 0
+	}
+}
+
+func intGenerator(args []string) (generator, error) {
+	lo, hi := int64(0), int64(1000)
+	var err error
+	if len(args) > 0 {
+		if lo, err = strconv.ParseInt(args[0], 10, 64); err != nil {
+			return nil, fmt.Errorf("invalid int min %q", args[0])
+		}
+		hi = lo + 1000
+	}
+	if len(args) > 1 {
+		if hi, err = strconv.ParseInt(args[1], 10, 64); err != nil {
+			return nil, fmt.Errorf("invalid int max %q", args[1])
+		}
+	}
+	if hi < lo {
+		return nil, fmt.Errorf("int max %d is less than min %d", hi, lo)
+	}
+	span := uint64(hi - lo)
+
+	return func(rec *record) any {
+		if span == math.MaxUint64 {
+			return int64(rec.rng.Uint64())
+		}
+		return lo + int64(randUint64n(rec.rng, span+1))
+	}, nil
+}
+
+func floatGenerator(args []string) (generator, error) {
+	lo, hi, decimals := 0.0, 1.0, 2
+	var err error
+	if len(args) > 0 {
+		if lo, err = strconv.ParseFloat(args[0], 64); err != nil {
+			return nil, fmt.Errorf("invalid float min %q", args[0])
+		}
+		hi = lo + 1
+	}
+	if len(args) > 1 {
+		if hi, err = strconv.ParseFloat(args[1], 64); err != nil {
+			return nil, fmt.Errorf("invalid float max %q", args[1])
+		}
+	}
+	if len(args) > 2 {
+		if decimals, err = strconv.Atoi(args[2]); err != nil || decimals < 0 || decimals > 10 {
+			return nil, fmt.Errorf("float decimals must be between 0 and 10")
+		}
+	}
+	if hi < lo {
+		return nil, fmt.Errorf("float max %g is less than min %g", hi, lo)
+	}
+	scale := math.Pow10(decimals)
+
+	return func(rec *record) any {
+		v := lo + rec.rng.Float64()*(hi-lo)
+		return math.Round(v*scale) / scale
+	}, nil
+}
+
+func timeGenerator(typ string, args []string) (generator, error) {
+	from, to := defaultFrom, defaultTo
+	var err error
+	if len(args) > 0 {
+		if from, err = parseTime(args[0]); err != nil {
+			return nil, err
+		}
+		to = from.AddDate(1, 0, 0)
+	}
+	if len(args) > 1 {
+		if to, err = parseTime(args[1]); err != nil {
+			return nil, err
+		}
+	}
+	if !to.After(from) {
+		return nil, fmt.Errorf("%s range end must be after its start", typ)
+	}
+	span := to.Unix() - from.Unix()
+
+	return func(rec *record) any {
+		// Add seconds to the Unix time: a Duration overflows past 292 years.
+		t := time.Unix(from.Unix()+rec.rng.Int63n(span), 0).UTC()
+		switch typ {
+		case "date":
+			return t.Format(time.DateOnly)
+		case "unix":
+			return t.Unix()
+		default:
+			return t.Format(time.RFC3339)
+		}
+	}, nil
+}
+
+// parseTime accepts a date or an RFC 3339 timestamp.
+func parseTime(s string) (time.Time, error) {
+	if t, err := time.Parse(time.DateOnly, s); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q, expected YYYY-MM-DD or RFC 3339", s)
+	}
+	return t.UTC(), nil
+}
+
+// randUint64n returns a uniform value in [0, n) for n > 0.
+func randUint64n(rng *rand.Rand, n uint64) uint64 {
+	if n <= math.MaxInt64 {
+		return uint64(rng.Int63n(int64(n)))
+	}
+	for {
+		if v := rng.Uint64(); v < n {
+			return v
+		}
+	}
+}
+
+func fakeUUID(rng *rand.Rand) string {
+	var b [16]byte
+	rng.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40 // version 4
+	b[8] = b[8]&0x3f | 0x80 // RFC 4122 variant
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+func fakeStreet(rng *rand.Rand) string {
+	return fmt.Sprintf("%d %s %s", 1+rng.Intn(9999), pick(rng, streetNames), pick(rng, streetSuffixes))
+}
+
+func pick[T any](rng *rand.Rand, items []T) T {
+	return items[rng.Intn(len(items))]
+}
+
+var firstNames = []string{
+	"Ada", "Alan", "Amara", "Ana", "Ben", "Carlos", "Chen", "Chloe", "Daniel",
+	"Elena", "Emma", "Farah", "Grace", "Hana", "Ivan", "James", "Jin", "Kofi",
+	"Laura", "Leila", "Liam", "Lucas", "Maya", "Mei", "Noah", "Olivia", "Omar",
+	"Priya", "Rafael", "Sara", "Sofia", "Tariq", "Tom", "Uma", "Victor", "Yuki",
+	"Zara", "Zoe",
+}
+
+var lastNames = []string{
+	"Adams", "Ahmed", "Baker", "Brown", "Chen", "Clark", "Costa", "Davis",
+	"Diaz", "Evans", "Garcia", "Green", "Hall", "Hughes", "Ito", "Johnson",
+	"Khan", "Kim", "Lee", "Lopez", "Martin", "Miller", "Moore", "Nguyen",
+	"Okafor", "Patel", "Reyes", "Rossi", "Singh", "Smith", "Taylor", "Walker",
+	"Wang", "Wilson", "Young",
+}
+
+// emailDomains are reserved for documentation, so generated addresses can
+// never reach a real mailbox.
+var emailDomains = []string{"example.com", "example.org", "example.net"}
+
+var companySuffixes = []string{"Group", "Inc", "LLC", "Labs", "Partners", "Systems", "& Co"}
+
+var streetNames = []string{
+	"Maple", "Oak", "Pine", "Cedar", "Elm", "Willow", "Lake", "Hill", "Park",
+	"Church", "Mill", "River", "Spring", "Sunset", "Washington", "Lincoln",
+}
+
+var streetSuffixes = []string{"Street", "Avenue", "Road", "Lane", "Drive", "Court", "Way", "Boulevard"}
+
+var cities = []struct{ name, state string }{
+	{"Portland", "OR"}, {"Seattle", "WA"}, {"Austin", "TX"}, {"Denver", "CO"},
+	{"Boston", "MA"}, {"Chicago", "IL"}, {"Phoenix", "AZ"}, {"Atlanta", "GA"},
+	{"Madison", "WI"}, {"Raleigh", "NC"}, {"Columbus", "OH"}, {"Nashville", "TN"},
+	{"San Diego", "CA"}, {"Miami", "FL"}, {"Minneapolis", "MN"}, {"Salt Lake City", "UT"},
+}
+
+var countries = []string{
+	"Argentina", "Australia", "Brazil", "Canada", "Egypt", "France", "Germany",
+	"India", "Ireland", "Japan", "Kenya", "Mexico", "Netherlands", "New Zealand",
+	"Nigeria", "Norway", "Portugal", "South Korea", "Spain", "Sweden",
+	"United Kingdom", "United States", "Vietnam",
+}
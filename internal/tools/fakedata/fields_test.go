@@ -0,0 +1,155 @@
+package fakedata
+
+import (
+	"math/rand"
+	"net/netip"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseTemplate(t *testing.T) {
+	fields, err := ParseTemplate(`
+		# a comment
+		id: seq
+		email: email
+		age: int(18, 99)
+
+		role: oneof(admin, user, guest)
+	`)
+	if err != nil {
+		t.Fatalf("ParseTemplate() unexpected error: %v", err)
+	}
+
+	var names []string
+	for _, f := range fields {
+		names = append(names, f.Name+"="+f.Type)
+	}
+	if got := strings.Join(names, " "); got != "id=seq email=email age=int role=oneof" {
+		t.Errorf("fields = %s", got)
+	}
+	if got := strings.Join(fields[3].Args, ","); got != "admin,user,guest" {
+		t.Errorf("oneof args = %s", got)
+	}
+}
+
+func TestParseTemplateErrors(t *testing.T) {
+	tests := []struct {
+		template string
+		errMsg   string
+	}{
+		{"", "no fields"},
+		{"id", "line 1: expected"},
+		{"id: seq\nid: uuid", "line 2: duplicate field"},
+		{"x: nope", `unknown type "nope"`},
+		{"x: int(1, 2", `missing ")"`},
+		{"x: int(a)", "invalid int min"},
+		{"x: int(5, 1)", "less than min"},
+		{"x: float(0, 1, 20)", "decimals"},
+		{"x: date(2024-13-01)", "invalid time"},
+		{"x: date(2024-01-01, 2023-01-01)", "must be after"},
+		{"x: oneof()", "at least one choice"},
+		{"x: uuid(1)", "at most 0 arguments"},
+		{"x: words(0)", "between 1 and 100"},
+	}
+
+	for _, tt := range tests {
+		_, err := ParseTemplate(tt.template)
+		if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+			t.Errorf("ParseTemplate(%q) error = %v, want it to contain %q", tt.template, err, tt.errMsg)
+		}
+	}
+}
+
+func TestFieldValues(t *testing.T) {
+	tests := []struct {
+		spec  string
+		check func(v any) bool
+	}{
+		{"uuid", matches(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)},
+		{"email", matches(`^[a-z]+\.[a-z]+@example\.(com|org|net)$`)},
+		{"username", matches(`^[a-z]+\.[a-z]+\d{1,2}$`)},
+		{"phone", matches(`^\+1-\d{3}-555-01\d{2}$`)},
+		{"zip", matches(`^\d{5}$`)},
+		{"address", matches(`^\d+ [A-Za-z]+ [A-Za-z]+, [A-Za-z ]+, [A-Z]{2} \d{5}$`)},
+		{"timestamp(2024-01-01, 2024-02-01)", func(v any) bool {
+			ts, err := time.Parse(time.RFC3339, v.(string))
+			return err == nil && ts.Month() == time.January && ts.Year() == 2024
+		}},
+		{"date", matches(`^20(2[0-5])-\d{2}-\d{2}$`)},
+		{"unix(2024-01-01)", func(v any) bool {
+			n := v.(int64)
+			return n >= 1704067200 && n < 1735689600
+		}},
+		// Ranges longer than a time.Duration can hold.
+		{"date(1000-01-01, 9999-12-31)", func(v any) bool {
+			d := v.(string)
+			return len(d) == 10 && d >= "1000-01-01" && d < "9999-12-31"
+		}},
+		{"unix(1000-01-01, 9999-12-31)", func(v any) bool {
+			n := v.(int64)
+			return n >= -30610224000 && n < 253402214400
+		}},
+		{"int(-5, 5)", func(v any) bool { n := v.(int64); return n >= -5 && n <= 5 }},
+		{"float(1, 2, 1)", func(v any) bool {
+			f := v.(float64)
+			return f >= 1 && f <= 2 && f*10 == float64(int(f*10+0.5))
+		}},
+		{"bool", func(v any) bool { _, ok := v.(bool); return ok }},
+		{"oneof(a, b)", func(v any) bool { return v == "a" || v == "b" }},
+		{"ipv4", func(v any) bool { a, err := netip.ParseAddr(v.(string)); return err == nil && a.Is4() }},
+		{"ipv6", func(v any) bool { a, err := netip.ParseAddr(v.(string)); return err == nil && a.Is6() }},
+		{"words(4)", func(v any) bool { return len(strings.Fields(v.(string))) == 4 }},
+		{"sentence", matches(`^[A-Z][a-z ,]+\.$`)},
+	}
+
+	rng := rand.New(rand.NewSource(7))
+	for _, tt := range tests {
+		f, err := parseField("f", tt.spec)
+		if err != nil {
+			t.Fatalf("parseField(%q) unexpected error: %v", tt.spec, err)
+		}
+		for i := 0; i < 50; i++ {
+			if v := f.gen(&record{rng: rng, index: i}); !tt.check(v) {
+				t.Errorf("%s produced unexpected value %#v", tt.spec, v)
+				break
+			}
+		}
+	}
+}
+
+func TestRecordConsistency(t *testing.T) {
+	fields, err := ParseTemplate("first: firstName\nlast: lastName\nname: name\nemail: email\ncity: city\naddress: address")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 20; i++ {
+		rec := &record{rng: rng, index: i}
+		values := make([]string, len(fields))
+		for j, f := range fields {
+			values[j] = f.gen(rec).(string)
+		}
+		first, last, name, email, city, address := values[0], values[1], values[2], values[3], values[4], values[5]
+
+		if name != first+" "+last {
+			t.Errorf("name %q does not match %q %q", name, first, last)
+		}
+		if !strings.HasPrefix(email, strings.ToLower(first+"."+last)+"@") {
+			t.Errorf("email %q does not match %q %q", email, first, last)
+		}
+		if !strings.Contains(address, ", "+city+", ") {
+			t.Errorf("address %q does not contain city %q", address, city)
+		}
+	}
+}
+
+func matches(pattern string) func(any) bool {
+	re := regexp.MustCompile(pattern)
+	return func(v any) bool {
+		s, ok := v.(string)
+		return ok && re.MatchString(s)
+	}
+}
@@ -0,0 +1,239 @@
+package fakedata
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"strconv"
+
+	"github.com/NickDiPreta1/toolhub/internal/workerpool"
+)
+
+// Format selects the output encoding of Generate.
+type Format string
+
+const (
+	FormatJSON   Format = "json"
+	FormatNDJSON Format = "ndjson"
+	FormatCSV    Format = "csv"
+)
+
+// MaxRecords caps the number of records Generate produces.
+const MaxRecords = 100000
+
+// batchSize is the number of records generated per worker job. Each batch has
+// its own random source derived from the seed and the batch number, so the
+// output does not depend on how many workers run or in which order they
+// finish. Changing batchSize changes the data produced for a given seed.
+const batchSize = 500
+
+// Options configures Generate.
+type Options struct {
+	Count  int
+	Seed   int64
+	Format Format
+	// Workers is the number of goroutines generating batches.
+	Workers int
+}
+
+// Generate writes opts.Count records built from fields to w. Records are
+// generated in batches through a workerpool.Pool and written in order.
+func Generate(ctx context.Context, w io.Writer, fields []Field, opts Options) error {
+	if opts.Count < 1 || opts.Count > MaxRecords {
+		return fmt.Errorf("count must be between 1 and %d", MaxRecords)
+	}
+	if len(fields) == 0 {
+		return fmt.Errorf("no fields to generate")
+	}
+	switch opts.Format {
+	case FormatJSON, FormatNDJSON, FormatCSV:
+	default:
+		return fmt.Errorf("unsupported format %q", opts.Format)
+	}
+	if opts.Workers <= 0 {
+		opts.Workers = 4
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	batches := (opts.Count + batchSize - 1) / batchSize
+
+	// inFlight bounds how many batches exist between generating and
+	// writing, which bounds memory and keeps Submit from blocking.
+	maxInFlight := opts.Workers * 2
+	inFlight := make(chan struct{}, maxInFlight)
+
+	pool := workerpool.NewPool(opts.Workers, maxInFlight)
+	results := pool.Start(ctx)
+
+	go func() {
+		defer pool.Shutdown()
+		for b := 0; b < batches; b++ {
+			select {
+			case inFlight <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+
+			start := b * batchSize
+			n := min(batchSize, opts.Count-start)
+			pool.Submit(workerpool.Job{
+				ID: b,
+				Func: func([]byte) ([]byte, error) {
+					return encodeBatch(fields, opts, b, start, n)
+				},
+			})
+		}
+	}()
+
+	out := bufio.NewWriter(w)
+	var writeErr error
+
+	switch opts.Format {
+	case FormatJSON:
+		_, writeErr = out.WriteString("[")
+	case FormatCSV:
+		header := make([]string, len(fields))
+		for i, f := range fields {
+			header[i] = f.Name
+		}
+		cw := csv.NewWriter(out)
+		cw.Write(header)
+		cw.Flush()
+		writeErr = cw.Error()
+	}
+
+	pending := map[int]workerpool.Result{}
+	next := 0
+	for res := range results {
+		pending[res.JobID] = res
+		for {
+			done, ok := pending[next]
+			if !ok {
+				break
+			}
+			delete(pending, next)
+
+			if writeErr == nil && done.Error != nil {
+				writeErr = done.Error
+			}
+			if writeErr == nil {
+				content := done.Content
+				// JSON records are prefixed with a comma; the first one in
+				// the array must not be.
+				if opts.Format == FormatJSON && next == 0 {
+					content = content[1:]
+				}
+				_, writeErr = out.Write(content)
+			}
+			if writeErr != nil {
+				cancel()
+			}
+			next++
+			<-inFlight
+		}
+	}
+
+	if writeErr != nil {
+		return writeErr
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if next != batches {
+		return fmt.Errorf("generated %d of %d batches", next, batches)
+	}
+
+	if opts.Format == FormatJSON {
+		out.WriteString("\n]\n")
+	}
+	return out.Flush()
+}
+
+// encodeBatch generates and encodes records start to start+n-1.
+func encodeBatch(fields []Field, opts Options, batch, start, n int) ([]byte, error) {
+	rng := rand.New(rand.NewSource(batchSeed(opts.Seed, batch)))
+
+	var buf bytes.Buffer
+	var cw *csv.Writer
+	var row []string
+	if opts.Format == FormatCSV {
+		cw = csv.NewWriter(&buf)
+		row = make([]string, len(fields))
+	}
+
+	for i := 0; i < n; i++ {
+		rec := &record{rng: rng, index: start + i}
+
+		if opts.Format == FormatCSV {
+			for j, f := range fields {
+				row[j] = formatValue(f.gen(rec))
+			}
+			if err := cw.Write(row); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if opts.Format == FormatJSON {
+			buf.WriteString(",\n  ")
+		}
+		buf.WriteByte('{')
+		for j, f := range fields {
+			if j > 0 {
+				buf.WriteByte(',')
+			}
+			key, _ := json.Marshal(f.Name)
+			value, err := json.Marshal(f.gen(rec))
+			if err != nil {
+				return nil, fmt.Errorf("field %q: %w", f.Name, err)
+			}
+			buf.Write(key)
+			buf.WriteByte(':')
+			buf.Write(value)
+		}
+		buf.WriteByte('}')
+		if opts.Format == FormatNDJSON {
+			buf.WriteByte('\n')
+		}
+	}
+
+	if cw != nil {
+		cw.Flush()
+		if err := cw.Error(); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// batchSeed mixes the seed and batch number (splitmix64) so neighbouring
+// batches get unrelated random sequences.
+func batchSeed(seed int64, batch int) int64 {
+	z := uint64(seed) + uint64(batch+1)*0x9e3779b97f4a7c15
+	z = (z ^ z>>30) * 0xbf58476d1ce4e5b9
+	z = (z ^ z>>27) * 0x94d049bb133111eb
+	return int64(z ^ z>>31)
+}
+
+// formatValue renders a generated value for CSV.
+func formatValue(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
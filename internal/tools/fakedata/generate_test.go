@@ -0,0 +1,121 @@
+package fakedata
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const testTemplate = "id: seq\nname: name\nscore: float(0, 100)\nactive: bool"
+
+func generate(t *testing.T, opts Options) string {
+	t.Helper()
+	fields, err := ParseTemplate(testTemplate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := Generate(context.Background(), &buf, fields, opts); err != nil {
+		t.Fatalf("Generate() unexpected error: %v", err)
+	}
+	return buf.String()
+}
+
+func TestGenerateJSON(t *testing.T) {
+	out := generate(t, Options{Count: 1234, Seed: 1, Format: FormatJSON})
+
+	var records []map[string]any
+	if err := json.Unmarshal([]byte(out), &records); err != nil {
+		t.Fatalf("output is not a JSON array: %v", err)
+	}
+	if len(records) != 1234 {
+		t.Fatalf("got %d records, want 1234", len(records))
+	}
+	for i, rec := range records {
+		if rec["id"] != float64(i+1) {
+			t.Fatalf("record %d has id %v", i, rec["id"])
+		}
+	}
+	if !strings.HasPrefix(out, "[\n  {\"id\":1,\"name\":") {
+		t.Errorf("fields are not in template order: %.40s", out)
+	}
+}
+
+func TestGenerateNDJSON(t *testing.T) {
+	out := generate(t, Options{Count: 600, Seed: 1, Format: FormatNDJSON})
+
+	lines := strings.Split(strings.TrimSuffix(out, "\n"), "\n")
+	if len(lines) != 600 {
+		t.Fatalf("got %d lines, want 600", len(lines))
+	}
+	for i, line := range lines {
+		var rec map[string]any
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			t.Fatalf("line %d: %v", i+1, err)
+		}
+	}
+}
+
+func TestGenerateCSV(t *testing.T) {
+	out := generate(t, Options{Count: 10, Seed: 1, Format: FormatCSV})
+
+	rows, err := csv.NewReader(strings.NewReader(out)).ReadAll()
+	if err != nil {
+		t.Fatalf("output is not valid CSV: %v", err)
+	}
+	if len(rows) != 11 {
+		t.Fatalf("got %d rows, want 11", len(rows))
+	}
+	if got := strings.Join(rows[0], ","); got != "id,name,score,active" {
+		t.Errorf("header = %s", got)
+	}
+	if rows[10][0] != "10" {
+		t.Errorf("last id = %s, want 10", rows[10][0])
+	}
+}
+
+func TestGenerateReproducible(t *testing.T) {
+	// The output depends only on the seed, not on the number of workers.
+	a := generate(t, Options{Count: 2500, Seed: 99, Format: FormatNDJSON, Workers: 1})
+	b := generate(t, Options{Count: 2500, Seed: 99, Format: FormatNDJSON, Workers: 8})
+	if a != b {
+		t.Error("same seed produced different output with different worker counts")
+	}
+
+	c := generate(t, Options{Count: 2500, Seed: 100, Format: FormatNDJSON})
+	if a == c {
+		t.Error("different seeds produced the same output")
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	fields, _ := ParseTemplate("id: seq")
+	tests := []struct {
+		fields []Field
+		opts   Options
+	}{
+		{fields, Options{Count: 0, Format: FormatJSON}},
+		{fields, Options{Count: MaxRecords + 1, Format: FormatJSON}},
+		{fields, Options{Count: 1, Format: "xml"}},
+		{nil, Options{Count: 1, Format: FormatJSON}},
+	}
+	for _, tt := range tests {
+		if err := Generate(context.Background(), &bytes.Buffer{}, tt.fields, tt.opts); err == nil {
+			t.Errorf("Generate(%+v) expected error", tt.opts)
+		}
+	}
+}
+
+func TestGenerateCancelled(t *testing.T) {
+	fields, _ := ParseTemplate("id: seq")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := Generate(ctx, &bytes.Buffer{}, fields, Options{Count: MaxRecords, Format: FormatNDJSON})
+	if err == nil {
+		t.Error("Generate() with a cancelled context expected error")
+	}
+}
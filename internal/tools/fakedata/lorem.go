@@ -0,0 +1,142 @@
+// Package fakedata generates placeholder text and fake structured records for
+// test fixtures. All output is derived from a seed, so the same seed always
+// produces the same data.
+package fakedata
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+)
+
+// LoremUnit selects what Lorem counts.
+type LoremUnit string
+
+const (
+	LoremWords      LoremUnit = "words"
+	LoremSentences  LoremUnit = "sentences"
+	LoremParagraphs LoremUnit = "paragraphs"
+)
+
+// MaxLoremCount caps the count accepted by Lorem for any unit.
+const MaxLoremCount = 10000
+
+// loremOpening is the traditional first sentence.
+const loremOpening = "Lorem ipsum dolor sit amet, consectetur adipiscing elit"
+
+var loremWords = strings.Fields(`
+	lorem ipsum dolor sit amet consectetur adipiscing elit sed do eiusmod
+	tempor incididunt ut labore et dolore magna aliqua enim ad minim veniam
+	quis nostrud exercitation ullamco laboris nisi aliquip ex ea commodo
+	consequat duis aute irure in reprehenderit voluptate velit esse cillum
+	eu fugiat nulla pariatur excepteur sint occaecat cupidatat non proident
+	sunt culpa qui officia deserunt mollit anim id est laborum curabitur
+	pretium tincidunt lacus nunc pulvinar sapien ligula vitae mauris integer
+	porta quam tortor viverra orci sagittis ante morbi feugiat felis vel
+	placerat donec facilisis nibh blandit cursus risus ultrices gravida
+	dictum fusce semper accumsan vivamus arcu vestibulum rhoncus mattis
+`)
+
+// LoremOptions configures Lorem.
+type LoremOptions struct {
+	Unit  LoremUnit
+	Count int
+	Seed  int64
+	// StartWithLorem begins the text with "Lorem ipsum dolor sit amet".
+	StartWithLorem bool
+}
+
+// Lorem returns Count words, sentences or paragraphs of lorem ipsum text.
+// Paragraphs are separated by a blank line.
+func Lorem(opts LoremOptions) (string, error) {
+	if opts.Count < 1 || opts.Count > MaxLoremCount {
+		return "", fmt.Errorf("count must be between 1 and %d", MaxLoremCount)
+	}
+
+	g := &loremGen{rng: rand.New(rand.NewSource(opts.Seed))}
+	if opts.StartWithLorem {
+		g.opening = strings.Fields(strings.ReplaceAll(loremOpening, ",", ""))
+		g.openSentence = true
+	}
+
+	switch opts.Unit {
+	case LoremWords:
+		words := make([]string, opts.Count)
+		for i := range words {
+			words[i] = g.word()
+		}
+		return strings.Join(words, " "), nil
+
+	case LoremSentences:
+		sentences := make([]string, opts.Count)
+		for i := range sentences {
+			sentences[i] = g.sentence()
+		}
+		return strings.Join(sentences, " "), nil
+
+	case LoremParagraphs:
+		paragraphs := make([]string, opts.Count)
+		for i := range paragraphs {
+			paragraphs[i] = g.paragraph()
+		}
+		return strings.Join(paragraphs, "\n\n"), nil
+
+	default:
+		return "", fmt.Errorf("unsupported unit %q", opts.Unit)
+	}
+}
+
+// loremGen produces lorem ipsum text from a random source. Words are taken
+// from opening first, if set, and the first sentence is the traditional one
+// when openSentence is set.
+type loremGen struct {
+	rng          *rand.Rand
+	opening      []string
+	openSentence bool
+}
+
+func (g *loremGen) word() string {
+	if len(g.opening) > 0 {
+		w := g.opening[0]
+		g.opening = g.opening[1:]
+		return w
+	}
+	return loremWords[g.rng.Intn(len(loremWords))]
+}
+
+// sentence returns 6 to 14 words, capitalized and ending in a period, with an
+// occasional comma after a word.
+func (g *loremGen) sentence() string {
+	if g.openSentence {
+		g.openSentence, g.opening = false, nil
+		return loremOpening + "."
+	}
+
+	n := 6 + g.rng.Intn(9)
+
+	var b strings.Builder
+	for i := 0; i < n; i++ {
+		w := g.word()
+		if i == 0 {
+			w = strings.ToUpper(w[:1]) + w[1:]
+		} else {
+			b.WriteByte(' ')
+		}
+		b.WriteString(w)
+		if i > 1 && i < n-2 && g.rng.Intn(8) == 0 {
+			b.WriteByte(',')
+		}
+	}
+	b.WriteByte('.')
+
+	return b.String()
+}
+
+func (g *loremGen) paragraph() string {
+	n := 3 + g.rng.Intn(4)
+	sentences := make([]string, n)
+	for i := range sentences {
+		sentences[i] = g.sentence()
+	}
+	return strings.Join(sentences, " ")
+}
@@ -0,0 +1,98 @@
+package fakedata
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLorem(t *testing.T) {
+	tests := []struct {
+		name  string
+		opts  LoremOptions
+		check func(t *testing.T, out string)
+	}{
+		{
+			name: "words",
+			opts: LoremOptions{Unit: LoremWords, Count: 25, Seed: 1},
+			check: func(t *testing.T, out string) {
+				if n := len(strings.Fields(out)); n != 25 {
+					t.Errorf("got %d words, want 25", n)
+				}
+			},
+		},
+		{
+			name: "words start with lorem",
+			opts: LoremOptions{Unit: LoremWords, Count: 3, StartWithLorem: true},
+			check: func(t *testing.T, out string) {
+				if out != "Lorem ipsum dolor" {
+					t.Errorf("got %q, want %q", out, "Lorem ipsum dolor")
+				}
+			},
+		},
+		{
+			name: "sentences",
+			opts: LoremOptions{Unit: LoremSentences, Count: 4, Seed: 2, StartWithLorem: true},
+			check: func(t *testing.T, out string) {
+				if !strings.HasPrefix(out, loremOpening+". ") {
+					t.Errorf("output does not start with the opening sentence: %q", out)
+				}
+				if n := strings.Count(out, "."); n != 4 {
+					t.Errorf("got %d sentences, want 4", n)
+				}
+			},
+		},
+		{
+			name: "paragraphs",
+			opts: LoremOptions{Unit: LoremParagraphs, Count: 3, Seed: 3},
+			check: func(t *testing.T, out string) {
+				paragraphs := strings.Split(out, "\n\n")
+				if len(paragraphs) != 3 {
+					t.Fatalf("got %d paragraphs, want 3", len(paragraphs))
+				}
+				for _, p := range paragraphs {
+					if n := strings.Count(p, "."); n < 3 || n > 6 {
+						t.Errorf("paragraph has %d sentences, want 3 to 6: %q", n, p)
+					}
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := Lorem(tt.opts)
+			if err != nil {
+				t.Fatalf("Lorem() unexpected error: %v", err)
+			}
+			tt.check(t, out)
+		})
+	}
+}
+
+func TestLoremSeed(t *testing.T) {
+	opts := LoremOptions{Unit: LoremParagraphs, Count: 2, Seed: 42}
+	a, _ := Lorem(opts)
+	b, _ := Lorem(opts)
+	if a != b {
+		t.Error("same seed produced different text")
+	}
+
+	opts.Seed = 43
+	c, _ := Lorem(opts)
+	if a == c {
+		t.Error("different seeds produced the same text")
+	}
+}
+
+func TestLoremErrors(t *testing.T) {
+	tests := []LoremOptions{
+		{Unit: LoremWords, Count: 0},
+		{Unit: LoremWords, Count: MaxLoremCount + 1},
+		{Unit: "pages", Count: 1},
+	}
+	for _, opts := range tests {
+		if _, err := Lorem(opts); err == nil {
+			t.Errorf("Lorem(%+v) expected error", opts)
+		}
+	}
+}
@@ -0,0 +1,456 @@
+package fileconvert
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"strings"
+	"time"
+)
+
+// ArchiveFormat names an archive container.
+type ArchiveFormat string
+
+const (
+	ArchiveZip   ArchiveFormat = "zip"
+	ArchiveTarGz ArchiveFormat = "tar.gz"
+	// ArchiveTar can be read but not created.
+	ArchiveTar ArchiveFormat = "tar"
+)
+
+// Extension returns the file extension for f, including the dot.
+func (f ArchiveFormat) Extension() string {
+	return "." + string(f)
+}
+
+// ContentType returns the MIME type of f.
+func (f ArchiveFormat) ContentType() string {
+	switch f {
+	case ArchiveZip:
+		return "application/zip"
+	case ArchiveTarGz:
+		return "application/gzip"
+	default:
+		return "application/x-tar"
+	}
+}
+
+// Limits applied when reading archives, to refuse decompression bombs.
+const (
+	// MaxArchiveEntries is the most entries an archive may hold.
+	MaxArchiveEntries = 10000
+	// MaxExpandedSize is the most bytes decompressed from one archive.
+	MaxExpandedSize = 1 << 30
+	// MaxExpansionRatio is the highest ratio of expanded to compressed size
+	// accepted once more than ratioThreshold bytes have been expanded.
+	MaxExpansionRatio = 100
+	// Small inputs, such as a short run of zeros, can compress by any
+	// ratio, so the ratio only applies beyond this size.
+	ratioThreshold = 1 << 20
+)
+
+// ErrUnsafeEntry reports an entry name that would escape the directory it
+// is extracted into.
+var ErrUnsafeEntry = errors.New("unsafe entry name")
+
+// SafeEntryName cleans an archive entry name and rejects names that are
+// absolute or climb out of the extraction directory with "..", which is
+// how zip-slip attacks work. Backslashes are treated as separators.
+func SafeEntryName(name string) (string, error) {
+	name = strings.ReplaceAll(name, `\`, "/")
+	if strings.HasPrefix(name, "/") || (len(name) >= 2 && name[1] == ':') {
+		return "", fmt.Errorf("%w: %q", ErrUnsafeEntry, name)
+	}
+	for _, part := range strings.Split(name, "/") {
+		if part == ".." {
+			return "", fmt.Errorf("%w: %q", ErrUnsafeEntry, name)
+		}
+	}
+	return path.Clean(name), nil
+}
+
+// ArchiveWriter builds a zip or tar.gz archive entry by entry.
+type ArchiveWriter struct {
+	zw    *zip.Writer
+	gz    *gzip.Writer
+	tw    *tar.Writer
+	names map[string]bool
+}
+
+// NewArchiveWriter starts an archive in format on w.
+func NewArchiveWriter(w io.Writer, format ArchiveFormat) (*ArchiveWriter, error) {
+	a := &ArchiveWriter{names: map[string]bool{}}
+	switch format {
+	case ArchiveZip:
+		a.zw = zip.NewWriter(w)
+	case ArchiveTarGz:
+		a.gz = gzip.NewWriter(w)
+		a.tw = tar.NewWriter(a.gz)
+	default:
+		return nil, fmt.Errorf("unsupported archive format %q", format)
+	}
+	return a, nil
+}
+
+// Add stores size bytes from r as a regular file. The name is cleaned with
+// SafeEntryName and given a numeric suffix if it is already in the archive;
+// the name used is returned.
+func (a *ArchiveWriter) Add(name string, size int64, modified time.Time, r io.Reader) (string, error) {
+	if len(a.names) == MaxArchiveEntries {
+		return "", fmt.Errorf("archives are limited to %d entries", MaxArchiveEntries)
+	}
+	name, err := SafeEntryName(name)
+	if err != nil {
+		return "", err
+	}
+	if name == "." {
+		return "", errors.New("archive entries need a name")
+	}
+	name = uniqueName(a.names, name)
+
+	var dst io.Writer
+	if a.zw != nil {
+		fh := &zip.FileHeader{Name: name, Method: zip.Deflate, Modified: modified}
+		fh.SetMode(0o644)
+		if dst, err = a.zw.CreateHeader(fh); err != nil {
+			return "", err
+		}
+	} else {
+		err = a.tw.WriteHeader(&tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     name,
+			Size:     size,
+			Mode:     0o644,
+			ModTime:  modified,
+		})
+		if err != nil {
+			return "", err
+		}
+		dst = a.tw
+	}
+
+	if _, err := io.CopyN(dst, r, size); err != nil {
+		return "", fmt.Errorf("adding %s: %w", name, err)
+	}
+	return name, nil
+}
+
+// Close finishes the archive. It does not close the underlying writer.
+func (a *ArchiveWriter) Close() error {
+	if a.zw != nil {
+		return a.zw.Close()
+	}
+	if err := a.tw.Close(); err != nil {
+		return err
+	}
+	return a.gz.Close()
+}
+
+// ArchiveEntry describes one entry of an archive.
+type ArchiveEntry struct {
+	Name string
+	Size int64
+	// CompressedSize is the stored size of a zip entry. Tar entries are
+	// compressed as a whole, so theirs is -1.
+	CompressedSize int64
+	Mode           fs.FileMode
+	Modified       time.Time
+	// Link is the target of a symbolic or hard link.
+	Link string
+	// Unsafe explains why the entry cannot be extracted, if it cannot.
+	Unsafe string
+}
+
+// Ratio returns how many times smaller the entry is when compressed, or 0
+// when that is unknown.
+func (e ArchiveEntry) Ratio() float64 {
+	if e.CompressedSize <= 0 || e.Size == 0 {
+		return 0
+	}
+	return float64(e.Size) / float64(e.CompressedSize)
+}
+
+// Regular reports whether the entry is a plain file that can be extracted.
+func (e ArchiveEntry) Regular() bool {
+	return e.Mode.IsRegular() && e.Link == "" && e.Unsafe == ""
+}
+
+// ArchiveListing is the table of contents of an archive.
+type ArchiveListing struct {
+	Format  ArchiveFormat
+	Entries []ArchiveEntry
+	// Size is the size of the archive itself and ExpandedSize the total of
+	// its entries.
+	Size         int64
+	ExpandedSize int64
+}
+
+// Ratio returns how many times smaller the archive is than its contents.
+func (l *ArchiveListing) Ratio() float64 {
+	if l.Size == 0 {
+		return 0
+	}
+	return float64(l.ExpandedSize) / float64(l.Size)
+}
+
+// DetectArchive identifies an archive by its leading bytes.
+func DetectArchive(r io.ReaderAt) (ArchiveFormat, error) {
+	head := make([]byte, 512)
+	n, err := r.ReadAt(head, 0)
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+	head = head[:n]
+
+	switch {
+	case bytes.HasPrefix(head, []byte("PK\x03\x04")), bytes.HasPrefix(head, []byte("PK\x05\x06")):
+		return ArchiveZip, nil
+	case bytes.HasPrefix(head, []byte{0x1f, 0x8b}):
+		return ArchiveTarGz, nil
+	case len(head) >= 262 && string(head[257:262]) == "ustar":
+		return ArchiveTar, nil
+	default:
+		return "", errors.New("not a zip, tar or tar.gz archive")
+	}
+}
+
+// ListArchive reads the table of contents of a zip, tar or tar.gz archive
+// of size bytes. Archives with too many entries, or that expand by more
+// than MaxExpansionRatio, are refused.
+func ListArchive(r io.ReaderAt, size int64) (*ArchiveListing, error) {
+	format, err := DetectArchive(r)
+	if err != nil {
+		return nil, err
+	}
+	listing := &ArchiveListing{Format: format, Size: size}
+
+	if format == ArchiveZip {
+		zr, err := openZip(r, size)
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range zr.File {
+			listing.Entries = append(listing.Entries, zipEntry(f))
+			listing.ExpandedSize += int64(f.UncompressedSize64)
+		}
+		// Entries can share compressed data, so the declared sizes are
+		// checked against the archive as a whole.
+		if err := checkRatio(listing.ExpandedSize, size); err != nil {
+			return nil, err
+		}
+		return listing, nil
+	}
+
+	err = walkTar(r, size, format, func(hdr *tar.Header, _ io.Reader) (bool, error) {
+		e := tarEntry(hdr)
+		listing.Entries = append(listing.Entries, e)
+		listing.ExpandedSize += e.Size
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return listing, nil
+}
+
+// ExtractEntry writes the contents of the named entry to w. Only regular
+// files with safe names can be extracted.
+func ExtractEntry(r io.ReaderAt, size int64, name string, w io.Writer) (ArchiveEntry, error) {
+	format, err := DetectArchive(r)
+	if err != nil {
+		return ArchiveEntry{}, err
+	}
+
+	if format == ArchiveZip {
+		zr, err := openZip(r, size)
+		if err != nil {
+			return ArchiveEntry{}, err
+		}
+		for _, f := range zr.File {
+			if f.Name != name {
+				continue
+			}
+			e := zipEntry(f)
+			if err := CheckEntry(e); err != nil {
+				return e, err
+			}
+			rc, err := f.Open()
+			if err != nil {
+				return e, err
+			}
+			defer rc.Close()
+			// archive/zip fails if an entry expands past its declared size.
+			_, err = io.Copy(w, rc)
+			return e, err
+		}
+		return ArchiveEntry{}, fmt.Errorf("no entry named %q", name)
+	}
+
+	var found *ArchiveEntry
+	err = walkTar(r, size, format, func(hdr *tar.Header, body io.Reader) (bool, error) {
+		if hdr.Name != name {
+			return true, nil
+		}
+		e := tarEntry(hdr)
+		found = &e
+		if err := CheckEntry(e); err != nil {
+			return false, err
+		}
+		_, err := io.Copy(w, body)
+		return false, err
+	})
+	if err != nil {
+		if found != nil {
+			return *found, err
+		}
+		return ArchiveEntry{}, err
+	}
+	if found == nil {
+		return ArchiveEntry{}, fmt.Errorf("no entry named %q", name)
+	}
+	return *found, nil
+}
+
+// CheckEntry reports why e cannot be extracted: it is not a regular file,
+// its name is unsafe or it expands beyond the limits.
+func CheckEntry(e ArchiveEntry) error {
+	switch {
+	case e.Unsafe != "":
+		return fmt.Errorf("%s: %s", e.Name, e.Unsafe)
+	case e.Link != "":
+		return fmt.Errorf("%s is a link to %s", e.Name, e.Link)
+	case !e.Mode.IsRegular():
+		return fmt.Errorf("%s is not a regular file", e.Name)
+	case e.Size > MaxExpandedSize:
+		return fmt.Errorf("%s is larger than %d MB", e.Name, MaxExpandedSize>>20)
+	case e.CompressedSize >= 0:
+		return checkRatio(e.Size, e.CompressedSize)
+	}
+	return nil
+}
+
+func openZip(r io.ReaderAt, size int64) (*zip.Reader, error) {
+	zr, err := zip.NewReader(r, size)
+	// Insecure names are reported per entry rather than failing the
+	// whole archive.
+	if err != nil && !errors.Is(err, zip.ErrInsecurePath) {
+		return nil, fmt.Errorf("reading zip: %w", err)
+	}
+	if len(zr.File) > MaxArchiveEntries {
+		return nil, fmt.Errorf("archive has %d entries; the limit is %d", len(zr.File), MaxArchiveEntries)
+	}
+	return zr, nil
+}
+
+func zipEntry(f *zip.File) ArchiveEntry {
+	e := ArchiveEntry{
+		Name:           f.Name,
+		Size:           int64(f.UncompressedSize64),
+		CompressedSize: int64(f.CompressedSize64),
+		Mode:           f.Mode(),
+		Modified:       f.Modified,
+	}
+	if _, err := SafeEntryName(f.Name); err != nil {
+		e.Unsafe = "name escapes the archive"
+	}
+	if e.Mode&fs.ModeSymlink != 0 {
+		e.Link = "(symbolic link)"
+	}
+	return e
+}
+
+func tarEntry(hdr *tar.Header) ArchiveEntry {
+	e := ArchiveEntry{
+		Name:           hdr.Name,
+		Size:           hdr.Size,
+		CompressedSize: -1,
+		Mode:           hdr.FileInfo().Mode(),
+		Modified:       hdr.ModTime,
+	}
+	if hdr.Typeflag == tar.TypeSymlink || hdr.Typeflag == tar.TypeLink {
+		e.Link = hdr.Linkname
+	}
+	if _, err := SafeEntryName(hdr.Name); err != nil {
+		e.Unsafe = "name escapes the archive"
+	}
+	return e
+}
+
+// walkTar calls fn for each entry of a tar or tar.gz archive until fn
+// returns false. Decompression is guarded by the expansion limits.
+func walkTar(r io.ReaderAt, size int64, format ArchiveFormat, fn func(*tar.Header, io.Reader) (bool, error)) error {
+	src := &countingReader{r: io.NewSectionReader(r, 0, size)}
+	var stream io.Reader = src
+	if format == ArchiveTarGz {
+		gz, err := gzip.NewReader(src)
+		if err != nil {
+			return fmt.Errorf("reading gzip: %w", err)
+		}
+		stream = &expansionGuard{r: gz, compressed: src}
+	}
+
+	tr := tar.NewReader(stream)
+	for count := 0; ; count++ {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("reading tar: %w", err)
+		}
+		if count == MaxArchiveEntries {
+			return fmt.Errorf("archive has more than %d entries", MaxArchiveEntries)
+		}
+		more, err := fn(hdr, tr)
+		if err != nil || !more {
+			return err
+		}
+	}
+}
+
+// checkRatio refuses data that expands by more than MaxExpansionRatio.
+func checkRatio(expanded, compressed int64) error {
+	if expanded > ratioThreshold && expanded > compressed*MaxExpansionRatio {
+		return fmt.Errorf("archive expands %d bytes to %d, more than %d times; it may be a decompression bomb",
+			compressed, expanded, MaxExpansionRatio)
+	}
+	return nil
+}
+
+// countingReader counts the bytes read through it.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// expansionGuard fails a decompressed stream once it grows too large for
+// the compressed bytes consumed so far.
+type expansionGuard struct {
+	r          io.Reader
+	compressed *countingReader
+	expanded   int64
+}
+
+func (g *expansionGuard) Read(p []byte) (int, error) {
+	n, err := g.r.Read(p)
+	g.expanded += int64(n)
+	if g.expanded > MaxExpandedSize {
+		return n, fmt.Errorf("archive expands to more than %d MB", MaxExpandedSize>>20)
+	}
+	if rerr := checkRatio(g.expanded, g.compressed.n); rerr != nil {
+		return n, rerr
+	}
+	return n, err
+}
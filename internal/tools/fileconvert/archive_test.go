@@ -0,0 +1,285 @@
+package fileconvert
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io/fs"
+	"strings"
+	"testing"
+	"time"
+)
+
+func buildArchive(t *testing.T, format ArchiveFormat, files map[string]string, order []string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	aw, err := NewArchiveWriter(&buf, format)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range order {
+		body := files[name]
+		if _, err := aw.Add(name, int64(len(body)), time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC), strings.NewReader(body)); err != nil {
+			t.Fatalf("Add(%q): %v", name, err)
+		}
+	}
+	if err := aw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestArchiveRoundTrip(t *testing.T) {
+	files := map[string]string{
+		"readme.txt":        "hello\n",
+		"docs/guide.md":     strings.Repeat("# Guide\n", 500),
+		`win\path\data.csv`: "a,b\n1,2\n",
+	}
+	order := []string{"readme.txt", "docs/guide.md", `win\path\data.csv`}
+
+	for _, format := range []ArchiveFormat{ArchiveZip, ArchiveTarGz} {
+		t.Run(string(format), func(t *testing.T) {
+			data := buildArchive(t, format, files, order)
+			r := bytes.NewReader(data)
+
+			listing, err := ListArchive(r, int64(len(data)))
+			if err != nil {
+				t.Fatalf("ListArchive: %v", err)
+			}
+			if listing.Format != format {
+				t.Errorf("format = %q, want %q", listing.Format, format)
+			}
+
+			wantNames := []string{"readme.txt", "docs/guide.md", "win/path/data.csv"}
+			if len(listing.Entries) != len(wantNames) {
+				t.Fatalf("got %d entries, want %d", len(listing.Entries), len(wantNames))
+			}
+			var total int64
+			for i, e := range listing.Entries {
+				if e.Name != wantNames[i] {
+					t.Errorf("entry %d = %q, want %q", i, e.Name, wantNames[i])
+				}
+				if !e.Regular() || e.Mode.Perm() != 0o644 {
+					t.Errorf("%s: mode %v, regular %v", e.Name, e.Mode, e.Regular())
+				}
+				if !e.Modified.Equal(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)) {
+					t.Errorf("%s: modified %v", e.Name, e.Modified)
+				}
+				total += e.Size
+			}
+			if listing.ExpandedSize != total {
+				t.Errorf("ExpandedSize = %d, want %d", listing.ExpandedSize, total)
+			}
+			if format == ArchiveZip && listing.Entries[1].Ratio() < 10 {
+				t.Errorf("repetitive entry ratio = %.1f, want > 10", listing.Entries[1].Ratio())
+			}
+
+			var out bytes.Buffer
+			e, err := ExtractEntry(r, int64(len(data)), "docs/guide.md", &out)
+			if err != nil {
+				t.Fatalf("ExtractEntry: %v", err)
+			}
+			if out.String() != files["docs/guide.md"] || e.Size != int64(out.Len()) {
+				t.Errorf("extracted %d bytes, want %d", out.Len(), len(files["docs/guide.md"]))
+			}
+
+			if _, err := ExtractEntry(r, int64(len(data)), "missing.txt", &out); err == nil {
+				t.Error("extracting a missing entry succeeded")
+			}
+		})
+	}
+}
+
+func TestArchiveWriterNames(t *testing.T) {
+	var buf bytes.Buffer
+	aw, _ := NewArchiveWriter(&buf, ArchiveZip)
+
+	for _, tt := range []struct{ in, want string }{
+		{"a.txt", "a.txt"},
+		{"a.txt", "a-2.txt"},
+		{"./dir//b.txt", "dir/b.txt"},
+	} {
+		got, err := aw.Add(tt.in, 1, time.Now(), strings.NewReader("x"))
+		if err != nil || got != tt.want {
+			t.Errorf("Add(%q) = %q, %v, want %q", tt.in, got, err, tt.want)
+		}
+	}
+	for _, bad := range []string{"../escape.txt", "/etc/passwd", `C:\evil.txt`, "."} {
+		if _, err := aw.Add(bad, 1, time.Now(), strings.NewReader("x")); err == nil {
+			t.Errorf("Add(%q) succeeded", bad)
+		}
+	}
+	if _, err := aw.Add("short.txt", 10, time.Now(), strings.NewReader("x")); err == nil {
+		t.Error("Add with a short reader succeeded")
+	}
+
+	if _, err := NewArchiveWriter(&buf, ArchiveTar); err == nil {
+		t.Error("creating a plain tar succeeded")
+	}
+}
+
+func TestSafeEntryName(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+		ok   bool
+	}{
+		{"a/b.txt", "a/b.txt", true},
+		{"a/./b/../c.txt", "", false},
+		{"dir/", "dir", true},
+		{"./", ".", true},
+		{"../x", "", false},
+		{"a/../../x", "", false},
+		{`..\x`, "", false},
+		{"/abs", "", false},
+		{"C:/x", "", false},
+		{"..foo/bar", "..foo/bar", true},
+	}
+	for _, tt := range tests {
+		got, err := SafeEntryName(tt.in)
+		if (err == nil) != tt.ok || got != tt.want {
+			t.Errorf("SafeEntryName(%q) = %q, %v; want %q, ok %v", tt.in, got, err, tt.want, tt.ok)
+		}
+		if err != nil && !errors.Is(err, ErrUnsafeEntry) {
+			t.Errorf("SafeEntryName(%q) error %v is not ErrUnsafeEntry", tt.in, err)
+		}
+	}
+}
+
+func TestArchiveZipSlip(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, name := range []string{"ok.txt", "../../evil.sh"} {
+		f, _ := zw.Create(name)
+		f.Write([]byte("data"))
+	}
+	zw.Close()
+	data := buf.Bytes()
+
+	listing, err := ListArchive(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("ListArchive: %v", err)
+	}
+	if listing.Entries[0].Unsafe != "" || listing.Entries[1].Unsafe == "" {
+		t.Errorf("unsafe flags = %q, %q", listing.Entries[0].Unsafe, listing.Entries[1].Unsafe)
+	}
+	if _, err := ExtractEntry(bytes.NewReader(data), int64(len(data)), "../../evil.sh", &bytes.Buffer{}); err == nil {
+		t.Error("extracting a zip-slip entry succeeded")
+	}
+}
+
+func TestArchiveTarSpecialEntries(t *testing.T) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	tw.WriteHeader(&tar.Header{Typeflag: tar.TypeDir, Name: "dir/", Mode: 0o755})
+	tw.WriteHeader(&tar.Header{Typeflag: tar.TypeSymlink, Name: "dir/link", Linkname: "/etc/passwd", Mode: 0o777})
+	tw.WriteHeader(&tar.Header{Typeflag: tar.TypeReg, Name: "/abs.txt", Size: 0, Mode: 0o600})
+	tw.Close()
+	gz.Close()
+	data := buf.Bytes()
+
+	listing, err := ListArchive(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("ListArchive: %v", err)
+	}
+	if len(listing.Entries) != 3 {
+		t.Fatalf("got %d entries, want 3", len(listing.Entries))
+	}
+	if e := listing.Entries[0]; !e.Mode.IsDir() || e.Regular() {
+		t.Errorf("dir entry: mode %v", e.Mode)
+	}
+	if e := listing.Entries[1]; e.Link != "/etc/passwd" || e.Mode&fs.ModeSymlink == 0 {
+		t.Errorf("link entry: %+v", e)
+	}
+	if e := listing.Entries[2]; e.Unsafe == "" || e.CompressedSize != -1 {
+		t.Errorf("absolute entry: %+v", e)
+	}
+
+	for _, name := range []string{"dir/", "dir/link", "/abs.txt"} {
+		if _, err := ExtractEntry(bytes.NewReader(data), int64(len(data)), name, &bytes.Buffer{}); err == nil {
+			t.Errorf("extracting %q succeeded", name)
+		}
+	}
+}
+
+func TestArchiveDecompressionBomb(t *testing.T) {
+	// 16MB of zeros gzips to about 16KB, far beyond the expansion ratio.
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	const size = 16 << 20
+	tw.WriteHeader(&tar.Header{Typeflag: tar.TypeReg, Name: "zeros", Size: size, Mode: 0o644})
+	tw.Write(make([]byte, size))
+	tw.Close()
+	gz.Close()
+	tarGz := buf.Bytes()
+
+	if _, err := ListArchive(bytes.NewReader(tarGz), int64(len(tarGz))); err == nil || !strings.Contains(err.Error(), "decompression bomb") {
+		t.Errorf("ListArchive error = %v, want a decompression bomb error", err)
+	}
+
+	buf.Reset()
+	zw := zip.NewWriter(&buf)
+	f, _ := zw.Create("zeros")
+	f.Write(make([]byte, size))
+	zw.Close()
+	zipData := buf.Bytes()
+
+	if _, err := ListArchive(bytes.NewReader(zipData), int64(len(zipData))); err == nil {
+		t.Error("ListArchive accepted a zip bomb")
+	}
+	if _, err := ExtractEntry(bytes.NewReader(zipData), int64(len(zipData)), "zeros", &bytes.Buffer{}); err == nil {
+		t.Error("ExtractEntry expanded a zip bomb")
+	}
+}
+
+func TestArchiveEntryLimit(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for i := 0; i <= MaxArchiveEntries; i++ {
+		zw.CreateHeader(&zip.FileHeader{Name: "f", Method: zip.Store})
+	}
+	zw.Close()
+	data := buf.Bytes()
+
+	if _, err := ListArchive(bytes.NewReader(data), int64(len(data))); err == nil || !strings.Contains(err.Error(), "entries") {
+		t.Errorf("error = %v, want an entry limit error", err)
+	}
+}
+
+func TestDetectArchive(t *testing.T) {
+	var tarBuf bytes.Buffer
+	tw := tar.NewWriter(&tarBuf)
+	tw.WriteHeader(&tar.Header{Typeflag: tar.TypeReg, Name: "a", Size: 1, Mode: 0o644})
+	tw.Write([]byte("x"))
+	tw.Close()
+
+	tests := []struct {
+		name string
+		data []byte
+		want ArchiveFormat
+	}{
+		{"zip", buildArchive(t, ArchiveZip, map[string]string{"a": "x"}, []string{"a"}), ArchiveZip},
+		{"tar.gz", buildArchive(t, ArchiveTarGz, map[string]string{"a": "x"}, []string{"a"}), ArchiveTarGz},
+		{"tar", tarBuf.Bytes(), ArchiveTar},
+	}
+	for _, tt := range tests {
+		got, err := DetectArchive(bytes.NewReader(tt.data))
+		if err != nil || got != tt.want {
+			t.Errorf("%s: DetectArchive = %q, %v", tt.name, got, err)
+		}
+	}
+
+	if _, err := DetectArchive(strings.NewReader("plain text")); err == nil {
+		t.Error("DetectArchive accepted plain text")
+	}
+
+	// Plain tar archives are listed too.
+	listing, err := ListArchive(bytes.NewReader(tarBuf.Bytes()), int64(tarBuf.Len()))
+	if err != nil || len(listing.Entries) != 1 {
+		t.Errorf("ListArchive(tar) = %+v, %v", listing, err)
+	}
+}
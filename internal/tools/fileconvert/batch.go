@@ -0,0 +1,224 @@
+package fileconvert
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/NickDiPreta1/toolhub/internal/workerpool"
+)
+
+// MaxThumbnailSize is the largest thumbnail edge BatchOptions accepts.
+const MaxThumbnailSize = 1024
+
+// ImageFile is one uploaded image in a batch.
+type ImageFile struct {
+	Name string
+	Data []byte
+}
+
+// BatchOptions configures ProcessImages.
+type BatchOptions struct {
+	ImageOptions
+	// ThumbnailSize, when positive, adds a thumbnail of each processed
+	// image under thumbs/ in the archive, fitted to a square of this size.
+	ThumbnailSize int
+	// Workers is the number of images processed at once.
+	Workers int
+}
+
+// BatchResult reports the outcome for one file of a batch.
+type BatchResult struct {
+	Name string
+	// Output is the archive entry written for the file, empty on error.
+	Output string
+	Err    error
+}
+
+// batchItem holds a worker's output for one file until it is written.
+type batchItem struct {
+	image  []byte
+	thumb  []byte
+	format ImageFormat
+}
+
+// ProcessImages applies opts to every file through a workerpool.Pool and
+// writes the results to w as a zip archive, in upload order. A file that
+// fails does not stop the batch: its error is returned in the results and
+// listed in an errors.txt entry.
+func ProcessImages(ctx context.Context, files []ImageFile, opts BatchOptions, w io.Writer) ([]BatchResult, error) {
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+	if opts.ThumbnailSize < 0 || opts.ThumbnailSize > MaxThumbnailSize {
+		return nil, fmt.Errorf("thumbnail size must be between 1 and %d", MaxThumbnailSize)
+	}
+	if opts.Workers <= 0 {
+		opts.Workers = 4
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// inFlight bounds how many encoded images wait to be written, as in
+	// jsonutil.StreamNDJSON.
+	maxInFlight := opts.Workers * 2
+	inFlight := make(chan struct{}, maxInFlight)
+
+	pool := workerpool.NewPool(opts.Workers, maxInFlight)
+	results := pool.Start(ctx)
+
+	// Each job writes only its own slot, before its result is sent.
+	items := make([]batchItem, len(files))
+
+	go func() {
+		defer pool.Shutdown()
+		for i, f := range files {
+			select {
+			case inFlight <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			pool.Submit(workerpool.Job{
+				ID:      i,
+				Content: f.Data,
+				Func: func(data []byte) ([]byte, error) {
+					item, err := processBatchImage(data, opts)
+					items[i] = item
+					return nil, err
+				},
+			})
+		}
+	}()
+
+	zw := zip.NewWriter(w)
+	names := map[string]bool{}
+	out := make([]BatchResult, len(files))
+	pending := map[int]workerpool.Result{}
+	next := 0
+	var writeErr error
+
+	for res := range results {
+		pending[res.JobID] = res
+		for {
+			done, ok := pending[next]
+			if !ok {
+				break
+			}
+			delete(pending, next)
+
+			out[next] = BatchResult{Name: files[next].Name, Err: done.Error}
+			if done.Error == nil && writeErr == nil {
+				item := items[next]
+				name := uniqueName(names, outputName(files[next].Name, item.format))
+				out[next].Output = name
+				writeErr = writeZipEntry(zw, name, item.image)
+				if writeErr == nil && item.thumb != nil {
+					writeErr = writeZipEntry(zw, "thumbs/"+name, item.thumb)
+				}
+				if writeErr != nil {
+					cancel()
+				}
+			}
+			items[next] = batchItem{}
+			next++
+			<-inFlight
+		}
+	}
+
+	if writeErr != nil {
+		return out, writeErr
+	}
+	if err := ctx.Err(); err != nil {
+		return out, err
+	}
+
+	var report strings.Builder
+	for _, r := range out {
+		if r.Err != nil {
+			fmt.Fprintf(&report, "%s: %v\n", r.Name, r.Err)
+		}
+	}
+	if report.Len() > 0 {
+		if err := writeZipEntry(zw, uniqueName(names, "errors.txt"), []byte(report.String())); err != nil {
+			return out, err
+		}
+	}
+
+	return out, zw.Close()
+}
+
+// processBatchImage decodes, transforms and encodes one file of a batch.
+func processBatchImage(data []byte, opts BatchOptions) (batchItem, error) {
+	img, name, err := decodeImage(data)
+	if err != nil {
+		return batchItem{}, err
+	}
+	img, err = Transform(img, opts.ImageOptions)
+	if err != nil {
+		return batchItem{}, err
+	}
+
+	item := batchItem{format: opts.Format}
+	if item.format == "" {
+		item.format = ImageFormat(name)
+	}
+
+	var buf bytes.Buffer
+	if err := EncodeImage(&buf, img, item.format, opts.Quality); err != nil {
+		return batchItem{}, err
+	}
+	item.image = buf.Bytes()
+
+	if opts.ThumbnailSize > 0 {
+		var thumb bytes.Buffer
+		if err := EncodeImage(&thumb, Thumbnail(img, opts.ThumbnailSize), item.format, opts.Quality); err != nil {
+			return batchItem{}, err
+		}
+		item.thumb = thumb.Bytes()
+	}
+	return item, nil
+}
+
+// outputName replaces the extension of an uploaded file name with that of
+// format, dropping any directory the client sent.
+func outputName(name string, format ImageFormat) string {
+	base := path.Base(strings.ReplaceAll(name, `\`, "/"))
+	base = strings.TrimSuffix(base, path.Ext(base))
+	if base == "" || base == "." || base == "/" {
+		base = "image"
+	}
+	return base + format.Extension()
+}
+
+// uniqueName returns name, or name with a numeric suffix if it is taken.
+func uniqueName(taken map[string]bool, name string) string {
+	ext := path.Ext(name)
+	stem := strings.TrimSuffix(name, ext)
+	candidate := name
+	for n := 2; taken[candidate]; n++ {
+		candidate = fmt.Sprintf("%s-%d%s", stem, n, ext)
+	}
+	taken[candidate] = true
+	return candidate
+}
+
+// writeZipEntry stores data under name. Image formats are already
+// compressed, so entries are stored rather than deflated.
+func writeZipEntry(zw *zip.Writer, name string, data []byte) error {
+	method := zip.Store
+	if strings.HasSuffix(name, ".bmp") || strings.HasSuffix(name, ".txt") {
+		method = zip.Deflate
+	}
+	f, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: method, Modified: time.Now()})
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	return err
+}
@@ -0,0 +1,157 @@
+package fileconvert
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"io"
+	"strings"
+	"testing"
+)
+
+func readZip(t *testing.T, data []byte) map[string][]byte {
+	t.Helper()
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("reading zip: %v", err)
+	}
+	entries := map[string][]byte{}
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		entries[f.Name] = body
+	}
+	return entries
+}
+
+func TestProcessImages(t *testing.T) {
+	png := encodePNG(t, testImage(40, 20, false))
+	files := []ImageFile{
+		{Name: "a.png", Data: png},
+		{Name: "broken.png", Data: []byte("not an image")},
+		{Name: `C:\photos\a.PNG`, Data: png},
+		{Name: "b", Data: png},
+	}
+
+	var buf bytes.Buffer
+	results, err := ProcessImages(context.Background(), files, BatchOptions{
+		ImageOptions:  ImageOptions{Format: FormatJPEG, Resize: ResizeFit, Width: 20},
+		ThumbnailSize: 8,
+		Workers:       3,
+	}, &buf)
+	if err != nil {
+		t.Fatalf("ProcessImages: %v", err)
+	}
+
+	wantOutputs := []string{"a.jpg", "", "a-2.jpg", "b.jpg"}
+	for i, r := range results {
+		if r.Name != files[i].Name || r.Output != wantOutputs[i] {
+			t.Errorf("result %d = %q -> %q, want %q -> %q", i, r.Name, r.Output, files[i].Name, wantOutputs[i])
+		}
+		if (r.Err != nil) != (i == 1) {
+			t.Errorf("result %d error = %v", i, r.Err)
+		}
+	}
+
+	entries := readZip(t, buf.Bytes())
+	for _, name := range []string{"a.jpg", "a-2.jpg", "b.jpg"} {
+		cfg, format, err := image.DecodeConfig(bytes.NewReader(entries[name]))
+		if err != nil || format != "jpeg" || cfg.Width != 20 || cfg.Height != 10 {
+			t.Errorf("%s: %s %dx%d, %v; want jpeg 20x10", name, format, cfg.Width, cfg.Height, err)
+		}
+		cfg, _, err = image.DecodeConfig(bytes.NewReader(entries["thumbs/"+name]))
+		if err != nil || cfg.Width != 8 || cfg.Height != 4 {
+			t.Errorf("thumbs/%s: %dx%d, %v; want 8x4", name, cfg.Width, cfg.Height, err)
+		}
+	}
+	if report := string(entries["errors.txt"]); !strings.HasPrefix(report, "broken.png: unrecognised image") {
+		t.Errorf("errors.txt = %q", report)
+	}
+	if len(entries) != 7 {
+		t.Errorf("archive has %d entries, want 7", len(entries))
+	}
+}
+
+func TestProcessImagesExtremeFill(t *testing.T) {
+	// Filling 1x100 from a 100x10 image rounds the crop width to zero; it
+	// used to panic inside a worker, where nothing recovers it.
+	files := []ImageFile{{Name: "wide.png", Data: encodePNG(t, testImage(100, 10, false))}}
+
+	var buf bytes.Buffer
+	results, err := ProcessImages(context.Background(), files, BatchOptions{
+		ImageOptions: ImageOptions{Format: FormatPNG, Resize: ResizeFill, Width: 1, Height: 100},
+		Workers:      1,
+	}, &buf)
+	if err != nil {
+		t.Fatalf("ProcessImages: %v", err)
+	}
+	if results[0].Err != nil {
+		t.Fatalf("result error = %v", results[0].Err)
+	}
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(readZip(t, buf.Bytes())["wide.png"]))
+	if err != nil || cfg.Width != 1 || cfg.Height != 100 {
+		t.Errorf("wide.png: %dx%d, %v; want 1x100", cfg.Width, cfg.Height, err)
+	}
+}
+
+func TestProcessImagesOrder(t *testing.T) {
+	// Larger images take longer, so with several workers they finish out of
+	// order; the archive must still follow the upload order.
+	var files []ImageFile
+	for i := 0; i < 20; i++ {
+		size := 10 + (i%5)*60
+		files = append(files, ImageFile{Name: "img.png", Data: encodePNG(t, testImage(size, size, false))})
+	}
+
+	var buf bytes.Buffer
+	if _, err := ProcessImages(context.Background(), files, BatchOptions{Workers: 4}, &buf); err != nil {
+		t.Fatalf("ProcessImages: %v", err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(zr.File) != len(files) {
+		t.Fatalf("archive has %d entries, want %d", len(zr.File), len(files))
+	}
+	for i, f := range zr.File {
+		want := "img.png"
+		if i > 0 {
+			want = fmt.Sprintf("img-%d.png", i+1)
+		}
+		if f.Name != want {
+			t.Errorf("entry %d = %q, want %q", i, f.Name, want)
+		}
+	}
+}
+
+func TestProcessImagesInvalidOptions(t *testing.T) {
+	_, err := ProcessImages(context.Background(), nil, BatchOptions{ThumbnailSize: MaxThumbnailSize + 1}, io.Discard)
+	if err == nil {
+		t.Error("oversized thumbnail accepted")
+	}
+	_, err = ProcessImages(context.Background(), nil, BatchOptions{ImageOptions: ImageOptions{Rotate: 10}}, io.Discard)
+	if err == nil {
+		t.Error("invalid rotation accepted")
+	}
+}
+
+func TestProcessImagesCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	files := []ImageFile{{Name: "a.png", Data: encodePNG(t, testImage(4, 4, false))}}
+	if _, err := ProcessImages(ctx, files, BatchOptions{}, io.Discard); err == nil {
+		t.Error("cancelled batch succeeded")
+	}
+}
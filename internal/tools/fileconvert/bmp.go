@@ -0,0 +1,357 @@
+package fileconvert
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+)
+
+// BMP compression types.
+const (
+	bmpRGB            = 0
+	bmpBitfields      = 3
+	bmpAlphaBitfields = 6
+)
+
+// bmpHeaderSize is the size of the BITMAPFILEHEADER.
+const bmpHeaderSize = 14
+
+var errBMPFormat = errors.New("bmp: invalid format")
+
+func init() {
+	image.RegisterFormat("bmp", "BM", DecodeBMP, DecodeBMPConfig)
+}
+
+// bmpInfo is the part of a BMP header needed to decode the pixels.
+type bmpInfo struct {
+	width, height int
+	topDown       bool
+	bpp           int
+	compression   uint32
+	masks         [4]uint32 // red, green, blue, alpha
+	palette       color.Palette
+	dataOffset    int
+	// headerLen is the number of bytes read so far.
+	headerLen int
+}
+
+// readBMPInfo reads the file header, DIB header, bit masks and palette. It
+// accepts the BITMAPCOREHEADER and BITMAPINFOHEADER through BITMAPV5HEADER,
+// uncompressed or with bit fields; RLE compression is not supported.
+func readBMPInfo(r io.Reader) (*bmpInfo, error) {
+	var head [bmpHeaderSize + 4]byte
+	if _, err := io.ReadFull(r, head[:]); err != nil {
+		return nil, err
+	}
+	if head[0] != 'B' || head[1] != 'M' {
+		return nil, errBMPFormat
+	}
+	info := &bmpInfo{dataOffset: int(binary.LittleEndian.Uint32(head[10:14]))}
+
+	dibSize := int(binary.LittleEndian.Uint32(head[14:18]))
+	if dibSize != 12 && (dibSize < 40 || dibSize > 124) {
+		return nil, fmt.Errorf("bmp: unsupported header size %d", dibSize)
+	}
+	dib := make([]byte, dibSize-4)
+	if _, err := io.ReadFull(r, dib); err != nil {
+		return nil, err
+	}
+	info.headerLen = bmpHeaderSize + dibSize
+
+	paletteEntrySize := 4
+	colorsUsed := 0
+	if dibSize == 12 {
+		info.width = int(binary.LittleEndian.Uint16(dib[0:2]))
+		info.height = int(binary.LittleEndian.Uint16(dib[2:4]))
+		info.bpp = int(binary.LittleEndian.Uint16(dib[6:8]))
+		paletteEntrySize = 3
+	} else {
+		info.width = int(int32(binary.LittleEndian.Uint32(dib[0:4])))
+		height := int(int32(binary.LittleEndian.Uint32(dib[4:8])))
+		if height < 0 {
+			info.topDown, height = true, -height
+		}
+		info.height = height
+		info.bpp = int(binary.LittleEndian.Uint16(dib[10:12]))
+		info.compression = binary.LittleEndian.Uint32(dib[12:16])
+		colorsUsed = int(binary.LittleEndian.Uint32(dib[28:32]))
+	}
+	if info.width <= 0 || info.height <= 0 {
+		return nil, fmt.Errorf("bmp: invalid dimensions %dx%d", info.width, info.height)
+	}
+
+	switch info.compression {
+	case bmpRGB:
+		switch info.bpp {
+		case 16:
+			info.masks = [4]uint32{0x7c00, 0x03e0, 0x001f, 0}
+		case 24, 32:
+			info.masks = [4]uint32{0xff0000, 0x00ff00, 0x0000ff, 0}
+		}
+	case bmpBitfields, bmpAlphaBitfields:
+		if info.bpp != 16 && info.bpp != 32 {
+			return nil, fmt.Errorf("bmp: bit fields with %d bits per pixel", info.bpp)
+		}
+		n := 3
+		if info.compression == bmpAlphaBitfields {
+			n = 4
+		}
+		if dibSize >= 56 {
+			// V3 and later headers carry all four masks.
+			n = 4
+		}
+		if dibSize > 40 {
+			for i := 0; i < n; i++ {
+				info.masks[i] = binary.LittleEndian.Uint32(dib[36+4*i:])
+			}
+		} else {
+			masks := make([]byte, 4*n)
+			if _, err := io.ReadFull(r, masks); err != nil {
+				return nil, err
+			}
+			info.headerLen += len(masks)
+			for i := 0; i < n; i++ {
+				info.masks[i] = binary.LittleEndian.Uint32(masks[4*i:])
+			}
+		}
+	default:
+		return nil, fmt.Errorf("bmp: unsupported compression %d", info.compression)
+	}
+
+	switch info.bpp {
+	case 1, 4, 8:
+		n := colorsUsed
+		if n == 0 || n > 1<<info.bpp {
+			n = 1 << info.bpp
+		}
+		raw := make([]byte, n*paletteEntrySize)
+		if _, err := io.ReadFull(r, raw); err != nil {
+			return nil, err
+		}
+		info.headerLen += len(raw)
+		info.palette = make(color.Palette, n)
+		for i := range info.palette {
+			p := raw[i*paletteEntrySize:]
+			info.palette[i] = color.RGBA{p[2], p[1], p[0], 0xff}
+		}
+	case 16, 24, 32:
+	default:
+		return nil, fmt.Errorf("bmp: unsupported bit depth %d", info.bpp)
+	}
+
+	return info, nil
+}
+
+// DecodeBMPConfig returns the dimensions and color model of a BMP image.
+func DecodeBMPConfig(r io.Reader) (image.Config, error) {
+	info, err := readBMPInfo(r)
+	if err != nil {
+		return image.Config{}, err
+	}
+
+	var model color.Model = color.RGBAModel
+	switch {
+	case info.palette != nil:
+		model = info.palette
+	case info.masks[3] != 0:
+		model = color.NRGBAModel
+	}
+	return image.Config{ColorModel: model, Width: info.width, Height: info.height}, nil
+}
+
+// DecodeBMP reads a BMP image. Paletted images decode to *image.Paletted,
+// images with an alpha mask to *image.NRGBA and all others to *image.RGBA.
+func DecodeBMP(r io.Reader) (image.Image, error) {
+	br := bufio.NewReader(r)
+	info, err := readBMPInfo(br)
+	if err != nil {
+		return nil, err
+	}
+
+	// Skip any gap between the headers and the pixel data.
+	if gap := info.dataOffset - info.headerLen; gap > 0 {
+		if _, err := br.Discard(gap); err != nil {
+			return nil, err
+		}
+	}
+
+	rowSize := (info.width*info.bpp + 31) / 32 * 4
+	row := make([]byte, rowSize)
+	bounds := image.Rect(0, 0, info.width, info.height)
+
+	var (
+		paletted *image.Paletted
+		rgba     *image.RGBA
+		nrgba    *image.NRGBA
+	)
+	var img image.Image
+	switch {
+	case info.palette != nil:
+		paletted = image.NewPaletted(bounds, info.palette)
+		img = paletted
+	case info.masks[3] != 0:
+		nrgba = image.NewNRGBA(bounds)
+		img = nrgba
+	default:
+		rgba = image.NewRGBA(bounds)
+		img = rgba
+	}
+
+	red, green, blue, alpha := newMask(info.masks[0]), newMask(info.masks[1]), newMask(info.masks[2]), newMask(info.masks[3])
+
+	for i := 0; i < info.height; i++ {
+		if _, err := io.ReadFull(br, row); err != nil {
+			return nil, fmt.Errorf("bmp: reading row %d: %w", i, err)
+		}
+		y := info.height - 1 - i
+		if info.topDown {
+			y = i
+		}
+
+		for x := 0; x < info.width; x++ {
+			switch info.bpp {
+			case 1, 4, 8:
+				bit := x * info.bpp
+				idx := row[bit/8] >> (8 - info.bpp - bit%8) & (1<<info.bpp - 1)
+				if int(idx) >= len(info.palette) {
+					idx = 0
+				}
+				paletted.Pix[y*paletted.Stride+x] = idx
+				continue
+			}
+
+			var px uint32
+			switch info.bpp {
+			case 16:
+				px = uint32(binary.LittleEndian.Uint16(row[2*x:]))
+			case 24:
+				px = uint32(row[3*x]) | uint32(row[3*x+1])<<8 | uint32(row[3*x+2])<<16
+			case 32:
+				px = binary.LittleEndian.Uint32(row[4*x:])
+			}
+
+			c := color.NRGBA{red.value(px), green.value(px), blue.value(px), 0xff}
+			if nrgba != nil {
+				c.A = alpha.value(px)
+				o := y*nrgba.Stride + 4*x
+				copy(nrgba.Pix[o:o+4], []uint8{c.R, c.G, c.B, c.A})
+			} else {
+				o := y*rgba.Stride + 4*x
+				copy(rgba.Pix[o:o+4], []uint8{c.R, c.G, c.B, 0xff})
+			}
+		}
+	}
+
+	return img, nil
+}
+
+// bitMask extracts a channel from a pixel and scales it to 8 bits.
+type bitMask struct {
+	shift uint
+	max   uint32
+}
+
+func newMask(m uint32) bitMask {
+	if m == 0 {
+		return bitMask{}
+	}
+	var shift uint
+	for m&1 == 0 {
+		m >>= 1
+		shift++
+	}
+	return bitMask{shift: shift, max: m}
+}
+
+func (m bitMask) value(px uint32) uint8 {
+	if m.max == 0 {
+		return 0
+	}
+	return uint8((px >> m.shift & m.max) * 255 / m.max)
+}
+
+// EncodeBMP writes img as an uncompressed bottom-up BMP: 24 bits per pixel
+// for opaque images, or 32 bits with an alpha channel and a BITMAPV4HEADER
+// otherwise.
+func EncodeBMP(w io.Writer, img image.Image) error {
+	b := img.Bounds()
+	width, height := b.Dx(), b.Dy()
+	if width <= 0 || height <= 0 {
+		return fmt.Errorf("bmp: cannot encode an empty image")
+	}
+
+	withAlpha := !isOpaque(img)
+	bpp, dibSize := 24, 40
+	if withAlpha {
+		bpp, dibSize = 32, 108
+	}
+	rowSize := (width*bpp + 31) / 32 * 4
+	dataOffset := bmpHeaderSize + dibSize
+	imageSize := rowSize * height
+
+	header := make([]byte, dataOffset)
+	header[0], header[1] = 'B', 'M'
+	binary.LittleEndian.PutUint32(header[2:], uint32(dataOffset+imageSize))
+	binary.LittleEndian.PutUint32(header[10:], uint32(dataOffset))
+
+	dib := header[bmpHeaderSize:]
+	binary.LittleEndian.PutUint32(dib[0:], uint32(dibSize))
+	binary.LittleEndian.PutUint32(dib[4:], uint32(width))
+	binary.LittleEndian.PutUint32(dib[8:], uint32(height))
+	binary.LittleEndian.PutUint16(dib[12:], 1)
+	binary.LittleEndian.PutUint16(dib[14:], uint16(bpp))
+	binary.LittleEndian.PutUint32(dib[20:], uint32(imageSize))
+	// 2835 pixels per metre is 72 DPI.
+	binary.LittleEndian.PutUint32(dib[24:], 2835)
+	binary.LittleEndian.PutUint32(dib[28:], 2835)
+	if withAlpha {
+		binary.LittleEndian.PutUint32(dib[16:], bmpBitfields)
+		binary.LittleEndian.PutUint32(dib[40:], 0x00ff0000)
+		binary.LittleEndian.PutUint32(dib[44:], 0x0000ff00)
+		binary.LittleEndian.PutUint32(dib[48:], 0x000000ff)
+		binary.LittleEndian.PutUint32(dib[52:], 0xff000000)
+		copy(dib[56:], "BGRs") // LCS_sRGB, little-endian
+	}
+
+	bw := bufio.NewWriter(w)
+	if _, err := bw.Write(header); err != nil {
+		return err
+	}
+
+	row := make([]byte, rowSize)
+	for y := b.Max.Y - 1; y >= b.Min.Y; y-- {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+			i := x - b.Min.X
+			if withAlpha {
+				copy(row[4*i:], []byte{c.B, c.G, c.R, c.A})
+			} else {
+				copy(row[3*i:], []byte{c.B, c.G, c.R})
+			}
+		}
+		if _, err := bw.Write(row); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// isOpaque reports whether every pixel of img is fully opaque.
+func isOpaque(img image.Image) bool {
+	if o, ok := img.(interface{ Opaque() bool }); ok {
+		return o.Opaque()
+	}
+	b := img.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			if _, _, _, a := img.At(x, y).RGBA(); a != 0xffff {
+				return false
+			}
+		}
+	}
+	return true
+}
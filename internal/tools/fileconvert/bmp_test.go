@@ -0,0 +1,183 @@
+package fileconvert
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"strings"
+	"testing"
+)
+
+func testImage(w, h int, alpha bool) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			a := uint8(0xff)
+			if alpha {
+				a = uint8(x * 255 / max(1, w-1))
+			}
+			img.SetNRGBA(x, y, color.NRGBA{uint8(x * 40), uint8(y * 40), uint8(x + y), a})
+		}
+	}
+	return img
+}
+
+func TestEncodeBMPRoundTrip(t *testing.T) {
+	tests := []struct {
+		name  string
+		alpha bool
+		bpp   uint16
+	}{
+		{"opaque", false, 24},
+		{"alpha", true, 32},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// An odd width exercises row padding.
+			src := testImage(5, 3, tt.alpha)
+			var buf bytes.Buffer
+			if err := EncodeBMP(&buf, src); err != nil {
+				t.Fatalf("EncodeBMP: %v", err)
+			}
+			if bpp := binary.LittleEndian.Uint16(buf.Bytes()[28:]); bpp != tt.bpp {
+				t.Errorf("bits per pixel = %d, want %d", bpp, tt.bpp)
+			}
+
+			got, format, err := image.Decode(bytes.NewReader(buf.Bytes()))
+			if err != nil {
+				t.Fatalf("image.Decode: %v", err)
+			}
+			if format != "bmp" {
+				t.Errorf("format = %q, want bmp", format)
+			}
+			if got.Bounds() != src.Bounds() {
+				t.Fatalf("bounds = %v, want %v", got.Bounds(), src.Bounds())
+			}
+			for y := 0; y < 3; y++ {
+				for x := 0; x < 5; x++ {
+					want := src.NRGBAAt(x, y)
+					have := color.NRGBAModel.Convert(got.At(x, y)).(color.NRGBA)
+					if have != want {
+						t.Fatalf("pixel (%d,%d) = %v, want %v", x, y, have, want)
+					}
+				}
+			}
+		})
+	}
+}
+
+// bmpFile assembles a BMP with a BITMAPINFOHEADER from its parts.
+func bmpFile(width, height int32, bpp uint16, compression uint32, extra, pixels []byte) []byte {
+	dataOffset := bmpHeaderSize + 40 + len(extra)
+	b := make([]byte, dataOffset)
+	b[0], b[1] = 'B', 'M'
+	binary.LittleEndian.PutUint32(b[2:], uint32(dataOffset+len(pixels)))
+	binary.LittleEndian.PutUint32(b[10:], uint32(dataOffset))
+	binary.LittleEndian.PutUint32(b[14:], 40)
+	binary.LittleEndian.PutUint32(b[18:], uint32(width))
+	binary.LittleEndian.PutUint32(b[22:], uint32(height))
+	binary.LittleEndian.PutUint16(b[26:], 1)
+	binary.LittleEndian.PutUint16(b[28:], bpp)
+	binary.LittleEndian.PutUint32(b[30:], compression)
+	if bpp <= 8 {
+		// Colors used, so short palettes are read correctly.
+		binary.LittleEndian.PutUint32(b[46:], uint32(len(extra)/4))
+	}
+	copy(b[bmpHeaderSize+40:], extra)
+	return append(b, pixels...)
+}
+
+func TestDecodeBMP(t *testing.T) {
+	red := color.NRGBA{0xff, 0, 0, 0xff}
+	blue := color.NRGBA{0, 0, 0xff, 0xff}
+
+	tests := []struct {
+		name string
+		data []byte
+		// want lists the pixels row by row from the top.
+		want [][]color.NRGBA
+	}{
+		{
+			name: "1-bit paletted bottom-up",
+			data: bmpFile(3, 2, 1, bmpRGB,
+				[]byte{0, 0, 0xff, 0, 0xff, 0, 0, 0}, // red, blue
+				[]byte{0b01000000, 0, 0, 0, 0b10100000, 0, 0, 0}),
+			want: [][]color.NRGBA{{blue, red, blue}, {red, blue, red}},
+		},
+		{
+			name: "8-bit paletted",
+			data: bmpFile(2, 1, 8, bmpRGB,
+				[]byte{0, 0, 0xff, 0, 0xff, 0, 0, 0},
+				[]byte{1, 0, 0, 0}),
+			want: [][]color.NRGBA{{blue, red}},
+		},
+		{
+			name: "24-bit top-down",
+			data: bmpFile(1, -2, 24, bmpRGB, nil,
+				[]byte{0, 0, 0xff, 0, 0xff, 0, 0, 0}),
+			want: [][]color.NRGBA{{red}, {blue}},
+		},
+		{
+			name: "16-bit 555",
+			data: bmpFile(2, 1, 16, bmpRGB, nil,
+				[]byte{0x00, 0x7c, 0x1f, 0x00}),
+			want: [][]color.NRGBA{{red, blue}},
+		},
+		{
+			name: "16-bit 565 bit fields",
+			data: bmpFile(2, 1, 16, bmpBitfields,
+				[]byte{0x00, 0xf8, 0, 0, 0xe0, 0x07, 0, 0, 0x1f, 0, 0, 0},
+				[]byte{0x00, 0xf8, 0x1f, 0x00}),
+			want: [][]color.NRGBA{{red, blue}},
+		},
+		{
+			name: "32-bit alpha bit fields",
+			data: bmpFile(1, 1, 32, bmpAlphaBitfields,
+				[]byte{0, 0, 0xff, 0, 0, 0xff, 0, 0, 0xff, 0, 0, 0, 0, 0, 0, 0xff},
+				[]byte{0x00, 0x00, 0xff, 0x80}),
+			want: [][]color.NRGBA{{{0xff, 0, 0, 0x80}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			img, err := DecodeBMP(bytes.NewReader(tt.data))
+			if err != nil {
+				t.Fatalf("DecodeBMP: %v", err)
+			}
+			for y, row := range tt.want {
+				for x, want := range row {
+					have := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+					if have != want {
+						t.Errorf("pixel (%d,%d) = %v, want %v", x, y, have, want)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestDecodeBMPErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    []byte
+		wantErr string
+	}{
+		{"not a bmp", []byte("GIF89a" + strings.Repeat("\x00", 40)), "invalid format"},
+		{"rle", bmpFile(1, 1, 8, 1, nil, nil), "unsupported compression"},
+		{"zero width", bmpFile(0, 1, 24, bmpRGB, nil, nil), "invalid dimensions"},
+		{"bit depth", bmpFile(1, 1, 7, bmpRGB, nil, nil), "unsupported bit depth"},
+		{"truncated pixels", bmpFile(2, 2, 24, bmpRGB, nil, []byte{1, 2, 3}), "reading row"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := DecodeBMP(bytes.NewReader(tt.data))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -1,18 +1,295 @@
 package fileconvert
 
 import (
+	"bufio"
 	"bytes"
+	"fmt"
 	"io"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/encoding/unicode/utf32"
+	"golang.org/x/text/language"
+	"golang.org/x/text/transform"
+
+	"github.com/NickDiPreta1/toolhub/internal/tools/textutil"
+)
+
+// Mode selects a conversion.
+type Mode string
+
+const (
+	ModeUpper         Mode = "upper"
+	ModeLower         Mode = "lower"
+	ModeTitle         Mode = "title"
+	ModeCRLFToLF      Mode = "crlf-to-lf"
+	ModeLFToCRLF      Mode = "lf-to-crlf"
+	ModeTabsToSpaces  Mode = "tabs-to-spaces"
+	ModeSpacesToTabs  Mode = "spaces-to-tabs"
+	ModeTrimTrailing  Mode = "trim-trailing"
+	ModeStripBOM      Mode = "strip-bom"
+	ModeTranscodeUTF8 Mode = "to-utf8"
+)
+
+// Tab widths accepted by the tab conversions.
+const (
+	DefaultTabWidth = 4
+	MaxTabWidth     = 16
 )
 
-// ToUpperText reads all input and returns a reader for the uppercased content.
+// Source charsets for ModeTranscodeUTF8.
+const (
+	CharsetAuto        = ""
+	CharsetLatin1      = "iso-8859-1"
+	CharsetWindows1252 = "windows-1252"
+	CharsetUTF16LE     = "utf-16le"
+	CharsetUTF16BE     = "utf-16be"
+)
+
+// ModeOption describes a selectable conversion for the UI.
+type ModeOption struct {
+	Mode Mode
+	Name string
+}
+
+// Modes lists the supported conversions in display order.
+var Modes = []ModeOption{
+	{ModeUpper, "UPPERCASE"},
+	{ModeLower, "lowercase"},
+	{ModeTitle, "Title Case"},
+	{ModeCRLFToLF, "Line endings: CRLF to LF"},
+	{ModeLFToCRLF, "Line endings: LF to CRLF"},
+	{ModeTabsToSpaces, "Tabs to spaces"},
+	{ModeSpacesToTabs, "Leading spaces to tabs"},
+	{ModeTrimTrailing, "Trim trailing whitespace"},
+	{ModeStripBOM, "Strip byte order mark"},
+	{ModeTranscodeUTF8, "Transcode to UTF-8"},
+}
+
+// Options configures Convert. TabWidth applies to the tab modes and Charset
+// to ModeTranscodeUTF8, where CharsetAuto detects the source encoding.
+type Options struct {
+	Mode     Mode
+	TabWidth int
+	Charset  string
+}
+
+// sniffLen is how much input is inspected to detect the source charset.
+const sniffLen = 4096
+
+// Convert returns a reader that converts r as it is read, so input of any
+// size is processed in constant memory. Only ModeTranscodeUTF8 with
+// CharsetAuto reads ahead, to detect the encoding from the first few
+// kilobytes. The other modes keep UTF-16 and UTF-32 input in its source
+// encoding. Read errors from r are returned by the reader.
+func Convert(r io.Reader, opts Options) (io.Reader, error) {
+	tabWidth := opts.TabWidth
+	if tabWidth == 0 {
+		tabWidth = DefaultTabWidth
+	}
+	if tabWidth < 1 || tabWidth > MaxTabWidth {
+		return nil, fmt.Errorf("tab width must be between 1 and %d", MaxTabWidth)
+	}
+
+	var t transform.Transformer
+	switch opts.Mode {
+	case ModeUpper:
+		t = cases.Upper(language.Und)
+	case ModeLower:
+		t = cases.Lower(language.Und)
+	case ModeTitle:
+		t = cases.Title(language.Und)
+	case ModeCRLFToLF:
+		t = newRewriter(&crlfToLF{})
+	case ModeLFToCRLF:
+		t = newRewriter(&lfToCRLF{})
+	case ModeTabsToSpaces:
+		t = newRewriter(&expandTabs{width: tabWidth})
+	case ModeSpacesToTabs:
+		t = newRewriter(&unexpandSpaces{width: tabWidth})
+	case ModeTrimTrailing:
+		t = newRewriter(&trimTrailing{})
+	case ModeStripBOM:
+		t = &stripBOM{}
+	case ModeTranscodeUTF8:
+		if opts.Charset == CharsetAuto {
+			br := bufio.NewReaderSize(r, sniffLen)
+			sample, err := br.Peek(sniffLen)
+			if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+				return nil, err
+			}
+			if err == nil {
+				sample = trimPartialRune(sample)
+			}
+			return transform.NewReader(br, detectDecoder(sample)), nil
+		}
+		enc, ok := charsets[strings.ToLower(opts.Charset)]
+		if !ok {
+			return nil, fmt.Errorf("unsupported charset %q", opts.Charset)
+		}
+		return transform.NewReader(r, enc.NewDecoder()), nil
+	default:
+		return nil, fmt.Errorf("unsupported mode %q", opts.Mode)
+	}
+
+	return &wideReader{r: r, t: t}, nil
+}
+
+// ToUpperText returns a reader for the uppercased content of r.
 func ToUpperText(r io.Reader) (io.Reader, error) {
-	data, err := io.ReadAll(r)
-	if err != nil {
-		return nil, err
+	return Convert(r, Options{Mode: ModeUpper})
+}
+
+// charsets maps the supported source charsets to their decoders. UTF-16
+// decoders drop a byte order mark if present.
+var charsets = map[string]encoding.Encoding{
+	CharsetLatin1:      charmap.ISO8859_1,
+	CharsetWindows1252: charmap.Windows1252,
+	CharsetUTF16LE:     unicode.UTF16(unicode.LittleEndian, unicode.UseBOM),
+	CharsetUTF16BE:     unicode.UTF16(unicode.BigEndian, unicode.UseBOM),
+}
+
+// detectDecoder picks a decoder for the encoding textutil.DetectEncoding
+// reports for sample. Like textutil.DecodeText, it drops any byte order mark
+// and replaces invalid sequences with U+FFFD.
+func detectDecoder(sample []byte) transform.Transformer {
+	enc, _ := textutil.DetectEncoding(sample)
+	switch enc {
+	case textutil.EncodingUTF16LE:
+		return unicode.UTF16(unicode.LittleEndian, unicode.UseBOM).NewDecoder()
+	case textutil.EncodingUTF16BE:
+		return unicode.UTF16(unicode.BigEndian, unicode.UseBOM).NewDecoder()
+	case textutil.EncodingUTF32LE:
+		return utf32.UTF32(utf32.LittleEndian, utf32.UseBOM).NewDecoder()
+	case textutil.EncodingUTF32BE:
+		return utf32.UTF32(utf32.BigEndian, utf32.UseBOM).NewDecoder()
+	case textutil.EncodingWindows1252:
+		return charmap.Windows1252.NewDecoder()
+	default:
+		return unicode.UTF8BOM.NewDecoder()
 	}
+}
+
+// wideReader applies a conversion that works on UTF-8. If the first read
+// shows the input is UTF-16 or UTF-32, it is decoded before the conversion
+// and encoded again afterwards rather than having its bytes rewritten
+// directly. Detection waits only for the first few bytes, so output still
+// streams while the input is open.
+type wideReader struct {
+	r   io.Reader
+	t   transform.Transformer
+	out io.Reader
+}
 
-	upper := bytes.ToUpper(data)
+func (w *wideReader) Read(p []byte) (int, error) {
+	if w.out == nil {
+		// Four bytes cover the longest byte order mark.
+		sample := make([]byte, sniffLen)
+		n, err := io.ReadAtLeast(w.r, sample, 4)
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			return 0, err
+		}
+		sample = sample[:n]
+
+		t := w.t
+		if enc := wideEncoding(sample); enc != nil {
+			t = transform.Chain(enc.NewDecoder(), t, enc.NewEncoder())
+		}
+		w.out = transform.NewReader(io.MultiReader(bytes.NewReader(sample), w.r), t)
+	}
+	return w.out.Read(p)
+}
 
-	return bytes.NewReader(upper), nil
+// wideEncoding returns the UTF-16 or UTF-32 encoding textutil.DetectEncoding
+// reports for sample, or nil for byte-oriented text. A byte order mark is
+// decoded as U+FEFF and written back unchanged, so it survives the round trip
+// unless ModeStripBOM removes it.
+func wideEncoding(sample []byte) encoding.Encoding {
+	// Detection needs whole UTF-16 code units.
+	enc, _ := textutil.DetectEncoding(sample[:len(sample)&^1])
+	switch enc {
+	case textutil.EncodingUTF16LE:
+		return unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM)
+	case textutil.EncodingUTF16BE:
+		return unicode.UTF16(unicode.BigEndian, unicode.IgnoreBOM)
+	case textutil.EncodingUTF32LE:
+		return utf32.UTF32(utf32.LittleEndian, utf32.IgnoreBOM)
+	case textutil.EncodingUTF32BE:
+		return utf32.UTF32(utf32.BigEndian, utf32.IgnoreBOM)
+	default:
+		return nil
+	}
+}
+
+// OutputCharset returns the charset of what Convert writes in mode for input
+// that starts with sample, or is all of sample if atEOF is set: UTF-8 after
+// transcoding, and otherwise the charset the input was detected to be in.
+func OutputCharset(sample []byte, atEOF bool, mode Mode) string {
+	if mode == ModeTranscodeUTF8 {
+		return "utf-8"
+	}
+
+	enc, _ := textutil.DetectEncoding(sample[:len(sample)&^1])
+	switch enc {
+	case textutil.EncodingUTF16LE:
+		return CharsetUTF16LE
+	case textutil.EncodingUTF16BE:
+		return CharsetUTF16BE
+	case textutil.EncodingUTF32LE:
+		return "utf-32le"
+	case textutil.EncodingUTF32BE:
+		return "utf-32be"
+	}
+	if !atEOF {
+		sample = trimPartialRune(sample)
+	}
+	if enc, _ := textutil.DetectEncoding(sample); enc == textutil.EncodingWindows1252 {
+		return CharsetWindows1252
+	}
+	return "utf-8"
+}
+
+// trimPartialRune drops an incomplete UTF-8 sequence cut off at the end of a
+// sample, so valid UTF-8 is not mistaken for another encoding.
+func trimPartialRune(b []byte) []byte {
+	for i := 1; i < utf8.UTFMax && i <= len(b); i++ {
+		if utf8.RuneStart(b[len(b)-i]) {
+			if !utf8.FullRune(b[len(b)-i:]) {
+				return b[:len(b)-i]
+			}
+			break
+		}
+	}
+	return b
+}
+
+// IsText reports whether sample, the first bytes of a file, looks like text
+// in any encoding Convert understands. Files that http.DetectContentType
+// recognises as a binary format, or that contain control characters other
+// than whitespace and escape, are rejected.
+func IsText(sample []byte) bool {
+	enc, bomLen := textutil.DetectEncoding(sample)
+	if bomLen > 0 || enc != textutil.EncodingASCII && enc != textutil.EncodingUTF8 && enc != textutil.EncodingWindows1252 {
+		// A byte order mark or UTF-16/32 text with NUL high bytes.
+		return true
+	}
+
+	if ct := http.DetectContentType(sample); !strings.HasPrefix(ct, "text/") && ct != "application/octet-stream" {
+		return false
+	}
+
+	for _, c := range sample {
+		if c < 0x20 && c != '\t' && c != '\n' && c != '\r' && c != '\f' && c != '\v' && c != 0x1b {
+			return false
+		}
+		if c == 0x7f {
+			return false
+		}
+	}
+	return true
 }
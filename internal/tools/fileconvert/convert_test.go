@@ -4,6 +4,10 @@ import (
 	"io"
 	"strings"
 	"testing"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/encoding/unicode/utf32"
 )
 
 func TestToUpperText(t *testing.T) {
@@ -69,3 +73,153 @@ func TestToUpperText(t *testing.T) {
 		})
 	}
 }
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     Options
+		input    string
+		expected string
+	}{
+		{"lower", Options{Mode: ModeLower}, "Hello WORLD", "hello world"},
+		{"upper non-ASCII", Options{Mode: ModeUpper}, "straße café", "STRASSE CAFÉ"},
+		{"title", Options{Mode: ModeTitle}, "hello wORLD\nsecond line", "Hello World\nSecond Line"},
+		{"crlf to lf", Options{Mode: ModeCRLFToLF}, "a\r\nb\rc\n", "a\nb\nc\n"},
+		{"lf to crlf", Options{Mode: ModeLFToCRLF}, "a\nb\r\nc\rd", "a\r\nb\r\nc\r\nd"},
+		{"tabs to spaces", Options{Mode: ModeTabsToSpaces}, "\tx\n12\ty\né\tz", "    x\n12  y\né   z"},
+		{"tabs to spaces after bom", Options{Mode: ModeTabsToSpaces}, "\xef\xbb\xbf\tx", "\xef\xbb\xbf    x"},
+		{"tabs to spaces width 2", Options{Mode: ModeTabsToSpaces, TabWidth: 2}, "\t\tx", "    x"},
+		{"spaces to tabs", Options{Mode: ModeSpacesToTabs}, "        x  y\n      z\n \tw", "\t\tx  y\n\t  z\n\tw"},
+		{"trim trailing", Options{Mode: ModeTrimTrailing}, "a  \r\nb\t\nc \n  ", "a\r\nb\nc\n"},
+		{"strip bom", Options{Mode: ModeStripBOM}, "\xef\xbb\xbfhello", "hello"},
+		{"strip bom without bom", Options{Mode: ModeStripBOM}, "hello", "hello"},
+		{"latin-1", Options{Mode: ModeTranscodeUTF8, Charset: CharsetLatin1}, "caf\xe9 \x80", "café \u0080"},
+		{"windows-1252", Options{Mode: ModeTranscodeUTF8, Charset: CharsetWindows1252}, "caf\xe9 \x80", "café €"},
+		{"utf-16le", Options{Mode: ModeTranscodeUTF8, Charset: CharsetUTF16LE}, "h\x00\xe9\x00", "hé"},
+		{"utf-16be with bom", Options{Mode: ModeTranscodeUTF8, Charset: CharsetUTF16BE}, "\xfe\xff\x00h\x00\xe9", "hé"},
+		{"auto utf-16le bom", Options{Mode: ModeTranscodeUTF8}, "\xff\xfeh\x00i\x00", "hi"},
+		{"auto windows-1252", Options{Mode: ModeTranscodeUTF8}, "\x93quoted\x94", "“quoted”"},
+		{"auto utf-8 unchanged", Options{Mode: ModeTranscodeUTF8}, "café", "café"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Convert(strings.NewReader(tt.input), tt.opts)
+			if err != nil {
+				t.Fatalf("Convert() unexpected error: %v", err)
+			}
+			output, err := io.ReadAll(result)
+			if err != nil {
+				t.Fatalf("Failed to read result: %v", err)
+			}
+			if string(output) != tt.expected {
+				t.Errorf("Convert(%q) = %q, want %q", tt.input, output, tt.expected)
+			}
+		})
+	}
+}
+
+func TestConvertWideEncodings(t *testing.T) {
+	utf16le := unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM)
+	utf16be := unicode.UTF16(unicode.BigEndian, unicode.IgnoreBOM)
+	utf32le := utf32.UTF32(utf32.LittleEndian, utf32.IgnoreBOM)
+
+	tests := []struct {
+		name     string
+		enc      encoding.Encoding
+		opts     Options
+		input    string
+		expected string
+	}{
+		{"upper", utf16le, Options{Mode: ModeUpper}, "\ufeffstraße", "\ufeffSTRASSE"},
+		{"lower", utf16le, Options{Mode: ModeLower}, "\ufeffHello WORLD", "\ufeffhello world"},
+		{"title", utf16le, Options{Mode: ModeTitle}, "\ufeffhello wORLD", "\ufeffHello World"},
+		{"crlf to lf", utf16le, Options{Mode: ModeCRLFToLF}, "\ufeffa\r\nb\rc", "\ufeffa\nb\nc"},
+		{"lf to crlf", utf16le, Options{Mode: ModeLFToCRLF}, "\ufeffa\nb\r\nc", "\ufeffa\r\nb\r\nc"},
+		{"tabs to spaces", utf16le, Options{Mode: ModeTabsToSpaces}, "\ufeff\tx\n12\ty", "\ufeff    x\n12  y"},
+		{"spaces to tabs", utf16le, Options{Mode: ModeSpacesToTabs}, "\ufeff        x\n      z", "\ufeff\t\tx\n\t  z"},
+		{"trim trailing", utf16le, Options{Mode: ModeTrimTrailing}, "\ufeffa  \r\nb\t\n", "\ufeffa\r\nb\n"},
+		{"strip bom", utf16le, Options{Mode: ModeStripBOM}, "\ufeffhello", "hello"},
+		{"utf-16be without bom", utf16be, Options{Mode: ModeCRLFToLF}, "one\r\ntwo\r\n", "one\ntwo\n"},
+		{"utf-32le", utf32le, Options{Mode: ModeUpper}, "\ufeffcafé", "\ufeffCAFÉ"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input, err := tt.enc.NewEncoder().String(tt.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want, err := tt.enc.NewEncoder().String(tt.expected)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := convertAll(t, strings.NewReader(input), tt.opts); got != want {
+				t.Errorf("Convert(%q) = %q, want %q", input, got, want)
+			}
+		})
+	}
+}
+
+func TestOutputCharset(t *testing.T) {
+	tests := []struct {
+		name     string
+		sample   string
+		atEOF    bool
+		mode     Mode
+		expected string
+	}{
+		{"ascii", "hello", true, ModeUpper, "utf-8"},
+		{"utf-16le bom", "\xff\xfeh\x00", false, ModeLower, "utf-16le"},
+		{"utf-16be without bom", "\x00h\x00e\x00l\x00l\x00o", true, ModeTrimTrailing, "utf-16be"},
+		{"utf-32le bom", "\xff\xfe\x00\x00h\x00\x00\x00", false, ModeCRLFToLF, "utf-32le"},
+		{"windows-1252 at eof", "caf\xe9", true, ModeUpper, "windows-1252"},
+		{"utf-8 cut off mid-rune", "caf\xc3", false, ModeUpper, "utf-8"},
+		{"transcoded", "\xff\xfeh\x00", false, ModeTranscodeUTF8, "utf-8"},
+	}
+	for _, tt := range tests {
+		if got := OutputCharset([]byte(tt.sample), tt.atEOF, tt.mode); got != tt.expected {
+			t.Errorf("OutputCharset(%s) = %q, want %q", tt.name, got, tt.expected)
+		}
+	}
+}
+
+func TestConvertErrors(t *testing.T) {
+	tests := []Options{
+		{Mode: "rot13"},
+		{Mode: ModeTabsToSpaces, TabWidth: MaxTabWidth + 1},
+		{Mode: ModeTranscodeUTF8, Charset: "ebcdic"},
+	}
+	for _, opts := range tests {
+		if _, err := Convert(strings.NewReader("x"), opts); err == nil {
+			t.Errorf("Convert(%+v) expected error", opts)
+		}
+	}
+}
+
+func TestIsText(t *testing.T) {
+	tests := []struct {
+		name     string
+		sample   string
+		expected bool
+	}{
+		{"ascii", "hello world\n", true},
+		{"utf-8", "héllo wörld", true},
+		{"windows-1252", "caf\xe9", true},
+		{"json", `{"a": 1}`, true},
+		{"utf-16le without bom", "h\x00e\x00l\x00l\x00o\x00", true},
+		{"utf-8 bom", "\xef\xbb\xbfhi", true},
+		{"ansi escapes", "\x1b[31mred\x1b[0m", true},
+		{"png", "\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR", false},
+		{"zip", "PK\x03\x04\x14\x00\x00\x00", false},
+		{"gzip", "\x1f\x8b\x08\x00\x00\x00\x00\x00", false},
+		{"pdf", "%PDF-1.7\n%\xe2\xe3\xcf\xd3\n", false},
+		{"nul bytes", "abc\x00def\x00\x01\x02", false},
+	}
+	for _, tt := range tests {
+		if got := IsText([]byte(tt.sample)); got != tt.expected {
+			t.Errorf("IsText(%s) = %v, want %v", tt.name, got, tt.expected)
+		}
+	}
+}
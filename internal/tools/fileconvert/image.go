@@ -0,0 +1,362 @@
+package fileconvert
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"strings"
+)
+
+// ImageFormat names an image file format.
+type ImageFormat string
+
+const (
+	FormatPNG  ImageFormat = "png"
+	FormatJPEG ImageFormat = "jpeg"
+	FormatGIF  ImageFormat = "gif"
+	FormatBMP  ImageFormat = "bmp"
+)
+
+// ImageFormats lists the supported output formats in display order.
+var ImageFormats = []ImageFormat{FormatPNG, FormatJPEG, FormatGIF, FormatBMP}
+
+// Extension returns the file extension for f, including the dot.
+func (f ImageFormat) Extension() string {
+	if f == FormatJPEG {
+		return ".jpg"
+	}
+	return "." + string(f)
+}
+
+// ResizeMode selects how an image is fitted to the target size.
+type ResizeMode string
+
+const (
+	// ResizeNone keeps the original size.
+	ResizeNone ResizeMode = ""
+	// ResizeFit scales the image to fit inside the box, keeping its aspect
+	// ratio. A zero width or height leaves that side unconstrained.
+	ResizeFit ResizeMode = "fit"
+	// ResizeFill scales the image to cover the box, keeping its aspect
+	// ratio, and crops the overflow evenly from both sides.
+	ResizeFill ResizeMode = "fill"
+	// ResizeExact stretches the image to the box.
+	ResizeExact ResizeMode = "exact"
+)
+
+// Limits on decoded and produced images, to refuse decompression bombs.
+const (
+	MaxImagePixels    = 50_000_000
+	MaxImageDimension = 20000
+)
+
+// DefaultJPEGQuality is used when ImageOptions.Quality is zero.
+const DefaultJPEGQuality = 85
+
+// ImageOptions describes the operations applied by ProcessImage, in order:
+// crop, rotate, resize, grayscale, then encode as Format. An empty Format
+// keeps the input format.
+type ImageOptions struct {
+	Format ImageFormat
+	// Quality is the JPEG quality, 1 to 100.
+	Quality int
+	// Crop is the region to keep, in source pixel coordinates. An empty
+	// rectangle keeps the whole image.
+	Crop image.Rectangle
+	// Rotate is a clockwise rotation in degrees: 0, 90, 180 or 270.
+	Rotate int
+	Resize ResizeMode
+	Width  int
+	Height int
+	// Grayscale converts the image to shades of gray, keeping transparency.
+	Grayscale bool
+}
+
+// ProcessImage decodes an image from r, applies opts and writes the result
+// to w. It returns the output format. Only the first frame of an animated
+// GIF is used.
+func ProcessImage(r io.Reader, w io.Writer, opts ImageOptions) (ImageFormat, error) {
+	if err := opts.validate(); err != nil {
+		return "", err
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return "", err
+	}
+	img, name, err := decodeImage(data)
+	if err != nil {
+		return "", err
+	}
+
+	format := opts.Format
+	if format == "" {
+		format = ImageFormat(name)
+	}
+
+	img, err = Transform(img, opts)
+	if err != nil {
+		return "", err
+	}
+	return format, EncodeImage(w, img, format, opts.Quality)
+}
+
+// decodeImage decodes data after checking its dimensions against the limits,
+// so oversized images are refused before their pixels are allocated.
+func decodeImage(data []byte) (image.Image, string, error) {
+	cfg, name, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("unrecognised image: %w", err)
+	}
+	if err := checkImageSize(cfg.Width, cfg.Height); err != nil {
+		return nil, "", err
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("decoding %s: %w", name, err)
+	}
+	return img, name, nil
+}
+
+func (opts ImageOptions) validate() error {
+	switch opts.Format {
+	case "", FormatPNG, FormatJPEG, FormatGIF, FormatBMP:
+	default:
+		return fmt.Errorf("unsupported image format %q", opts.Format)
+	}
+	if opts.Quality < 0 || opts.Quality > 100 {
+		return fmt.Errorf("quality must be between 1 and 100")
+	}
+	switch opts.Rotate {
+	case 0, 90, 180, 270:
+	default:
+		return fmt.Errorf("rotation must be 0, 90, 180 or 270 degrees")
+	}
+	switch opts.Resize {
+	case ResizeNone:
+	case ResizeFit:
+		if opts.Width <= 0 && opts.Height <= 0 {
+			return fmt.Errorf("fit needs a width or a height")
+		}
+	case ResizeFill, ResizeExact:
+		if opts.Width <= 0 || opts.Height <= 0 {
+			return fmt.Errorf("%s needs both a width and a height", opts.Resize)
+		}
+	default:
+		return fmt.Errorf("unsupported resize mode %q", opts.Resize)
+	}
+	if opts.Width < 0 || opts.Height < 0 || opts.Width > MaxImageDimension || opts.Height > MaxImageDimension {
+		return fmt.Errorf("width and height must be between 1 and %d", MaxImageDimension)
+	}
+	return nil
+}
+
+// Transform applies the crop, rotation, resize and grayscale steps of opts
+// to img.
+func Transform(img image.Image, opts ImageOptions) (image.Image, error) {
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+
+	if !opts.Crop.Empty() {
+		crop := opts.Crop.Add(img.Bounds().Min).Intersect(img.Bounds())
+		if crop.Empty() {
+			return nil, fmt.Errorf("crop %v is outside the %dx%d image", opts.Crop, img.Bounds().Dx(), img.Bounds().Dy())
+		}
+		img = cropImage(img, crop)
+	}
+
+	if opts.Rotate != 0 {
+		img = Rotate(img, opts.Rotate)
+	}
+
+	if opts.Resize != ResizeNone {
+		w, h := targetSize(img.Bounds(), opts.Resize, opts.Width, opts.Height)
+		if err := checkImageSize(w, h); err != nil {
+			return nil, fmt.Errorf("resized %w", err)
+		}
+		img = resizeTo(img, opts.Resize, opts.Width, opts.Height)
+	}
+
+	if opts.Grayscale {
+		img = Grayscale(img)
+	}
+	return img, nil
+}
+
+// EncodeImage writes img in format. JPEG has no transparency, so images are
+// flattened onto white first; GIF output is reduced to a 256-color palette.
+func EncodeImage(w io.Writer, img image.Image, format ImageFormat, quality int) error {
+	switch format {
+	case FormatPNG:
+		return png.Encode(w, img)
+	case FormatJPEG:
+		if quality == 0 {
+			quality = DefaultJPEGQuality
+		}
+		if !isOpaque(img) {
+			img = flatten(img, color.White)
+		}
+		return jpeg.Encode(w, img, &jpeg.Options{Quality: quality})
+	case FormatGIF:
+		return gif.Encode(w, img, nil)
+	case FormatBMP:
+		return EncodeBMP(w, img)
+	default:
+		return fmt.Errorf("unsupported image format %q", format)
+	}
+}
+
+// ParseImageFormat accepts a format name or file extension, such as "jpg".
+func ParseImageFormat(s string) (ImageFormat, error) {
+	switch strings.TrimPrefix(strings.ToLower(s), ".") {
+	case "png":
+		return FormatPNG, nil
+	case "jpg", "jpeg":
+		return FormatJPEG, nil
+	case "gif":
+		return FormatGIF, nil
+	case "bmp":
+		return FormatBMP, nil
+	default:
+		return "", fmt.Errorf("unsupported image format %q", s)
+	}
+}
+
+// cropImage copies rect out of img into a new image at the origin.
+func cropImage(img image.Image, rect image.Rectangle) image.Image {
+	out := image.NewNRGBA(image.Rect(0, 0, rect.Dx(), rect.Dy()))
+	draw.Draw(out, out.Bounds(), img, rect.Min, draw.Src)
+	return out
+}
+
+// Rotate turns img clockwise by a multiple of 90 degrees.
+func Rotate(img image.Image, degrees int) image.Image {
+	src := toNRGBA(img)
+	w, h := src.Bounds().Dx(), src.Bounds().Dy()
+
+	var out *image.NRGBA
+	var at func(x, y int) (int, int)
+	switch degrees % 360 {
+	case 90:
+		out = image.NewNRGBA(image.Rect(0, 0, h, w))
+		at = func(x, y int) (int, int) { return y, h - 1 - x }
+	case 180:
+		out = image.NewNRGBA(image.Rect(0, 0, w, h))
+		at = func(x, y int) (int, int) { return w - 1 - x, h - 1 - y }
+	case 270:
+		out = image.NewNRGBA(image.Rect(0, 0, h, w))
+		at = func(x, y int) (int, int) { return w - 1 - y, x }
+	default:
+		return src
+	}
+
+	ob := out.Bounds()
+	for y := 0; y < ob.Dy(); y++ {
+		for x := 0; x < ob.Dx(); x++ {
+			sx, sy := at(x, y)
+			copy(out.Pix[out.PixOffset(x, y):][:4], src.Pix[src.PixOffset(sx, sy):][:4])
+		}
+	}
+	return out
+}
+
+// Grayscale converts img to luma, keeping its alpha channel.
+func Grayscale(img image.Image) image.Image {
+	src := toNRGBA(img)
+	if isOpaque(src) {
+		out := image.NewGray(src.Bounds())
+		draw.Draw(out, out.Bounds(), src, src.Bounds().Min, draw.Src)
+		return out
+	}
+
+	out := image.NewNRGBA(src.Bounds())
+	for i := 0; i < len(src.Pix); i += 4 {
+		p := src.Pix[i : i+4]
+		y := color.GrayModel.Convert(color.RGBA{p[0], p[1], p[2], 0xff}).(color.Gray).Y
+		copy(out.Pix[i:i+4], []uint8{y, y, y, p[3]})
+	}
+	return out
+}
+
+// Thumbnail scales img to fit inside a size by size square.
+func Thumbnail(img image.Image, size int) image.Image {
+	return resizeTo(img, ResizeFit, size, size)
+}
+
+// checkImageSize refuses images too large to process safely.
+func checkImageSize(width, height int) error {
+	if width > MaxImageDimension || height > MaxImageDimension || width*height > MaxImagePixels {
+		return fmt.Errorf("image is %dx%d; the limit is %d pixels a side and %d megapixels",
+			width, height, MaxImageDimension, MaxImagePixels/1_000_000)
+	}
+	return nil
+}
+
+// targetSize returns the output size of resizing an image with bounds b.
+func targetSize(b image.Rectangle, mode ResizeMode, width, height int) (int, int) {
+	if mode != ResizeFit {
+		return width, height
+	}
+
+	sw, sh := b.Dx(), b.Dy()
+	scale := 0.0
+	if width > 0 {
+		scale = float64(width) / float64(sw)
+	}
+	if height > 0 && (scale == 0 || float64(height)/float64(sh) < scale) {
+		scale = float64(height) / float64(sh)
+	}
+	return max(1, int(float64(sw)*scale+0.5)), max(1, int(float64(sh)*scale+0.5))
+}
+
+// resizeTo resamples img to the size given by mode, width and height.
+func resizeTo(img image.Image, mode ResizeMode, width, height int) image.Image {
+	b := img.Bounds()
+	sw, sh := b.Dx(), b.Dy()
+
+	switch mode {
+	case ResizeFit:
+		width, height = targetSize(b, mode, width, height)
+		return Resize(img, width, height)
+
+	case ResizeFill:
+		scale := max(float64(width)/float64(sw), float64(height)/float64(sh))
+		// Crop the source to the target aspect ratio, then scale. Extreme
+		// ratios can round the crop to nothing, so keep at least a pixel.
+		cw := min(sw, max(1, int(float64(width)/scale+0.5)))
+		ch := min(sh, max(1, int(float64(height)/scale+0.5)))
+		x0 := b.Min.X + (sw-cw)/2
+		y0 := b.Min.Y + (sh-ch)/2
+		return Resize(cropImage(img, image.Rect(x0, y0, x0+cw, y0+ch)), width, height)
+
+	default:
+		return Resize(img, width, height)
+	}
+}
+
+// flatten composites img over a solid background.
+func flatten(img image.Image, bg color.Color) image.Image {
+	out := image.NewRGBA(img.Bounds())
+	draw.Draw(out, out.Bounds(), image.NewUniform(bg), image.Point{}, draw.Src)
+	draw.Draw(out, out.Bounds(), img, img.Bounds().Min, draw.Over)
+	return out
+}
+
+// toNRGBA returns img as an *image.NRGBA with its origin at (0, 0).
+func toNRGBA(img image.Image) *image.NRGBA {
+	if n, ok := img.(*image.NRGBA); ok && n.Bounds().Min == (image.Point{}) {
+		return n
+	}
+	b := img.Bounds()
+	out := image.NewNRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(out, out.Bounds(), img, b.Min, draw.Src)
+	return out
+}
@@ -0,0 +1,267 @@
+package fileconvert
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+func encodePNG(t *testing.T, img image.Image) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestProcessImageFormats(t *testing.T) {
+	src := encodePNG(t, testImage(8, 6, false))
+
+	for _, format := range ImageFormats {
+		t.Run(string(format), func(t *testing.T) {
+			var buf bytes.Buffer
+			got, err := ProcessImage(bytes.NewReader(src), &buf, ImageOptions{Format: format})
+			if err != nil {
+				t.Fatalf("ProcessImage: %v", err)
+			}
+			if got != format {
+				t.Errorf("format = %q, want %q", got, format)
+			}
+
+			cfg, name, err := image.DecodeConfig(&buf)
+			if err != nil {
+				t.Fatalf("decoding output: %v", err)
+			}
+			if ImageFormat(name) != format || cfg.Width != 8 || cfg.Height != 6 {
+				t.Errorf("output is %s %dx%d, want %s 8x6", name, cfg.Width, cfg.Height, format)
+			}
+		})
+	}
+}
+
+func TestProcessImageKeepsFormat(t *testing.T) {
+	var src bytes.Buffer
+	if err := gif.Encode(&src, testImage(4, 4, false), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	format, err := ProcessImage(&src, &out, ImageOptions{Rotate: 90})
+	if err != nil {
+		t.Fatalf("ProcessImage: %v", err)
+	}
+	if format != FormatGIF {
+		t.Errorf("format = %q, want gif", format)
+	}
+}
+
+func TestTransformSizes(t *testing.T) {
+	src := testImage(200, 100, false)
+
+	tests := []struct {
+		name  string
+		opts  ImageOptions
+		wantW int
+		wantH int
+	}{
+		{"none", ImageOptions{}, 200, 100},
+		{"fit box", ImageOptions{Resize: ResizeFit, Width: 50, Height: 50}, 50, 25},
+		{"fit width only", ImageOptions{Resize: ResizeFit, Width: 100}, 100, 50},
+		{"fit height only", ImageOptions{Resize: ResizeFit, Height: 20}, 40, 20},
+		{"fit upscale", ImageOptions{Resize: ResizeFit, Width: 400, Height: 400}, 400, 200},
+		{"fill", ImageOptions{Resize: ResizeFill, Width: 50, Height: 50}, 50, 50},
+		{"exact", ImageOptions{Resize: ResizeExact, Width: 30, Height: 70}, 30, 70},
+		{"crop", ImageOptions{Crop: image.Rect(10, 10, 60, 40)}, 50, 30},
+		{"crop clipped", ImageOptions{Crop: image.Rect(150, 50, 300, 300)}, 50, 50},
+		{"rotate 90", ImageOptions{Rotate: 90}, 100, 200},
+		{"rotate 180", ImageOptions{Rotate: 180}, 200, 100},
+		{"crop rotate fit", ImageOptions{Crop: image.Rect(0, 0, 100, 50), Rotate: 270, Resize: ResizeFit, Width: 10}, 10, 20},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			img, err := Transform(src, tt.opts)
+			if err != nil {
+				t.Fatalf("Transform: %v", err)
+			}
+			b := img.Bounds()
+			if b.Dx() != tt.wantW || b.Dy() != tt.wantH {
+				t.Errorf("size = %dx%d, want %dx%d", b.Dx(), b.Dy(), tt.wantW, tt.wantH)
+			}
+		})
+	}
+}
+
+func TestTransformErrors(t *testing.T) {
+	src := testImage(10, 10, false)
+
+	tests := []struct {
+		name    string
+		opts    ImageOptions
+		wantErr string
+	}{
+		{"format", ImageOptions{Format: "tiff"}, "unsupported image format"},
+		{"quality", ImageOptions{Quality: 101}, "quality"},
+		{"rotate", ImageOptions{Rotate: 45}, "rotation"},
+		{"resize mode", ImageOptions{Resize: "squash", Width: 1, Height: 1}, "unsupported resize mode"},
+		{"fit without size", ImageOptions{Resize: ResizeFit}, "fit needs"},
+		{"fill without height", ImageOptions{Resize: ResizeFill, Width: 10}, "both a width and a height"},
+		{"too wide", ImageOptions{Resize: ResizeExact, Width: MaxImageDimension + 1, Height: 1}, "between 1 and"},
+		{"too many pixels", ImageOptions{Resize: ResizeExact, Width: MaxImageDimension, Height: MaxImageDimension}, "megapixels"},
+		{"crop outside", ImageOptions{Crop: image.Rect(20, 20, 30, 30)}, "outside"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Transform(src, tt.opts)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestProcessImageRejectsHugeImages(t *testing.T) {
+	// A header claiming 30000x30000 pixels must be refused before the
+	// pixels are allocated.
+	data := bmpFile(30000, 30000, 24, bmpRGB, nil, nil)
+	_, err := ProcessImage(bytes.NewReader(data), &bytes.Buffer{}, ImageOptions{})
+	if err == nil || !strings.Contains(err.Error(), "limit") {
+		t.Errorf("error = %v, want a size limit error", err)
+	}
+}
+
+func TestRotate(t *testing.T) {
+	// 2x1: red then blue.
+	src := image.NewNRGBA(image.Rect(0, 0, 2, 1))
+	red := color.NRGBA{0xff, 0, 0, 0xff}
+	blue := color.NRGBA{0, 0, 0xff, 0xff}
+	src.SetNRGBA(0, 0, red)
+	src.SetNRGBA(1, 0, blue)
+
+	tests := []struct {
+		degrees int
+		want    [][]color.NRGBA
+	}{
+		{90, [][]color.NRGBA{{red}, {blue}}},
+		{180, [][]color.NRGBA{{blue, red}}},
+		{270, [][]color.NRGBA{{blue}, {red}}},
+	}
+
+	for _, tt := range tests {
+		img := Rotate(src, tt.degrees)
+		for y, row := range tt.want {
+			for x, want := range row {
+				if have := color.NRGBAModel.Convert(img.At(x, y)); have != want {
+					t.Errorf("Rotate(%d) pixel (%d,%d) = %v, want %v", tt.degrees, x, y, have, want)
+				}
+			}
+		}
+	}
+}
+
+func TestGrayscale(t *testing.T) {
+	opaque := Grayscale(testImage(4, 4, false))
+	if _, ok := opaque.(*image.Gray); !ok {
+		t.Errorf("opaque image became %T, want *image.Gray", opaque)
+	}
+
+	translucent := Grayscale(testImage(4, 4, true))
+	for x := 0; x < 4; x++ {
+		c := color.NRGBAModel.Convert(translucent.At(x, 1)).(color.NRGBA)
+		if c.R != c.G || c.G != c.B {
+			t.Errorf("pixel %d = %v, want gray", x, c)
+		}
+		if want := uint8(x * 255 / 3); c.A != want {
+			t.Errorf("pixel %d alpha = %d, want %d", x, c.A, want)
+		}
+	}
+}
+
+func TestResizeUniform(t *testing.T) {
+	// Resampling a flat color must not change it, in either pass order.
+	src := image.NewNRGBA(image.Rect(0, 0, 30, 7))
+	fill := color.NRGBA{10, 200, 90, 0x80}
+	for i := 0; i < len(src.Pix); i += 4 {
+		copy(src.Pix[i:], []uint8{fill.R, fill.G, fill.B, fill.A})
+	}
+
+	for _, size := range [][2]int{{11, 3}, {3, 40}, {90, 5}} {
+		img := Resize(src, size[0], size[1])
+		if b := img.Bounds(); b.Dx() != size[0] || b.Dy() != size[1] {
+			t.Fatalf("size = %v, want %v", b.Size(), size)
+		}
+		for y := 0; y < size[1]; y++ {
+			for x := 0; x < size[0]; x++ {
+				c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+				if diff(c.R, fill.R) > 2 || diff(c.G, fill.G) > 2 || diff(c.B, fill.B) > 2 || c.A != fill.A {
+					t.Fatalf("Resize to %v: pixel (%d,%d) = %v, want %v", size, x, y, c, fill)
+				}
+			}
+		}
+	}
+}
+
+func TestResizeZeroSize(t *testing.T) {
+	tests := []struct {
+		name          string
+		src           image.Rectangle
+		width, height int
+	}{
+		{"empty source", image.Rect(0, 0, 0, 10), 5, 5},
+		{"zero width", image.Rect(0, 0, 10, 10), 0, 5},
+		{"negative height", image.Rect(0, 0, 10, 10), 5, -3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			img := Resize(image.NewNRGBA(tt.src), tt.width, tt.height)
+			want := image.Pt(max(tt.width, 0), max(tt.height, 0))
+			if got := img.Bounds().Size(); got != want {
+				t.Errorf("size = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func diff(a, b uint8) int {
+	if a > b {
+		return int(a - b)
+	}
+	return int(b - a)
+}
+
+func TestEncodeJPEGFlattensOntoWhite(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 8, 8))
+	var buf bytes.Buffer
+	if err := EncodeImage(&buf, img, FormatJPEG, 0); err != nil {
+		t.Fatalf("EncodeImage: %v", err)
+	}
+	out, err := jpeg.Decode(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r, g, b, _ := out.At(4, 4).RGBA(); r < 0xf000 || g < 0xf000 || b < 0xf000 {
+		t.Errorf("transparent pixel encoded as %v, want white", out.At(4, 4))
+	}
+}
+
+func TestParseImageFormat(t *testing.T) {
+	tests := map[string]ImageFormat{
+		"png": FormatPNG, "JPG": FormatJPEG, ".jpeg": FormatJPEG, "gif": FormatGIF, "bmp": FormatBMP,
+	}
+	for in, want := range tests {
+		if got, err := ParseImageFormat(in); err != nil || got != want {
+			t.Errorf("ParseImageFormat(%q) = %q, %v, want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseImageFormat("webp"); err == nil {
+		t.Error("ParseImageFormat(webp) succeeded, want an error")
+	}
+}
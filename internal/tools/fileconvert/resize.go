@@ -0,0 +1,141 @@
+package fileconvert
+
+import (
+	"image"
+	"image/draw"
+	"math"
+)
+
+// Resize resamples img to width by height with a Catmull-Rom filter. When
+// shrinking, the filter is widened by the scale factor so every source pixel
+// contributes, which avoids the aliasing of nearest-neighbour sampling.
+// Pixels are filtered with premultiplied alpha so transparent areas do not
+// bleed their color into the result. A zero-sized source has no pixels to
+// sample, so it gives a transparent image; a zero-sized target gives an
+// empty image.
+func Resize(img image.Image, width, height int) image.Image {
+	width, height = max(width, 0), max(height, 0)
+	if width == 0 || height == 0 || img.Bounds().Empty() {
+		return image.NewRGBA(image.Rect(0, 0, width, height))
+	}
+
+	src := image.NewRGBA(image.Rect(0, 0, img.Bounds().Dx(), img.Bounds().Dy()))
+	draw.Draw(src, src.Bounds(), img, img.Bounds().Min, draw.Src)
+
+	sw, sh := src.Bounds().Dx(), src.Bounds().Dy()
+	if sw == width && sh == height {
+		return src
+	}
+	xw := resampleWeights(sw, width)
+	yw := resampleWeights(sh, height)
+
+	out := image.NewRGBA(image.Rect(0, 0, width, height))
+	store := func(o int, px [4]float32) {
+		// Premultiplied channels may not exceed alpha.
+		a := clamp8(px[3])
+		out.Pix[o] = uint8(min(clamp8(px[0]), a))
+		out.Pix[o+1] = uint8(min(clamp8(px[1]), a))
+		out.Pix[o+2] = uint8(min(clamp8(px[2]), a))
+		out.Pix[o+3] = uint8(a)
+	}
+
+	// Resample one axis into a float buffer, then the other into out. Doing
+	// the axis with the smaller intermediate first bounds memory by the
+	// geometric mean of the source and output sizes.
+	if width*sh <= sw*height {
+		tmp := make([]float32, 4*width*sh)
+		resamplePass(src.Pix, sh, src.Stride, 4, xw, 4*width, 4, storeFloat(tmp))
+		resamplePass(tmp, width, 4, 4*width, yw, 4, out.Stride, store)
+	} else {
+		tmp := make([]float32, 4*sw*height)
+		resamplePass(src.Pix, sw, 4, src.Stride, yw, 4, 4*sw, storeFloat(tmp))
+		resamplePass(tmp, height, 4*sw, 4, xw, out.Stride, 4, store)
+	}
+	return out
+}
+
+// resamplePass filters every line of in along one axis. Lines start
+// lineStride apart and their pixels are pixStride apart; output pixel i of
+// line l is stored at offset l*outLineStride + i*outPixStride.
+func resamplePass[T uint8 | float32](in []T, lines, lineStride, pixStride int, ws []pixelWeights, outLineStride, outPixStride int, store func(int, [4]float32)) {
+	for l := 0; l < lines; l++ {
+		line := l * lineStride
+		for i, pw := range ws {
+			var px [4]float32
+			for _, w := range pw.weights {
+				p := in[line+w.index*pixStride:]
+				px[0] += float32(p[0]) * w.weight
+				px[1] += float32(p[1]) * w.weight
+				px[2] += float32(p[2]) * w.weight
+				px[3] += float32(p[3]) * w.weight
+			}
+			store(l*outLineStride+i*outPixStride, px)
+		}
+	}
+}
+
+func storeFloat(buf []float32) func(int, [4]float32) {
+	return func(o int, px [4]float32) {
+		copy(buf[o:o+4], px[:])
+	}
+}
+
+type weight struct {
+	index  int
+	weight float32
+}
+
+type pixelWeights struct {
+	weights []weight
+}
+
+// resampleWeights returns, for each of the dst output pixels, the source
+// pixels that contribute and their normalized weights.
+func resampleWeights(src, dst int) []pixelWeights {
+	scale := float64(src) / float64(dst)
+	support := 2.0
+	filterScale := math.Max(scale, 1)
+	radius := support * filterScale
+
+	out := make([]pixelWeights, dst)
+	for i := range out {
+		center := (float64(i)+0.5)*scale - 0.5
+		lo := int(math.Ceil(center - radius))
+		hi := int(math.Floor(center + radius))
+
+		var sum float64
+		ws := make([]weight, 0, hi-lo+1)
+		for j := lo; j <= hi; j++ {
+			w := catmullRom((float64(j) - center) / filterScale)
+			if w == 0 {
+				continue
+			}
+			// Clamp to the edge so border pixels are repeated.
+			idx := min(max(j, 0), src-1)
+			ws = append(ws, weight{idx, float32(w)})
+			sum += w
+		}
+		for k := range ws {
+			ws[k].weight /= float32(sum)
+		}
+		out[i] = pixelWeights{ws}
+	}
+	return out
+}
+
+// catmullRom is the Catmull-Rom cubic (B=0, C=0.5), with support [-2, 2].
+func catmullRom(x float64) float64 {
+	x = math.Abs(x)
+	switch {
+	case x < 1:
+		return (1.5*x-2.5)*x*x + 1
+	case x < 2:
+		return ((-0.5*x+2.5)*x-4)*x + 2
+	default:
+		return 0
+	}
+}
+
+func clamp8(v float32) float32 {
+	return min(max(v+0.5, 0), 255)
+}
@@ -0,0 +1,379 @@
+package fileconvert
+
+import (
+	"archive/zip"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/NickDiPreta1/toolhub/internal/tools/hashutil"
+)
+
+// ManifestName is the entry of a split archive that describes its parts.
+const ManifestName = "manifest.json"
+
+// MaxSplitParts is the most parts a file can be split into. Parts are
+// numbered with three digits, from .001 to .999.
+const MaxSplitParts = 999
+
+// maxManifestSize bounds how much of an uploaded manifest is read.
+const maxManifestSize = 1 << 20
+
+// Manifest records how a file was split, so the parts can be checked and
+// joined back together.
+type Manifest struct {
+	Name   string `json:"name"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+	// ChunkSize is the size of the first, and largest, part.
+	ChunkSize int64   `json:"chunk_size"`
+	Chunks    []Chunk `json:"chunks"`
+}
+
+// Chunk is one part of a split file.
+type Chunk struct {
+	Name   string `json:"name"`
+	Offset int64  `json:"offset"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// SplitOptions chooses how a file is split: into Parts parts of equal
+// size, or into chunks of ChunkSize bytes. Exactly one must be set.
+type SplitOptions struct {
+	Parts     int
+	ChunkSize int64
+}
+
+// PartSizes returns the size of each part of a file of size bytes, or why
+// the file cannot be split that way. Equal parts differ by at most one
+// byte, with the larger ones first.
+func (o SplitOptions) PartSizes(size int64) ([]int64, error) {
+	switch {
+	case size == 0:
+		return nil, errors.New("the file is empty")
+	case o.Parts != 0 && o.ChunkSize != 0:
+		return nil, errors.New("choose either a number of parts or a chunk size, not both")
+	case o.Parts != 0:
+		if o.Parts < 2 || o.Parts > MaxSplitParts {
+			return nil, fmt.Errorf("the number of parts must be between 2 and %d", MaxSplitParts)
+		}
+		n := int64(o.Parts)
+		if n > size {
+			return nil, fmt.Errorf("a %d byte file cannot be split into %d parts", size, o.Parts)
+		}
+		sizes := make([]int64, n)
+		for i := range sizes {
+			sizes[i] = size / n
+			if int64(i) < size%n {
+				sizes[i]++
+			}
+		}
+		return sizes, nil
+	case o.ChunkSize != 0:
+		if o.ChunkSize < 0 {
+			return nil, errors.New("the chunk size must be positive")
+		}
+		if (size+o.ChunkSize-1)/o.ChunkSize > MaxSplitParts {
+			return nil, fmt.Errorf("a chunk size of %d bytes would make more than %d parts", o.ChunkSize, MaxSplitParts)
+		}
+		var sizes []int64
+		for left := size; left > 0; left -= o.ChunkSize {
+			sizes = append(sizes, min(o.ChunkSize, left))
+		}
+		return sizes, nil
+	default:
+		return nil, errors.New("choose a number of parts or a chunk size")
+	}
+}
+
+// Split writes the size bytes of r to w as a zip archive of numbered parts
+// named after name, followed by a manifest with the SHA-256 of each part
+// and of the whole file. Parts are stored uncompressed so they are quick
+// to write and come out exactly as they were cut.
+func Split(w io.Writer, r io.ReaderAt, name string, size int64, opts SplitOptions) (*Manifest, error) {
+	sizes, err := opts.PartSizes(size)
+	if err != nil {
+		return nil, err
+	}
+
+	name = path.Base(strings.ReplaceAll(name, `\`, "/"))
+	if name == "." || name == "/" || name == ".." {
+		name = "file"
+	}
+	m := &Manifest{Name: name, Size: size, ChunkSize: sizes[0]}
+
+	// Each part is hashed on its own and as part of the whole file.
+	whole := hashutil.NewDigester()
+	zw := zip.NewWriter(w)
+	now := time.Now()
+	var off int64
+	for i, n := range sizes {
+		c := Chunk{Name: fmt.Sprintf("%s.%03d", name, i+1), Offset: off, Size: n}
+		off += n
+		entry, err := zw.CreateHeader(&zip.FileHeader{Name: c.Name, Method: zip.Store, Modified: now})
+		if err != nil {
+			return nil, err
+		}
+		part := hashutil.NewDigester()
+		written, err := io.Copy(io.MultiWriter(entry, part, whole), io.NewSectionReader(r, c.Offset, c.Size))
+		if err == nil && written != c.Size {
+			err = io.ErrUnexpectedEOF
+		}
+		if err != nil {
+			return nil, fmt.Errorf("writing %s: %w", c.Name, err)
+		}
+		c.SHA256 = part.Sum().SHA256
+		m.Chunks = append(m.Chunks, c)
+	}
+	m.SHA256 = whole.Sum().SHA256
+
+	manifest, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	entry, err := zw.CreateHeader(&zip.FileHeader{Name: ManifestName, Method: zip.Deflate, Modified: now})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := entry.Write(append(manifest, '\n')); err != nil {
+		return nil, err
+	}
+	return m, zw.Close()
+}
+
+// ParseManifest reads a manifest written by Split and checks that its
+// parts are safely named and cover the file exactly once, in order.
+func ParseManifest(r io.Reader) (*Manifest, error) {
+	var m Manifest
+	if err := json.NewDecoder(io.LimitReader(r, maxManifestSize)).Decode(&m); err != nil {
+		return nil, fmt.Errorf("reading manifest: %w", err)
+	}
+	if err := m.validate(); err != nil {
+		return nil, fmt.Errorf("invalid manifest: %w", err)
+	}
+	return &m, nil
+}
+
+func (m *Manifest) validate() error {
+	if !plainName(m.Name) {
+		return fmt.Errorf("file name %q is not a plain file name", m.Name)
+	}
+	if !validSHA256(m.SHA256) {
+		return errors.New("the file SHA-256 must be 64 hex digits")
+	}
+	if len(m.Chunks) == 0 || len(m.Chunks) > MaxSplitParts {
+		return fmt.Errorf("it must list between 1 and %d parts", MaxSplitParts)
+	}
+	names := map[string]bool{}
+	var off int64
+	for _, c := range m.Chunks {
+		switch {
+		case !plainName(c.Name):
+			return fmt.Errorf("part name %q is not a plain file name", c.Name)
+		case names[c.Name]:
+			return fmt.Errorf("part %s is listed twice", c.Name)
+		case c.Offset != off:
+			return fmt.Errorf("part %s starts at %d, expected %d", c.Name, c.Offset, off)
+		case c.Size <= 0:
+			return fmt.Errorf("part %s has no bytes", c.Name)
+		case !validSHA256(c.SHA256):
+			return fmt.Errorf("the SHA-256 of part %s must be 64 hex digits", c.Name)
+		}
+		names[c.Name] = true
+		off += c.Size
+	}
+	if off != m.Size {
+		return fmt.Errorf("the parts add up to %d bytes, but the file is %d bytes", off, m.Size)
+	}
+	return nil
+}
+
+// plainName reports whether name is a file name without any directory.
+func plainName(name string) bool {
+	return name != "" && name != "." && name != ".." && !strings.ContainsAny(name, `/\`)
+}
+
+func validSHA256(s string) bool {
+	b, err := hex.DecodeString(s)
+	return err == nil && len(b) == 32
+}
+
+// OpenFunc opens the part with the given name. It returns an error
+// matching fs.ErrNotExist when the part was not supplied.
+type OpenFunc func(name string) (io.ReadCloser, error)
+
+// ChunkCheck is the result of verifying one part against the manifest.
+type ChunkCheck struct {
+	Chunk
+	// Problem says why the part cannot be used; it is empty when the part
+	// is intact.
+	Problem string
+}
+
+// OK reports whether the part was found intact.
+func (c ChunkCheck) OK() bool {
+	return c.Problem == ""
+}
+
+// Verification is the result of checking parts against a manifest.
+type Verification struct {
+	Chunks []ChunkCheck
+	// Problems counts the parts that are missing or corrupted.
+	Problems int
+	// FileProblem is set when every part is intact but together they do
+	// not match the SHA-256 recorded for the whole file.
+	FileProblem string
+}
+
+// OK reports whether the parts can be joined into the original file.
+func (v *Verification) OK() bool {
+	return v.Problems == 0 && v.FileProblem == ""
+}
+
+// CheckChunks reads every part listed in m and reports which are missing
+// or do not match their recorded size and SHA-256. When all of them match,
+// their concatenation in manifest order is checked against the SHA-256 of
+// the whole file, so a file that Join would reject is caught before any of
+// it is written. The error is only for failures to read a part that was
+// supplied.
+func CheckChunks(m *Manifest, open OpenFunc) (*Verification, error) {
+	v := &Verification{Chunks: make([]ChunkCheck, len(m.Chunks))}
+	whole := hashutil.NewDigester()
+	for i, c := range m.Chunks {
+		check := &v.Chunks[i]
+		check.Chunk = c
+		rc, err := open(c.Name)
+		if errors.Is(err, fs.ErrNotExist) {
+			check.Problem = "missing"
+			v.Problems++
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		part := hashutil.NewDigester()
+		n, err := io.Copy(io.MultiWriter(part, whole), rc)
+		rc.Close()
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", c.Name, err)
+		}
+		switch {
+		case n != c.Size:
+			check.Problem = fmt.Sprintf("corrupted: %d bytes, expected %d", n, c.Size)
+		case part.Sum().SHA256 != c.SHA256:
+			check.Problem = "corrupted: SHA-256 does not match"
+		}
+		if check.Problem != "" {
+			v.Problems++
+		}
+	}
+
+	if v.Problems == 0 && whole.Sum().SHA256 != m.SHA256 {
+		v.FileProblem = "the joined parts do not match the SHA-256 of the whole file"
+	}
+	return v, nil
+}
+
+// Join writes the parts listed in m to w in order and checks the result
+// against the SHA-256 of the whole file. A mismatch is only found once the
+// file has been written, so run CheckChunks first.
+func Join(w io.Writer, m *Manifest, open OpenFunc) error {
+	jr := &joinReader{chunks: m.Chunks, open: open}
+	defer jr.Close()
+
+	sums, _, err := hashutil.Digest(io.TeeReader(jr, w))
+	if err != nil {
+		return err
+	}
+	if sums.SHA256 != m.SHA256 {
+		return errors.New("the joined file does not match the SHA-256 in the manifest")
+	}
+	return nil
+}
+
+// joinReader reads the recorded size of each part in turn.
+type joinReader struct {
+	chunks []Chunk
+	open   OpenFunc
+	cur    io.ReadCloser
+	left   int64
+}
+
+func (j *joinReader) Read(p []byte) (int, error) {
+	for {
+		if j.cur == nil {
+			if len(j.chunks) == 0 {
+				return 0, io.EOF
+			}
+			rc, err := j.open(j.chunks[0].Name)
+			if err != nil {
+				return 0, fmt.Errorf("opening %s: %w", j.chunks[0].Name, err)
+			}
+			j.cur, j.left = rc, j.chunks[0].Size
+		}
+		if j.left == 0 {
+			j.Close()
+			j.chunks = j.chunks[1:]
+			continue
+		}
+
+		n, err := j.cur.Read(p[:min(int64(len(p)), j.left)])
+		j.left -= int64(n)
+		if err == io.EOF {
+			if j.left > 0 {
+				return n, fmt.Errorf("%s is shorter than the manifest says", j.chunks[0].Name)
+			}
+			err = nil
+		}
+		return n, err
+	}
+}
+
+func (j *joinReader) Close() error {
+	if j.cur == nil {
+		return nil
+	}
+	err := j.cur.Close()
+	j.cur = nil
+	return err
+}
+
+// ParseSize parses a byte count such as "1500", "64k", "1.5MB" or "2 GiB".
+// Units are powers of 1024.
+func ParseSize(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	num := strings.TrimRightFunc(s, func(r rune) bool {
+		return r == ' ' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+	})
+	scale, ok := sizeUnits[strings.ToLower(strings.TrimSpace(s[len(num):]))]
+	if !ok {
+		return 0, fmt.Errorf("invalid size %q: unknown unit", s)
+	}
+
+	n, err := strconv.ParseFloat(num, 64)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+	switch bytes := n * scale; {
+	case bytes < 1:
+		return 0, fmt.Errorf("size %q is less than one byte", s)
+	case bytes > 1<<50:
+		return 0, fmt.Errorf("size %q is too large", s)
+	}
+	return int64(n * scale), nil
+}
+
+var sizeUnits = map[string]float64{
+	"": 1, "b": 1,
+	"k": 1 << 10, "kb": 1 << 10, "kib": 1 << 10,
+	"m": 1 << 20, "mb": 1 << 20, "mib": 1 << 20,
+	"g": 1 << 30, "gb": 1 << 30, "gib": 1 << 30,
+}
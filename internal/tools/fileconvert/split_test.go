@@ -0,0 +1,315 @@
+package fileconvert
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"strings"
+	"testing"
+)
+
+// splitParts splits data and returns the archive entries by name.
+func splitParts(t *testing.T, data []byte, opts SplitOptions) (*Manifest, map[string][]byte) {
+	t.Helper()
+	var buf bytes.Buffer
+	m, err := Split(&buf, bytes.NewReader(data), "backup.tar", int64(len(data)), opts)
+	if err != nil {
+		t.Fatalf("Split() error = %v", err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := map[string][]byte{}
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		parts[f.Name] = body
+	}
+	return m, parts
+}
+
+func openParts(parts map[string][]byte) OpenFunc {
+	return func(name string) (io.ReadCloser, error) {
+		body, ok := parts[name]
+		if !ok {
+			return nil, fmt.Errorf("%s: %w", name, fs.ErrNotExist)
+		}
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}
+}
+
+func TestSplit(t *testing.T) {
+	data := []byte(strings.Repeat("0123456789", 10))
+
+	tests := []struct {
+		name      string
+		opts      SplitOptions
+		wantSizes []int64
+	}{
+		{"equal parts", SplitOptions{Parts: 4}, []int64{25, 25, 25, 25}},
+		{"uneven parts", SplitOptions{Parts: 3}, []int64{34, 33, 33}},
+		{"fixed chunks", SplitOptions{ChunkSize: 30}, []int64{30, 30, 30, 10}},
+		{"one chunk", SplitOptions{ChunkSize: 1 << 20}, []int64{100}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, parts := splitParts(t, data, tt.opts)
+
+			if len(m.Chunks) != len(tt.wantSizes) {
+				t.Fatalf("got %d chunks, want %d", len(m.Chunks), len(tt.wantSizes))
+			}
+			var joined []byte
+			for i, c := range m.Chunks {
+				if want := fmt.Sprintf("backup.tar.%03d", i+1); c.Name != want {
+					t.Errorf("chunk %d name = %q, want %q", i, c.Name, want)
+				}
+				if c.Size != tt.wantSizes[i] {
+					t.Errorf("chunk %d size = %d, want %d", i, c.Size, tt.wantSizes[i])
+				}
+				if int64(len(parts[c.Name])) != c.Size {
+					t.Errorf("entry %s has %d bytes, want %d", c.Name, len(parts[c.Name]), c.Size)
+				}
+				joined = append(joined, parts[c.Name]...)
+			}
+			if !bytes.Equal(joined, data) {
+				t.Error("parts do not add up to the original file")
+			}
+			if m.ChunkSize != tt.wantSizes[0] {
+				t.Errorf("ChunkSize = %d, want %d", m.ChunkSize, tt.wantSizes[0])
+			}
+
+			// The manifest entry must match what Split returned.
+			got, err := ParseManifest(bytes.NewReader(parts[ManifestName]))
+			if err != nil {
+				t.Fatalf("ParseManifest() error = %v", err)
+			}
+			if got.SHA256 != m.SHA256 || len(got.Chunks) != len(m.Chunks) || got.Chunks[0] != m.Chunks[0] {
+				t.Errorf("manifest entry = %+v, want %+v", got, m)
+			}
+		})
+	}
+}
+
+func TestSplitKnownDigests(t *testing.T) {
+	m, _ := splitParts(t, []byte("hello world"), SplitOptions{ChunkSize: 5})
+
+	if m.SHA256 != "b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9" {
+		t.Errorf("file SHA-256 = %s", m.SHA256)
+	}
+	// SHA-256 of "hello".
+	if m.Chunks[0].SHA256 != "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824" {
+		t.Errorf("first chunk SHA-256 = %s", m.Chunks[0].SHA256)
+	}
+	if m.Chunks[2].Size != 1 || m.Chunks[2].Offset != 10 {
+		t.Errorf("last chunk = %+v, want 1 byte at offset 10", m.Chunks[2])
+	}
+}
+
+func TestSplitErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		size    int
+		opts    SplitOptions
+		wantErr string
+	}{
+		{"empty file", 0, SplitOptions{Parts: 2}, "empty"},
+		{"no option", 10, SplitOptions{}, "choose a number of parts"},
+		{"both options", 10, SplitOptions{Parts: 2, ChunkSize: 5}, "not both"},
+		{"one part", 10, SplitOptions{Parts: 1}, "between 2 and"},
+		{"more parts than bytes", 3, SplitOptions{Parts: 4}, "cannot be split into 4 parts"},
+		{"too many chunks", 5000, SplitOptions{ChunkSize: 5}, "more than 999 parts"},
+		{"negative chunk size", 10, SplitOptions{ChunkSize: -1}, "must be positive"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := bytes.Repeat([]byte("x"), tt.size)
+			_, err := Split(io.Discard, bytes.NewReader(data), "f", int64(tt.size), tt.opts)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Split() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSplitName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"report.pdf", "report.pdf.001"},
+		{"../../etc/passwd", "passwd.001"},
+		{`C:\Users\me\data.bin`, "data.bin.001"},
+		{"", "file.001"},
+	}
+
+	for _, tt := range tests {
+		m, err := Split(io.Discard, strings.NewReader("abcd"), tt.name, 4, SplitOptions{Parts: 2})
+		if err != nil {
+			t.Fatalf("Split(%q) error = %v", tt.name, err)
+		}
+		if m.Chunks[0].Name != tt.want {
+			t.Errorf("Split(%q) first part = %q, want %q", tt.name, m.Chunks[0].Name, tt.want)
+		}
+	}
+}
+
+func TestCheckChunksAndJoin(t *testing.T) {
+	data := []byte(strings.Repeat("split me into pieces ", 20))
+	m, parts := splitParts(t, data, SplitOptions{Parts: 4})
+
+	t.Run("all parts intact", func(t *testing.T) {
+		v, err := CheckChunks(m, openParts(parts))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !v.OK() {
+			t.Errorf("CheckChunks() = %+v, want every part intact", v)
+		}
+
+		var out bytes.Buffer
+		if err := Join(&out, m, openParts(parts)); err != nil {
+			t.Fatalf("Join() error = %v", err)
+		}
+		if !bytes.Equal(out.Bytes(), data) {
+			t.Error("Join() did not restore the original file")
+		}
+	})
+
+	t.Run("missing and corrupted parts", func(t *testing.T) {
+		damaged := map[string][]byte{}
+		for name, body := range parts {
+			damaged[name] = body
+		}
+		delete(damaged, "backup.tar.002")
+		flipped := bytes.Clone(parts["backup.tar.003"])
+		flipped[0] ^= 0xff
+		damaged["backup.tar.003"] = flipped
+		damaged["backup.tar.004"] = parts["backup.tar.004"][:10]
+
+		v, err := CheckChunks(m, openParts(damaged))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v.Problems != 3 || v.FileProblem != "" {
+			t.Errorf("Problems = %d, FileProblem = %q; want 3 and none", v.Problems, v.FileProblem)
+		}
+		want := []string{
+			"",
+			"missing",
+			"corrupted: SHA-256 does not match",
+			fmt.Sprintf("corrupted: 10 bytes, expected %d", m.Chunks[3].Size),
+		}
+		for i, c := range v.Chunks {
+			if c.Problem != want[i] {
+				t.Errorf("%s problem = %q, want %q", c.Name, c.Problem, want[i])
+			}
+		}
+
+		if err := Join(io.Discard, m, openParts(damaged)); err == nil {
+			t.Error("Join() expected an error for a missing part")
+		}
+		delete(damaged, "backup.tar.004")
+		damaged["backup.tar.002"] = parts["backup.tar.002"]
+		if err := Join(io.Discard, m, openParts(damaged)); err == nil {
+			t.Error("Join() expected an error for a corrupted part")
+		}
+	})
+
+	t.Run("manifest whose file digest is wrong", func(t *testing.T) {
+		tampered := *m
+		tampered.SHA256 = strings.Repeat("0", 64)
+
+		// The mismatch is found before anything is joined.
+		v, err := CheckChunks(&tampered, openParts(parts))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v.OK() || v.Problems != 0 || !strings.Contains(v.FileProblem, "SHA-256 of the whole file") {
+			t.Errorf("CheckChunks() = %+v, want a whole-file mismatch", v)
+		}
+
+		err = Join(io.Discard, &tampered, openParts(parts))
+		if err == nil || !strings.Contains(err.Error(), "does not match the SHA-256") {
+			t.Errorf("Join() error = %v, want a digest mismatch", err)
+		}
+	})
+}
+
+func TestParseManifestErrors(t *testing.T) {
+	sum := strings.Repeat("ab", 32)
+	chunk := func(name string, off, size int64) string {
+		return fmt.Sprintf(`{"name": %q, "offset": %d, "size": %d, "sha256": %q}`, name, off, size, sum)
+	}
+	manifest := func(size int64, chunks ...string) string {
+		return fmt.Sprintf(`{"name": "f", "size": %d, "sha256": %q, "chunks": [%s]}`, size, sum, strings.Join(chunks, ","))
+	}
+
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{"not json", "hello", "reading manifest"},
+		{"no chunks", manifest(0), "between 1 and 999 parts"},
+		{"path in name", manifest(5, chunk("../f.001", 0, 5)), "not a plain file name"},
+		{"duplicate name", manifest(10, chunk("f.001", 0, 5), chunk("f.001", 5, 5)), "listed twice"},
+		{"gap", manifest(10, chunk("f.001", 0, 5), chunk("f.002", 6, 4)), "starts at 6, expected 5"},
+		{"size mismatch", manifest(11, chunk("f.001", 0, 5), chunk("f.002", 5, 5)), "add up to 10 bytes"},
+		{"bad digest", `{"name": "f", "size": 1, "sha256": "xyz", "chunks": []}`, "64 hex digits"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseManifest(strings.NewReader(tt.input))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ParseManifest() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    int64
+		wantErr bool
+	}{
+		{"1500", 1500, false},
+		{"64k", 64 << 10, false},
+		{"1.5MB", 3 << 19, false},
+		{"2 GiB", 2 << 30, false},
+		{"10 b", 10, false},
+		{"", 0, true},
+		{"0", 0, true},
+		{"0.1", 0, true},
+		{"0.5 b", 0, true},
+		{"0.001k", 1, false},
+		{"-5M", 0, true},
+		{"12 parsecs", 0, true},
+		{"MB", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseSize(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseSize(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseSize(%q) = %d, want %d", tt.input, got, tt.want)
+		}
+	}
+}
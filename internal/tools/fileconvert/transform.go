@@ -0,0 +1,267 @@
+package fileconvert
+
+import (
+	"bytes"
+	"unicode/utf8"
+
+	"golang.org/x/text/transform"
+)
+
+// runeRewriter is a stateful rewrite applied one rune at a time. rewrite
+// appends the replacement for r, whose encoding is raw, to out; flush appends
+// anything still held back at the end of the input.
+type runeRewriter interface {
+	rewrite(out []byte, r rune, raw []byte) []byte
+	flush(out []byte) []byte
+	reset()
+}
+
+// rewriter adapts a runeRewriter to a transform.Transformer. It never splits
+// a UTF-8 sequence across calls, and output that does not fit in dst is
+// kept and written on the next call.
+type rewriter struct {
+	rw      runeRewriter
+	buf     []byte
+	pending []byte
+	flushed bool
+}
+
+func newRewriter(rw runeRewriter) *rewriter {
+	return &rewriter{rw: rw}
+}
+
+func (t *rewriter) Reset() {
+	t.rw.reset()
+	t.pending = nil
+	t.flushed = false
+}
+
+func (t *rewriter) Transform(dst, src []byte, atEOF bool) (nDst, nSrc int, err error) {
+	for {
+		n := copy(dst[nDst:], t.pending)
+		nDst += n
+		t.pending = t.pending[n:]
+		if len(t.pending) > 0 {
+			return nDst, nSrc, transform.ErrShortDst
+		}
+
+		if nSrc == len(src) {
+			if !atEOF || t.flushed {
+				return nDst, nSrc, nil
+			}
+			t.flushed = true
+			t.buf = t.rw.flush(t.buf[:0])
+			t.pending = t.buf
+			continue
+		}
+
+		rest := src[nSrc:]
+		if !atEOF && !utf8.FullRune(rest) {
+			return nDst, nSrc, transform.ErrShortSrc
+		}
+		r, size := utf8.DecodeRune(rest)
+		t.buf = t.rw.rewrite(t.buf[:0], r, rest[:size])
+		t.pending = t.buf
+		nSrc += size
+	}
+}
+
+// crlfToLF replaces CRLF and lone CR line breaks with LF.
+type crlfToLF struct {
+	afterCR bool
+}
+
+func (c *crlfToLF) rewrite(out []byte, r rune, raw []byte) []byte {
+	afterCR := c.afterCR
+	c.afterCR = r == '\r'
+	switch {
+	case r == '\r':
+		return append(out, '\n')
+	case r == '\n' && afterCR:
+		return out
+	default:
+		return append(out, raw...)
+	}
+}
+
+func (c *crlfToLF) flush(out []byte) []byte { return out }
+func (c *crlfToLF) reset()                  { *c = crlfToLF{} }
+
+// lfToCRLF writes every line break, LF or lone CR, as CRLF.
+type lfToCRLF struct {
+	afterCR bool
+}
+
+func (c *lfToCRLF) rewrite(out []byte, r rune, raw []byte) []byte {
+	afterCR := c.afterCR
+	c.afterCR = r == '\r'
+	switch {
+	case r == '\r':
+		return append(out, '\r', '\n')
+	case r == '\n' && afterCR:
+		return out
+	case r == '\n':
+		return append(out, '\r', '\n')
+	default:
+		return append(out, raw...)
+	}
+}
+
+func (c *lfToCRLF) flush(out []byte) []byte { return out }
+func (c *lfToCRLF) reset()                  { *c = lfToCRLF{} }
+
+// expandTabs replaces each tab with spaces up to the next tab stop, counting
+// columns in runes from the start of each line.
+type expandTabs struct {
+	width int
+	col   int
+}
+
+func (e *expandTabs) rewrite(out []byte, r rune, raw []byte) []byte {
+	switch r {
+	case '\t':
+		n := e.width - e.col%e.width
+		e.col += n
+		return append(out, spaces(n)...)
+	case '\n', '\r':
+		e.col = 0
+	case '\ufeff':
+		// A byte order mark takes up no column.
+	default:
+		e.col++
+	}
+	return append(out, raw...)
+}
+
+func (e *expandTabs) flush(out []byte) []byte { return out }
+func (e *expandTabs) reset()                  { e.col = 0 }
+
+// maxHeld caps how many bytes of blanks a rewriter holds back or writes in
+// one go, keeping memory constant however long a run of blanks is.
+const maxHeld = 64 << 10
+
+// unexpandSpaces rewrites the leading indentation of each line with tabs,
+// like unexpand(1) without -a. Spaces that do not reach a tab stop are kept.
+// Only the width of the indentation is held back, never the blanks
+// themselves, and whole tab stops are written out once it passes maxHeld
+// columns.
+type unexpandSpaces struct {
+	width      int
+	col        int
+	pastIndent bool
+}
+
+func (u *unexpandSpaces) rewrite(out []byte, r rune, raw []byte) []byte {
+	if !u.pastIndent {
+		switch r {
+		case '\ufeff':
+			// A byte order mark before the indentation does not end it.
+			if u.col == 0 {
+				return append(out, raw...)
+			}
+		case ' ':
+			u.col++
+			return u.spill(out)
+		case '\t':
+			u.col += u.width - u.col%u.width
+			return u.spill(out)
+		}
+		out = u.flush(out)
+		u.pastIndent = true
+	}
+
+	if r == '\n' || r == '\r' {
+		u.pastIndent = false
+	}
+	return append(out, raw...)
+}
+
+func (u *unexpandSpaces) flush(out []byte) []byte {
+	out = append(out, bytes.Repeat([]byte{'\t'}, u.col/u.width)...)
+	out = append(out, spaces(u.col%u.width)...)
+	u.col = 0
+	return out
+}
+
+// spill writes the tabs for a very deep indentation early. Tab stops repeat
+// every width columns, so only the remainder needs to be kept.
+func (u *unexpandSpaces) spill(out []byte) []byte {
+	if u.col < maxHeld {
+		return out
+	}
+	out = append(out, bytes.Repeat([]byte{'\t'}, u.col/u.width)...)
+	u.col %= u.width
+	return out
+}
+
+func (u *unexpandSpaces) reset() { *u = unexpandSpaces{width: u.width} }
+
+// trimTrailing removes spaces and tabs at the end of each line, keeping the
+// line break itself. A run of blanks is held back until the next rune shows
+// whether it ends the line. Once more than maxHeld blanks are held they are
+// written out, so only the last maxHeld bytes of a longer trailing run are
+// removed.
+type trimTrailing struct {
+	blanks []byte
+}
+
+func (t *trimTrailing) rewrite(out []byte, r rune, raw []byte) []byte {
+	switch r {
+	case ' ', '\t':
+		if len(t.blanks) == maxHeld {
+			out = append(out, t.blanks...)
+			t.blanks = t.blanks[:0]
+		}
+		t.blanks = append(t.blanks, byte(r))
+		return out
+	case '\n', '\r':
+		t.blanks = t.blanks[:0]
+		return append(out, raw...)
+	}
+	out = append(out, t.blanks...)
+	t.blanks = t.blanks[:0]
+	return append(out, raw...)
+}
+
+func (t *trimTrailing) flush(out []byte) []byte {
+	t.blanks = t.blanks[:0]
+	return out
+}
+
+func (t *trimTrailing) reset() { t.blanks = t.blanks[:0] }
+
+// stripBOM removes a UTF-8 byte order mark from the start of the input.
+// Convert decodes UTF-16 and UTF-32 first, so their marks are removed too.
+type stripBOM struct {
+	started bool
+}
+
+var utf8BOM = []byte("\xef\xbb\xbf")
+
+func (s *stripBOM) Reset() { s.started = false }
+
+func (s *stripBOM) Transform(dst, src []byte, atEOF bool) (nDst, nSrc int, err error) {
+	if !s.started {
+		if !atEOF && len(src) < len(utf8BOM) && bytes.HasPrefix(utf8BOM, src) {
+			return 0, 0, transform.ErrShortSrc
+		}
+		s.started = true
+		if bytes.HasPrefix(src, utf8BOM) {
+			nSrc = len(utf8BOM)
+		}
+	}
+
+	n := copy(dst, src[nSrc:])
+	nDst, nSrc = n, nSrc+n
+	if nSrc < len(src) {
+		err = transform.ErrShortDst
+	}
+	return nDst, nSrc, err
+}
+
+var spaceRun = bytes.Repeat([]byte{' '}, MaxTabWidth)
+
+// spaces returns n spaces, n at most MaxTabWidth.
+func spaces(n int) []byte {
+	return spaceRun[:n]
+}
@@ -0,0 +1,229 @@
+package fileconvert
+
+import (
+	"bytes"
+	"io"
+	"math/rand"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+)
+
+// streamModes covers every mode, with the transcoding modes on input they
+// can decode.
+var streamModes = []Options{
+	{Mode: ModeUpper},
+	{Mode: ModeLower},
+	{Mode: ModeTitle},
+	{Mode: ModeCRLFToLF},
+	{Mode: ModeLFToCRLF},
+	{Mode: ModeTabsToSpaces, TabWidth: 3},
+	{Mode: ModeSpacesToTabs, TabWidth: 4},
+	{Mode: ModeTrimTrailing},
+	{Mode: ModeStripBOM},
+	{Mode: ModeTranscodeUTF8, Charset: CharsetWindows1252},
+	{Mode: ModeTranscodeUTF8},
+}
+
+// randomText builds text from pieces that exercise line endings, blanks,
+// multi-byte runes and a leading BOM.
+func randomText(rng *rand.Rand, n int) string {
+	pieces := []string{"a", "Word", " ", "  ", "\t", "\n", "\r\n", "\r", "é", "ß", "€", "😀", "ǆ"}
+	var b strings.Builder
+	if rng.Intn(2) == 0 {
+		b.WriteString("\xef\xbb\xbf")
+	}
+	for i := 0; i < n; i++ {
+		b.WriteString(pieces[rng.Intn(len(pieces))])
+	}
+	return b.String()
+}
+
+func convertAll(t *testing.T, r io.Reader, opts Options) string {
+	t.Helper()
+	converted, err := Convert(r, opts)
+	if err != nil {
+		t.Fatalf("Convert(%+v) unexpected error: %v", opts, err)
+	}
+	out, err := io.ReadAll(converted)
+	if err != nil {
+		t.Fatalf("Convert(%+v) read error: %v", opts, err)
+	}
+	return string(out)
+}
+
+func TestConvertStreamingMatchesWhole(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 50; i++ {
+		input := randomText(rng, rng.Intn(2000))
+		for _, opts := range streamModes {
+			whole := convertAll(t, strings.NewReader(input), opts)
+			oneByte := convertAll(t, iotest.OneByteReader(strings.NewReader(input)), opts)
+			if whole != oneByte {
+				t.Fatalf("%+v: one-byte reads gave %q, want %q (input %q)", opts, oneByte, whole, input)
+			}
+		}
+	}
+}
+
+func TestConvertStreamingUTF16(t *testing.T) {
+	// Decoding and re-encoding must not depend on how the input is split.
+	enc := unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM)
+	rng := rand.New(rand.NewSource(2))
+	for i := 0; i < 20; i++ {
+		input, err := enc.NewEncoder().String("\ufeff" + randomText(rng, rng.Intn(2000)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, opts := range streamModes {
+			if opts.Mode == ModeTranscodeUTF8 {
+				continue
+			}
+			whole := convertAll(t, strings.NewReader(input), opts)
+			oneByte := convertAll(t, iotest.OneByteReader(strings.NewReader(input)), opts)
+			if whole != oneByte {
+				t.Fatalf("%+v: one-byte reads gave %q, want %q", opts, oneByte, whole)
+			}
+		}
+	}
+}
+
+func TestRewriterShortDst(t *testing.T) {
+	// Feed the transformer one byte of destination at a time so every
+	// replacement has to be split across calls.
+	tr := newRewriter(&expandTabs{width: 8})
+	src := []byte("\tx\t\té")
+
+	var out []byte
+	dst := make([]byte, 1)
+	for nSrc := 0; ; {
+		nDst, n, err := tr.Transform(dst, src[nSrc:], true)
+		out = append(out, dst[:nDst]...)
+		nSrc += n
+		if err == nil {
+			break
+		}
+		if err != transform.ErrShortDst {
+			t.Fatalf("Transform() unexpected error: %v", err)
+		}
+	}
+
+	want := "        x" + strings.Repeat(" ", 7) + strings.Repeat(" ", 8) + "é"
+	if string(out) != want {
+		t.Errorf("got %q, want %q", out, want)
+	}
+}
+
+func TestConvertStreamsBeforeEOF(t *testing.T) {
+	// Converted lines must be readable while the input is still open.
+	for _, opts := range []Options{{Mode: ModeUpper}, {Mode: ModeCRLFToLF}, {Mode: ModeTrimTrailing}, {Mode: ModeSpacesToTabs}} {
+		pr, pw := io.Pipe()
+		converted, err := Convert(pr, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		go pw.Write([]byte("    line one  \r\nline two"))
+
+		got := make(chan string, 1)
+		go func() {
+			buf := make([]byte, 64)
+			n, _ := converted.Read(buf)
+			got <- string(buf[:n])
+		}()
+
+		select {
+		case s := <-got:
+			if !strings.HasPrefix(strings.ToLower(s), "\tline one") && !strings.HasPrefix(strings.ToLower(s), "    line one") {
+				t.Errorf("%s: first read = %q", opts.Mode, s)
+			}
+		case <-time.After(2 * time.Second):
+			t.Errorf("%s: no output before the input was closed", opts.Mode)
+		}
+		pw.Close()
+	}
+}
+
+func TestConvertLargeInput(t *testing.T) {
+	// 8MB through the streaming path, compared against a direct conversion.
+	line := "\tsome text   \r\n"
+	input := strings.Repeat(line, 8<<20/len(line))
+
+	out := convertAll(t, strings.NewReader(input), Options{Mode: ModeTrimTrailing})
+	if want := strings.Repeat("\tsome text\r\n", 8<<20/len(line)); out != want {
+		t.Errorf("output length %d, want %d", len(out), len(want))
+	}
+}
+
+func TestRewritersBoundBlanks(t *testing.T) {
+	// 8MB of blanks must never be held back or written out in one piece.
+	const n = 8 << 20
+	tests := []struct {
+		name    string
+		rw      runeRewriter
+		end     rune
+		wantLen int
+	}{
+		{"trim trailing before text", &trimTrailing{}, 'x', n + 1},
+		{"trim trailing at line end", &trimTrailing{}, '\n', n - maxHeld + 1},
+		{"spaces to tabs", &unexpandSpaces{width: 4}, 'x', n/4 + 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var total, largest int
+			var out []byte
+			for i := 0; i <= n; i++ {
+				r, raw := ' ', []byte{' '}
+				if i == n {
+					r, raw = tt.end, []byte(string(tt.end))
+				}
+				out = tt.rw.rewrite(out[:0], r, raw)
+				total += len(out)
+				largest = max(largest, len(out))
+			}
+
+			if total != tt.wantLen {
+				t.Errorf("wrote %d bytes, want %d", total, tt.wantLen)
+			}
+			if largest > maxHeld+1 {
+				t.Errorf("wrote %d bytes in one piece, want at most %d", largest, maxHeld+1)
+			}
+			if tr, ok := tt.rw.(*trimTrailing); ok && cap(tr.blanks) > 2*maxHeld {
+				t.Errorf("held back %d bytes of blanks", cap(tr.blanks))
+			}
+		})
+	}
+}
+
+func TestTrimPartialRune(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"abc", "abc"},
+		{"ab\xc3", "ab"},
+		{"ab\xe2\x82", "ab"},
+		{"ab\xf0\x9f\x98", "ab"},
+		{"ab€", "ab€"},
+		{"ab\xff", "ab\xff"},
+	}
+	for _, tt := range tests {
+		if got := string(trimPartialRune([]byte(tt.input))); got != tt.expected {
+			t.Errorf("trimPartialRune(%q) = %q, want %q", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestConvertAutoDetectAcrossSniffBoundary(t *testing.T) {
+	// A multi-byte rune straddling the sniff length must not make valid
+	// UTF-8 look like Windows-1252.
+	input := strings.Repeat("a", sniffLen-1) + "é" + strings.Repeat("b", 10)
+	if out := convertAll(t, bytes.NewReader([]byte(input)), Options{Mode: ModeTranscodeUTF8}); out != input {
+		t.Errorf("UTF-8 input was changed by auto-detection")
+	}
+}
@@ -0,0 +1,37 @@
+package fileinfo
+
+// DiffRanges compares a and b byte by byte and returns the ranges where
+// they differ, up to limit of them, along with the total number of ranges
+// and of differing bytes. When one input is longer, its extra bytes form a
+// final range.
+func DiffRanges(a, b []byte, limit int) (ranges []Range, total int, differing int64) {
+	add := func(r Range) {
+		if total < limit {
+			ranges = append(ranges, r)
+		}
+		total++
+		differing += r.Len()
+	}
+
+	n := min(len(a), len(b))
+	start := -1
+	for i := 0; i < n; i++ {
+		switch {
+		case a[i] != b[i] && start < 0:
+			start = i
+		case a[i] == b[i] && start >= 0:
+			add(Range{Start: int64(start), End: int64(i)})
+			start = -1
+		}
+	}
+
+	end := max(len(a), len(b))
+	switch {
+	case start >= 0:
+		// A difference running into the tail of the longer input.
+		add(Range{Start: int64(start), End: int64(end)})
+	case n < end:
+		add(Range{Start: int64(n), End: int64(end)})
+	}
+	return ranges, total, differing
+}
@@ -0,0 +1,35 @@
+package fileinfo
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffRanges(t *testing.T) {
+	tests := []struct {
+		name          string
+		a, b          string
+		limit         int
+		want          []Range
+		wantTotal     int
+		wantDiffering int64
+	}{
+		{"identical", "same bytes", "same bytes", 10, nil, 0, 0},
+		{"both empty", "", "", 10, nil, 0, 0},
+		{"single byte", "abcdef", "abXdef", 10, []Range{{2, 3}}, 1, 1},
+		{"several ranges", "aaaaaaaa", "XaaYYaaZ", 10, []Range{{0, 1}, {3, 5}, {7, 8}}, 3, 4},
+		{"b longer", "abc", "abcde", 10, []Range{{3, 5}}, 1, 2},
+		{"a longer", "abcdef", "abc", 10, []Range{{3, 6}}, 1, 3},
+		{"difference runs into tail", "abcd", "abXYZ!", 10, []Range{{2, 6}}, 1, 4},
+		{"one empty", "", "xyz", 10, []Range{{0, 3}}, 1, 3},
+		{"limited", "aaaaaa", "XaXaXa", 2, []Range{{0, 1}, {2, 3}}, 3, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, total, differing := DiffRanges([]byte(tt.a), []byte(tt.b), tt.limit)
+			if !reflect.DeepEqual(got, tt.want) || total != tt.wantTotal || differing != tt.wantDiffering {
+				t.Errorf("DiffRanges() = %v, %d, %d, want %v, %d, %d", got, total, differing, tt.want, tt.wantTotal, tt.wantDiffering)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package fileinfo
+
+import "math"
+
+// byteCounter is an io.Writer that counts byte values for Entropy.
+type byteCounter struct {
+	counts [256]int64
+	total  int64
+}
+
+func (c *byteCounter) Write(p []byte) (int, error) {
+	for _, b := range p {
+		c.counts[b]++
+	}
+	c.total += int64(len(p))
+	return len(p), nil
+}
+
+// entropy returns the Shannon entropy of the counted bytes in bits per
+// byte, from 0 for a single repeated value to 8 for uniformly random data.
+func (c *byteCounter) entropy() float64 {
+	if c.total == 0 {
+		return 0
+	}
+	var h float64
+	for _, n := range c.counts {
+		if n == 0 {
+			continue
+		}
+		p := float64(n) / float64(c.total)
+		h -= p * math.Log2(p)
+	}
+	return h
+}
+
+// Entropy returns the Shannon entropy of data in bits per byte.
+func Entropy(data []byte) float64 {
+	var c byteCounter
+	c.Write(data)
+	return c.entropy()
+}
+
+// DescribeEntropy gives a rough reading of an entropy value.
+func DescribeEntropy(bits float64) string {
+	switch {
+	case bits == 0:
+		return "no variation"
+	case bits < 5:
+		return "typical of text or structured data"
+	case bits < 7.5:
+		return "typical of binary data such as executables"
+	default:
+		return "typical of compressed or encrypted data"
+	}
+}
@@ -0,0 +1,50 @@
+package fileinfo
+
+import (
+	"bytes"
+	"math"
+	"testing"
+)
+
+func TestEntropy(t *testing.T) {
+	all := make([]byte, 256*4)
+	for i := range all {
+		all[i] = byte(i)
+	}
+
+	tests := []struct {
+		name  string
+		input []byte
+		want  float64
+	}{
+		{"empty", nil, 0},
+		{"single value", bytes.Repeat([]byte{'a'}, 100), 0},
+		{"two values", []byte("abababab"), 1},
+		{"four values", []byte("abcdabcd"), 2},
+		{"every byte value", all, 8},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Entropy(tt.input); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("Entropy() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDescribeEntropy(t *testing.T) {
+	tests := []struct {
+		bits float64
+		want string
+	}{
+		{0, "no variation"},
+		{4.2, "typical of text or structured data"},
+		{6, "typical of binary data such as executables"},
+		{7.99, "typical of compressed or encrypted data"},
+	}
+	for _, tt := range tests {
+		if got := DescribeEntropy(tt.bits); got != tt.want {
+			t.Errorf("DescribeEntropy(%v) = %q, want %q", tt.bits, got, tt.want)
+		}
+	}
+}
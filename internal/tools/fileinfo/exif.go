@@ -0,0 +1,489 @@
+package fileinfo
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ExifField is one decoded EXIF tag.
+type ExifField struct {
+	Name  string
+	Value string
+}
+
+var errInvalidTIFF = errors.New("invalid TIFF structure")
+
+// TIFF field types.
+const (
+	tiffByte      = 1
+	tiffASCII     = 2
+	tiffShort     = 3
+	tiffLong      = 4
+	tiffRational  = 5
+	tiffUndefined = 7
+	tiffSLong     = 9
+	tiffSRational = 10
+)
+
+var tiffTypeSizes = map[uint16]uint64{1: 1, 2: 1, 3: 2, 4: 4, 5: 8, 6: 1, 7: 1, 8: 2, 9: 4, 10: 8, 11: 4, 12: 8}
+
+// Pointers from IFD0 to the Exif and GPS sub-directories.
+const (
+	exifIFDPointer = 0x8769
+	gpsIFDPointer  = 0x8825
+)
+
+type ifdEntry struct {
+	tag   uint16
+	typ   uint16
+	count uint32
+	value []byte
+}
+
+type tiffReader struct {
+	data  []byte
+	order binary.ByteOrder
+}
+
+func newTIFFReader(data []byte) (*tiffReader, error) {
+	if len(data) < 8 {
+		return nil, errInvalidTIFF
+	}
+	var order binary.ByteOrder
+	switch string(data[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return nil, errInvalidTIFF
+	}
+	if order.Uint16(data[2:]) != 42 {
+		return nil, errInvalidTIFF
+	}
+	return &tiffReader{data: data, order: order}, nil
+}
+
+// ifd reads the directory at off. Entries whose values fall outside the
+// data are skipped.
+func (t *tiffReader) ifd(off uint32) ([]ifdEntry, error) {
+	if uint64(off)+2 > uint64(len(t.data)) {
+		return nil, errInvalidTIFF
+	}
+	n := int(t.order.Uint16(t.data[off:]))
+	start := int(off) + 2
+	if start+n*12 > len(t.data) {
+		return nil, errInvalidTIFF
+	}
+
+	entries := make([]ifdEntry, 0, n)
+	for i := range n {
+		e := t.data[start+i*12 : start+i*12+12]
+		entry := ifdEntry{
+			tag:   t.order.Uint16(e),
+			typ:   t.order.Uint16(e[2:]),
+			count: t.order.Uint32(e[4:]),
+		}
+		size := tiffTypeSizes[entry.typ] * uint64(entry.count)
+		switch {
+		case size == 0:
+			continue
+		case size <= 4:
+			entry.value = e[8 : 8+size]
+		default:
+			valueOff := uint64(t.order.Uint32(e[8:]))
+			if valueOff+size > uint64(len(t.data)) {
+				continue
+			}
+			entry.value = t.data[valueOff : valueOff+size]
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// uints decodes BYTE, SHORT and LONG values.
+func (t *tiffReader) uints(e ifdEntry) []uint64 {
+	var out []uint64
+	for i := range int(e.count) {
+		switch e.typ {
+		case tiffByte, tiffUndefined:
+			out = append(out, uint64(e.value[i]))
+		case tiffShort:
+			out = append(out, uint64(t.order.Uint16(e.value[i*2:])))
+		case tiffLong:
+			out = append(out, uint64(t.order.Uint32(e.value[i*4:])))
+		case tiffSLong:
+			out = append(out, uint64(int32(t.order.Uint32(e.value[i*4:]))))
+		default:
+			return nil
+		}
+	}
+	return out
+}
+
+// rationals decodes RATIONAL and SRATIONAL values. A zero denominator
+// yields zero.
+func (t *tiffReader) rationals(e ifdEntry) []float64 {
+	if e.typ != tiffRational && e.typ != tiffSRational {
+		return nil
+	}
+	out := make([]float64, e.count)
+	for i := range out {
+		num, den := t.order.Uint32(e.value[i*8:]), t.order.Uint32(e.value[i*8+4:])
+		if den == 0 {
+			continue
+		}
+		if e.typ == tiffSRational {
+			out[i] = float64(int32(num)) / float64(int32(den))
+		} else {
+			out[i] = float64(num) / float64(den)
+		}
+	}
+	return out
+}
+
+// exifTag names a tag and optionally formats its value; tags without a
+// formatter use formatValue.
+type exifTag struct {
+	name   string
+	format func(t *tiffReader, e ifdEntry) string
+}
+
+var ifd0Tags = map[uint16]exifTag{
+	0x010e: {"Image Description", nil},
+	0x010f: {"Camera Make", nil},
+	0x0110: {"Camera Model", nil},
+	0x0112: {"Orientation", formatOrientation},
+	0x011a: {"X Resolution", nil},
+	0x011b: {"Y Resolution", nil},
+	0x0128: {"Resolution Unit", formatResolutionUnit},
+	0x0131: {"Software", nil},
+	0x0132: {"Date Modified", nil},
+	0x013b: {"Artist", nil},
+	0x8298: {"Copyright", nil},
+}
+
+var exifSubTags = map[uint16]exifTag{
+	0x829a: {"Exposure Time", formatExposure},
+	0x829d: {"F-Number", formatFNumber},
+	0x8827: {"ISO", nil},
+	0x9003: {"Date Taken", nil},
+	0x9004: {"Date Digitized", nil},
+	0x9010: {"Time Zone Offset", nil},
+	0x9204: {"Exposure Bias", formatEV},
+	0x9209: {"Flash", formatFlash},
+	0x920a: {"Focal Length", formatFocalLength},
+	0xa002: {"Pixel Width", nil},
+	0xa003: {"Pixel Height", nil},
+	0xa405: {"Focal Length (35mm)", formatFocalLength},
+	0xa433: {"Lens Make", nil},
+	0xa434: {"Lens Model", nil},
+}
+
+var gpsTags = map[uint16]exifTag{
+	0x0002: {"GPS Latitude", formatDegrees},
+	0x0004: {"GPS Longitude", formatDegrees},
+	0x0006: {"GPS Altitude", formatAltitude},
+	0x0007: {"GPS Time (UTC)", formatGPSTime},
+	0x001d: {"GPS Date", nil},
+}
+
+// ParseExif decodes the common tags of an EXIF block, which is a TIFF
+// structure, covering the main image directory and its Exif and GPS
+// sub-directories. Unknown tags are left out. When the GPS directory holds
+// a position it is also given in decimal degrees.
+func ParseExif(data []byte) ([]ExifField, error) {
+	t, err := newTIFFReader(data)
+	if err != nil {
+		return nil, err
+	}
+	ifd0, err := t.ifd(t.order.Uint32(data[4:]))
+	if err != nil {
+		return nil, err
+	}
+
+	var fields []ExifField
+	add := func(entries []ifdEntry, tags map[uint16]exifTag) {
+		for _, e := range entries {
+			tag, ok := tags[e.tag]
+			if !ok {
+				continue
+			}
+			var v string
+			if tag.format != nil {
+				v = tag.format(t, e)
+			} else {
+				v = formatValue(t, e)
+			}
+			if v != "" {
+				fields = append(fields, ExifField{Name: tag.name, Value: v})
+			}
+		}
+	}
+	add(ifd0, ifd0Tags)
+
+	for _, e := range ifd0 {
+		if e.tag != exifIFDPointer && e.tag != gpsIFDPointer {
+			continue
+		}
+		offsets := t.uints(e)
+		if len(offsets) != 1 {
+			continue
+		}
+		sub, err := t.ifd(uint32(offsets[0]))
+		if err != nil {
+			continue
+		}
+		if e.tag == exifIFDPointer {
+			add(sub, exifSubTags)
+			continue
+		}
+		add(sub, gpsTags)
+		if pos := gpsPosition(t, sub); pos != "" {
+			fields = append(fields, ExifField{Name: "GPS Position", Value: pos})
+		}
+	}
+	return fields, nil
+}
+
+// formatValue renders a value by its TIFF type.
+func formatValue(t *tiffReader, e ifdEntry) string {
+	switch e.typ {
+	case tiffASCII:
+		s, _, _ := strings.Cut(string(e.value), "\x00")
+		return strings.TrimSpace(s)
+	case tiffUndefined, tiffByte:
+		if printable(e.value) {
+			return strings.TrimSpace(strings.TrimRight(string(e.value), "\x00"))
+		}
+		return fmt.Sprintf("(%d bytes)", len(e.value))
+	case tiffRational, tiffSRational:
+		var parts []string
+		for _, r := range t.rationals(e) {
+			parts = append(parts, strconv.FormatFloat(r, 'f', -1, 64))
+		}
+		return strings.Join(parts, ", ")
+	default:
+		var parts []string
+		for _, n := range t.uints(e) {
+			if e.typ == tiffSLong {
+				parts = append(parts, strconv.FormatInt(int64(n), 10))
+			} else {
+				parts = append(parts, strconv.FormatUint(n, 10))
+			}
+		}
+		return strings.Join(parts, ", ")
+	}
+}
+
+func printable(b []byte) bool {
+	b = bytes.TrimRight(b, "\x00")
+	if len(b) == 0 {
+		return false
+	}
+	for _, c := range b {
+		if c < 0x20 || c > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+var orientations = map[uint64]string{
+	1: "Normal", 2: "Mirrored horizontally", 3: "Rotated 180°", 4: "Mirrored vertically",
+	5: "Mirrored horizontally, rotated 270°", 6: "Rotated 90° clockwise",
+	7: "Mirrored horizontally, rotated 90°", 8: "Rotated 90° counter-clockwise",
+}
+
+func formatOrientation(t *tiffReader, e ifdEntry) string {
+	v := t.uints(e)
+	if len(v) == 1 {
+		if s, ok := orientations[v[0]]; ok {
+			return s
+		}
+	}
+	return formatValue(t, e)
+}
+
+func formatResolutionUnit(t *tiffReader, e ifdEntry) string {
+	v := t.uints(e)
+	if len(v) == 1 {
+		switch v[0] {
+		case 2:
+			return "inches"
+		case 3:
+			return "centimetres"
+		}
+	}
+	return formatValue(t, e)
+}
+
+func formatExposure(t *tiffReader, e ifdEntry) string {
+	r := t.rationals(e)
+	if len(r) != 1 || r[0] <= 0 {
+		return formatValue(t, e)
+	}
+	if r[0] < 1 {
+		return fmt.Sprintf("1/%d s", int(math.Round(1/r[0])))
+	}
+	return strconv.FormatFloat(r[0], 'f', -1, 64) + " s"
+}
+
+func formatFNumber(t *tiffReader, e ifdEntry) string {
+	r := t.rationals(e)
+	if len(r) != 1 {
+		return formatValue(t, e)
+	}
+	return "f/" + strconv.FormatFloat(r[0], 'f', 1, 64)
+}
+
+func formatEV(t *tiffReader, e ifdEntry) string {
+	r := t.rationals(e)
+	if len(r) != 1 {
+		return formatValue(t, e)
+	}
+	return strconv.FormatFloat(r[0], 'f', -1, 64) + " EV"
+}
+
+func formatFocalLength(t *tiffReader, e ifdEntry) string {
+	if r := t.rationals(e); len(r) == 1 {
+		return strconv.FormatFloat(r[0], 'f', -1, 64) + " mm"
+	}
+	if v := t.uints(e); len(v) == 1 {
+		return strconv.FormatUint(v[0], 10) + " mm"
+	}
+	return formatValue(t, e)
+}
+
+func formatFlash(t *tiffReader, e ifdEntry) string {
+	v := t.uints(e)
+	if len(v) != 1 {
+		return formatValue(t, e)
+	}
+	if v[0]&1 != 0 {
+		return "Fired"
+	}
+	return "Did not fire"
+}
+
+// formatDegrees renders degrees, minutes and seconds.
+func formatDegrees(t *tiffReader, e ifdEntry) string {
+	r := t.rationals(e)
+	if len(r) != 3 {
+		return formatValue(t, e)
+	}
+	return fmt.Sprintf("%g° %g′ %.2f″", r[0], r[1], r[2])
+}
+
+func formatAltitude(t *tiffReader, e ifdEntry) string {
+	r := t.rationals(e)
+	if len(r) != 1 {
+		return formatValue(t, e)
+	}
+	return strconv.FormatFloat(r[0], 'f', -1, 64) + " m"
+}
+
+func formatGPSTime(t *tiffReader, e ifdEntry) string {
+	r := t.rationals(e)
+	if len(r) != 3 {
+		return formatValue(t, e)
+	}
+	return fmt.Sprintf("%02d:%02d:%02d", int(r[0]), int(r[1]), int(r[2]))
+}
+
+// gpsPosition combines the latitude, longitude and their N/S and E/W
+// references into signed decimal degrees.
+func gpsPosition(t *tiffReader, entries []ifdEntry) string {
+	var lat, lon []float64
+	var latRef, lonRef string
+	for _, e := range entries {
+		switch e.tag {
+		case 0x0001:
+			latRef = formatValue(t, e)
+		case 0x0002:
+			lat = t.rationals(e)
+		case 0x0003:
+			lonRef = formatValue(t, e)
+		case 0x0004:
+			lon = t.rationals(e)
+		}
+	}
+	if len(lat) != 3 || len(lon) != 3 {
+		return ""
+	}
+	latDeg := lat[0] + lat[1]/60 + lat[2]/3600
+	lonDeg := lon[0] + lon[1]/60 + lon[2]/3600
+	if latRef == "S" {
+		latDeg = -latDeg
+	}
+	if lonRef == "W" {
+		lonDeg = -lonDeg
+	}
+	return fmt.Sprintf("%.6f, %.6f", latDeg, lonDeg)
+}
+
+// findExif returns the EXIF block embedded in the head of a JPEG, PNG,
+// WebP or TIFF file, or nil when there is none.
+func findExif(head []byte, t Type) []byte {
+	switch t.MIME {
+	case "image/jpeg":
+		// Walk the marker segments up to the start of the image data.
+		for i := 2; i+4 <= len(head); {
+			if head[i] != 0xff {
+				return nil
+			}
+			marker := head[i+1]
+			if marker == 0xda || marker == 0xd9 {
+				return nil
+			}
+			size := int(binary.BigEndian.Uint16(head[i+2:]))
+			end := i + 2 + size
+			if size < 2 || end > len(head) {
+				return nil
+			}
+			payload := head[i+4 : end]
+			if marker == 0xe1 && bytes.HasPrefix(payload, []byte("Exif\x00\x00")) {
+				return payload[6:]
+			}
+			i = end
+		}
+
+	case "image/png":
+		for i := 8; i+8 <= len(head); {
+			size := int(binary.BigEndian.Uint32(head[i:]))
+			kind := string(head[i+4 : i+8])
+			end := i + 8 + size
+			if size < 0 || end > len(head) || kind == "IDAT" || kind == "IEND" {
+				return nil
+			}
+			if kind == "eXIf" {
+				return head[i+8 : end]
+			}
+			i = end + 4 // skip the CRC
+		}
+
+	case "image/webp":
+		for i := 12; i+8 <= len(head); {
+			kind := string(head[i : i+4])
+			size := int(binary.LittleEndian.Uint32(head[i+4:]))
+			end := i + 8 + size
+			if size < 0 || end > len(head) {
+				return nil
+			}
+			if kind == "EXIF" {
+				return bytes.TrimPrefix(head[i+8:end], []byte("Exif\x00\x00"))
+			}
+			i = end + size%2
+		}
+
+	case "image/tiff":
+		return head
+	}
+	return nil
+}
@@ -0,0 +1,225 @@
+package fileinfo
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+// testOrder is implemented by binary.LittleEndian and binary.BigEndian.
+type testOrder interface {
+	binary.ByteOrder
+	binary.AppendByteOrder
+}
+
+type tiffEntry struct {
+	tag   uint16
+	typ   uint16
+	count uint32
+	data  []byte
+}
+
+func asciiEntry(tag uint16, s string) tiffEntry {
+	return tiffEntry{tag, tiffASCII, uint32(len(s) + 1), append([]byte(s), 0)}
+}
+
+func shortEntry(order testOrder, tag uint16, v uint16) tiffEntry {
+	return tiffEntry{tag, tiffShort, 1, order.AppendUint16(nil, v)}
+}
+
+func rationalEntry(order testOrder, tag uint16, pairs ...uint32) tiffEntry {
+	var data []byte
+	for _, v := range pairs {
+		data = order.AppendUint32(data, v)
+	}
+	return tiffEntry{tag, tiffRational, uint32(len(pairs) / 2), data}
+}
+
+// buildTIFF lays out a TIFF structure with IFD0 followed by the optional
+// Exif and GPS directories, adding the pointers to them to IFD0, and then
+// every value too large to be stored inline.
+func buildTIFF(order testOrder, ifd0, exif, gps []tiffEntry) []byte {
+	ifdSize := func(entries []tiffEntry) int { return 2 + 12*len(entries) + 4 }
+
+	ifds := [][]tiffEntry{ifd0}
+	pointers := []uint16{}
+	if exif != nil {
+		ifds, pointers = append(ifds, exif), append(pointers, exifIFDPointer)
+	}
+	if gps != nil {
+		ifds, pointers = append(ifds, gps), append(pointers, gpsIFDPointer)
+	}
+	for _, p := range pointers {
+		ifds[0] = append(ifds[0], tiffEntry{p, tiffLong, 1, make([]byte, 4)})
+	}
+
+	offsets := []int{8}
+	for _, entries := range ifds {
+		offsets = append(offsets, offsets[len(offsets)-1]+ifdSize(entries))
+	}
+	for i := range pointers {
+		ifds[0][len(ifds[0])-len(pointers)+i].data = order.AppendUint32(nil, uint32(offsets[i+1]))
+	}
+
+	out := make([]byte, 8)
+	if order == binary.LittleEndian {
+		copy(out, "II")
+	} else {
+		copy(out, "MM")
+	}
+	order.PutUint16(out[2:], 42)
+	order.PutUint32(out[4:], 8)
+
+	dataOff := offsets[len(offsets)-1]
+	var extra []byte
+	for _, entries := range ifds {
+		out = order.AppendUint16(out, uint16(len(entries)))
+		for _, e := range entries {
+			out = order.AppendUint16(out, e.tag)
+			out = order.AppendUint16(out, e.typ)
+			out = order.AppendUint32(out, e.count)
+			if len(e.data) <= 4 {
+				out = append(out, e.data...)
+				out = append(out, make([]byte, 4-len(e.data))...)
+				continue
+			}
+			out = order.AppendUint32(out, uint32(dataOff+len(extra)))
+			extra = append(extra, e.data...)
+		}
+		out = order.AppendUint32(out, 0)
+	}
+	return append(out, extra...)
+}
+
+func sampleExif(order testOrder) []byte {
+	return buildTIFF(order,
+		[]tiffEntry{
+			asciiEntry(0x010f, "Canon"),
+			asciiEntry(0x0110, "EOS R5"),
+			shortEntry(order, 0x0112, 6),
+			shortEntry(order, 0x9999, 1), // unknown, left out
+		},
+		[]tiffEntry{
+			rationalEntry(order, 0x829a, 1, 200),
+			rationalEntry(order, 0x829d, 28, 10),
+			shortEntry(order, 0x8827, 400),
+			asciiEntry(0x9003, "2024:05:01 12:30:00"),
+			shortEntry(order, 0x9209, 0x19),
+			rationalEntry(order, 0x920a, 50, 1),
+		},
+		[]tiffEntry{
+			asciiEntry(0x0001, "N"),
+			rationalEntry(order, 0x0002, 48, 1, 51, 1, 3024, 100),
+			asciiEntry(0x0003, "W"),
+			rationalEntry(order, 0x0004, 2, 1, 17, 1, 4000, 100),
+			rationalEntry(order, 0x0006, 355, 10),
+		},
+	)
+}
+
+func TestParseExif(t *testing.T) {
+	want := []ExifField{
+		{"Camera Make", "Canon"},
+		{"Camera Model", "EOS R5"},
+		{"Orientation", "Rotated 90° clockwise"},
+		{"Exposure Time", "1/200 s"},
+		{"F-Number", "f/2.8"},
+		{"ISO", "400"},
+		{"Date Taken", "2024:05:01 12:30:00"},
+		{"Flash", "Fired"},
+		{"Focal Length", "50 mm"},
+		{"GPS Latitude", "48° 51′ 30.24″"},
+		{"GPS Longitude", "2° 17′ 40.00″"},
+		{"GPS Altitude", "35.5 m"},
+		{"GPS Position", "48.858400, -2.294444"},
+	}
+
+	for _, order := range []testOrder{binary.LittleEndian, binary.BigEndian} {
+		t.Run(order.String(), func(t *testing.T) {
+			got, err := ParseExif(sampleExif(order))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != len(want) {
+				t.Fatalf("got %d fields, want %d: %v", len(got), len(want), got)
+			}
+			for i := range want {
+				if got[i] != want[i] {
+					t.Errorf("field %d = %+v, want %+v", i, got[i], want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParseExifInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		input []byte
+	}{
+		{"too short", []byte("II*")},
+		{"bad byte order", []byte("XX*\x00\x08\x00\x00\x00")},
+		{"bad magic", []byte("II\x2b\x00\x08\x00\x00\x00")},
+		{"IFD offset out of range", []byte("II*\x00\xff\x00\x00\x00")},
+		{"truncated IFD", []byte("II*\x00\x08\x00\x00\x00\x05\x00")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseExif(tt.input); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+
+	// Values pointing past the end are skipped rather than failing.
+	data := buildTIFF(binary.LittleEndian, []tiffEntry{asciiEntry(0x010f, "A long camera make")}, nil, nil)
+	fields, err := ParseExif(data[:len(data)-4])
+	if err != nil || len(fields) != 0 {
+		t.Errorf("ParseExif(truncated value) = %v, %v", fields, err)
+	}
+}
+
+func TestFindExif(t *testing.T) {
+	exif := sampleExif(binary.LittleEndian)
+
+	app1 := append([]byte("Exif\x00\x00"), exif...)
+	jpeg := []byte("\xff\xd8\xff\xe0\x00\x04\x00\x00\xff\xe1")
+	jpeg = binary.BigEndian.AppendUint16(jpeg, uint16(len(app1)+2))
+	jpeg = append(jpeg, app1...)
+	jpeg = append(jpeg, "\xff\xda\x00\x02"...)
+
+	png := []byte("\x89PNG\r\n\x1a\n")
+	png = binary.BigEndian.AppendUint32(png, 0)
+	png = append(png, "sRGB\x00\x00\x00\x00"...)
+	png = binary.BigEndian.AppendUint32(png, uint32(len(exif)))
+	png = append(png, "eXIf"...)
+	png = append(png, exif...)
+	png = append(png, "CRC!"...)
+
+	webp := []byte("RIFF\x00\x00\x00\x00WEBPVP8X\x0a\x00\x00\x00")
+	webp = append(webp, make([]byte, 10)...)
+	webp = append(webp, "EXIF"...)
+	webp = binary.LittleEndian.AppendUint32(webp, uint32(len(exif)))
+	webp = append(webp, exif...)
+
+	tests := []struct {
+		name string
+		head []byte
+		want []byte
+	}{
+		{"JPEG APP1", jpeg, exif},
+		{"PNG eXIf", png, exif},
+		{"WebP EXIF", webp, exif},
+		{"TIFF", exif, exif},
+		{"JPEG without EXIF", []byte("\xff\xd8\xff\xe0\x00\x04\x00\x00\xff\xda"), nil},
+		{"truncated JPEG segment", jpeg[:20], nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := findExif(tt.head, Detect(tt.head))
+			if !bytes.Equal(got, tt.want) {
+				t.Errorf("findExif() = %d bytes, want %d", len(got), len(tt.want))
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package fileinfo
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Hexdump formats data in the canonical hex+ASCII layout of hexdump -C,
+// sixteen bytes per line. Offsets start at base, so a slice taken from the
+// middle of a file is labelled with its position in the file.
+func Hexdump(data []byte, base int64) string {
+	var b strings.Builder
+	for i := 0; i < len(data); i += 16 {
+		line := data[i:min(i+16, len(data))]
+		fmt.Fprintf(&b, "%08x  ", base+int64(i))
+		for j := range 16 {
+			switch {
+			case j < len(line):
+				fmt.Fprintf(&b, "%02x ", line[j])
+			default:
+				b.WriteString("   ")
+			}
+			if j == 7 {
+				b.WriteByte(' ')
+			}
+		}
+		b.WriteString(" |")
+		for _, c := range line {
+			if c < 0x20 || c > 0x7e {
+				c = '.'
+			}
+			b.WriteByte(c)
+		}
+		b.WriteString("|\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,48 @@
+package fileinfo
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func TestHexdump(t *testing.T) {
+	tests := []struct {
+		name  string
+		input []byte
+		base  int64
+		want  string
+	}{
+		{"empty", nil, 0, ""},
+		{
+			name:  "partial line",
+			input: []byte("Hello\n\x00\xff"),
+			want:  "00000000  48 65 6c 6c 6f 0a 00 ff                           |Hello...|\n",
+		},
+		{
+			name:  "offset base",
+			input: []byte("0123456789abcdefXY"),
+			base:  0x1230,
+			want: "00001230  30 31 32 33 34 35 36 37  38 39 61 62 63 64 65 66  |0123456789abcdef|\n" +
+				"00001240  58 59                                             |XY|\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Hexdump(tt.input, tt.base); got != tt.want {
+				t.Errorf("Hexdump()\n got %q\nwant %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// With a zero base the layout matches encoding/hex.Dump, which follows
+// hexdump -C.
+func TestHexdumpMatchesDump(t *testing.T) {
+	data := make([]byte, 100)
+	for i := range data {
+		data[i] = byte(i * 7)
+	}
+	if got, want := Hexdump(data, 0), hex.Dump(data); got != want {
+		t.Errorf("Hexdump() differs from hex.Dump:\n%s\n%s", got, want)
+	}
+}
@@ -0,0 +1,120 @@
+package fileinfo
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// HexRowWidth is the number of bytes in a hexdump row.
+const HexRowWidth = 16
+
+// Range is a half-open span of byte offsets.
+type Range struct {
+	Start int64
+	End   int64
+}
+
+// Len returns the number of bytes in r.
+func (r Range) Len() int64 {
+	return r.End - r.Start
+}
+
+func (r Range) String() string {
+	if r.Len() == 1 {
+		return fmt.Sprintf("0x%08x", r.Start)
+	}
+	return fmt.Sprintf("0x%08x-0x%08x (%d bytes)", r.Start, r.End-1, r.Len())
+}
+
+// Marks selects bytes to highlight in a hex view. Both range lists must be
+// sorted and non-overlapping. Cursor is the offset of a single byte to point
+// out, or -1.
+type Marks struct {
+	Matches []Range
+	Diffs   []Range
+	Cursor  int64
+}
+
+// HexCell is one byte of a hex view.
+type HexCell struct {
+	Hex    string
+	Char   string
+	Match  bool
+	Diff   bool
+	Cursor bool
+}
+
+// HexRow is one line of a hex view. The final row of a file may hold fewer
+// than HexRowWidth cells.
+type HexRow struct {
+	Offset int64
+	Cells  []HexCell
+}
+
+// Padding returns the spaces that line up the text column of a short final
+// row with full rows, in the layout of Hexdump.
+func (r HexRow) Padding() string {
+	missing := HexRowWidth - len(r.Cells)
+	n := missing * 3
+	if len(r.Cells) <= HexRowWidth/2 {
+		n++ // the gap between the two halves
+	}
+	return strings.Repeat(" ", n)
+}
+
+// HexRows lays out data, which starts at file offset base, as hexdump rows
+// with the marked bytes flagged.
+func HexRows(data []byte, base int64, marks Marks) []HexRow {
+	var rows []HexRow
+	for i := 0; i < len(data); i += HexRowWidth {
+		row := HexRow{Offset: base + int64(i)}
+		for j, c := range data[i:min(i+HexRowWidth, len(data))] {
+			off := base + int64(i+j)
+			char := "."
+			if c >= 0x20 && c <= 0x7e {
+				char = string(rune(c))
+			}
+			row.Cells = append(row.Cells, HexCell{
+				Hex:    fmt.Sprintf("%02x", c),
+				Char:   char,
+				Match:  inRanges(marks.Matches, off),
+				Diff:   inRanges(marks.Diffs, off),
+				Cursor: off == marks.Cursor,
+			})
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// inRanges reports whether off falls in one of the sorted ranges.
+func inRanges(ranges []Range, off int64) bool {
+	i := sort.Search(len(ranges), func(i int) bool { return ranges[i].End > off })
+	return i < len(ranges) && ranges[i].Start <= off
+}
+
+// RowStart rounds off down to the start of its hexdump row.
+func RowStart(off int64) int64 {
+	return off - off%HexRowWidth
+}
+
+// ParseOffset reads a byte offset written in decimal or, with a 0x prefix or
+// h suffix, in hexadecimal.
+func ParseOffset(s string) (int64, error) {
+	s = strings.ReplaceAll(strings.TrimSpace(s), "_", "")
+	lower := strings.ToLower(s)
+	base, digits := 10, lower
+	switch {
+	case strings.HasPrefix(lower, "0x"):
+		base, digits = 16, lower[2:]
+	case strings.HasSuffix(lower, "h"):
+		base, digits = 16, strings.TrimSuffix(lower, "h")
+	}
+	off, err := strconv.ParseInt(digits, base, 64)
+	if err != nil || off < 0 {
+		return 0, fmt.Errorf("invalid offset %q: use a decimal number or hex such as 0x1f0", s)
+	}
+	return off, nil
+}
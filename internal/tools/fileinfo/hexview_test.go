@@ -0,0 +1,118 @@
+package fileinfo
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestHexRows(t *testing.T) {
+	data := []byte("Hello, world!\n\x00\xffABCDEF")
+	rows := HexRows(data, 0x100, Marks{
+		Matches: []Range{{0x107, 0x10c}},
+		Diffs:   []Range{{0x100, 0x101}, {0x111, 0x113}},
+		Cursor:  0x110,
+	})
+
+	if len(rows) != 2 || rows[0].Offset != 0x100 || rows[1].Offset != 0x110 {
+		t.Fatalf("unexpected rows %+v", rows)
+	}
+	if len(rows[0].Cells) != 16 || len(rows[1].Cells) != 6 {
+		t.Fatalf("row lengths %d and %d", len(rows[0].Cells), len(rows[1].Cells))
+	}
+
+	tests := []struct {
+		row, col int
+		want     HexCell
+	}{
+		{0, 0, HexCell{Hex: "48", Char: "H", Diff: true}},
+		{0, 6, HexCell{Hex: "20", Char: " "}},
+		{0, 7, HexCell{Hex: "77", Char: "w", Match: true}},
+		{0, 11, HexCell{Hex: "64", Char: "d", Match: true}},
+		{0, 12, HexCell{Hex: "21", Char: "!"}},
+		{0, 13, HexCell{Hex: "0a", Char: "."}},
+		{0, 15, HexCell{Hex: "ff", Char: "."}},
+		{1, 0, HexCell{Hex: "41", Char: "A", Cursor: true}},
+		{1, 1, HexCell{Hex: "42", Char: "B", Diff: true}},
+		{1, 3, HexCell{Hex: "44", Char: "D"}},
+	}
+	for _, tt := range tests {
+		if got := rows[tt.row].Cells[tt.col]; got != tt.want {
+			t.Errorf("cell %d,%d = %+v, want %+v", tt.row, tt.col, got, tt.want)
+		}
+	}
+
+	if rows := HexRows(nil, 0, Marks{Cursor: -1}); len(rows) != 0 {
+		t.Errorf("HexRows(nil) = %v", rows)
+	}
+}
+
+// Rows rendered with Padding line up the same way as Hexdump output.
+func TestHexRowPadding(t *testing.T) {
+	for n := range 17 {
+		data := make([]byte, n)
+		rows := HexRows(data, 0, Marks{Cursor: -1})
+		if n == 0 {
+			continue
+		}
+		row := rows[0]
+		var b strings.Builder
+		fmt.Fprintf(&b, "%08x  ", row.Offset)
+		for i, c := range row.Cells {
+			if i == HexRowWidth/2 {
+				b.WriteString(" ")
+			}
+			b.WriteString(c.Hex + " ")
+		}
+		b.WriteString(row.Padding() + " |")
+		for _, c := range row.Cells {
+			b.WriteString(c.Char)
+		}
+		b.WriteString("|\n")
+		if got, want := b.String(), Hexdump(data, 0); got != want {
+			t.Errorf("%d bytes:\n got %q\nwant %q", n, got, want)
+		}
+	}
+}
+
+func TestRowStart(t *testing.T) {
+	for off, want := range map[int64]int64{0: 0, 15: 0, 16: 16, 0x1234: 0x1230} {
+		if got := RowStart(off); got != want {
+			t.Errorf("RowStart(%d) = %d, want %d", off, got, want)
+		}
+	}
+}
+
+func TestParseOffset(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    int64
+		wantErr bool
+	}{
+		{"0", 0, false},
+		{"1024", 1024, false},
+		{" 0x1F0 ", 0x1f0, false},
+		{"0X10", 16, false},
+		{"ffh", 255, false},
+		{"1_000", 1000, false},
+		{"", 0, true},
+		{"-5", 0, true},
+		{"0xzz", 0, true},
+		{"ten", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseOffset(tt.input)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseOffset(%q) = %d, %v", tt.input, got, err)
+		}
+	}
+}
+
+func TestRangeString(t *testing.T) {
+	if got := (Range{0x10, 0x11}).String(); got != "0x00000010" {
+		t.Errorf("single byte range = %q", got)
+	}
+	if got := (Range{0x10, 0x20}).String(); got != "0x00000010-0x0000001f (16 bytes)" {
+		t.Errorf("range = %q", got)
+	}
+}
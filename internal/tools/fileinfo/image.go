@@ -0,0 +1,116 @@
+package fileinfo
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+)
+
+// imageSize reads the pixel dimensions of an image from the head of its
+// file. Formats the standard library decodes use image.DecodeConfig; the
+// rest are read from their headers.
+func imageSize(head []byte, t Type) (width, height int, ok bool) {
+	switch t.MIME {
+	case "image/png", "image/jpeg", "image/gif":
+		cfg, _, err := image.DecodeConfig(bytes.NewReader(head))
+		if err != nil {
+			return 0, 0, false
+		}
+		return cfg.Width, cfg.Height, true
+
+	case "image/bmp":
+		if len(head) < 26 {
+			return 0, 0, false
+		}
+		if binary.LittleEndian.Uint32(head[14:]) == 12 {
+			// OS/2 bitmaps have 16-bit dimensions.
+			return int(binary.LittleEndian.Uint16(head[18:])), int(binary.LittleEndian.Uint16(head[20:])), true
+		}
+		w := int32(binary.LittleEndian.Uint32(head[18:]))
+		h := int32(binary.LittleEndian.Uint32(head[22:]))
+		// A negative height marks a top-down bitmap.
+		return int(w), int(max(h, -h)), true
+
+	case "image/webp":
+		return webpSize(head)
+
+	case "image/tiff":
+		return tiffSize(head)
+
+	case "image/vnd.adobe.photoshop":
+		if len(head) < 22 {
+			return 0, 0, false
+		}
+		return int(binary.BigEndian.Uint32(head[18:])), int(binary.BigEndian.Uint32(head[14:])), true
+
+	case "image/x-icon":
+		// The first image in the directory; zero means 256.
+		if len(head) < 8 {
+			return 0, 0, false
+		}
+		w, h := int(head[6]), int(head[7])
+		if w == 0 {
+			w = 256
+		}
+		if h == 0 {
+			h = 256
+		}
+		return w, h, true
+	}
+	return 0, 0, false
+}
+
+// webpSize reads the canvas size from the first chunk of a WebP file.
+func webpSize(head []byte) (width, height int, ok bool) {
+	if len(head) < 30 {
+		return 0, 0, false
+	}
+	chunk := head[20:]
+	switch string(head[12:16]) {
+	case "VP8 ":
+		// After the frame tag and start code come 14-bit dimensions.
+		if string(chunk[3:6]) != "\x9d\x01\x2a" {
+			return 0, 0, false
+		}
+		return int(binary.LittleEndian.Uint16(chunk[6:]) & 0x3fff), int(binary.LittleEndian.Uint16(chunk[8:]) & 0x3fff), true
+	case "VP8L":
+		if chunk[0] != 0x2f {
+			return 0, 0, false
+		}
+		bits := binary.LittleEndian.Uint32(chunk[1:])
+		return int(bits&0x3fff) + 1, int(bits>>14&0x3fff) + 1, true
+	case "VP8X":
+		w := int(chunk[4]) | int(chunk[5])<<8 | int(chunk[6])<<16
+		h := int(chunk[7]) | int(chunk[8])<<8 | int(chunk[9])<<16
+		return w + 1, h + 1, true
+	}
+	return 0, 0, false
+}
+
+// tiffSize reads ImageWidth and ImageLength from the first directory.
+func tiffSize(head []byte) (width, height int, ok bool) {
+	t, err := newTIFFReader(head)
+	if err != nil {
+		return 0, 0, false
+	}
+	entries, err := t.ifd(t.order.Uint32(head[4:]))
+	if err != nil {
+		return 0, 0, false
+	}
+	for _, e := range entries {
+		v := t.uints(e)
+		if len(v) != 1 {
+			continue
+		}
+		switch e.tag {
+		case 0x0100:
+			width = int(v[0])
+		case 0x0101:
+			height = int(v[0])
+		}
+	}
+	return width, height, width > 0 && height > 0
+}
@@ -0,0 +1,74 @@
+package fileinfo
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+func TestImageSize(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 7, 3))
+	encode := func(f func(*bytes.Buffer) error) []byte {
+		var buf bytes.Buffer
+		if err := f(&buf); err != nil {
+			t.Fatal(err)
+		}
+		return buf.Bytes()
+	}
+	pngData := encode(func(b *bytes.Buffer) error { return png.Encode(b, img) })
+	jpegData := encode(func(b *bytes.Buffer) error { return jpeg.Encode(b, img, nil) })
+	gifData := encode(func(b *bytes.Buffer) error { return gif.Encode(b, img, nil) })
+
+	bmp := padded("BM", 54)
+	binary.LittleEndian.PutUint32(bmp[14:], 40)
+	binary.LittleEndian.PutUint32(bmp[18:], 640)
+	binary.LittleEndian.PutUint32(bmp[22:], uint32(0xffffffff-480+1)) // -480, top-down
+
+	vp8 := append([]byte("RIFF\x00\x00\x00\x00WEBPVP8 \x00\x00\x00\x00"), "\x00\x00\x00\x9d\x01\x2a"...)
+	vp8 = binary.LittleEndian.AppendUint16(vp8, 320)
+	vp8 = binary.LittleEndian.AppendUint16(vp8, 240)
+	vp8 = append(vp8, make([]byte, 4)...)
+
+	vp8l := append([]byte("RIFF\x00\x00\x00\x00WEBPVP8L\x00\x00\x00\x00"), 0x2f)
+	vp8l = binary.LittleEndian.AppendUint32(vp8l, (100-1)|(50-1)<<14)
+	vp8l = append(vp8l, make([]byte, 8)...)
+
+	vp8x := append([]byte("RIFF\x00\x00\x00\x00WEBPVP8X\x0a\x00\x00\x00"), 0, 0, 0, 0, 0x1f, 0x03, 0, 0xdf, 0x01, 0)
+
+	tiff := buildTIFF(binary.BigEndian, []tiffEntry{
+		shortEntry(binary.BigEndian, 0x0100, 1024),
+		shortEntry(binary.BigEndian, 0x0101, 768),
+	}, nil, nil)
+
+	tests := []struct {
+		name   string
+		head   []byte
+		width  int
+		height int
+		ok     bool
+	}{
+		{"PNG", pngData, 7, 3, true},
+		{"JPEG", jpegData, 7, 3, true},
+		{"GIF", gifData, 7, 3, true},
+		{"BMP top-down", bmp, 640, 480, true},
+		{"WebP lossy", vp8, 320, 240, true},
+		{"WebP lossless", vp8l, 100, 50, true},
+		{"WebP extended", vp8x, 800, 480, true},
+		{"TIFF", tiff, 1024, 768, true},
+		{"ICO", []byte("\x00\x00\x01\x00\x01\x00\x00\x20"), 256, 32, true},
+		{"truncated PNG", pngData[:20], 0, 0, false},
+		{"not an image", []byte("hello"), 0, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, h, ok := imageSize(tt.head, Detect(tt.head))
+			if w != tt.width || h != tt.height || ok != tt.ok {
+				t.Errorf("imageSize() = %d, %d, %v, want %d, %d, %v", w, h, ok, tt.width, tt.height, tt.ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package fileinfo
+
+import (
+	"errors"
+	"io"
+
+	"github.com/NickDiPreta1/toolhub/internal/tools/fileconvert"
+	"github.com/NickDiPreta1/toolhub/internal/tools/hashutil"
+	"github.com/NickDiPreta1/toolhub/internal/tools/textutil"
+)
+
+// HeadSize is how much of a file Inspect keeps for type detection, image
+// headers and EXIF. JPEG EXIF segments are at most 64KB, so the image
+// header that follows one still fits.
+const HeadSize = 128 << 10
+
+// Report describes an inspected file.
+type Report struct {
+	Size    int64
+	Type    Type
+	Digests hashutil.Digests
+	// Entropy is the Shannon entropy of the whole file in bits per byte.
+	Entropy float64
+	// Encoding is the guessed text encoding, or empty for binary files.
+	Encoding textutil.Encoding
+	// Width and Height are zero unless the file is an image whose header
+	// could be read.
+	Width  int
+	Height int
+	Exif   []ExifField
+	// ExifError is set when an EXIF block was found but could not be read.
+	ExifError string
+	// Head holds the first bytes of the file, up to HeadSize.
+	Head []byte
+}
+
+// Inspect reads r to the end in a single pass, hashing it and measuring its
+// entropy, and identifies it from its first HeadSize bytes.
+func Inspect(r io.Reader) (*Report, error) {
+	head := make([]byte, HeadSize)
+	n, err := io.ReadFull(r, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	head = head[:n]
+
+	digester := hashutil.NewDigester()
+	var counter byteCounter
+	w := io.MultiWriter(digester, &counter)
+	w.Write(head)
+	rest, err := io.Copy(w, r)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{
+		Size:    int64(n) + rest,
+		Type:    Detect(head),
+		Digests: digester.Sum(),
+		Entropy: counter.entropy(),
+		Head:    head,
+	}
+
+	if fileconvert.IsText(head[:min(len(head), 512)]) {
+		report.Encoding, _ = textutil.DetectEncoding(head)
+		// http.DetectContentType only recognises UTF-16 with a BOM.
+		if report.Type.MIME == "application/octet-stream" && len(head) > 0 {
+			report.Type = Type{Name: "Plain text", MIME: "text/plain", Extension: ".txt"}
+		}
+	}
+
+	if w, h, ok := imageSize(head, report.Type); ok {
+		report.Width, report.Height = w, h
+	}
+
+	if exif := findExif(head, report.Type); exif != nil {
+		fields, err := ParseExif(exif)
+		if err != nil {
+			report.ExifError = err.Error()
+		}
+		report.Exif = fields
+	}
+	return report, nil
+}
@@ -0,0 +1,111 @@
+package fileinfo
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/jpeg"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/NickDiPreta1/toolhub/internal/tools/hashutil"
+	"github.com/NickDiPreta1/toolhub/internal/tools/textutil"
+)
+
+func TestInspect(t *testing.T) {
+	var jpg bytes.Buffer
+	if err := jpeg.Encode(&jpg, image.NewGray(image.Rect(0, 0, 40, 30)), nil); err != nil {
+		t.Fatal(err)
+	}
+	exif := sampleExif(binary.LittleEndian)
+	app1 := append([]byte("Exif\x00\x00"), exif...)
+	withExif := []byte("\xff\xd8\xff\xe1")
+	withExif = binary.BigEndian.AppendUint16(withExif, uint16(len(app1)+2))
+	withExif = append(withExif, app1...)
+	withExif = append(withExif, jpg.Bytes()[2:]...)
+
+	random := make([]byte, HeadSize+5000)
+	rand.Read(random)
+
+	t.Run("JPEG with EXIF", func(t *testing.T) {
+		r, err := Inspect(bytes.NewReader(withExif))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r.Type.Name != "JPEG image" || r.Width != 40 || r.Height != 30 {
+			t.Errorf("got %s %dx%d", r.Type.Name, r.Width, r.Height)
+		}
+		if len(r.Exif) == 0 || r.Exif[0] != (ExifField{"Camera Make", "Canon"}) {
+			t.Errorf("unexpected EXIF %v", r.Exif)
+		}
+		if r.Encoding != "" {
+			t.Errorf("binary file reported as %s text", r.Encoding)
+		}
+	})
+
+	t.Run("UTF-8 text", func(t *testing.T) {
+		input := "héllo wörld\n"
+		r, err := Inspect(strings.NewReader(input))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, _, _ := hashutil.Digest(strings.NewReader(input))
+		if r.Size != int64(len(input)) || r.Digests != want {
+			t.Errorf("size %d digests %+v", r.Size, r.Digests)
+		}
+		if r.Encoding != textutil.EncodingUTF8 || r.Type.Name != "Plain text" {
+			t.Errorf("got %s, encoding %s", r.Type.Name, r.Encoding)
+		}
+		if string(r.Head) != input {
+			t.Errorf("head = %q", r.Head)
+		}
+	})
+
+	t.Run("UTF-16 text without BOM", func(t *testing.T) {
+		r, err := Inspect(strings.NewReader("h\x00e\x00l\x00l\x00o\x00"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r.Encoding != textutil.EncodingUTF16LE || r.Type.Name != "Plain text" {
+			t.Errorf("got %s, encoding %s", r.Type.Name, r.Encoding)
+		}
+	})
+
+	t.Run("larger than the head", func(t *testing.T) {
+		r, err := Inspect(bytes.NewReader(random))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, _, _ := hashutil.Digest(bytes.NewReader(random))
+		if r.Size != int64(len(random)) || r.Digests != want || len(r.Head) != HeadSize {
+			t.Errorf("size %d, head %d, digests match %v", r.Size, len(r.Head), r.Digests == want)
+		}
+		if r.Entropy < 7.9 {
+			t.Errorf("entropy of random data = %v", r.Entropy)
+		}
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		r, err := Inspect(strings.NewReader(""))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r.Size != 0 || r.Type.Name != "Empty file" || r.Entropy != 0 {
+			t.Errorf("got %+v", r)
+		}
+	})
+
+	t.Run("read error", func(t *testing.T) {
+		r := io.MultiReader(strings.NewReader("abc"), errReader{})
+		if _, err := Inspect(r); err == nil {
+			t.Error("expected an error")
+		}
+	})
+}
+
+type errReader struct{}
+
+func (errReader) Read([]byte) (int, error) { return 0, errors.New("boom") }
@@ -0,0 +1,408 @@
+// Package fileinfo identifies files by their content and reports metadata
+// such as digests, entropy, text encoding, image dimensions and EXIF tags.
+package fileinfo
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+)
+
+// Type describes a detected file format.
+type Type struct {
+	Name string
+	MIME string
+	// Extension is the usual extension including the dot, or empty when the
+	// format has none.
+	Extension string
+	// Detail adds format specifics such as the architecture of an
+	// executable.
+	Detail string
+}
+
+// signature is a fixed byte sequence at a fixed offset.
+type signature struct {
+	offset int
+	magic  string
+	typ    Type
+}
+
+// signatures are checked in order after the formats that need more than a
+// prefix match, so longer and more specific magic comes first.
+var signatures = []signature{
+	{0, "\x89PNG\r\n\x1a\n", Type{Name: "PNG image", MIME: "image/png", Extension: ".png"}},
+	{0, "\xff\xd8\xff", Type{Name: "JPEG image", MIME: "image/jpeg", Extension: ".jpg"}},
+	{0, "GIF87a", Type{Name: "GIF image", MIME: "image/gif", Extension: ".gif"}},
+	{0, "GIF89a", Type{Name: "GIF image", MIME: "image/gif", Extension: ".gif"}},
+	{0, "II*\x00", Type{Name: "TIFF image", MIME: "image/tiff", Extension: ".tif", Detail: "little-endian"}},
+	{0, "MM\x00*", Type{Name: "TIFF image", MIME: "image/tiff", Extension: ".tif", Detail: "big-endian"}},
+	{0, "8BPS", Type{Name: "Photoshop document", MIME: "image/vnd.adobe.photoshop", Extension: ".psd"}},
+	{0, "\x00\x00\x01\x00", Type{Name: "Windows icon", MIME: "image/x-icon", Extension: ".ico"}},
+	{0, "%PDF-", Type{Name: "PDF document", MIME: "application/pdf", Extension: ".pdf"}},
+	{0, "SQLite format 3\x00", Type{Name: "SQLite database", MIME: "application/vnd.sqlite3", Extension: ".sqlite"}},
+	{0, "\x00asm", Type{Name: "WebAssembly module", MIME: "application/wasm", Extension: ".wasm"}},
+	{0, "fLaC", Type{Name: "FLAC audio", MIME: "audio/flac", Extension: ".flac"}},
+	{0, "MThd", Type{Name: "MIDI audio", MIME: "audio/midi", Extension: ".mid"}},
+	{0, "ID3", Type{Name: "MP3 audio", MIME: "audio/mpeg", Extension: ".mp3", Detail: "ID3v2 tagged"}},
+	{0, "\x1a\x45\xdf\xa3", Type{Name: "Matroska video", MIME: "video/x-matroska", Extension: ".mkv"}},
+	{0, "\x1f\x8b", Type{Name: "gzip archive", MIME: "application/gzip", Extension: ".gz"}},
+	{0, "BZh", Type{Name: "bzip2 archive", MIME: "application/x-bzip2", Extension: ".bz2"}},
+	{0, "\xfd7zXZ\x00", Type{Name: "xz archive", MIME: "application/x-xz", Extension: ".xz"}},
+	{0, "\x28\xb5\x2f\xfd", Type{Name: "Zstandard archive", MIME: "application/zstd", Extension: ".zst"}},
+	{0, "7z\xbc\xaf\x27\x1c", Type{Name: "7-Zip archive", MIME: "application/x-7z-compressed", Extension: ".7z"}},
+	{0, "Rar!\x1a\x07", Type{Name: "RAR archive", MIME: "application/vnd.rar", Extension: ".rar"}},
+	{257, "ustar", Type{Name: "tar archive", MIME: "application/x-tar", Extension: ".tar"}},
+	{0, "wOFF", Type{Name: "WOFF font", MIME: "font/woff", Extension: ".woff"}},
+	{0, "wOF2", Type{Name: "WOFF2 font", MIME: "font/woff2", Extension: ".woff2"}},
+	{0, "OTTO", Type{Name: "OpenType font", MIME: "font/otf", Extension: ".otf"}},
+	{0, "\x00\x01\x00\x00\x00", Type{Name: "TrueType font", MIME: "font/ttf", Extension: ".ttf"}},
+}
+
+// Detect identifies the format of a file from its first bytes. Formats
+// without a recognised signature fall back to http.DetectContentType, which
+// also tells plain text, HTML and XML apart.
+func Detect(head []byte) Type {
+	detectors := []func([]byte) (Type, bool){
+		detectZip, detectRIFF, detectFtyp, detectOgg, detectAIFF,
+		detectELF, detectMachO, detectPE, detectMPEGAudio, detectBMP,
+	}
+	for _, detect := range detectors {
+		if t, ok := detect(head); ok {
+			return t
+		}
+	}
+
+	for _, s := range signatures {
+		if len(head) >= s.offset+len(s.magic) && string(head[s.offset:s.offset+len(s.magic)]) == s.magic {
+			t := s.typ
+			if t.MIME == "application/pdf" {
+				t.Detail = pdfVersion(head)
+			}
+			return t
+		}
+	}
+
+	return sniffedType(head)
+}
+
+// sniffedType names the result of http.DetectContentType.
+func sniffedType(head []byte) Type {
+	mime := http.DetectContentType(head)
+	base, _, _ := strings.Cut(mime, ";")
+	switch {
+	case len(head) == 0:
+		return Type{Name: "Empty file", MIME: "application/octet-stream"}
+	case base == "text/html":
+		return Type{Name: "HTML document", MIME: mime, Extension: ".html"}
+	case base == "text/xml":
+		return Type{Name: "XML document", MIME: mime, Extension: ".xml"}
+	case base == "text/plain":
+		return Type{Name: "Plain text", MIME: mime, Extension: ".txt"}
+	case base == "image/bmp" && utf8.Valid(head):
+		// detectBMP has already rejected the header, and the sniffer only
+		// checks for "BM".
+		return Type{Name: "Plain text", MIME: "text/plain; charset=utf-8", Extension: ".txt"}
+	case base == "application/octet-stream":
+		return Type{Name: "Unknown binary data", MIME: mime}
+	default:
+		return Type{Name: base, MIME: mime}
+	}
+}
+
+func pdfVersion(head []byte) string {
+	v := head[5:]
+	n := 0
+	for n < len(v) && n < 4 && (v[n] == '.' || v[n] >= '0' && v[n] <= '9') {
+		n++
+	}
+	if n == 0 {
+		return ""
+	}
+	return "version " + string(v[:n])
+}
+
+// zipMIMETypes maps the first "mimetype" entry of ODF and EPUB files to
+// their type.
+var zipMIMETypes = map[string]Type{
+	"application/epub+zip":                            {Name: "EPUB e-book", MIME: "application/epub+zip", Extension: ".epub"},
+	"application/vnd.oasis.opendocument.text":         {Name: "OpenDocument text", MIME: "application/vnd.oasis.opendocument.text", Extension: ".odt"},
+	"application/vnd.oasis.opendocument.spreadsheet":  {Name: "OpenDocument spreadsheet", MIME: "application/vnd.oasis.opendocument.spreadsheet", Extension: ".ods"},
+	"application/vnd.oasis.opendocument.presentation": {Name: "OpenDocument presentation", MIME: "application/vnd.oasis.opendocument.presentation", Extension: ".odp"},
+}
+
+// zipEntryTypes identifies zip-based formats by an entry name prefix.
+var zipEntryTypes = []struct {
+	prefix string
+	typ    Type
+}{
+	{"word/", Type{Name: "Word document", MIME: "application/vnd.openxmlformats-officedocument.wordprocessingml.document", Extension: ".docx"}},
+	{"xl/", Type{Name: "Excel workbook", MIME: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", Extension: ".xlsx"}},
+	{"ppt/", Type{Name: "PowerPoint presentation", MIME: "application/vnd.openxmlformats-officedocument.presentationml.presentation", Extension: ".pptx"}},
+	{"AndroidManifest.xml", Type{Name: "Android package", MIME: "application/vnd.android.package-archive", Extension: ".apk"}},
+	{"META-INF/MANIFEST.MF", Type{Name: "Java archive", MIME: "application/java-archive", Extension: ".jar"}},
+}
+
+// detectZip recognises zip archives and the document formats built on
+// them. The entry names are read from the local file headers within head,
+// since the central directory is at the end of the file.
+func detectZip(head []byte) (Type, bool) {
+	if !bytes.HasPrefix(head, []byte("PK\x03\x04")) && !bytes.HasPrefix(head, []byte("PK\x05\x06")) {
+		return Type{}, false
+	}
+
+	var names []string
+	for i := 0; i+30 <= len(head); {
+		j := bytes.Index(head[i:], []byte("PK\x03\x04"))
+		if j < 0 || i+j+30 > len(head) {
+			break
+		}
+		h := head[i+j:]
+		method := binary.LittleEndian.Uint16(h[8:])
+		nameLen := int(binary.LittleEndian.Uint16(h[26:]))
+		extraLen := int(binary.LittleEndian.Uint16(h[28:]))
+		if 30+nameLen > len(h) {
+			break
+		}
+		name := string(h[30 : 30+nameLen])
+		names = append(names, name)
+
+		// ODF and EPUB store their media type uncompressed as the first
+		// entry. Its size may only be in a trailing data descriptor, so
+		// the media type is matched as a prefix.
+		data := min(30+nameLen+extraLen, len(h))
+		if len(names) == 1 && name == "mimetype" && method == 0 {
+			for mime, t := range zipMIMETypes {
+				if bytes.HasPrefix(h[data:], []byte(mime)) {
+					return t, true
+				}
+			}
+		}
+		i += j + 30 + nameLen
+	}
+
+	for _, e := range zipEntryTypes {
+		for _, name := range names {
+			if strings.HasPrefix(name, e.prefix) {
+				return e.typ, true
+			}
+		}
+	}
+	return Type{Name: "zip archive", MIME: "application/zip", Extension: ".zip"}, true
+}
+
+// detectRIFF recognises RIFF containers: WAV, AVI and WebP.
+func detectRIFF(head []byte) (Type, bool) {
+	if len(head) < 12 || string(head[:4]) != "RIFF" {
+		return Type{}, false
+	}
+	switch string(head[8:12]) {
+	case "WAVE":
+		return Type{Name: "WAV audio", MIME: "audio/wav", Extension: ".wav"}, true
+	case "AVI ":
+		return Type{Name: "AVI video", MIME: "video/x-msvideo", Extension: ".avi"}, true
+	case "WEBP":
+		t := Type{Name: "WebP image", MIME: "image/webp", Extension: ".webp"}
+		if len(head) >= 16 {
+			switch string(head[12:16]) {
+			case "VP8 ":
+				t.Detail = "lossy"
+			case "VP8L":
+				t.Detail = "lossless"
+			case "VP8X":
+				t.Detail = "extended"
+			}
+		}
+		return t, true
+	}
+	return Type{Name: "RIFF container", MIME: "application/octet-stream"}, true
+}
+
+// detectFtyp recognises ISO base media files (MP4, QuickTime, M4A, HEIC
+// and AVIF) by the major brand of their ftyp box.
+func detectFtyp(head []byte) (Type, bool) {
+	if len(head) < 12 || string(head[4:8]) != "ftyp" {
+		return Type{}, false
+	}
+	brand := string(head[8:12])
+	switch brand {
+	case "M4A ", "M4B ":
+		return Type{Name: "MPEG-4 audio", MIME: "audio/mp4", Extension: ".m4a"}, true
+	case "qt  ":
+		return Type{Name: "QuickTime video", MIME: "video/quicktime", Extension: ".mov"}, true
+	case "heic", "heix", "heim", "heis", "mif1", "msf1":
+		return Type{Name: "HEIF image", MIME: "image/heic", Extension: ".heic"}, true
+	case "avif", "avis":
+		return Type{Name: "AVIF image", MIME: "image/avif", Extension: ".avif"}, true
+	case "3gp4", "3gp5", "3gp6", "3g2a":
+		return Type{Name: "3GPP video", MIME: "video/3gpp", Extension: ".3gp"}, true
+	}
+	return Type{Name: "MPEG-4 video", MIME: "video/mp4", Extension: ".mp4", Detail: "brand " + strings.TrimSpace(brand)}, true
+}
+
+// detectOgg recognises Ogg streams and names the codec of the first page.
+func detectOgg(head []byte) (Type, bool) {
+	if !bytes.HasPrefix(head, []byte("OggS")) {
+		return Type{}, false
+	}
+	payload := head[min(len(head), 28):]
+	switch {
+	case bytes.HasPrefix(payload, []byte("OpusHead")):
+		return Type{Name: "Ogg audio", MIME: "audio/ogg", Extension: ".opus", Detail: "Opus"}, true
+	case bytes.HasPrefix(payload, []byte("\x01vorbis")):
+		return Type{Name: "Ogg audio", MIME: "audio/ogg", Extension: ".ogg", Detail: "Vorbis"}, true
+	case bytes.HasPrefix(payload, []byte("\x7fFLAC")):
+		return Type{Name: "Ogg audio", MIME: "audio/ogg", Extension: ".oga", Detail: "FLAC"}, true
+	case bytes.HasPrefix(payload, []byte("\x80theora")):
+		return Type{Name: "Ogg video", MIME: "video/ogg", Extension: ".ogv", Detail: "Theora"}, true
+	}
+	return Type{Name: "Ogg container", MIME: "application/ogg", Extension: ".ogg"}, true
+}
+
+func detectAIFF(head []byte) (Type, bool) {
+	if len(head) < 12 || string(head[:4]) != "FORM" {
+		return Type{}, false
+	}
+	switch string(head[8:12]) {
+	case "AIFF", "AIFC":
+		return Type{Name: "AIFF audio", MIME: "audio/aiff", Extension: ".aiff"}, true
+	}
+	return Type{}, false
+}
+
+// detectMPEGAudio recognises MP3 files without an ID3 tag by the frame
+// sync of their first frame, and AAC in ADTS framing.
+func detectMPEGAudio(head []byte) (Type, bool) {
+	if len(head) < 3 || head[0] != 0xff || head[1]&0xe0 != 0xe0 {
+		return Type{}, false
+	}
+	layer := head[1] >> 1 & 0x3
+	version := head[1] >> 3 & 0x3
+	bitrate := head[2] >> 4
+	switch {
+	case layer == 0 && head[1]&0xf6 == 0xf0:
+		return Type{Name: "AAC audio", MIME: "audio/aac", Extension: ".aac", Detail: "ADTS"}, true
+	case layer == 1 && version != 1 && bitrate != 0 && bitrate != 0xf:
+		return Type{Name: "MP3 audio", MIME: "audio/mpeg", Extension: ".mp3"}, true
+	}
+	return Type{}, false
+}
+
+// detectBMP checks the info header size as well as the short "BM" magic,
+// so text that happens to start with those letters is not mistaken for a
+// bitmap.
+func detectBMP(head []byte) (Type, bool) {
+	if len(head) < 18 || string(head[:2]) != "BM" {
+		return Type{}, false
+	}
+	switch binary.LittleEndian.Uint32(head[14:]) {
+	case 12, 40, 52, 56, 64, 108, 124:
+		return Type{Name: "BMP image", MIME: "image/bmp", Extension: ".bmp"}, true
+	}
+	return Type{}, false
+}
+
+var elfMachines = map[uint16]string{
+	0x03: "x86", 0x08: "MIPS", 0x14: "PowerPC", 0x15: "PowerPC64", 0x16: "S390",
+	0x28: "ARM", 0x2b: "SPARC V9", 0x3e: "x86-64", 0xb7: "AArch64", 0xf3: "RISC-V",
+}
+
+var elfKinds = map[uint16]string{1: "relocatable object", 2: "executable", 3: "shared object", 4: "core dump"}
+
+// detectELF recognises ELF binaries and describes their class, byte order,
+// kind and machine.
+func detectELF(head []byte) (Type, bool) {
+	if len(head) < 20 || string(head[:4]) != "\x7fELF" {
+		return Type{}, false
+	}
+	var order binary.ByteOrder = binary.LittleEndian
+	endian := "little-endian"
+	if head[5] == 2 {
+		order, endian = binary.BigEndian, "big-endian"
+	}
+	class := "32-bit"
+	if head[4] == 2 {
+		class = "64-bit"
+	}
+	kind := elfKinds[order.Uint16(head[16:])]
+	if kind == "" {
+		kind = "file"
+	}
+	detail := fmt.Sprintf("%s %s %s", class, endian, kind)
+	if m, ok := elfMachines[order.Uint16(head[18:])]; ok {
+		detail += ", " + m
+	}
+	return Type{Name: "ELF binary", MIME: "application/x-elf", Detail: detail}, true
+}
+
+var machoCPUs = map[uint32]string{7: "x86", 0x01000007: "x86-64", 12: "ARM", 0x0100000c: "ARM64", 18: "PowerPC", 0x01000012: "PowerPC64"}
+
+var machoKinds = map[uint32]string{1: "object", 2: "executable", 4: "core dump", 6: "dynamic library", 8: "bundle"}
+
+// detectMachO recognises Mach-O binaries, including universal binaries,
+// and Java class files, which share the universal binary magic.
+func detectMachO(head []byte) (Type, bool) {
+	if len(head) < 16 {
+		return Type{}, false
+	}
+
+	if binary.BigEndian.Uint32(head) == 0xcafebabe {
+		// A universal binary holds a handful of architectures; in a class
+		// file the same word is the version, which is at least 45.
+		if n := binary.BigEndian.Uint32(head[4:]); n > 0 && n < 20 {
+			return Type{Name: "Mach-O universal binary", MIME: "application/x-mach-binary", Detail: fmt.Sprintf("%d architectures", n)}, true
+		}
+		major := binary.BigEndian.Uint16(head[6:])
+		return Type{Name: "Java class file", MIME: "application/java-vm", Extension: ".class", Detail: fmt.Sprintf("class file version %d", major)}, true
+	}
+
+	var order binary.ByteOrder
+	var class string
+	switch binary.BigEndian.Uint32(head) {
+	case 0xfeedface:
+		order, class = binary.BigEndian, "32-bit"
+	case 0xfeedfacf:
+		order, class = binary.BigEndian, "64-bit"
+	case 0xcefaedfe:
+		order, class = binary.LittleEndian, "32-bit"
+	case 0xcffaedfe:
+		order, class = binary.LittleEndian, "64-bit"
+	default:
+		return Type{}, false
+	}
+	kind := machoKinds[order.Uint32(head[12:])]
+	if kind == "" {
+		kind = "file"
+	}
+	detail := class + " " + kind
+	if cpu, ok := machoCPUs[order.Uint32(head[4:])]; ok {
+		detail += ", " + cpu
+	}
+	return Type{Name: "Mach-O binary", MIME: "application/x-mach-binary", Detail: detail}, true
+}
+
+var peMachines = map[uint16]string{0x14c: "x86", 0x8664: "x86-64", 0x1c0: "ARM", 0xaa64: "ARM64"}
+
+// detectPE recognises Windows executables and DLLs, and plain DOS
+// executables when there is no PE header.
+func detectPE(head []byte) (Type, bool) {
+	if len(head) < 64 || string(head[:2]) != "MZ" {
+		return Type{}, false
+	}
+	off := int(binary.LittleEndian.Uint32(head[0x3c:]))
+	if off <= 0 || off+24 > len(head) || string(head[off:off+4]) != "PE\x00\x00" {
+		return Type{Name: "DOS executable", MIME: "application/x-msdownload", Extension: ".exe"}, true
+	}
+
+	t := Type{Name: "Windows executable", MIME: "application/vnd.microsoft.portable-executable", Extension: ".exe"}
+	characteristics := binary.LittleEndian.Uint16(head[off+22:])
+	if characteristics&0x2000 != 0 {
+		t.Name, t.Extension = "Windows DLL", ".dll"
+	}
+	if m, ok := peMachines[binary.LittleEndian.Uint16(head[off+4:])]; ok {
+		t.Detail = m
+	}
+	return t, true
+}
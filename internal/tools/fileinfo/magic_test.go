@@ -0,0 +1,135 @@
+package fileinfo
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+// zipWith builds a zip archive holding the named empty entries; a
+// "mimetype" entry is stored uncompressed with content as its data.
+func zipWith(t *testing.T, content string, names ...string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, name := range names {
+		if name == "mimetype" {
+			w, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Store})
+			if err != nil {
+				t.Fatal(err)
+			}
+			w.Write([]byte(content))
+			continue
+		}
+		if _, err := zw.Create(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func elfHeader(class, data byte, kind, machine uint16) []byte {
+	h := make([]byte, 64)
+	copy(h, "\x7fELF")
+	h[4], h[5] = class, data
+	binary.LittleEndian.PutUint16(h[16:], kind)
+	binary.LittleEndian.PutUint16(h[18:], machine)
+	return h
+}
+
+func peHeader(machine, characteristics uint16) []byte {
+	h := make([]byte, 256)
+	copy(h, "MZ")
+	binary.LittleEndian.PutUint32(h[0x3c:], 0x80)
+	copy(h[0x80:], "PE\x00\x00")
+	binary.LittleEndian.PutUint16(h[0x84:], machine)
+	binary.LittleEndian.PutUint16(h[0x80+22:], characteristics)
+	return h
+}
+
+func padded(prefix string, n int) []byte {
+	b := make([]byte, n)
+	copy(b, prefix)
+	return b
+}
+
+func TestDetect(t *testing.T) {
+	tar := make([]byte, 512)
+	copy(tar, "file.txt")
+	copy(tar[257:], "ustar\x0000")
+
+	bmp := padded("BM", 54)
+	binary.LittleEndian.PutUint32(bmp[14:], 40)
+
+	tests := []struct {
+		name       string
+		input      []byte
+		wantName   string
+		wantMIME   string
+		wantDetail string
+	}{
+		{"PNG", []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"), "PNG image", "image/png", ""},
+		{"JPEG", []byte("\xff\xd8\xff\xe0\x00\x10JFIF"), "JPEG image", "image/jpeg", ""},
+		{"GIF", []byte("GIF89a\x01\x00\x01\x00"), "GIF image", "image/gif", ""},
+		{"WebP lossless", []byte("RIFF\x00\x00\x00\x00WEBPVP8L"), "WebP image", "image/webp", "lossless"},
+		{"BMP", bmp, "BMP image", "image/bmp", ""},
+		{"text starting with BM", []byte("BMW owners club\n"), "Plain text", "text/plain; charset=utf-8", ""},
+		{"TIFF", []byte("II*\x00\x08\x00\x00\x00"), "TIFF image", "image/tiff", "little-endian"},
+		{"HEIC", []byte("\x00\x00\x00\x18ftypheic\x00\x00\x00\x00"), "HEIF image", "image/heic", ""},
+		{"PDF", []byte("%PDF-1.7\n%\xe2\xe3"), "PDF document", "application/pdf", "version 1.7"},
+		{"SQLite", []byte("SQLite format 3\x00\x10\x00"), "SQLite database", "application/vnd.sqlite3", ""},
+		{"WebAssembly", []byte("\x00asm\x01\x00\x00\x00"), "WebAssembly module", "application/wasm", ""},
+		{"ELF", elfHeader(2, 1, 3, 0x3e), "ELF binary", "application/x-elf", "64-bit little-endian shared object, x86-64"},
+		{"ELF big-endian", func() []byte {
+			h := elfHeader(1, 2, 0, 0)
+			binary.BigEndian.PutUint16(h[16:], 2)
+			binary.BigEndian.PutUint16(h[18:], 0x08)
+			return h
+		}(), "ELF binary", "application/x-elf", "32-bit big-endian executable, MIPS"},
+		{"Mach-O", []byte("\xcf\xfa\xed\xfe\x0c\x00\x00\x01\x00\x00\x00\x00\x02\x00\x00\x00"), "Mach-O binary", "application/x-mach-binary", "64-bit executable, ARM64"},
+		{"Mach-O universal", []byte("\xca\xfe\xba\xbe\x00\x00\x00\x02\x01\x00\x00\x07\x00\x00\x00\x03"), "Mach-O universal binary", "application/x-mach-binary", "2 architectures"},
+		{"Java class", []byte("\xca\xfe\xba\xbe\x00\x00\x00\x41\x00\x10\x0a\x00\x02\x00\x03\x07"), "Java class file", "application/java-vm", "class file version 65"},
+		{"PE executable", peHeader(0x8664, 0x0022), "Windows executable", "application/vnd.microsoft.portable-executable", "x86-64"},
+		{"PE DLL", peHeader(0x14c, 0x2102), "Windows DLL", "application/vnd.microsoft.portable-executable", "x86"},
+		{"DOS executable", padded("MZ", 64), "DOS executable", "application/x-msdownload", ""},
+		{"WAV", []byte("RIFF\x24\x00\x00\x00WAVEfmt "), "WAV audio", "audio/wav", ""},
+		{"MP3 with ID3", []byte("ID3\x04\x00\x00\x00\x00\x00\x00"), "MP3 audio", "audio/mpeg", "ID3v2 tagged"},
+		{"MP3 frame", []byte("\xff\xfb\x90\x64\x00\x00"), "MP3 audio", "audio/mpeg", ""},
+		{"AAC", []byte("\xff\xf1\x50\x80\x00\x1f\xfc"), "AAC audio", "audio/aac", "ADTS"},
+		{"FLAC", []byte("fLaC\x00\x00\x00\x22"), "FLAC audio", "audio/flac", ""},
+		{"Ogg Opus", append(padded("OggS", 28), "OpusHead"...), "Ogg audio", "audio/ogg", "Opus"},
+		{"M4A", []byte("\x00\x00\x00\x20ftypM4A \x00\x00\x00\x00"), "MPEG-4 audio", "audio/mp4", ""},
+		{"MP4", []byte("\x00\x00\x00\x20ftypisom\x00\x00\x02\x00"), "MPEG-4 video", "video/mp4", "brand isom"},
+		{"AIFF", []byte("FORM\x00\x00\x00\x00AIFFCOMM"), "AIFF audio", "audio/aiff", ""},
+		{"gzip", []byte("\x1f\x8b\x08\x00"), "gzip archive", "application/gzip", ""},
+		{"tar", tar, "tar archive", "application/x-tar", ""},
+		{"zip", zipWith(t, "", "a.txt"), "zip archive", "application/zip", ""},
+		{"empty zip", zipWith(t, ""), "zip archive", "application/zip", ""},
+		{"DOCX", zipWith(t, "", "[Content_Types].xml", "_rels/.rels", "word/document.xml"), "Word document", "application/vnd.openxmlformats-officedocument.wordprocessingml.document", ""},
+		{"XLSX", zipWith(t, "", "[Content_Types].xml", "xl/workbook.xml"), "Excel workbook", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", ""},
+		{"PPTX", zipWith(t, "", "[Content_Types].xml", "ppt/presentation.xml"), "PowerPoint presentation", "application/vnd.openxmlformats-officedocument.presentationml.presentation", ""},
+		{"JAR", zipWith(t, "", "META-INF/MANIFEST.MF", "Main.class"), "Java archive", "application/java-archive", ""},
+		{"EPUB", zipWith(t, "application/epub+zip", "mimetype", "META-INF/container.xml"), "EPUB e-book", "application/epub+zip", ""},
+		{"ODT", zipWith(t, "application/vnd.oasis.opendocument.text", "mimetype", "content.xml"), "OpenDocument text", "application/vnd.oasis.opendocument.text", ""},
+		{"WOFF2", []byte("wOF2\x00\x01\x00\x00"), "WOFF2 font", "font/woff2", ""},
+		{"HTML", []byte("<!DOCTYPE html><html><body>hi</body></html>"), "HTML document", "text/html; charset=utf-8", ""},
+		{"XML", []byte("<?xml version=\"1.0\"?><a/>"), "XML document", "text/xml; charset=utf-8", ""},
+		{"plain text", []byte("just some words\n"), "Plain text", "text/plain; charset=utf-8", ""},
+		{"UTF-16 text with BOM", []byte("\xff\xfeh\x00i\x00"), "Plain text", "text/plain; charset=utf-16le", ""},
+		{"unknown binary", []byte{0x00, 0x01, 0x02, 0x03, 0xfe, 0x10}, "Unknown binary data", "application/octet-stream", ""},
+		{"empty", nil, "Empty file", "application/octet-stream", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Detect(tt.input)
+			if got.Name != tt.wantName || got.MIME != tt.wantMIME || got.Detail != tt.wantDetail {
+				t.Errorf("Detect() = %+v, want name %q, MIME %q, detail %q", got, tt.wantName, tt.wantMIME, tt.wantDetail)
+			}
+		})
+	}
+}
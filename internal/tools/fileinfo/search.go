@@ -0,0 +1,76 @@
+package fileinfo
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// PatternMode says how a search pattern is written.
+type PatternMode string
+
+const (
+	PatternText PatternMode = "text"
+	PatternHex  PatternMode = "hex"
+)
+
+// ParsePattern converts a search pattern to bytes. Text patterns are
+// searched for as UTF-8. Hex patterns may separate bytes with spaces,
+// colons or commas and may use a 0x prefix, as in "de ad be ef" or
+// "0xDEADBEEF".
+func ParsePattern(s string, mode PatternMode) ([]byte, error) {
+	switch mode {
+	case PatternText:
+		if s == "" {
+			return nil, fmt.Errorf("the search text is empty")
+		}
+		return []byte(s), nil
+
+	case PatternHex:
+		var digits strings.Builder
+		for _, field := range strings.FieldsFunc(s, func(r rune) bool {
+			return r == ' ' || r == '\t' || r == ':' || r == ',' || r == '\n' || r == '\r'
+		}) {
+			field = strings.TrimPrefix(strings.TrimPrefix(field, "0x"), "0X")
+			if len(field)%2 != 0 {
+				// Allow single-digit bytes between separators: "a 1 ff".
+				field = "0" + field
+			}
+			digits.WriteString(field)
+		}
+		if digits.Len() == 0 {
+			return nil, fmt.Errorf("the hex pattern is empty")
+		}
+		b, err := hex.DecodeString(digits.String())
+		if err != nil {
+			return nil, fmt.Errorf("invalid hex pattern %q", s)
+		}
+		return b, nil
+
+	default:
+		return nil, fmt.Errorf("unknown pattern mode %q", mode)
+	}
+}
+
+// FindAll returns the ranges of non-overlapping occurrences of pattern in
+// data, up to limit of them, and the total number of occurrences.
+func FindAll(data, pattern []byte, limit int) ([]Range, int) {
+	var matches []Range
+	total := 0
+	if len(pattern) == 0 {
+		return nil, 0
+	}
+	for i := 0; ; {
+		j := bytes.Index(data[i:], pattern)
+		if j < 0 {
+			return matches, total
+		}
+		start := int64(i + j)
+		if total < limit {
+			matches = append(matches, Range{Start: start, End: start + int64(len(pattern))})
+		}
+		total++
+		i += j + len(pattern)
+	}
+}
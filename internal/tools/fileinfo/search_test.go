@@ -0,0 +1,65 @@
+package fileinfo
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestParsePattern(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		mode    PatternMode
+		want    []byte
+		wantErr bool
+	}{
+		{"text", "PK", PatternText, []byte("PK"), false},
+		{"unicode text", "é", PatternText, []byte{0xc3, 0xa9}, false},
+		{"empty text", "", PatternText, nil, true},
+		{"hex with spaces", "de ad be ef", PatternHex, []byte{0xde, 0xad, 0xbe, 0xef}, false},
+		{"hex run with prefix", "0xCAFEBABE", PatternHex, []byte{0xca, 0xfe, 0xba, 0xbe}, false},
+		{"hex with colons", "50:4b:03:04", PatternHex, []byte("PK\x03\x04"), false},
+		{"single-digit bytes", "a, 1, ff", PatternHex, []byte{0x0a, 0x01, 0xff}, false},
+		{"invalid hex", "zz", PatternHex, nil, true},
+		{"odd-length run", "abc", PatternHex, []byte{0x0a, 0xbc}, false},
+		{"empty hex", " : ", PatternHex, nil, true},
+		{"unknown mode", "x", "regex", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePattern(tt.input, tt.mode)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !bytes.Equal(got, tt.want) {
+				t.Errorf("ParsePattern() = %x, want %x", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindAll(t *testing.T) {
+	data := []byte("abcabcaaab")
+	tests := []struct {
+		name      string
+		pattern   string
+		limit     int
+		want      []Range
+		wantTotal int
+	}{
+		{"several", "abc", 10, []Range{{0, 3}, {3, 6}}, 2},
+		{"non-overlapping", "aa", 10, []Range{{6, 8}}, 1},
+		{"limited", "a", 2, []Range{{0, 1}, {3, 4}}, 5},
+		{"none", "xyz", 10, nil, 0},
+		{"empty pattern", "", 10, nil, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, total := FindAll(data, []byte(tt.pattern), tt.limit)
+			if !reflect.DeepEqual(got, tt.want) || total != tt.wantTotal {
+				t.Errorf("FindAll() = %v, %d, want %v, %d", got, total, tt.want, tt.wantTotal)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package hashutil
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"hash"
+	"hash/crc32"
+	"io"
+)
+
+// Digests holds hex-encoded checksums of one input.
+type Digests struct {
+	CRC32  string
+	MD5    string
+	SHA1   string
+	SHA256 string
+	SHA512 string
+}
+
+// Digester computes every digest in Digests in a single pass. Write the
+// input to it, then call Sum.
+type Digester struct {
+	crc32  hash.Hash32
+	md5    hash.Hash
+	sha1   hash.Hash
+	sha256 hash.Hash
+	sha512 hash.Hash
+	w      io.Writer
+}
+
+func NewDigester() *Digester {
+	d := &Digester{
+		crc32:  crc32.NewIEEE(),
+		md5:    md5.New(),
+		sha1:   sha1.New(),
+		sha256: sha256.New(),
+		sha512: sha512.New(),
+	}
+	d.w = io.MultiWriter(d.crc32, d.md5, d.sha1, d.sha256, d.sha512)
+	return d
+}
+
+// Write adds p to every digest. It never returns an error.
+func (d *Digester) Write(p []byte) (int, error) {
+	return d.w.Write(p)
+}
+
+// Sum returns the digests of everything written so far.
+func (d *Digester) Sum() Digests {
+	return Digests{
+		CRC32:  hex.EncodeToString(d.crc32.Sum(nil)),
+		MD5:    hex.EncodeToString(d.md5.Sum(nil)),
+		SHA1:   hex.EncodeToString(d.sha1.Sum(nil)),
+		SHA256: hex.EncodeToString(d.sha256.Sum(nil)),
+		SHA512: hex.EncodeToString(d.sha512.Sum(nil)),
+	}
+}
+
+// Digest reads r to the end and returns its digests and length.
+func Digest(r io.Reader) (Digests, int64, error) {
+	d := NewDigester()
+	n, err := io.Copy(d, r)
+	if err != nil {
+		return Digests{}, n, err
+	}
+	return d.Sum(), n, nil
+}
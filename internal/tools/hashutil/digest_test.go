@@ -0,0 +1,77 @@
+package hashutil
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestDigest(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  Digests
+	}{
+		{
+			name:  "empty input",
+			input: "",
+			want: Digests{
+				CRC32:  "00000000",
+				MD5:    "d41d8cd98f00b204e9800998ecf8427e",
+				SHA1:   "da39a3ee5e6b4b0d3255bfef95601890afd80709",
+				SHA256: "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+				SHA512: "cf83e1357eefb8bdf1542850d66d8007d620e4050b5715dc83f4a921d36ce9ce47d0d13c5d85f2b0ff8318d2877eec2f63b931bd47417a81a538327af927da3e",
+			},
+		},
+		{
+			name:  "hello",
+			input: "hello",
+			want: Digests{
+				CRC32:  "3610a686",
+				MD5:    "5d41402abc4b2a76b9719d911017c592",
+				SHA1:   "aaf4c61ddcc5e8a2dabede0f3b482cd9aea9434d",
+				SHA256: "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824",
+				SHA512: "9b71d224bd62f3785d96d46ad3ea3d73319bfbc2890caadae2dff72519673ca72323c3d99ba5c11d7c7acc6e14b8c5da0c4663475c2e5c3adef46f73bcdec043",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, n, err := Digest(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if n != int64(len(tt.input)) {
+				t.Errorf("length = %d, want %d", n, len(tt.input))
+			}
+			if got != tt.want {
+				t.Errorf("Digest(%q)\n got %+v\nwant %+v", tt.input, got, tt.want)
+			}
+			if sha, _ := Hash([]byte(tt.input)); sha != got.SHA256 {
+				t.Errorf("SHA256 %s does not match Hash %s", got.SHA256, sha)
+			}
+		})
+	}
+}
+
+func TestDigesterIncremental(t *testing.T) {
+	d := NewDigester()
+	io.WriteString(d, "hel")
+	io.WriteString(d, "lo")
+	want, _, _ := Digest(strings.NewReader("hello"))
+	if got := d.Sum(); got != want {
+		t.Errorf("incremental digests differ:\n got %+v\nwant %+v", got, want)
+	}
+}
+
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) { return 0, errors.New("boom") }
+
+func TestDigestReadError(t *testing.T) {
+	if _, _, err := Digest(failingReader{}); err == nil {
+		t.Error("expected read error")
+	}
+}
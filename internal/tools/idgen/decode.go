@@ -0,0 +1,155 @@
+package idgen
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+// gregorianOffset is the number of 100-nanosecond intervals between the UUID
+// epoch, 1582-10-15, and the Unix epoch.
+const gregorianOffset = 122192928000000000
+
+var maxUUID = [16]byte{
+	0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+	0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+}
+
+// Detail is one labelled value of a decoded identifier.
+type Detail struct {
+	Label string
+	Value string
+}
+
+// Decoded describes an existing identifier. Version and Variant are set for
+// UUIDs only; Time is the zero time when the identifier has no timestamp.
+type Decoded struct {
+	Type      string
+	Canonical string
+	Hex       string
+	Version   int
+	Variant   string
+	Time      time.Time
+	Details   []Detail
+}
+
+// Decode identifies s as a UUID, ULID or KSUID by its length and extracts
+// the fields it encodes. UUID versions 1, 6 and 7 carry a timestamp.
+func Decode(s string) (*Decoded, error) {
+	s = strings.TrimSpace(s)
+
+	switch len(s) {
+	case 26:
+		u, err := parseULID(s)
+		if err != nil {
+			return nil, err
+		}
+		return &Decoded{
+			Type:      "ULID",
+			Canonical: encodeULID(u),
+			Hex:       hex.EncodeToString(u[:]),
+			Time:      time.UnixMilli(int64(uint48(u[:6]))).UTC(),
+			Details:   []Detail{{"Randomness", hex.EncodeToString(u[6:])}},
+		}, nil
+
+	case 27:
+		k, err := parseKSUID(s)
+		if err != nil {
+			return nil, err
+		}
+		return &Decoded{
+			Type:      "KSUID",
+			Canonical: encodeKSUID(k),
+			Hex:       hex.EncodeToString(k[:]),
+			Time:      ksuidTime(k),
+			Details:   []Detail{{"Payload", hex.EncodeToString(k[4:])}},
+		}, nil
+	}
+
+	u, err := parseUUID(s)
+	if err != nil {
+		return nil, fmt.Errorf("not a UUID, ULID or KSUID: %w", err)
+	}
+	return decodeUUID(u), nil
+}
+
+func decodeUUID(u [16]byte) *Decoded {
+	d := &Decoded{
+		Type:      "UUID",
+		Canonical: formatUUID(u),
+		Hex:       hex.EncodeToString(u[:]),
+		Version:   int(u[6] >> 4),
+		Variant:   uuidVariant(u[8]),
+	}
+
+	switch {
+	case u == [16]byte{}:
+		d.Details = append(d.Details, Detail{"Special", "Nil UUID"})
+		return d
+	case u == maxUUID:
+		d.Details = append(d.Details, Detail{"Special", "Max UUID"})
+		return d
+	}
+	if d.Variant != "RFC 9562" {
+		return d
+	}
+
+	switch d.Version {
+	case 1, 6:
+		var ticks uint64
+		if d.Version == 1 {
+			ticks = uint64(binary.BigEndian.Uint16(u[6:8])&0x0fff)<<48 |
+				uint64(binary.BigEndian.Uint16(u[4:6]))<<32 |
+				uint64(binary.BigEndian.Uint32(u[0:4]))
+		} else {
+			ticks = uint64(binary.BigEndian.Uint32(u[0:4]))<<28 |
+				uint64(binary.BigEndian.Uint16(u[4:6]))<<12 |
+				uint64(binary.BigEndian.Uint16(u[6:8])&0x0fff)
+		}
+		d.Time = uuidTime(ticks)
+
+		node := net.HardwareAddr(u[10:16]).String()
+		if u[10]&0x01 != 0 {
+			node += " (random, multicast bit set)"
+		}
+		d.Details = append(d.Details,
+			Detail{"Clock sequence", fmt.Sprint(binary.BigEndian.Uint16(u[8:10]) & 0x3fff)},
+			Detail{"Node", node},
+		)
+
+	case 7:
+		d.Time = time.UnixMilli(int64(uint48(u[:6]))).UTC()
+		d.Details = append(d.Details, Detail{"Random bits", fmt.Sprintf("%03x %s", binary.BigEndian.Uint16(u[6:8])&0x0fff, hex.EncodeToString(u[8:]))})
+
+	case 2:
+		d.Details = append(d.Details, Detail{"Note", "DCE Security UUID; the timestamp is partly replaced by a local identifier"})
+
+	case 3, 4, 5, 8:
+		names := map[int]string{3: "MD5 name-based", 4: "Random", 5: "SHA-1 name-based", 8: "Custom"}
+		d.Details = append(d.Details, Detail{"Kind", names[d.Version]})
+	}
+	return d
+}
+
+// uuidVariant names the variant encoded in the top bits of octet 8.
+func uuidVariant(b byte) string {
+	switch {
+	case b&0x80 == 0:
+		return "NCS (reserved)"
+	case b&0xc0 == 0x80:
+		return "RFC 9562"
+	case b&0xe0 == 0xc0:
+		return "Microsoft (reserved)"
+	default:
+		return "Future (reserved)"
+	}
+}
+
+// uuidTime converts a count of 100-nanosecond intervals since 1582-10-15.
+func uuidTime(ticks uint64) time.Time {
+	t := int64(ticks) - gregorianOffset
+	return time.Unix(t/1e7, t%1e7*100).UTC()
+}
@@ -0,0 +1,81 @@
+package idgen
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDecode(t *testing.T) {
+	// UUID vectors from RFC 9562 appendix A.
+	vectorTime := time.Date(2022, 2, 22, 19, 22, 22, 0, time.UTC)
+
+	tests := []struct {
+		input   string
+		typ     string
+		version int
+		variant string
+		time    time.Time
+		details map[string]string
+	}{
+		{
+			input: "C232AB00-9414-11EC-B3C8-9F6BDECED846", typ: "UUID", version: 1, variant: "RFC 9562", time: vectorTime,
+			details: map[string]string{"Clock sequence": "13256", "Node": "9f:6b:de:ce:d8:46 (random, multicast bit set)"},
+		},
+		{
+			input: "1EC9414C-232A-6B00-B3C8-9F6BDECED846", typ: "UUID", version: 6, variant: "RFC 9562", time: vectorTime,
+			details: map[string]string{"Clock sequence": "13256"},
+		},
+		{
+			input: "017F22E2-79B0-7CC3-98C4-DC0C0C07398F", typ: "UUID", version: 7, variant: "RFC 9562", time: vectorTime,
+			details: map[string]string{"Random bits": "cc3 98c4dc0c0c07398f"},
+		},
+		{
+			input: "919108f7-52d1-4320-9bac-f847db4148a8", typ: "UUID", version: 4, variant: "RFC 9562",
+			details: map[string]string{"Kind": "Random"},
+		},
+		{
+			input: "00000000-0000-0000-0000-000000000000", typ: "UUID", variant: "NCS (reserved)",
+			details: map[string]string{"Special": "Nil UUID"},
+		},
+		{
+			input: "01ARZ3NDEKTSV4RRFFQ69G5FAV", typ: "ULID", time: time.UnixMilli(1469922850259).UTC(),
+		},
+		{
+			input: "0ujtsYcgvSTl8PAuAdqWYSMnLOv", typ: "KSUID", time: time.Date(2017, 10, 10, 4, 0, 47, 0, time.UTC),
+			details: map[string]string{"Payload": "b5a1cd34b5f99d1154fb6853345c9735"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			d, err := Decode(tt.input)
+			if err != nil {
+				t.Fatalf("Decode() unexpected error: %v", err)
+			}
+			if d.Type != tt.typ || d.Version != tt.version || d.Variant != tt.variant {
+				t.Errorf("Decode() = %s v%d %q, want %s v%d %q", d.Type, d.Version, d.Variant, tt.typ, tt.version, tt.variant)
+			}
+			if !d.Time.Equal(tt.time) {
+				t.Errorf("time = %v, want %v", d.Time, tt.time)
+			}
+
+			got := map[string]string{}
+			for _, detail := range d.Details {
+				got[detail.Label] = detail.Value
+			}
+			for label, want := range tt.details {
+				if got[label] != want {
+					t.Errorf("%s = %q, want %q", label, got[label], want)
+				}
+			}
+		})
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	for _, in := range []string{"", "hello", "01ARZ3NDEKTSV4RRFFQ69G5FA!", "c232ab00-9414-11ec-b3c8-9f6bdeced84g"} {
+		if _, err := Decode(in); err == nil {
+			t.Errorf("Decode(%q) expected error", in)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+// Package idgen generates and decodes unique identifiers: UUIDs (version 4
+// and 7), ULIDs, KSUIDs and nanoids.
+package idgen
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Kind names an identifier format.
+type Kind string
+
+const (
+	KindUUIDv4 Kind = "uuidv4"
+	KindUUIDv7 Kind = "uuidv7"
+	KindULID   Kind = "ulid"
+	KindKSUID  Kind = "ksuid"
+	KindNanoID Kind = "nanoid"
+)
+
+// KindOption describes a selectable identifier format for the UI.
+type KindOption struct {
+	Kind    Kind
+	Name    string
+	Example string
+}
+
+// Kinds lists the formats Generate supports, in display order.
+var Kinds = []KindOption{
+	{KindUUIDv4, "UUID v4", "9b2f0c1e-5d3a-4f6b-8e7c-2a1d0f9e8b7c"},
+	{KindUUIDv7, "UUID v7", "01926b3c-8f2a-7c4d-9e1f-3a5b7c9d1e2f"},
+	{KindULID, "ULID", "01J9NKS3WAFH6TSZK4HX2Z0ZDK"},
+	{KindKSUID, "KSUID", "2n6yEYLXQXRxZU6PKjG3SGkMZpQ"},
+	{KindNanoID, "nanoid", "V1StGXR8_Z5jdHi6B-myT"},
+}
+
+// MaxCount caps the number of identifiers Generate produces at once.
+const MaxCount = 10000
+
+// Options configures Generate. Size and Alphabet apply to nanoids only.
+type Options struct {
+	Kind     Kind
+	Count    int
+	Size     int
+	Alphabet string
+}
+
+// Generate returns opts.Count new identifiers. Time-ordered formats (UUID v7
+// and ULID) are strictly increasing within one call, even when several are
+// created in the same millisecond.
+func Generate(opts Options) ([]string, error) {
+	g := &generator{now: time.Now, rand: rand.Reader}
+	return g.generate(opts)
+}
+
+// generator holds the clock, randomness source and monotonic state for one
+// Generate call.
+type generator struct {
+	now  func() time.Time
+	rand io.Reader
+
+	// lastMS, lastHi and lastLo are the timestamp and random bits of the
+	// previous time-ordered identifier.
+	lastMS int64
+	lastHi uint16
+	lastLo uint64
+}
+
+func (g *generator) generate(opts Options) ([]string, error) {
+	if opts.Count < 1 || opts.Count > MaxCount {
+		return nil, fmt.Errorf("count must be between 1 and %d", MaxCount)
+	}
+
+	var next func() (string, error)
+	switch opts.Kind {
+	case KindUUIDv4:
+		next = g.uuidV4
+	case KindUUIDv7:
+		next = g.uuidV7
+	case KindULID:
+		next = g.ulid
+	case KindKSUID:
+		next = g.ksuid
+	case KindNanoID:
+		alphabet, size, err := nanoIDParams(opts.Alphabet, opts.Size)
+		if err != nil {
+			return nil, err
+		}
+		next = func() (string, error) { return g.nanoID(alphabet, size) }
+	default:
+		return nil, fmt.Errorf("unsupported kind %q", opts.Kind)
+	}
+
+	ids := make([]string, opts.Count)
+	for i := range ids {
+		id, err := next()
+		if err != nil {
+			return nil, err
+		}
+		ids[i] = id
+	}
+	return ids, nil
+}
+
+// monotonic returns the current Unix time in milliseconds and a random
+// number of the given width (at most 80 bits), split into its top 16 and low
+// 64 bits. Within the same millisecond, or if the clock goes backwards, the
+// previous timestamp is reused and the previous number incremented, so the
+// result always sorts after the last one.
+func (g *generator) monotonic(bits int) (ms int64, hi uint16, lo uint64, err error) {
+	hiMask := uint16(1)<<(bits-64) - 1
+
+	ms = g.now().UnixMilli()
+	if ms > g.lastMS {
+		var r [10]byte
+		if _, err := io.ReadFull(g.rand, r[:]); err != nil {
+			return 0, 0, 0, err
+		}
+		g.lastMS = ms
+		g.lastHi = binary.BigEndian.Uint16(r[:2]) & hiMask
+		g.lastLo = binary.BigEndian.Uint64(r[2:])
+		return g.lastMS, g.lastHi, g.lastLo, nil
+	}
+
+	g.lastLo++
+	if g.lastLo == 0 {
+		g.lastHi = (g.lastHi + 1) & hiMask
+		if g.lastHi == 0 {
+			// The counter overflowed; move to the next millisecond.
+			g.lastMS++
+		}
+	}
+	return g.lastMS, g.lastHi, g.lastLo, nil
+}
@@ -0,0 +1,98 @@
+package idgen
+
+import (
+	"bytes"
+	"math/rand"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testGenerator returns a generator with a fixed clock and seeded randomness.
+func testGenerator(now time.Time) *generator {
+	return &generator{
+		now:  func() time.Time { return now },
+		rand: rand.New(rand.NewSource(1)),
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	for _, k := range Kinds {
+		t.Run(string(k.Kind), func(t *testing.T) {
+			ids, err := Generate(Options{Kind: k.Kind, Count: 100})
+			if err != nil {
+				t.Fatalf("Generate() unexpected error: %v", err)
+			}
+			if len(ids) != 100 {
+				t.Fatalf("got %d ids, want 100", len(ids))
+			}
+
+			seen := map[string]bool{}
+			for _, id := range ids {
+				if len(id) != len(k.Example) {
+					t.Errorf("id %q has length %d, want %d like %q", id, len(id), len(k.Example), k.Example)
+				}
+				if seen[id] {
+					t.Errorf("duplicate id %q", id)
+				}
+				seen[id] = true
+			}
+		})
+	}
+}
+
+func TestGenerateMonotonic(t *testing.T) {
+	// With a frozen clock every id shares a millisecond, so order must come
+	// from the counter.
+	for _, kind := range []Kind{KindUUIDv7, KindULID} {
+		g := testGenerator(time.UnixMilli(1700000000000))
+		ids, err := g.generate(Options{Kind: kind, Count: 1000})
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", kind, err)
+		}
+		if !sort.StringsAreSorted(ids) {
+			t.Errorf("%s ids are not strictly increasing", kind)
+		}
+	}
+}
+
+func TestMonotonicOverflow(t *testing.T) {
+	g := testGenerator(time.UnixMilli(5000))
+	g.lastMS, g.lastHi, g.lastLo = 5000, 1<<10-1, ^uint64(0)
+
+	ms, hi, lo, err := g.monotonic(74)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ms != 5001 || hi != 0 || lo != 0 {
+		t.Errorf("monotonic() after overflow = %d %d %d, want 5001 0 0", ms, hi, lo)
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	tests := []struct {
+		opts   Options
+		errMsg string
+	}{
+		{Options{Kind: KindUUIDv4, Count: 0}, "count must be"},
+		{Options{Kind: KindUUIDv4, Count: MaxCount + 1}, "count must be"},
+		{Options{Kind: "snowflake", Count: 1}, "unsupported kind"},
+		{Options{Kind: KindNanoID, Count: 1, Size: MaxNanoIDSize + 1}, "size must be"},
+		{Options{Kind: KindNanoID, Count: 1, Alphabet: "a"}, "between 2 and 256"},
+		{Options{Kind: KindNanoID, Count: 1, Alphabet: "abca"}, "repeats"},
+	}
+	for _, tt := range tests {
+		_, err := Generate(tt.opts)
+		if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+			t.Errorf("Generate(%+v) error = %v, want it to contain %q", tt.opts, err, tt.errMsg)
+		}
+	}
+}
+
+func TestGenerateRandError(t *testing.T) {
+	g := &generator{now: time.Now, rand: bytes.NewReader(nil)}
+	if _, err := g.generate(Options{Kind: KindUUIDv4, Count: 1}); err == nil {
+		t.Error("expected error when the random source is exhausted")
+	}
+}
@@ -0,0 +1,79 @@
+package idgen
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// ksuidEpoch is the KSUID timestamp origin, 2014-05-13T16:53:20Z.
+const ksuidEpoch = 1400000000
+
+const base62 = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// ksuid returns a KSUID: a 32-bit timestamp in seconds since ksuidEpoch and
+// 128 random bits, base62 encoded in 27 characters.
+func (g *generator) ksuid() (string, error) {
+	var k [20]byte
+	binary.BigEndian.PutUint32(k[:4], uint32(g.now().Unix()-ksuidEpoch))
+	if _, err := io.ReadFull(g.rand, k[4:]); err != nil {
+		return "", err
+	}
+	return encodeKSUID(k), nil
+}
+
+// encodeKSUID converts the 160-bit value to base62 by repeated division,
+// left-padding with zeros to 27 digits.
+func encodeKSUID(k [20]byte) string {
+	var words [5]uint32
+	for i := range words {
+		words[i] = binary.BigEndian.Uint32(k[i*4:])
+	}
+
+	b := []byte(strings.Repeat("0", 27))
+	for i := len(b) - 1; i >= 0; i-- {
+		var rem uint64
+		for j := range words {
+			cur := rem<<32 | uint64(words[j])
+			words[j] = uint32(cur / 62)
+			rem = cur % 62
+		}
+		b[i] = base62[rem]
+	}
+	return string(b)
+}
+
+func parseKSUID(s string) ([20]byte, error) {
+	var k [20]byte
+	if len(s) != 27 {
+		return k, fmt.Errorf("invalid KSUID: expected 27 characters, got %d", len(s))
+	}
+
+	var words [5]uint32
+	for i := 0; i < len(s); i++ {
+		v := strings.IndexByte(base62, s[i])
+		if v < 0 {
+			return k, fmt.Errorf("invalid KSUID: character %q at position %d", s[i], i+1)
+		}
+		carry := uint64(v)
+		for j := len(words) - 1; j >= 0; j-- {
+			cur := uint64(words[j])*62 + carry
+			words[j] = uint32(cur)
+			carry = cur >> 32
+		}
+		if carry != 0 {
+			return k, fmt.Errorf("invalid KSUID: value overflows 160 bits")
+		}
+	}
+
+	for i, w := range words {
+		binary.BigEndian.PutUint32(k[i*4:], w)
+	}
+	return k, nil
+}
+
+func ksuidTime(k [20]byte) time.Time {
+	return time.Unix(int64(binary.BigEndian.Uint32(k[:4]))+ksuidEpoch, 0).UTC()
+}
@@ -0,0 +1,50 @@
+package idgen
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestKSUID(t *testing.T) {
+	// Example from the KSUID documentation.
+	k, err := parseKSUID("0ujtsYcgvSTl8PAuAdqWYSMnLOv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.ToUpper(hex.EncodeToString(k[:])); got != "0669F7EFB5A1CD34B5F99D1154FB6853345C9735" {
+		t.Errorf("decoded = %s", got)
+	}
+	if got := ksuidTime(k); !got.Equal(time.Date(2017, 10, 10, 4, 0, 47, 0, time.UTC)) {
+		t.Errorf("time = %v", got)
+	}
+	if got := encodeKSUID(k); got != "0ujtsYcgvSTl8PAuAdqWYSMnLOv" {
+		t.Errorf("encodeKSUID() = %s", got)
+	}
+}
+
+func TestKSUIDGenerate(t *testing.T) {
+	now := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	g := testGenerator(now)
+
+	id, err := g.ksuid()
+	if err != nil {
+		t.Fatal(err)
+	}
+	k, err := parseKSUID(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ksuidTime(k).Equal(now) {
+		t.Errorf("time = %v, want %v", ksuidTime(k), now)
+	}
+}
+
+func TestParseKSUIDErrors(t *testing.T) {
+	for _, in := range []string{"0ujtsYcgvSTl8PAuAdqWYSMnLO", "0ujtsYcgvSTl8PAuAdqWYSMnLO!", "zzzzzzzzzzzzzzzzzzzzzzzzzzz"} {
+		if _, err := parseKSUID(in); err == nil {
+			t.Errorf("parseKSUID(%q) expected error", in)
+		}
+	}
+}
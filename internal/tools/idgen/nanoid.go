@@ -0,0 +1,71 @@
+package idgen
+
+import (
+	"fmt"
+	"io"
+	"math/bits"
+	"unicode/utf8"
+)
+
+// DefaultNanoIDAlphabet is the URL-safe nanoid alphabet.
+const DefaultNanoIDAlphabet = "_-0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+// DefaultNanoIDSize gives about the same collision resistance as UUID v4.
+const DefaultNanoIDSize = 21
+
+// MaxNanoIDSize caps the length of a nanoid.
+const MaxNanoIDSize = 256
+
+// nanoIDParams validates a custom alphabet and size, applying the defaults
+// for zero values.
+func nanoIDParams(alphabet string, size int) ([]rune, int, error) {
+	if alphabet == "" {
+		alphabet = DefaultNanoIDAlphabet
+	}
+	if size == 0 {
+		size = DefaultNanoIDSize
+	}
+	if size < 1 || size > MaxNanoIDSize {
+		return nil, 0, fmt.Errorf("size must be between 1 and %d", MaxNanoIDSize)
+	}
+	if !utf8.ValidString(alphabet) {
+		return nil, 0, fmt.Errorf("alphabet is not valid UTF-8")
+	}
+
+	runes := []rune(alphabet)
+	seen := map[rune]bool{}
+	for _, r := range runes {
+		if seen[r] {
+			return nil, 0, fmt.Errorf("alphabet repeats %q", r)
+		}
+		seen[r] = true
+	}
+	if len(runes) < 2 || len(runes) > 256 {
+		return nil, 0, fmt.Errorf("alphabet must have between 2 and 256 characters")
+	}
+	return runes, size, nil
+}
+
+// nanoID returns size characters drawn uniformly from alphabet. Random bytes
+// are masked to the next power of two and out-of-range values rejected, so
+// no character is more likely than another.
+func (g *generator) nanoID(alphabet []rune, size int) (string, error) {
+	mask := byte(1<<bits.Len(uint(len(alphabet)-1)) - 1)
+
+	id := make([]rune, 0, size)
+	buf := make([]byte, size*2)
+	for len(id) < size {
+		if _, err := io.ReadFull(g.rand, buf); err != nil {
+			return "", err
+		}
+		for _, b := range buf {
+			if i := int(b & mask); i < len(alphabet) {
+				id = append(id, alphabet[i])
+				if len(id) == size {
+					break
+				}
+			}
+		}
+	}
+	return string(id), nil
+}
@@ -0,0 +1,61 @@
+package idgen
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestNanoID(t *testing.T) {
+	tests := []struct {
+		alphabet string
+		size     int
+	}{
+		{"", 0},
+		{"0123456789abcdef", 32},
+		{"abc", 10},
+		{"αβγδ", 8},
+	}
+
+	g := testGenerator(time.Now())
+	for _, tt := range tests {
+		alphabet, size, err := nanoIDParams(tt.alphabet, tt.size)
+		if err != nil {
+			t.Fatal(err)
+		}
+		id, err := g.nanoID(alphabet, size)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		wantAlphabet, wantSize := tt.alphabet, tt.size
+		if wantAlphabet == "" {
+			wantAlphabet, wantSize = DefaultNanoIDAlphabet, DefaultNanoIDSize
+		}
+		if n := utf8.RuneCountInString(id); n != wantSize {
+			t.Errorf("nanoID(%q) = %q has %d characters, want %d", tt.alphabet, id, n, wantSize)
+		}
+		for _, r := range id {
+			if !strings.ContainsRune(wantAlphabet, r) {
+				t.Errorf("nanoID(%q) = %q contains %q", tt.alphabet, id, r)
+			}
+		}
+	}
+}
+
+func TestNanoIDUniform(t *testing.T) {
+	// A three-letter alphabet needs rejection sampling; every letter should
+	// still appear about a third of the time.
+	alphabet, _, _ := nanoIDParams("abc", 1)
+	g := testGenerator(time.Now())
+	id, err := g.nanoID(alphabet, 30000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range alphabet {
+		if n := strings.Count(id, string(r)); n < 9500 || n > 10500 {
+			t.Errorf("%q appeared %d times, want about 10000", r, n)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package idgen
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+)
+
+// crockford is the Crockford base32 alphabet used by ULIDs.
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// ulid returns a ULID: a 48-bit Unix millisecond timestamp and 80 random
+// bits in 26 Crockford base32 characters. ULIDs from one generator are
+// monotonic, as in the ULID spec.
+func (g *generator) ulid() (string, error) {
+	ms, hi, lo, err := g.monotonic(80)
+	if err != nil {
+		return "", err
+	}
+
+	var u [16]byte
+	putUint48(u[:6], uint64(ms))
+	binary.BigEndian.PutUint16(u[6:8], hi)
+	binary.BigEndian.PutUint64(u[8:], lo)
+	return encodeULID(u), nil
+}
+
+// encodeULID writes the 128-bit value as 26 base32 digits, most significant
+// first; the first digit carries only 3 bits.
+func encodeULID(u [16]byte) string {
+	hi := binary.BigEndian.Uint64(u[:8])
+	lo := binary.BigEndian.Uint64(u[8:])
+
+	var b [26]byte
+	for i := len(b) - 1; i >= 0; i-- {
+		b[i] = crockford[lo&31]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(b[:])
+}
+
+// parseULID decodes a ULID case-insensitively, reading I and L as 1 and O as
+// 0 as Crockford base32 allows.
+func parseULID(s string) ([16]byte, error) {
+	var u [16]byte
+	if len(s) != 26 {
+		return u, fmt.Errorf("invalid ULID: expected 26 characters, got %d", len(s))
+	}
+
+	var hi, lo uint64
+	for i, c := range strings.ToUpper(s) {
+		switch c {
+		case 'I', 'L':
+			c = '1'
+		case 'O':
+			c = '0'
+		}
+		v := strings.IndexRune(crockford, c)
+		if v < 0 {
+			return u, fmt.Errorf("invalid ULID: character %q at position %d", c, i+1)
+		}
+		if i == 0 && v > 7 {
+			return u, fmt.Errorf("invalid ULID: value overflows 128 bits")
+		}
+		hi = hi<<5 | lo>>59
+		lo = lo<<5 | uint64(v)
+	}
+
+	binary.BigEndian.PutUint64(u[:8], hi)
+	binary.BigEndian.PutUint64(u[8:], lo)
+	return u, nil
+}
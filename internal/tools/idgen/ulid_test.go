@@ -0,0 +1,46 @@
+package idgen
+
+import (
+	"testing"
+	"time"
+)
+
+func TestULIDRoundTrip(t *testing.T) {
+	g := testGenerator(time.Now())
+	for i := 0; i < 100; i++ {
+		id, err := g.ulid()
+		if err != nil {
+			t.Fatal(err)
+		}
+		u, err := parseULID(id)
+		if err != nil {
+			t.Fatalf("parseULID(%q) unexpected error: %v", id, err)
+		}
+		if got := encodeULID(u); got != id {
+			t.Fatalf("round trip of %q gave %q", id, got)
+		}
+	}
+}
+
+func TestParseULID(t *testing.T) {
+	// Example from the ULID spec.
+	u, err := parseULID("01ARZ3NDEKTSV4RRFFQ69G5FAV")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ms := uint48(u[:6]); ms != 1469922850259 {
+		t.Errorf("timestamp = %d, want 1469922850259", ms)
+	}
+
+	// Crockford aliases decode like the digits they stand for.
+	alias, err := parseULID("olarz3ndektsv4rrffq69g5fav")
+	if err != nil || alias != u {
+		t.Errorf("parseULID with aliases = %x, %v, want %x", alias, err, u)
+	}
+
+	for _, in := range []string{"01ARZ3NDEKTSV4RRFFQ69G5FA", "01ARZ3NDEKTSV4RRFFQ69G5FAU", "81ARZ3NDEKTSV4RRFFQ69G5FAV"} {
+		if _, err := parseULID(in); err == nil {
+			t.Errorf("parseULID(%q) expected error", in)
+		}
+	}
+}
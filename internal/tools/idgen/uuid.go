@@ -0,0 +1,91 @@
+package idgen
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// uuidV4 returns a random (version 4) UUID.
+func (g *generator) uuidV4() (string, error) {
+	var u [16]byte
+	if _, err := io.ReadFull(g.rand, u[:]); err != nil {
+		return "", err
+	}
+	u[6] = u[6]&0x0f | 0x40
+	u[8] = u[8]&0x3f | 0x80
+	return formatUUID(u), nil
+}
+
+// uuidV7 returns a time-ordered (version 7) UUID: a 48-bit Unix millisecond
+// timestamp followed by 74 random bits, per RFC 9562. Within a millisecond
+// the random bits act as a counter (RFC 9562 section 6.2, method 2).
+func (g *generator) uuidV7() (string, error) {
+	ms, hi, lo, err := g.monotonic(74)
+	if err != nil {
+		return "", err
+	}
+
+	// The top 12 bits go in rand_a after the version, the low 62 in rand_b
+	// after the variant.
+	randA := hi<<2 | uint16(lo>>62)
+	randB := lo & (1<<62 - 1)
+
+	var u [16]byte
+	putUint48(u[:6], uint64(ms))
+	binary.BigEndian.PutUint16(u[6:8], 0x7000|randA)
+	binary.BigEndian.PutUint64(u[8:], 0x8000000000000000|randB)
+	return formatUUID(u), nil
+}
+
+func formatUUID(u [16]byte) string {
+	var b [36]byte
+	hex.Encode(b[0:8], u[0:4])
+	b[8] = '-'
+	hex.Encode(b[9:13], u[4:6])
+	b[13] = '-'
+	hex.Encode(b[14:18], u[6:8])
+	b[18] = '-'
+	hex.Encode(b[19:23], u[8:10])
+	b[23] = '-'
+	hex.Encode(b[24:], u[10:])
+	return string(b[:])
+}
+
+// parseUUID accepts the canonical form, 32 bare hex digits, braces and the
+// urn:uuid: prefix.
+func parseUUID(s string) ([16]byte, error) {
+	var u [16]byte
+
+	s = strings.TrimPrefix(strings.ToLower(s), "urn:uuid:")
+	if strings.HasPrefix(s, "{") && strings.HasSuffix(s, "}") {
+		s = s[1 : len(s)-1]
+	}
+	if len(s) == 36 {
+		if s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+			return u, fmt.Errorf("invalid UUID: hyphens in the wrong place")
+		}
+		s = s[0:8] + s[9:13] + s[14:18] + s[19:23] + s[24:]
+	}
+	if len(s) != 32 {
+		return u, fmt.Errorf("invalid UUID: expected 32 hex digits")
+	}
+	if _, err := hex.Decode(u[:], []byte(s)); err != nil {
+		return u, fmt.Errorf("invalid UUID: %w", err)
+	}
+	return u, nil
+}
+
+func putUint48(b []byte, v uint64) {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], v)
+	copy(b, buf[2:])
+}
+
+func uint48(b []byte) uint64 {
+	var buf [8]byte
+	copy(buf[2:], b[:6])
+	return binary.BigEndian.Uint64(buf[:])
+}
@@ -0,0 +1,69 @@
+package idgen
+
+import (
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestUUIDv4(t *testing.T) {
+	re := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	g := testGenerator(time.Now())
+	for i := 0; i < 100; i++ {
+		id, err := g.uuidV4()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !re.MatchString(id) {
+			t.Fatalf("uuidV4() = %q is not a version 4 UUID", id)
+		}
+	}
+}
+
+func TestUUIDv7(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 30, 45, 123000000, time.UTC)
+	g := testGenerator(now)
+
+	id, err := g.uuidV7()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-7[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`).MatchString(id) {
+		t.Fatalf("uuidV7() = %q is not a version 7 UUID", id)
+	}
+
+	d, err := Decode(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !d.Time.Equal(now) {
+		t.Errorf("decoded time = %v, want %v", d.Time, now)
+	}
+}
+
+func TestParseUUID(t *testing.T) {
+	want := "c232ab00-9414-11ec-b3c8-9f6bdeced846"
+	inputs := []string{
+		want,
+		"C232AB00-9414-11EC-B3C8-9F6BDECED846",
+		"{c232ab00-9414-11ec-b3c8-9f6bdeced846}",
+		"urn:uuid:c232ab00-9414-11ec-b3c8-9f6bdeced846",
+		"c232ab00941411ecb3c89f6bdeced846",
+	}
+	for _, in := range inputs {
+		u, err := parseUUID(in)
+		if err != nil {
+			t.Errorf("parseUUID(%q) unexpected error: %v", in, err)
+			continue
+		}
+		if got := formatUUID(u); got != want {
+			t.Errorf("parseUUID(%q) = %s, want %s", in, got, want)
+		}
+	}
+
+	for _, in := range []string{"c232ab00-9414-11ec-b3c8", "c232ab0-09414-11ec-b3c8-9f6bdeced846", "z232ab00941411ecb3c89f6bdeced846"} {
+		if _, err := parseUUID(in); err == nil {
+			t.Errorf("parseUUID(%q) expected error", in)
+		}
+	}
+}
@@ -0,0 +1,252 @@
+package jsonutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/NickDiPreta1/toolhub/internal/tools/hashutil"
+)
+
+// Canonicalize returns the RFC 8785 JSON Canonicalization Scheme (JCS) form of
+// input: no insignificant whitespace, object members sorted by their UTF-16
+// code units, numbers in ECMAScript shortest form and minimal string escaping.
+// Documents with duplicate object keys are rejected, as JCS requires I-JSON.
+func Canonicalize(input string) (string, error) {
+	if err := validateJSON(input); err != nil {
+		return "", err
+	}
+
+	dec := json.NewDecoder(strings.NewReader(input))
+	dec.UseNumber()
+
+	var buf bytes.Buffer
+	if err := canonicalValue(dec, &buf); err != nil {
+		return "", fmt.Errorf("invalid JSON: %w", err)
+	}
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("invalid JSON: unexpected data after top-level value")
+	}
+
+	return buf.String(), nil
+}
+
+// CanonicalHash canonicalizes input and returns both the canonical text and
+// its SHA-256 hash, so semantically equal documents hash identically.
+func CanonicalHash(input string) (canonical string, hash string, err error) {
+	canonical, err = Canonicalize(input)
+	if err != nil {
+		return "", "", err
+	}
+
+	hash, err = hashutil.Hash([]byte(canonical))
+	if err != nil {
+		return "", "", err
+	}
+
+	return canonical, hash, nil
+}
+
+// canonicalValue reads the next value from dec and writes its canonical form.
+func canonicalValue(dec *json.Decoder, buf *bytes.Buffer) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+
+	switch t := tok.(type) {
+	case json.Delim:
+		if t == '[' {
+			return canonicalArray(dec, buf)
+		}
+		if t == '{' {
+			return canonicalObject(dec, buf)
+		}
+		return fmt.Errorf("unexpected %q", t)
+	case string:
+		writeCanonicalString(buf, t)
+	case json.Number:
+		s, err := canonicalNumber(t)
+		if err != nil {
+			return err
+		}
+		buf.WriteString(s)
+	case bool:
+		buf.WriteString(strconv.FormatBool(t))
+	case nil:
+		buf.WriteString("null")
+	}
+
+	return nil
+}
+
+func canonicalArray(dec *json.Decoder, buf *bytes.Buffer) error {
+	buf.WriteByte('[')
+	for i := 0; dec.More(); i++ {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if err := canonicalValue(dec, buf); err != nil {
+			return err
+		}
+	}
+	if _, err := dec.Token(); err != nil {
+		return err
+	}
+	buf.WriteByte(']')
+
+	return nil
+}
+
+func canonicalObject(dec *json.Decoder, buf *bytes.Buffer) error {
+	type member struct {
+		key   string
+		units []uint16
+		value []byte
+	}
+
+	var members []member
+	seen := map[string]bool{}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key := tok.(string)
+		if seen[key] {
+			return fmt.Errorf("duplicate object key %q", key)
+		}
+		seen[key] = true
+
+		var value bytes.Buffer
+		if err := canonicalValue(dec, &value); err != nil {
+			return err
+		}
+		members = append(members, member{key: key, units: utf16.Encode([]rune(key)), value: value.Bytes()})
+	}
+	if _, err := dec.Token(); err != nil {
+		return err
+	}
+
+	sort.Slice(members, func(i, j int) bool {
+		return compareUTF16(members[i].units, members[j].units) < 0
+	})
+
+	buf.WriteByte('{')
+	for i, m := range members {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		writeCanonicalString(buf, m.key)
+		buf.WriteByte(':')
+		buf.Write(m.value)
+	}
+	buf.WriteByte('}')
+
+	return nil
+}
+
+// compareUTF16 orders two strings by their UTF-16 code units, as JCS requires.
+func compareUTF16(a, b []uint16) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			if a[i] < b[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+
+	return len(a) - len(b)
+}
+
+// writeCanonicalString escapes only what JSON requires: quotes, backslashes
+// and control characters. Everything else is written as raw UTF-8.
+func writeCanonicalString(buf *bytes.Buffer, s string) {
+	const hex = "0123456789abcdef"
+
+	buf.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			buf.WriteString(`\"`)
+		case '\\':
+			buf.WriteString(`\\`)
+		case '\b':
+			buf.WriteString(`\b`)
+		case '\f':
+			buf.WriteString(`\f`)
+		case '\n':
+			buf.WriteString(`\n`)
+		case '\r':
+			buf.WriteString(`\r`)
+		case '\t':
+			buf.WriteString(`\t`)
+		default:
+			if r < 0x20 {
+				buf.WriteString(`\u00`)
+				buf.WriteByte(hex[r>>4])
+				buf.WriteByte(hex[r&0xf])
+				continue
+			}
+			buf.WriteRune(r)
+		}
+	}
+	buf.WriteByte('"')
+}
+
+// canonicalNumber formats n the way ECMAScript's Number.prototype.toString
+// formats an IEEE 754 double.
+func canonicalNumber(n json.Number) (string, error) {
+	f, err := strconv.ParseFloat(n.String(), 64)
+	if err != nil || math.IsInf(f, 0) || math.IsNaN(f) {
+		return "", fmt.Errorf("number %s cannot be represented as a double", n)
+	}
+	if f == 0 {
+		return "0", nil
+	}
+
+	var sign string
+	if f < 0 {
+		sign = "-"
+		f = -f
+	}
+
+	// Shortest round-tripping digits and decimal exponent: f = 0.digits × 10^point.
+	sci := strconv.FormatFloat(f, 'e', -1, 64)
+	mantissa, expStr, _ := strings.Cut(sci, "e")
+	digits := strings.Replace(mantissa, ".", "", 1)
+	exp, _ := strconv.Atoi(expStr)
+	k := len(digits)
+	point := exp + 1
+
+	var out string
+	switch {
+	case k <= point && point <= 21:
+		out = digits + strings.Repeat("0", point-k)
+	case 0 < point && point <= 21:
+		out = digits[:point] + "." + digits[point:]
+	case -6 < point && point <= 0:
+		out = "0." + strings.Repeat("0", -point) + digits
+	default:
+		out = digits[:1]
+		if k > 1 {
+			out += "." + digits[1:]
+		}
+		e := point - 1
+		if e >= 0 {
+			out += "e+" + strconv.Itoa(e)
+		} else {
+			out += "e-" + strconv.Itoa(-e)
+		}
+	}
+
+	return sign + out, nil
+}
@@ -0,0 +1,106 @@
+package jsonutil
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestCanonicalize(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		expected    string
+		expectError bool
+	}{
+		// Structure and ordering
+		{
+			name:     "whitespace removed and keys sorted",
+			input:    "{\n  \"b\": [1, 2],\n  \"a\": {\"d\": true, \"c\": null}\n}",
+			expected: `{"a":{"c":null,"d":true},"b":[1,2]}`,
+		},
+		{
+			// Example from RFC 8785 section 3.2.3.
+			name:     "utf-16 key ordering",
+			input:    `{"\u20ac":"Euro Sign","\r":"Carriage Return","\ufb33":"Hebrew Letter Dalet With Dagesh","1":"One","\ud83d\ude00":"Emoji: Grinning Face","\u0080":"Control","\u00f6":"Latin Small Letter O With Diaeresis"}`,
+			expected: "{\"\\r\":\"Carriage Return\",\"1\":\"One\",\"\u0080\":\"Control\",\"ö\":\"Latin Small Letter O With Diaeresis\",\"€\":\"Euro Sign\",\"\U0001F600\":\"Emoji: Grinning Face\",\"\ufb33\":\"Hebrew Letter Dalet With Dagesh\"}",
+		},
+
+		// Strings
+		{
+			name:     "minimal escaping",
+			input:    `"<tag> & \u00e9 \u0001 \/ \""`,
+			expected: "\"<tag> & é \\u0001 / \\\"\"",
+		},
+
+		// Numbers (examples from RFC 8785 appendix B)
+		{name: "zero", input: `0`, expected: `0`},
+		{name: "negative zero", input: `-0`, expected: `0`},
+		{name: "integer", input: `1e2`, expected: `100`},
+		{name: "fraction", input: `4.50`, expected: `4.5`},
+		{name: "small fraction", input: `0.002`, expected: `0.002`},
+		{name: "small exponent", input: `1e-7`, expected: `1e-7`},
+		{name: "threshold before exponent", input: `1e20`, expected: `100000000000000000000`},
+		{name: "large exponent", input: `1e21`, expected: `1e+21`},
+		{name: "large mantissa", input: `1.5e30`, expected: `1.5e+30`},
+		{name: "rounding", input: `333333333.33333329`, expected: `333333333.3333333`},
+		{name: "micro", input: `0.000001`, expected: `0.000001`},
+		{name: "max safe integer", input: `9007199254740991`, expected: `9007199254740991`},
+
+		// Errors
+		{name: "empty", input: ``, expectError: true},
+		{name: "duplicate keys", input: `{"a":1,"a":2}`, expectError: true},
+		{name: "overflowing number", input: `1e400`, expectError: true},
+		{name: "trailing data", input: `{} []`, expectError: true},
+		{name: "invalid", input: `{"a":}`, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Canonicalize(tt.input)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Canonicalize(%q) expected error but got nil", tt.input)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Canonicalize(%q) unexpected error: %v", tt.input, err)
+			}
+			if got != tt.expected {
+				t.Errorf("Canonicalize(%q)\ngot:  %s\nwant: %s", tt.input, got, tt.expected)
+			}
+			if !json.Valid([]byte(got)) {
+				t.Errorf("Canonicalize(%q) produced invalid JSON: %s", tt.input, got)
+			}
+		})
+	}
+}
+
+func TestCanonicalHashStable(t *testing.T) {
+	a := `{"name":"toolhub","tags":["go","web"],"version":1.0}`
+	b := "{\n  \"version\": 1,\n  \"tags\": [\"go\", \"web\"],\n  \"name\": \"tool\\u0068ub\"\n}"
+
+	canonA, hashA, err := CanonicalHash(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	canonB, hashB, err := CanonicalHash(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if canonA != canonB {
+		t.Errorf("canonical forms differ:\n%s\n%s", canonA, canonB)
+	}
+	if hashA != hashB {
+		t.Errorf("hashes differ: %s vs %s", hashA, hashB)
+	}
+
+	_, hashC, err := CanonicalHash(`{"name":"toolhub","tags":["web","go"],"version":1}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hashC == hashA {
+		t.Error("reordered array produced the same hash")
+	}
+}
@@ -0,0 +1,624 @@
+package jsonutil
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Fix records one change Repair made to its input.
+type Fix struct {
+	Line        int
+	Column      int
+	Description string
+}
+
+func (f Fix) String() string {
+	return fmt.Sprintf("line %d, column %d: %s", f.Line, f.Column, f.Description)
+}
+
+// ParseLenient accepts JSON5 and JSONC input (comments, trailing commas,
+// single-quoted strings, unquoted keys, hex numbers, leading or trailing
+// decimal points and explicit plus signs) and returns equivalent strict JSON
+// in compact form.
+func ParseLenient(input string) (string, error) {
+	p := newLenientParser(input, false)
+	if err := p.document(); err != nil {
+		return "", err
+	}
+
+	return p.out.String(), nil
+}
+
+// Repair accepts everything ParseLenient does and additionally fixes common
+// breakage: smart quotes, Python literals (True, False, None), missing commas,
+// unterminated strings, truncated documents and stray closing brackets. It
+// returns strict compact JSON together with every fix it applied, including
+// the JSON5 conveniences that strict JSON does not allow.
+func Repair(input string) (string, []Fix, error) {
+	p := newLenientParser(input, true)
+	if err := p.document(); err != nil {
+		return "", p.fixes, err
+	}
+
+	return p.out.String(), p.fixes, nil
+}
+
+// lenientParser is a recursive-descent parser that writes strict JSON as it
+// reads. In repair mode it records a Fix for every deviation from strict JSON
+// and recovers from errors it knows how to fix.
+type lenientParser struct {
+	src    []rune
+	pos    int
+	repair bool
+	out    bytes.Buffer
+	fixes  []Fix
+
+	// lineStarts holds the offset of every line, filled in by position.
+	lineStarts []int
+}
+
+func newLenientParser(input string, repair bool) *lenientParser {
+	return &lenientParser{src: []rune(input), repair: repair}
+}
+
+func (p *lenientParser) eof() bool { return p.pos >= len(p.src) }
+
+func (p *lenientParser) peek() rune {
+	if p.eof() {
+		return 0
+	}
+
+	return p.src[p.pos]
+}
+
+// position converts a rune offset into a 1-based line and column. The line
+// starts are found on first use, so each lookup is a binary search rather
+// than a scan from the start of the input.
+func (p *lenientParser) position(offset int) (int, int) {
+	if p.lineStarts == nil {
+		p.lineStarts = []int{0}
+		for i, r := range p.src {
+			if r == '\n' {
+				p.lineStarts = append(p.lineStarts, i+1)
+			}
+		}
+	}
+
+	offset = min(offset, len(p.src))
+	line := sort.SearchInts(p.lineStarts, offset+1)
+
+	return line, offset - p.lineStarts[line-1] + 1
+}
+
+// fix records a repair at offset. In lenient mode it does nothing.
+func (p *lenientParser) fix(offset int, format string, args ...any) {
+	if !p.repair {
+		return
+	}
+	line, col := p.position(offset)
+	p.fixes = append(p.fixes, Fix{Line: line, Column: col, Description: fmt.Sprintf(format, args...)})
+}
+
+// errorf reports an error at the current position.
+func (p *lenientParser) errorf(format string, args ...any) error {
+	return p.errorAt(p.pos, format, args...)
+}
+
+// errorAt reports an error at offset, in the same format as Fix.String.
+func (p *lenientParser) errorAt(offset int, format string, args ...any) error {
+	line, col := p.position(offset)
+	return fmt.Errorf("line %d, column %d: %s", line, col, fmt.Sprintf(format, args...))
+}
+
+func (p *lenientParser) document() error {
+	if strings.TrimSpace(string(p.src)) == "" {
+		return fmt.Errorf("input cannot be empty")
+	}
+
+	if err := p.value(); err != nil {
+		return err
+	}
+
+	p.skipSpace()
+	for !p.eof() {
+		if !p.repair {
+			return p.errorf("unexpected %q after top-level value", p.peek())
+		}
+		start := p.pos
+		if c := p.peek(); c == '}' || c == ']' || c == ',' {
+			p.pos++
+			p.fix(start, "removed stray %q", c)
+		} else {
+			p.pos = len(p.src)
+			p.fix(start, "removed trailing text after the top-level value")
+		}
+		p.skipSpace()
+	}
+
+	return nil
+}
+
+// skipSpace skips whitespace and JavaScript-style comments.
+func (p *lenientParser) skipSpace() {
+	for !p.eof() {
+		c := p.peek()
+		switch {
+		case unicode.IsSpace(c) || c == '\uFEFF':
+			p.pos++
+		case c == '/' && p.pos+1 < len(p.src) && p.src[p.pos+1] == '/':
+			start := p.pos
+			for !p.eof() && p.peek() != '\n' {
+				p.pos++
+			}
+			p.fix(start, "removed line comment")
+		case c == '/' && p.pos+1 < len(p.src) && p.src[p.pos+1] == '*':
+			start := p.pos
+			p.pos += 2
+			for !p.eof() && !(p.peek() == '*' && p.pos+1 < len(p.src) && p.src[p.pos+1] == '/') {
+				p.pos++
+			}
+			p.pos = min(p.pos+2, len(p.src))
+			p.fix(start, "removed block comment")
+		default:
+			return
+		}
+	}
+}
+
+func (p *lenientParser) value() error {
+	p.skipSpace()
+	if p.eof() {
+		if p.repair {
+			p.fix(p.pos, "inserted null for missing value at end of input")
+			p.out.WriteString("null")
+			return nil
+		}
+		return p.errorf("unexpected end of input")
+	}
+
+	switch c := p.peek(); {
+	case c == '{':
+		return p.object()
+	case c == '[':
+		return p.array()
+	case c == '"' || c == '\'' || isSmartQuote(c):
+		s, err := p.str()
+		if err != nil {
+			return err
+		}
+		writeCanonicalString(&p.out, s)
+		return nil
+	case c == '-' || c == '+' || c == '.' || (c >= '0' && c <= '9'):
+		return p.number()
+	case isIdentStart(c):
+		return p.literal()
+	default:
+		return p.errorf("unexpected %q", c)
+	}
+}
+
+func (p *lenientParser) object() error {
+	open := p.pos
+	p.pos++
+	p.out.WriteByte('{')
+
+	first := true
+	for {
+		p.skipSpace()
+		if p.eof() {
+			if !p.repair {
+				return p.errorf("unterminated object starting at offset %d", open)
+			}
+			p.fix(p.pos, "added missing '}'")
+			p.out.WriteByte('}')
+			return nil
+		}
+		if p.peek() == '}' {
+			p.pos++
+			p.out.WriteByte('}')
+			return nil
+		}
+
+		if !first {
+			p.out.WriteByte(',')
+		}
+		first = false
+
+		if err := p.key(); err != nil {
+			return err
+		}
+
+		p.skipSpace()
+		switch {
+		case p.peek() == ':':
+			p.pos++
+		case p.repair:
+			p.fix(p.pos, "inserted missing ':'")
+		default:
+			return p.errorf("expected ':' after object key")
+		}
+		p.out.WriteByte(':')
+
+		if err := p.value(); err != nil {
+			return err
+		}
+
+		if err := p.separator('}'); err != nil {
+			return err
+		}
+	}
+}
+
+func (p *lenientParser) array() error {
+	p.pos++
+	p.out.WriteByte('[')
+
+	first := true
+	for {
+		p.skipSpace()
+		if p.eof() {
+			if !p.repair {
+				return p.errorf("unterminated array")
+			}
+			p.fix(p.pos, "added missing ']'")
+			p.out.WriteByte(']')
+			return nil
+		}
+		if p.peek() == ']' {
+			p.pos++
+			p.out.WriteByte(']')
+			return nil
+		}
+
+		if !first {
+			p.out.WriteByte(',')
+		}
+		first = false
+
+		if err := p.value(); err != nil {
+			return err
+		}
+
+		if err := p.separator(']'); err != nil {
+			return err
+		}
+	}
+}
+
+// separator consumes the comma after a member, tolerating trailing commas
+// and, in repair mode, missing commas.
+func (p *lenientParser) separator(closer rune) error {
+	p.skipSpace()
+	if p.eof() {
+		return nil
+	}
+
+	switch c := p.peek(); {
+	case c == ',':
+		comma := p.pos
+		p.pos++
+		p.skipSpace()
+		if p.peek() == closer {
+			p.fix(comma, "removed trailing comma")
+		}
+		return nil
+	case c == closer:
+		return nil
+	case p.repair && (c == '}' || c == ']'):
+		// A mismatched closer: assume the intended one was forgotten.
+		p.fix(p.pos, "replaced mismatched %q with %q", c, closer)
+		p.src[p.pos] = closer
+		return nil
+	case p.repair:
+		p.fix(p.pos, "inserted missing ','")
+		return nil
+	default:
+		return p.errorf("expected ',' or %q, found %q", closer, c)
+	}
+}
+
+func (p *lenientParser) key() error {
+	c := p.peek()
+	if c == '"' || c == '\'' || isSmartQuote(c) {
+		s, err := p.str()
+		if err != nil {
+			return err
+		}
+		writeCanonicalString(&p.out, s)
+		return nil
+	}
+
+	if !isIdentStart(c) {
+		return p.errorf("expected object key, found %q", c)
+	}
+
+	start := p.pos
+	for !p.eof() && isIdentPart(p.peek()) {
+		p.pos++
+	}
+	name := string(p.src[start:p.pos])
+	p.fix(start, "quoted key %s", name)
+	writeCanonicalString(&p.out, name)
+
+	return nil
+}
+
+// str reads a quoted string and returns its decoded contents.
+func (p *lenientParser) str() (string, error) {
+	start := p.pos
+	quote := p.peek()
+	p.pos++
+
+	closers := map[rune]bool{quote: true}
+	switch quote {
+	case '\'':
+		p.fix(start, "converted single-quoted string")
+	case '“', '”', '„':
+		p.fix(start, "replaced smart quotes")
+		closers = map[rune]bool{'“': true, '”': true, '"': true}
+	case '‘', '’':
+		p.fix(start, "replaced smart quotes")
+		closers = map[rune]bool{'‘': true, '’': true, '\'': true}
+	}
+	if isSmartQuote(quote) && !p.repair {
+		return "", p.errorf("unexpected %q", quote)
+	}
+
+	var b strings.Builder
+	for {
+		if p.eof() {
+			if !p.repair {
+				return "", p.errorAt(start, "unterminated string")
+			}
+			p.fix(p.pos, "closed unterminated string")
+			return b.String(), nil
+		}
+
+		c := p.peek()
+		p.pos++
+
+		switch {
+		case closers[c]:
+			return b.String(), nil
+		case c == '\n' && p.repair:
+			p.fix(p.pos-1, "escaped raw newline in string")
+			b.WriteRune(c)
+		case c == '\\':
+			if err := p.escape(&b); err != nil {
+				return "", err
+			}
+		default:
+			b.WriteRune(c)
+		}
+	}
+}
+
+// escape decodes one backslash escape, including the JSON5 additions \',
+// \x and line continuations.
+func (p *lenientParser) escape(b *strings.Builder) error {
+	if p.eof() {
+		return nil
+	}
+
+	c := p.peek()
+	p.pos++
+
+	switch c {
+	case 'b':
+		b.WriteByte('\b')
+	case 'f':
+		b.WriteByte('\f')
+	case 'n':
+		b.WriteByte('\n')
+	case 'r':
+		b.WriteByte('\r')
+	case 't':
+		b.WriteByte('\t')
+	case 'v':
+		b.WriteByte('\v')
+	case '0':
+		b.WriteByte(0)
+	case '\n':
+		// Line continuation: the backslash and newline are dropped.
+	case '\r':
+		if p.peek() == '\n' {
+			p.pos++
+		}
+	case 'x':
+		return p.hexEscape(b, 2)
+	case 'u':
+		return p.hexEscape(b, 4)
+	default:
+		// \" \' \\ \/ and any other character escape themselves.
+		b.WriteRune(c)
+	}
+
+	return nil
+}
+
+func (p *lenientParser) hexEscape(b *strings.Builder, digits int) error {
+	if p.pos+digits > len(p.src) {
+		return p.errorf("incomplete escape sequence")
+	}
+
+	n, err := strconv.ParseUint(string(p.src[p.pos:p.pos+digits]), 16, 32)
+	if err != nil {
+		return p.errorf("invalid escape sequence")
+	}
+	p.pos += digits
+
+	r := rune(n)
+	// Join UTF-16 surrogate pairs written as two \u escapes.
+	if r >= 0xD800 && r < 0xDC00 && p.pos+6 <= len(p.src) && string(p.src[p.pos:p.pos+2]) == `\u` {
+		if lo, err := strconv.ParseUint(string(p.src[p.pos+2:p.pos+6]), 16, 32); err == nil && lo >= 0xDC00 && lo < 0xE000 {
+			r = (r-0xD800)<<10 + (rune(lo) - 0xDC00) + 0x10000
+			p.pos += 6
+		}
+	}
+	b.WriteRune(r)
+
+	return nil
+}
+
+func (p *lenientParser) number() error {
+	start := p.pos
+	for !p.eof() && strings.ContainsRune("+-.0123456789abcdefABCDEFxXI", p.peek()) {
+		// Stop "Infinity" from swallowing the rest of the identifier.
+		if p.peek() == 'I' {
+			break
+		}
+		p.pos++
+	}
+	if !p.eof() && p.peek() == 'I' {
+		return p.errorf("Infinity cannot be represented in JSON")
+	}
+	raw := string(p.src[start:p.pos])
+
+	sign := ""
+	body := raw
+	switch {
+	case strings.HasPrefix(body, "+"):
+		p.fix(start, "removed leading '+'")
+		body = body[1:]
+	case strings.HasPrefix(body, "-"):
+		sign = "-"
+		body = body[1:]
+	}
+
+	if strings.HasPrefix(body, "0x") || strings.HasPrefix(body, "0X") {
+		n, err := strconv.ParseUint(body[2:], 16, 64)
+		if err != nil {
+			return p.errorf("invalid hexadecimal number %q", raw)
+		}
+		p.fix(start, "converted hexadecimal %s", raw)
+		p.out.WriteString(sign + strconv.FormatUint(n, 10))
+		return nil
+	}
+
+	// Only one sign is allowed, and the mantissa needs a digit, so "--1"
+	// and ".e5" are refused rather than passed on or rewritten.
+	if !isDecimal(body) {
+		return p.errorf("invalid number %q", raw)
+	}
+	if strings.HasPrefix(body, ".") {
+		p.fix(start, "added leading zero to %s", raw)
+		body = "0" + body
+	}
+	if mantissa, exp, hasExp := strings.Cut(strings.ToLower(body), "e"); strings.HasSuffix(mantissa, ".") {
+		p.fix(start, "removed trailing decimal point from %s", raw)
+		body = strings.TrimSuffix(mantissa, ".")
+		if hasExp {
+			body += "e" + exp
+		}
+	}
+	if len(body) > 1 && body[0] == '0' && body[1] >= '0' && body[1] <= '9' {
+		if !p.repair {
+			return p.errorf("invalid number %q", raw)
+		}
+		p.fix(start, "removed leading zeros from %s", raw)
+		body = strings.TrimLeft(body, "0")
+		if body == "" || body[0] == '.' || body[0] == 'e' || body[0] == 'E' {
+			body = "0" + body
+		}
+	}
+
+	if _, err := strconv.ParseFloat(body, 64); err != nil && !strings.Contains(err.Error(), "range") {
+		return p.errorf("invalid number %q", raw)
+	}
+	p.out.WriteString(sign + body)
+
+	return nil
+}
+
+// isDecimal reports whether s is an unsigned JSON5 decimal number: digits
+// with an optional decimal point on either side of them and an optional
+// exponent.
+func isDecimal(s string) bool {
+	digits := func() int {
+		n := 0
+		for len(s) > 0 && s[0] >= '0' && s[0] <= '9' {
+			s = s[1:]
+			n++
+		}
+		return n
+	}
+
+	n := digits()
+	if strings.HasPrefix(s, ".") {
+		s = s[1:]
+		n += digits()
+	}
+	if n == 0 {
+		return false
+	}
+	if len(s) > 0 && (s[0] == 'e' || s[0] == 'E') {
+		s = s[1:]
+		if len(s) > 0 && (s[0] == '+' || s[0] == '-') {
+			s = s[1:]
+		}
+		if digits() == 0 {
+			return false
+		}
+	}
+	return s == ""
+}
+
+// literal handles true, false, null and, in repair mode, their Python and
+// JavaScript spellings.
+func (p *lenientParser) literal() error {
+	start := p.pos
+	for !p.eof() && isIdentPart(p.peek()) {
+		p.pos++
+	}
+	word := string(p.src[start:p.pos])
+
+	switch word {
+	case "true", "false", "null":
+		p.out.WriteString(word)
+		return nil
+	case "Infinity", "NaN":
+		return p.errorAt(start, "%s cannot be represented in JSON", word)
+	}
+
+	if p.repair {
+		replacements := map[string]string{
+			"True": "true", "False": "false", "None": "null",
+			"TRUE": "true", "FALSE": "false", "NULL": "null",
+			"undefined": "null", "nil": "null",
+		}
+		if repl, ok := replacements[word]; ok {
+			p.fix(start, "replaced %s with %s", word, repl)
+			p.out.WriteString(repl)
+			return nil
+		}
+
+		// Treat any other bare word as an unquoted string.
+		p.fix(start, "quoted bare word %s", word)
+		writeCanonicalString(&p.out, word)
+		return nil
+	}
+
+	p.pos = start
+	return p.errorf("unexpected identifier %q", word)
+}
+
+func isSmartQuote(r rune) bool {
+	switch r {
+	case '“', '”', '„', '‘', '’':
+		return true
+	}
+
+	return false
+}
+
+func isIdentStart(r rune) bool {
+	return r == '_' || r == '$' || unicode.IsLetter(r)
+}
+
+func isIdentPart(r rune) bool {
+	return isIdentStart(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,235 @@
+package jsonutil
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseLenient(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		expected    string
+		expectError bool
+	}{
+		// JSONC
+		{
+			name:     "line and block comments",
+			input:    "{\n  // name of the service\n  \"name\": \"api\", /* port */ \"port\": 80\n}",
+			expected: `{"name":"api","port":80}`,
+		},
+		{
+			name:     "trailing commas",
+			input:    `{"a":[1,2,],}`,
+			expected: `{"a":[1,2]}`,
+		},
+
+		// JSON5
+		{
+			name:     "unquoted keys and single quotes",
+			input:    `{name: 'O\'Brien', $id: 1, _x: "y"}`,
+			expected: `{"name":"O'Brien","$id":1,"_x":"y"}`,
+		},
+		{
+			name:     "hex and decimal point forms",
+			input:    `[0x1F, .5, 5., +3, -0xA]`,
+			expected: `[31,0.5,5,3,-10]`,
+		},
+		{
+			name:     "line continuation and hex escape",
+			input:    "'line \\\none \\x41'",
+			expected: `"line one A"`,
+		},
+		{
+			name:     "strict JSON passes through",
+			input:    `{"a": {"b": [true, false, null, 1e3, "é"]}}`,
+			expected: `{"a":{"b":[true,false,null,1e3,"é"]}}`,
+		},
+
+		// Errors: lenient does not repair broken input
+		{name: "empty", input: "  ", expectError: true},
+		{name: "python literal", input: `{"a": True}`, expectError: true},
+		{name: "truncated", input: `{"a": [1, 2`, expectError: true},
+		{name: "missing comma", input: `[1 2]`, expectError: true},
+		{name: "smart quotes", input: `{“a”: 1}`, expectError: true},
+		{name: "infinity", input: `{"a": Infinity}`, expectError: true},
+		{name: "leading zero", input: `[007]`, expectError: true},
+		{name: "double minus", input: `[--1]`, expectError: true},
+		{name: "plus then minus", input: `[+-1]`, expectError: true},
+		{name: "mantissa without digits", input: `[.e5]`, expectError: true},
+		{name: "lone decimal point", input: `[.]`, expectError: true},
+		{name: "exponent without digits", input: `[1e+]`, expectError: true},
+		{name: "trailing data", input: `{} x`, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseLenient(tt.input)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("ParseLenient(%q) expected error but got %q", tt.input, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseLenient(%q) unexpected error: %v", tt.input, err)
+			}
+			if got != tt.expected {
+				t.Errorf("ParseLenient(%q)\ngot:  %s\nwant: %s", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRepair(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+		fixes    []string
+	}{
+		{
+			name:     "trailing comma",
+			input:    `{"a": 1,}`,
+			expected: `{"a":1}`,
+			fixes:    []string{"line 1, column 8: removed trailing comma"},
+		},
+		{
+			name:     "smart quotes",
+			input:    `{“name”: ‘Ann’}`,
+			expected: `{"name":"Ann"}`,
+			fixes:    []string{"line 1, column 2: replaced smart quotes", "line 1, column 10: replaced smart quotes"},
+		},
+		{
+			name:     "python literals",
+			input:    "{'ok': True,\n 'err': None}",
+			expected: `{"ok":true,"err":null}`,
+			fixes: []string{
+				"line 1, column 2: converted single-quoted string",
+				"line 1, column 8: replaced True with true",
+				"line 2, column 2: converted single-quoted string",
+				"line 2, column 9: replaced None with null",
+			},
+		},
+		{
+			name:     "truncated document",
+			input:    `{"a": [1, 2`,
+			expected: `{"a":[1,2]}`,
+			fixes:    []string{"line 1, column 12: added missing ']'", "line 1, column 12: added missing '}'"},
+		},
+		{
+			name:     "truncated string and value",
+			input:    `{"a": "hel`,
+			expected: `{"a":"hel"}`,
+			fixes:    []string{"line 1, column 11: closed unterminated string", "line 1, column 11: added missing '}'"},
+		},
+		{
+			name:     "missing value at end",
+			input:    `{"a":`,
+			expected: `{"a":null}`,
+			fixes:    []string{"line 1, column 6: inserted null for missing value at end of input", "line 1, column 6: added missing '}'"},
+		},
+		{
+			name:     "missing comma",
+			input:    `[1 2]`,
+			expected: `[1,2]`,
+			fixes:    []string{"line 1, column 4: inserted missing ','"},
+		},
+		{
+			name:     "stray closing brace",
+			input:    `{"a":1}}`,
+			expected: `{"a":1}`,
+			fixes:    []string{"line 1, column 8: removed stray '}'"},
+		},
+		{
+			name:     "mismatched closer",
+			input:    `[1, 2}`,
+			expected: `[1,2]`,
+			fixes:    []string{"line 1, column 6: replaced mismatched '}' with ']'"},
+		},
+		{
+			name:     "comments and unquoted keys",
+			input:    "{a: 1 // one\n}",
+			expected: `{"a":1}`,
+			fixes:    []string{"line 1, column 2: quoted key a", "line 1, column 7: removed line comment"},
+		},
+		{
+			name:     "valid JSON needs no fixes",
+			input:    `{"a": [1, 2]}`,
+			expected: `{"a":[1,2]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, fixes, err := Repair(tt.input)
+			if err != nil {
+				t.Fatalf("Repair(%q) unexpected error: %v", tt.input, err)
+			}
+			if got != tt.expected {
+				t.Errorf("Repair(%q)\ngot:  %s\nwant: %s", tt.input, got, tt.expected)
+			}
+			if !json.Valid([]byte(got)) {
+				t.Errorf("Repair(%q) produced invalid JSON: %s", tt.input, got)
+			}
+
+			var descriptions []string
+			for _, f := range fixes {
+				descriptions = append(descriptions, f.String())
+			}
+			if strings.Join(descriptions, "\n") != strings.Join(tt.fixes, "\n") {
+				t.Errorf("Repair(%q) fixes\ngot:\n%s\nwant:\n%s", tt.input, strings.Join(descriptions, "\n"), strings.Join(tt.fixes, "\n"))
+			}
+		})
+	}
+}
+
+func TestRepairUnrecoverable(t *testing.T) {
+	for _, input := range []string{"", `{"a": Infinity}`, `[@]`, `[--1]`, `[+-1]`, `[.e5]`} {
+		if _, _, err := Repair(input); err == nil {
+			t.Errorf("Repair(%q) expected error but got nil", input)
+		}
+	}
+}
+
+func TestParseLenientErrorPositions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"{\n  \"a\": 'open", "line 2, column 8: unterminated string"},
+		{"[1,\n NaN]", "line 2, column 2: NaN cannot be represented in JSON"},
+		{"[1,\n  @]", "line 2, column 3: unexpected '@'"},
+	}
+	for _, tt := range tests {
+		if _, err := ParseLenient(tt.input); err == nil || err.Error() != tt.expected {
+			t.Errorf("ParseLenient(%q) error = %v, want %q", tt.input, err, tt.expected)
+		}
+	}
+}
+
+func TestRepairScalesLinearly(t *testing.T) {
+	// Every element needs a fix, so looking up each fix's position by
+	// rescanning the input would make this quadratic.
+	elapsed := func(n int) time.Duration {
+		input := "[" + strings.Repeat("'x',\n", n) + "]"
+		best := time.Duration(math.MaxInt64)
+		for range 3 {
+			start := time.Now()
+			_, fixes, err := Repair(input)
+			if err != nil || len(fixes) < n {
+				t.Fatalf("Repair() = %d fixes, %v; want at least %d", len(fixes), err, n)
+			}
+			best = min(best, time.Since(start))
+		}
+		return best
+	}
+
+	small, large := elapsed(20000), elapsed(80000)
+	if large > 10*small {
+		t.Errorf("4x the input took %v, %.1fx as long as %v", large, float64(large)/float64(small), small)
+	}
+}
@@ -0,0 +1,380 @@
+package jsonutil
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/NickDiPreta1/toolhub/internal/workerpool"
+)
+
+// MaxLineSize is the longest NDJSON record accepted, in bytes.
+const MaxLineSize = 4 * 1024 * 1024
+
+// maxLineErrors caps how many line errors are kept; later errors are only
+// counted.
+const maxLineErrors = 100
+
+// LineError describes an NDJSON record that could not be processed.
+type LineError struct {
+	Line int
+	Err  error
+}
+
+func (e LineError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+// LineFunc transforms one NDJSON record. Returning nil output with a nil
+// error drops the record.
+type LineFunc func(line []byte) ([]byte, error)
+
+// NDJSONOptions configures StreamNDJSON.
+type NDJSONOptions struct {
+	// Transform is applied to every non-blank line.
+	Transform LineFunc
+	// Workers is the number of goroutines processing chunks.
+	Workers int
+	// ChunkLines is the number of lines handed to a worker at once.
+	ChunkLines int
+	// AsArray wraps the output records in a JSON array instead of writing
+	// one record per line.
+	AsArray bool
+}
+
+// NDJSONStats summarises a streaming run.
+type NDJSONStats struct {
+	Lines   int
+	Records int
+	Written int
+	// ErrorCount counts every failing line, including those beyond the
+	// errors kept in Errors.
+	ErrorCount int
+	Errors     []LineError
+}
+
+// ValidateLine checks that line is a single JSON value and returns it
+// compacted.
+func ValidateLine(line []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, line); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// PrettyLine validates line and indents it.
+func PrettyLine(line []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, line, "", "  "); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// FilterLines returns a LineFunc that keeps only records matching q, emitted
+// in compact form.
+func FilterLines(q *Query) LineFunc {
+	return func(line []byte) ([]byte, error) {
+		dec := json.NewDecoder(bytes.NewReader(line))
+		dec.UseNumber()
+
+		var v any
+		if err := dec.Decode(&v); err != nil {
+			return nil, err
+		}
+		if !q.Match(v) {
+			return nil, nil
+		}
+
+		return ValidateLine(line)
+	}
+}
+
+// DiscardLines validates each record and drops it, for validation-only runs.
+func DiscardLines(line []byte) ([]byte, error) {
+	_, err := ValidateLine(line)
+	return nil, err
+}
+
+// ndjsonChunk is a batch of consecutive lines handled by one worker job.
+type ndjsonChunk struct {
+	lines [][]byte
+	// lineNums holds the physical line number of each entry in lines.
+	lineNums []int
+	// written and errors are filled in by the worker.
+	written int
+	errors  []LineError
+}
+
+// chunkSet tracks submitted chunks by job ID until they are written.
+type chunkSet struct {
+	mu     sync.Mutex
+	chunks map[int]*ndjsonChunk
+}
+
+func (cs *chunkSet) put(id int, c *ndjsonChunk) {
+	cs.mu.Lock()
+	cs.chunks[id] = c
+	cs.mu.Unlock()
+}
+
+func (cs *chunkSet) take(id int) *ndjsonChunk {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	c := cs.chunks[id]
+	delete(cs.chunks, id)
+
+	return c
+}
+
+// StreamNDJSON reads newline-delimited JSON from r, transforms each record
+// through a workerpool.Pool and writes the results to w in input order.
+// Memory use is bounded by the number of chunks in flight rather than the
+// size of the input. Line-level failures are collected in the returned stats;
+// the returned error is reserved for I/O and cancellation.
+func StreamNDJSON(ctx context.Context, r io.Reader, w io.Writer, opts NDJSONOptions) (NDJSONStats, error) {
+	if opts.Transform == nil {
+		opts.Transform = ValidateLine
+	}
+	if opts.Workers <= 0 {
+		opts.Workers = 4
+	}
+	if opts.ChunkLines <= 0 {
+		opts.ChunkLines = 512
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// inFlight bounds how many chunks exist between reading and writing,
+	// which bounds memory even when one slow chunk holds up the writer.
+	maxInFlight := opts.Workers * 2
+	inFlight := make(chan struct{}, maxInFlight)
+
+	pool := workerpool.NewPool(opts.Workers, maxInFlight)
+	results := pool.Start(ctx)
+
+	chunks := &chunkSet{chunks: map[int]*ndjsonChunk{}}
+	readErr := make(chan error, 1)
+	var lines, records int
+
+	go func() {
+		defer pool.Shutdown()
+		var err error
+		lines, records, err = readChunks(ctx, r, opts, inFlight, pool, chunks)
+		readErr <- err
+	}()
+
+	out := bufio.NewWriter(w)
+	pending := map[int]workerpool.Result{}
+	next := 0
+	var stats NDJSONStats
+	var writeErr error
+
+	if opts.AsArray {
+		_, writeErr = out.WriteString("[")
+	}
+
+	for res := range results {
+		pending[res.JobID] = res
+		for {
+			done, ok := pending[next]
+			if !ok {
+				break
+			}
+			delete(pending, next)
+			next++
+
+			chunk := chunks.take(done.JobID)
+			for _, le := range chunk.errors {
+				stats.ErrorCount++
+				if len(stats.Errors) < maxLineErrors {
+					stats.Errors = append(stats.Errors, le)
+				}
+			}
+
+			if writeErr == nil {
+				content := done.Content
+				// Array records are prefixed with a comma; the first one
+				// in the output must not be.
+				if opts.AsArray && stats.Written == 0 && len(content) > 0 {
+					content = content[1:]
+				}
+				if _, writeErr = out.Write(content); writeErr != nil {
+					cancel()
+				}
+				stats.Written += chunk.written
+			}
+			<-inFlight
+		}
+	}
+
+	stats.Lines, stats.Records = lines, records
+	if err := <-readErr; err != nil {
+		return stats, err
+	}
+	if writeErr != nil {
+		return stats, writeErr
+	}
+	if err := ctx.Err(); err != nil {
+		return stats, err
+	}
+
+	if opts.AsArray {
+		if stats.Written > 0 {
+			out.WriteString("\n")
+		}
+		out.WriteString("]\n")
+	}
+
+	return stats, out.Flush()
+}
+
+// readChunks splits r into chunks and submits one job per chunk. It returns
+// the number of physical lines and non-blank records read.
+func readChunks(ctx context.Context, r io.Reader, opts NDJSONOptions, inFlight chan struct{}, pool *workerpool.Pool, chunks *chunkSet) (int, int, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), MaxLineSize)
+
+	id := 0
+	current := &ndjsonChunk{}
+	lineNum, records := 0, 0
+
+	submit := func() error {
+		select {
+		case inFlight <- struct{}{}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		chunk := current
+		chunks.put(id, chunk)
+		pool.Submit(workerpool.Job{
+			ID: id,
+			Func: func([]byte) ([]byte, error) {
+				return processChunk(chunk, opts.Transform, opts.AsArray), nil
+			},
+		})
+		id++
+		current = &ndjsonChunk{}
+
+		return nil
+	}
+
+	for scanner.Scan() {
+		lineNum++
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		current.lines = append(current.lines, append([]byte(nil), line...))
+		current.lineNums = append(current.lineNums, lineNum)
+		records++
+
+		if len(current.lines) == opts.ChunkLines {
+			if err := submit(); err != nil {
+				return lineNum, records, err
+			}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		if errors.Is(err, bufio.ErrTooLong) {
+			return lineNum, records, fmt.Errorf("line %d exceeds the maximum record size of %d bytes", lineNum+1, MaxLineSize)
+		}
+		return lineNum, records, err
+	}
+
+	if len(current.lines) > 0 {
+		return lineNum, records, submit()
+	}
+
+	return lineNum, records, nil
+}
+
+// processChunk applies fn to every line in chunk, recording line errors on
+// the chunk and returning the rendered output.
+func processChunk(chunk *ndjsonChunk, fn LineFunc, asArray bool) []byte {
+	var buf bytes.Buffer
+	for i, line := range chunk.lines {
+		out, err := fn(line)
+		if err != nil {
+			chunk.errors = append(chunk.errors, LineError{Line: chunk.lineNums[i], Err: err})
+			continue
+		}
+		if out == nil {
+			continue
+		}
+
+		if asArray {
+			buf.WriteString(",\n  ")
+			buf.Write(bytes.ReplaceAll(out, []byte("\n"), []byte("\n  ")))
+		} else {
+			buf.Write(out)
+			buf.WriteByte('\n')
+		}
+		chunk.written++
+	}
+	chunk.lines = nil
+
+	return buf.Bytes()
+}
+
+// ArrayToNDJSON streams the elements of a top-level JSON array from r to w,
+// one compact record per line, without holding the whole array in memory.
+func ArrayToNDJSON(r io.Reader, w io.Writer) (int, error) {
+	dec := json.NewDecoder(r)
+	tok, err := dec.Token()
+	if err != nil {
+		return 0, fmt.Errorf("invalid JSON: %w", err)
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '[' {
+		return 0, fmt.Errorf("invalid JSON: expected a top-level array")
+	}
+
+	out := bufio.NewWriter(w)
+	count := 0
+	for dec.More() {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return count, fmt.Errorf("invalid JSON in element %d: %w", count+1, err)
+		}
+
+		var buf bytes.Buffer
+		if err := json.Compact(&buf, raw); err != nil {
+			return count, err
+		}
+		buf.WriteByte('\n')
+		if _, err := out.Write(buf.Bytes()); err != nil {
+			return count, err
+		}
+		count++
+	}
+
+	if _, err := dec.Token(); err != nil {
+		return count, fmt.Errorf("invalid JSON: %w", err)
+	}
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		return count, fmt.Errorf("invalid JSON: unexpected data after top-level array")
+	}
+
+	return count, out.Flush()
+}
+
+// NDJSONString is a convenience wrapper around StreamNDJSON for small inputs.
+func NDJSONString(input string, opts NDJSONOptions) (string, NDJSONStats, error) {
+	var buf strings.Builder
+	stats, err := StreamNDJSON(context.Background(), strings.NewReader(input), &buf, opts)
+
+	return buf.String(), stats, err
+}
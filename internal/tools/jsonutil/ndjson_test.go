@@ -0,0 +1,179 @@
+package jsonutil
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestStreamNDJSON(t *testing.T) {
+	input := "{\"id\": 1, \"level\": \"info\"}\n\n{\"id\":2,\"level\":\"error\"}\n{bad}\n{\"id\":3,\"level\":\"error\"}\n"
+
+	level, err := CompileQuery(`.level == "error"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		opts       NDJSONOptions
+		expected   string
+		errorLines []int
+	}{
+		{
+			name:       "minify",
+			opts:       NDJSONOptions{Transform: ValidateLine},
+			expected:   "{\"id\":1,\"level\":\"info\"}\n{\"id\":2,\"level\":\"error\"}\n{\"id\":3,\"level\":\"error\"}\n",
+			errorLines: []int{4},
+		},
+		{
+			name:       "pretty",
+			opts:       NDJSONOptions{Transform: PrettyLine},
+			expected:   "{\n  \"id\": 1,\n  \"level\": \"info\"\n}\n{\n  \"id\": 2,\n  \"level\": \"error\"\n}\n{\n  \"id\": 3,\n  \"level\": \"error\"\n}\n",
+			errorLines: []int{4},
+		},
+		{
+			name:       "filter",
+			opts:       NDJSONOptions{Transform: FilterLines(level)},
+			expected:   "{\"id\":2,\"level\":\"error\"}\n{\"id\":3,\"level\":\"error\"}\n",
+			errorLines: []int{4},
+		},
+		{
+			name:       "validate only",
+			opts:       NDJSONOptions{Transform: DiscardLines},
+			expected:   "",
+			errorLines: []int{4},
+		},
+		{
+			name:       "to array",
+			opts:       NDJSONOptions{Transform: FilterLines(level), AsArray: true},
+			expected:   "[\n  {\"id\":2,\"level\":\"error\"},\n  {\"id\":3,\"level\":\"error\"}\n]\n",
+			errorLines: []int{4},
+		},
+		{
+			name:       "pretty array",
+			opts:       NDJSONOptions{Transform: PrettyLine, AsArray: true, ChunkLines: 1},
+			expected:   "[\n  {\n    \"id\": 1,\n    \"level\": \"info\"\n  },\n  {\n    \"id\": 2,\n    \"level\": \"error\"\n  },\n  {\n    \"id\": 3,\n    \"level\": \"error\"\n  }\n]\n",
+			errorLines: []int{4},
+		},
+		{
+			name:       "single line chunks",
+			opts:       NDJSONOptions{Transform: ValidateLine, ChunkLines: 1, Workers: 3},
+			expected:   "{\"id\":1,\"level\":\"info\"}\n{\"id\":2,\"level\":\"error\"}\n{\"id\":3,\"level\":\"error\"}\n",
+			errorLines: []int{4},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, stats, err := NDJSONString(input, tt.opts)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got != tt.expected {
+				t.Errorf("output\ngot:\n%s\nwant:\n%s", got, tt.expected)
+			}
+
+			if len(stats.Errors) != len(tt.errorLines) {
+				t.Fatalf("got %d line errors (%v), want %d", len(stats.Errors), stats.Errors, len(tt.errorLines))
+			}
+			for i, line := range tt.errorLines {
+				if stats.Errors[i].Line != line {
+					t.Errorf("error %d on line %d, want line %d", i, stats.Errors[i].Line, line)
+				}
+			}
+
+			if stats.Lines != 5 || stats.Records != 4 {
+				t.Errorf("stats = %+v, want 5 lines and 4 records", stats)
+			}
+		})
+	}
+}
+
+// TestStreamNDJSONPreservesOrder pushes many small chunks through several
+// workers and checks records come out in input order.
+func TestStreamNDJSONPreservesOrder(t *testing.T) {
+	var in, want strings.Builder
+	for i := 0; i < 5000; i++ {
+		fmt.Fprintf(&in, "{ \"n\" : %d }\n", i)
+		fmt.Fprintf(&want, "{\"n\":%d}\n", i)
+	}
+
+	got, stats, err := NDJSONString(in.String(), NDJSONOptions{Workers: 8, ChunkLines: 7})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want.String() {
+		t.Error("records were reordered or altered")
+	}
+	if stats.Written != 5000 {
+		t.Errorf("Written = %d, want 5000", stats.Written)
+	}
+}
+
+func TestStreamNDJSONCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := StreamNDJSON(ctx, strings.NewReader(strings.Repeat("{}\n", 10000)), io.Discard, NDJSONOptions{ChunkLines: 1})
+	if err == nil {
+		t.Error("expected error for cancelled context")
+	}
+}
+
+func TestArrayToNDJSON(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		expected    string
+		expectError bool
+	}{
+		{
+			name:     "objects",
+			input:    "[\n  {\"a\": 1},\n  {\"a\": [1, 2]}\n]",
+			expected: "{\"a\":1}\n{\"a\":[1,2]}\n",
+		},
+		{
+			name:     "empty array",
+			input:    "[]",
+			expected: "",
+		},
+		{
+			name:        "not an array",
+			input:       `{"a":1}`,
+			expectError: true,
+		},
+		{
+			name:        "truncated",
+			input:       `[{"a":1},`,
+			expectError: true,
+		},
+		{
+			name:        "trailing data",
+			input:       `[1] 2`,
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out strings.Builder
+			_, err := ArrayToNDJSON(strings.NewReader(tt.input), &out)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("ArrayToNDJSON(%q) expected error but got nil", tt.input)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ArrayToNDJSON(%q) unexpected error: %v", tt.input, err)
+			}
+			if out.String() != tt.expected {
+				t.Errorf("ArrayToNDJSON(%q) = %q, want %q", tt.input, out.String(), tt.expected)
+			}
+		})
+	}
+}
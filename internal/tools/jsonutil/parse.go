@@ -0,0 +1,49 @@
+package jsonutil
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Parse validates input and decodes a single JSON value. Numbers are kept as
+// json.Number so callers can tell integers from floats.
+func Parse(input string) (any, error) {
+	values, err := ParseAll(input)
+	if err != nil {
+		return nil, err
+	}
+	if len(values) > 1 {
+		return nil, fmt.Errorf("invalid JSON: unexpected data after top-level value")
+	}
+
+	return values[0], nil
+}
+
+// ParseAll decodes every whitespace-separated JSON value in input, such as a
+// series of sample documents pasted one after another.
+func ParseAll(input string) ([]any, error) {
+	if err := validateJSON(input); err != nil {
+		return nil, err
+	}
+
+	dec := json.NewDecoder(strings.NewReader(input))
+	dec.UseNumber()
+
+	var values []any
+	for {
+		var v any
+		err := dec.Decode(&v)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid JSON in value %d: %w", len(values)+1, err)
+		}
+		values = append(values, v)
+	}
+
+	return values, nil
+}
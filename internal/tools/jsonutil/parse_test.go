@@ -0,0 +1,64 @@
+package jsonutil
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		expectError bool
+	}{
+		{name: "object", input: `{"a":1}`},
+		{name: "surrounding whitespace", input: "  [1, 2]\n"},
+		{name: "empty input", input: "", expectError: true},
+		{name: "invalid JSON", input: `{"a":`, expectError: true},
+		{name: "two values", input: `{} {}`, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.input)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Parse(%q) expected error but got nil", tt.input)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Parse(%q) unexpected error: %v", tt.input, err)
+			}
+		})
+	}
+}
+
+func TestParseKeepsNumbers(t *testing.T) {
+	v, err := Parse(`{"big":12345678901234567890,"small":1.5}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	obj := v.(map[string]any)
+	if n, ok := obj["big"].(json.Number); !ok || n.String() != "12345678901234567890" {
+		t.Errorf("expected json.Number 12345678901234567890, got %#v", obj["big"])
+	}
+	if n, ok := obj["small"].(json.Number); !ok || n.String() != "1.5" {
+		t.Errorf("expected json.Number 1.5, got %#v", obj["small"])
+	}
+}
+
+func TestParseAll(t *testing.T) {
+	values, err := ParseAll("{\"a\":1}\n{\"a\":2}\n[3]")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(values) != 3 {
+		t.Errorf("ParseAll returned %d values, want 3", len(values))
+	}
+
+	if _, err := ParseAll(`{"a":1} {"a":`); err == nil {
+		t.Error("ParseAll expected error for truncated second value")
+	}
+}
@@ -0,0 +1,374 @@
+package jsonutil
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Query is a compiled filter expression evaluated against decoded JSON
+// records. The syntax is a small subset of jq-style paths with comparisons:
+//
+//	.level == "error"
+//	.status >= 500 and .user.name != "bot"
+//	.tags contains "beta" or .items[0].price < 10
+//	.deleted_at exists
+//
+// A bare path matches when the value exists and is not null or false.
+type Query struct {
+	expr string
+	// or holds alternatives; each alternative is a list of conditions that
+	// must all match.
+	or [][]condition
+}
+
+type condition struct {
+	path  []pathStep
+	op    string
+	value any
+}
+
+// pathStep is one ".key" or "[index]" segment.
+type pathStep struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+var comparisonOps = []string{"==", "!=", ">=", "<=", ">", "<"}
+
+// CompileQuery parses a filter expression.
+func CompileQuery(expr string) (*Query, error) {
+	p := &queryParser{src: expr}
+	q := &Query{expr: expr}
+
+	group := []condition{}
+	for {
+		cond, err := p.condition()
+		if err != nil {
+			return nil, fmt.Errorf("invalid query: %w", err)
+		}
+		group = append(group, cond)
+
+		p.skipSpace()
+		if p.done() {
+			break
+		}
+
+		switch {
+		case p.consumeWord("and"), p.consume("&&"):
+		case p.consumeWord("or"), p.consume("||"):
+			q.or = append(q.or, group)
+			group = []condition{}
+		default:
+			return nil, fmt.Errorf("invalid query: unexpected %q at position %d", p.rest(), p.pos+1)
+		}
+	}
+	q.or = append(q.or, group)
+
+	return q, nil
+}
+
+// String returns the source expression.
+func (q *Query) String() string {
+	return q.expr
+}
+
+// Match reports whether the decoded JSON value satisfies the query.
+func (q *Query) Match(v any) bool {
+	for _, group := range q.or {
+		all := true
+		for _, cond := range group {
+			if !cond.match(v) {
+				all = false
+				break
+			}
+		}
+		if all {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (c condition) match(root any) bool {
+	v, ok := lookup(root, c.path)
+
+	switch c.op {
+	case "":
+		return ok && v != nil && v != false
+	case "exists":
+		return ok
+	case "==":
+		return ok && jsonEqual(v, c.value)
+	case "!=":
+		return !ok || !jsonEqual(v, c.value)
+	case "contains":
+		if !ok {
+			return false
+		}
+		switch haystack := v.(type) {
+		case string:
+			needle, isString := c.value.(string)
+			return isString && strings.Contains(haystack, needle)
+		case []any:
+			for _, elem := range haystack {
+				if jsonEqual(elem, c.value) {
+					return true
+				}
+			}
+		}
+		return false
+	default:
+		if !ok {
+			return false
+		}
+		cmp, comparable := compareJSON(v, c.value)
+		if !comparable {
+			return false
+		}
+		switch c.op {
+		case ">":
+			return cmp > 0
+		case ">=":
+			return cmp >= 0
+		case "<":
+			return cmp < 0
+		case "<=":
+			return cmp <= 0
+		}
+		return false
+	}
+}
+
+// lookup follows path from root, reporting whether every step existed.
+func lookup(root any, path []pathStep) (any, bool) {
+	v := root
+	for _, step := range path {
+		if step.isIndex {
+			list, ok := v.([]any)
+			if !ok || step.index < 0 || step.index >= len(list) {
+				return nil, false
+			}
+			v = list[step.index]
+			continue
+		}
+
+		obj, ok := v.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		v, ok = obj[step.key]
+		if !ok {
+			return nil, false
+		}
+	}
+
+	return v, true
+}
+
+// jsonEqual compares two decoded values, treating numbers numerically.
+func jsonEqual(a, b any) bool {
+	if fa, ok := toFloat(a); ok {
+		fb, ok := toFloat(b)
+		return ok && fa == fb
+	}
+
+	return reflect.DeepEqual(a, b)
+}
+
+// compareJSON orders two numbers or two strings.
+func compareJSON(a, b any) (int, bool) {
+	if fa, ok := toFloat(a); ok {
+		fb, ok := toFloat(b)
+		if !ok {
+			return 0, false
+		}
+		switch {
+		case fa < fb:
+			return -1, true
+		case fa > fb:
+			return 1, true
+		}
+		return 0, true
+	}
+
+	sa, ok := a.(string)
+	if !ok {
+		return 0, false
+	}
+	sb, ok := b.(string)
+	if !ok {
+		return 0, false
+	}
+
+	return strings.Compare(sa, sb), true
+}
+
+func toFloat(v any) (float64, bool) {
+	switch n := v.(type) {
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	case float64:
+		return n, true
+	}
+
+	return 0, false
+}
+
+// queryParser is a hand-written scanner over the query source.
+type queryParser struct {
+	src string
+	pos int
+}
+
+func (p *queryParser) done() bool   { return p.pos >= len(p.src) }
+func (p *queryParser) rest() string { return p.src[p.pos:] }
+
+func (p *queryParser) skipSpace() {
+	for !p.done() && (p.src[p.pos] == ' ' || p.src[p.pos] == '\t') {
+		p.pos++
+	}
+}
+
+func (p *queryParser) consume(s string) bool {
+	if strings.HasPrefix(p.rest(), s) {
+		p.pos += len(s)
+		return true
+	}
+
+	return false
+}
+
+// consumeWord consumes a keyword only when it is followed by a space or the
+// end of input, so "and" does not match the start of "android".
+func (p *queryParser) consumeWord(word string) bool {
+	rest := p.rest()
+	if !strings.HasPrefix(rest, word) {
+		return false
+	}
+	if len(rest) > len(word) && rest[len(word)] != ' ' && rest[len(word)] != '\t' {
+		return false
+	}
+	p.pos += len(word)
+
+	return true
+}
+
+func (p *queryParser) condition() (condition, error) {
+	p.skipSpace()
+	path, err := p.path()
+	if err != nil {
+		return condition{}, err
+	}
+	cond := condition{path: path}
+
+	p.skipSpace()
+	if p.consumeWord("exists") {
+		cond.op = "exists"
+		return cond, nil
+	}
+
+	switch {
+	case p.consumeWord("contains"):
+		cond.op = "contains"
+	default:
+		for _, op := range comparisonOps {
+			if p.consume(op) {
+				cond.op = op
+				break
+			}
+		}
+	}
+	if cond.op == "" {
+		return cond, nil
+	}
+
+	p.skipSpace()
+	cond.value, err = p.literal()
+	if err != nil {
+		return condition{}, err
+	}
+
+	return cond, nil
+}
+
+func (p *queryParser) path() ([]pathStep, error) {
+	if !p.consume(".") {
+		return nil, fmt.Errorf("expected path starting with '.' at position %d", p.pos+1)
+	}
+
+	var steps []pathStep
+	for {
+		switch {
+		case p.consume("["):
+			end := strings.IndexByte(p.rest(), ']')
+			if end < 0 {
+				return nil, fmt.Errorf("unclosed '[' at position %d", p.pos)
+			}
+			inner := p.rest()[:end]
+			p.pos += end + 1
+			if strings.HasPrefix(inner, `"`) {
+				key, err := strconv.Unquote(inner)
+				if err != nil {
+					return nil, fmt.Errorf("invalid key %s", inner)
+				}
+				steps = append(steps, pathStep{key: key})
+				continue
+			}
+			i, err := strconv.Atoi(inner)
+			if err != nil {
+				return nil, fmt.Errorf("invalid index [%s]", inner)
+			}
+			steps = append(steps, pathStep{index: i, isIndex: true})
+		case len(steps) > 0 && p.consume("."):
+			key := p.ident()
+			if key == "" {
+				return nil, fmt.Errorf("expected key after '.' at position %d", p.pos+1)
+			}
+			steps = append(steps, pathStep{key: key})
+		case len(steps) == 0:
+			// The leading "." is already consumed; an identifier may follow
+			// directly, or the path may be "." alone.
+			if key := p.ident(); key != "" {
+				steps = append(steps, pathStep{key: key})
+				continue
+			}
+			return steps, nil
+		default:
+			return steps, nil
+		}
+	}
+}
+
+func (p *queryParser) ident() string {
+	start := p.pos
+	for !p.done() {
+		c := p.src[p.pos]
+		if c == '_' || c == '-' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' {
+			p.pos++
+			continue
+		}
+		break
+	}
+
+	return p.src[start:p.pos]
+}
+
+// literal decodes the JSON scalar at the current position.
+func (p *queryParser) literal() (any, error) {
+	dec := json.NewDecoder(strings.NewReader(p.rest()))
+	dec.UseNumber()
+
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, fmt.Errorf("expected JSON value at position %d", p.pos+1)
+	}
+	p.pos += int(dec.InputOffset())
+
+	return v, nil
+}
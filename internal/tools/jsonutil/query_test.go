@@ -0,0 +1,66 @@
+package jsonutil
+
+import "testing"
+
+func TestQueryMatch(t *testing.T) {
+	record := `{"level":"error","status":503,"user":{"name":"ann"},"tags":["beta","eu"],"items":[{"price":4.5}],"deleted":null,"ok":false}`
+
+	tests := []struct {
+		name     string
+		expr     string
+		expected bool
+	}{
+		{name: "string equality", expr: `.level == "error"`, expected: true},
+		{name: "string inequality", expr: `.level != "error"`, expected: false},
+		{name: "numeric comparison", expr: `.status >= 500`, expected: true},
+		{name: "numeric less than", expr: `.status < 500`, expected: false},
+		{name: "nested path", expr: `.user.name == "ann"`, expected: true},
+		{name: "array index", expr: `.items[0].price < 10`, expected: true},
+		{name: "bracket key", expr: `.user["name"] == "ann"`, expected: true},
+		{name: "array contains", expr: `.tags contains "eu"`, expected: true},
+		{name: "string contains", expr: `.level contains "rr"`, expected: true},
+		{name: "exists on null", expr: `.deleted exists`, expected: true},
+		{name: "missing key exists", expr: `.missing exists`, expected: false},
+		{name: "bare path truthy", expr: `.user`, expected: true},
+		{name: "bare path false", expr: `.ok`, expected: false},
+		{name: "bare path null", expr: `.deleted`, expected: false},
+		{name: "and", expr: `.status == 503 and .level == "warn"`, expected: false},
+		{name: "or", expr: `.level == "warn" || .status == 503`, expected: true},
+		{name: "and binds tighter than or", expr: `.level == "warn" and .status == 1 or .ok == false`, expected: true},
+		{name: "missing key not equal", expr: `.missing != 1`, expected: true},
+		{name: "type mismatch", expr: `.level > 3`, expected: false},
+	}
+
+	v, err := Parse(record)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := CompileQuery(tt.expr)
+			if err != nil {
+				t.Fatalf("CompileQuery(%q) unexpected error: %v", tt.expr, err)
+			}
+			if got := q.Match(v); got != tt.expected {
+				t.Errorf("Query(%q).Match = %v, want %v", tt.expr, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCompileQueryErrors(t *testing.T) {
+	for _, expr := range []string{
+		``,
+		`level == "x"`,
+		`.level ==`,
+		`.level == 'x'`,
+		`.items[0`,
+		`.items[x]`,
+		`.a == 1 xor .b == 2`,
+	} {
+		if _, err := CompileQuery(expr); err == nil {
+			t.Errorf("CompileQuery(%q) expected error but got nil", expr)
+		}
+	}
+}
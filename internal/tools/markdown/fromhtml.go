@@ -0,0 +1,443 @@
+package markdown
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// blockTags start a new block when converting HTML to Markdown.
+var blockTags = map[string]bool{
+	"html": true, "body": true, "p": true, "div": true, "section": true, "article": true,
+	"header": true, "footer": true, "main": true, "nav": true, "aside": true, "address": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"hr": true, "pre": true, "blockquote": true, "ul": true, "ol": true, "li": true,
+	"table": true, "figure": true, "figcaption": true, "dl": true, "dt": true, "dd": true,
+	"details": true, "summary": true, "form": true, "fieldset": true,
+}
+
+// skippedTags contribute nothing to the Markdown.
+var skippedTags = map[string]bool{
+	"head": true, "title": true, "script": true, "style": true, "template": true,
+	"noscript": true, "iframe": true, "object": true, "embed": true, "svg": true,
+	"button": true, "select": true, "textarea": true,
+}
+
+var (
+	spaceRun = regexp.MustCompile(`\s+`)
+	// blockStart matches line openings that Markdown would read as a
+	// heading, quote, list item or rule.
+	blockStart = regexp.MustCompile(`^(#|>|[-+=]|\d+[.)])`)
+)
+
+// markdownEscaper escapes characters that would otherwise start emphasis,
+// code, links or HTML.
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, `*`, `\*`, `_`, `\_`, "`", "\\`", `[`, `\[`, `]`, `\]`, `<`, `\<`,
+)
+
+// block is converted Markdown; list marks blocks that are lists, which sit
+// directly under the text of a list item.
+type block struct {
+	text string
+	list bool
+}
+
+// FromHTML converts an HTML document or fragment to Markdown. Headings,
+// paragraphs, emphasis, code, links, images, lists and task lists, block
+// quotes, rules and tables are converted; other elements contribute only
+// their text, and scripts and styles are dropped.
+func FromHTML(src string) (string, error) {
+	doc, err := html.Parse(strings.NewReader(src))
+	if err != nil {
+		return "", fmt.Errorf("invalid HTML: %w", err)
+	}
+	root := findElement(doc, "body")
+	if root == nil {
+		root = doc
+	}
+	return joinBlocks(blocks(root), false), nil
+}
+
+// blocks converts the children of n, gathering runs of inline content into
+// paragraphs.
+func blocks(n *html.Node) []block {
+	var out []block
+	var para strings.Builder
+	flush := func() {
+		if text := strings.Join(paragraphLines(para.String()), "\\\n"); text != "" {
+			out = append(out, block{text: text})
+		}
+		para.Reset()
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.ElementNode && blockTags[c.Data] {
+			flush()
+			out = append(out, blockElement(c)...)
+			continue
+		}
+		para.WriteString(inline(c))
+	}
+	flush()
+	return out
+}
+
+func blockElement(n *html.Node) []block {
+	switch n.Data {
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		text := strings.Join(paragraphLines(inlineChildren(n)), " ")
+		if text == "" {
+			return nil
+		}
+		return []block{{text: strings.Repeat("#", int(n.Data[1]-'0')) + " " + text}}
+	case "hr":
+		return []block{{text: "---"}}
+	case "pre":
+		return []block{{text: codeBlock(n)}}
+	case "blockquote":
+		inner := joinBlocks(blocks(n), false)
+		if inner == "" {
+			return nil
+		}
+		lines := strings.Split(inner, "\n")
+		for i, line := range lines {
+			lines[i] = strings.TrimRight("> "+line, " ")
+		}
+		return []block{{text: strings.Join(lines, "\n")}}
+	case "ul", "ol":
+		return list(n)
+	case "table":
+		return table(n)
+	default:
+		return blocks(n)
+	}
+}
+
+func joinBlocks(bs []block, tight bool) string {
+	var b strings.Builder
+	for i, bl := range bs {
+		if i > 0 {
+			if tight && bl.list {
+				b.WriteString("\n")
+			} else {
+				b.WriteString("\n\n")
+			}
+		}
+		b.WriteString(bl.text)
+	}
+	return b.String()
+}
+
+// paragraphLines splits converted inline content at its hard line breaks,
+// trimming each line and escaping openings that would change the block
+// type.
+func paragraphLines(s string) []string {
+	var lines []string
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if loc := blockStart.FindStringIndex(line); loc != nil {
+			// Escape the marker's last character: "\#", "1\.".
+			line = line[:loc[1]-1] + `\` + line[loc[1]-1:]
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func inlineChildren(n *html.Node) string {
+	var b strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		b.WriteString(inline(c))
+	}
+	return b.String()
+}
+
+func inline(n *html.Node) string {
+	switch n.Type {
+	case html.TextNode:
+		return markdownEscaper.Replace(spaceRun.ReplaceAllString(n.Data, " "))
+	case html.ElementNode:
+	default:
+		return ""
+	}
+	if skippedTags[n.Data] {
+		return ""
+	}
+
+	switch n.Data {
+	case "strong", "b":
+		return wrap(n, "**")
+	case "em", "i":
+		return wrap(n, "*")
+	case "del", "s", "strike":
+		return wrap(n, "~~")
+	case "code", "kbd", "samp", "tt":
+		return codeSpan(textContent(n))
+	case "br":
+		// Text collapses its own line breaks, so these are the only ones.
+		return "\n"
+	case "a":
+		text := strings.TrimSpace(inlineChildren(n))
+		href, _ := getAttr(n, "href")
+		if href == "" {
+			return text
+		}
+		if text == "" {
+			text = markdownEscaper.Replace(href)
+		}
+		return "[" + text + "](" + linkDestination(n, href) + ")"
+	case "img":
+		src, _ := getAttr(n, "src")
+		if src == "" {
+			return ""
+		}
+		alt, _ := getAttr(n, "alt")
+		return "![" + markdownEscaper.Replace(alt) + "](" + linkDestination(n, src) + ")"
+	case "input":
+		if typ, _ := getAttr(n, "type"); strings.ToLower(typ) == "checkbox" {
+			if _, checked := getAttr(n, "checked"); checked {
+				return "[x]"
+			}
+			return "[ ]"
+		}
+		return ""
+	default:
+		return inlineChildren(n)
+	}
+}
+
+// wrap surrounds the content of n with marker, keeping surrounding spaces
+// outside it as emphasis requires.
+func wrap(n *html.Node, marker string) string {
+	inner := inlineChildren(n)
+	trimmed := strings.TrimSpace(inner)
+	if trimmed == "" {
+		return inner
+	}
+	lead := inner[:len(inner)-len(strings.TrimLeft(inner, " "))]
+	trail := inner[len(strings.TrimRight(inner, " ")):]
+	return lead + marker + trimmed + marker + trail
+}
+
+// linkDestination formats url and any title of n for a link or image.
+func linkDestination(n *html.Node, url string) string {
+	if strings.ContainsAny(url, " ()<>") {
+		url = "<" + strings.NewReplacer("<", "%3C", ">", "%3E").Replace(url) + ">"
+	}
+	if title, ok := getAttr(n, "title"); ok && title != "" {
+		url += ` "` + strings.ReplaceAll(title, `"`, `\"`) + `"`
+	}
+	return url
+}
+
+func codeSpan(code string) string {
+	code = strings.ReplaceAll(code, "\n", " ")
+	if code == "" {
+		return ""
+	}
+	ticks := "`"
+	for strings.Contains(code, ticks) {
+		ticks += "`"
+	}
+	if strings.HasPrefix(code, "`") || strings.HasSuffix(code, "`") {
+		code = " " + code + " "
+	}
+	return ticks + code + ticks
+}
+
+func codeBlock(pre *html.Node) string {
+	code := strings.TrimSuffix(textContent(pre), "\n")
+	lang := ""
+	if c := findElement(pre, "code"); c != nil {
+		class, _ := getAttr(c, "class")
+		for _, f := range strings.Fields(class) {
+			if l, ok := strings.CutPrefix(f, "language-"); ok {
+				lang = l
+				break
+			}
+		}
+	}
+
+	fence := "```"
+	for strings.Contains(code, fence) {
+		fence += "`"
+	}
+	return fence + lang + "\n" + code + "\n" + fence
+}
+
+func list(n *html.Node) []block {
+	number := 1
+	if start, ok := getAttr(n, "start"); ok {
+		if v, err := strconv.Atoi(start); err == nil {
+			number = v
+		}
+	}
+
+	var items []string
+	for li := n.FirstChild; li != nil; li = li.NextSibling {
+		if li.Type != html.ElementNode || li.Data != "li" {
+			continue
+		}
+		marker := "- "
+		if n.Data == "ol" {
+			marker = strconv.Itoa(number) + ". "
+			number++
+		}
+		body := joinBlocks(blocks(li), true)
+		// Text is escaped, so a leading "[x]" can only be a task checkbox.
+		for _, box := range []string{"[x]", "[ ]"} {
+			if rest, ok := strings.CutPrefix(body, box); ok {
+				body = box + " " + strings.TrimLeft(rest, " ")
+			}
+		}
+		lines := strings.Split(body, "\n")
+		for i := 1; i < len(lines); i++ {
+			if lines[i] != "" {
+				lines[i] = strings.Repeat(" ", len(marker)) + lines[i]
+			}
+		}
+		items = append(items, strings.TrimRight(marker+strings.Join(lines, "\n"), " "))
+	}
+	if len(items) == 0 {
+		return nil
+	}
+	return []block{{text: strings.Join(items, "\n"), list: true}}
+}
+
+// table converts a table to a GFM table whose first row is the header.
+func table(n *html.Node) []block {
+	var rows [][]string
+	var aligns []string
+	var collect func(*html.Node)
+	collect = func(parent *html.Node) {
+		for c := parent.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type != html.ElementNode {
+				continue
+			}
+			switch c.Data {
+			case "thead", "tbody", "tfoot":
+				collect(c)
+			case "tr":
+				var row []string
+				for cell := c.FirstChild; cell != nil; cell = cell.NextSibling {
+					if cell.Type != html.ElementNode || (cell.Data != "td" && cell.Data != "th") {
+						continue
+					}
+					text := strings.Join(paragraphLines(inlineChildren(cell)), "<br>")
+					row = append(row, strings.ReplaceAll(text, "|", `\|`))
+					if len(rows) == 0 {
+						aligns = append(aligns, cellAlign(cell))
+					}
+				}
+				rows = append(rows, row)
+			}
+		}
+	}
+	collect(n)
+	if len(rows) == 0 {
+		return nil
+	}
+
+	width := 0
+	for _, row := range rows {
+		width = max(width, len(row))
+	}
+	if width == 0 {
+		return nil
+	}
+
+	var b strings.Builder
+	writeRow := func(row []string) {
+		b.WriteString("|")
+		for i := 0; i < width; i++ {
+			cell := ""
+			if i < len(row) {
+				cell = row[i]
+			}
+			b.WriteString(" " + cell + " |")
+		}
+		b.WriteString("\n")
+	}
+	writeRow(rows[0])
+	b.WriteString("|")
+	for i := 0; i < width; i++ {
+		align := ""
+		if i < len(aligns) {
+			align = aligns[i]
+		}
+		switch align {
+		case "left":
+			b.WriteString(" :-- |")
+		case "right":
+			b.WriteString(" --: |")
+		case "center":
+			b.WriteString(" :-: |")
+		default:
+			b.WriteString(" --- |")
+		}
+	}
+	b.WriteString("\n")
+	for _, row := range rows[1:] {
+		writeRow(row)
+	}
+	return []block{{text: strings.TrimSuffix(b.String(), "\n")}}
+}
+
+func cellAlign(cell *html.Node) string {
+	if align, ok := getAttr(cell, "align"); ok {
+		return strings.ToLower(align)
+	}
+	style, _ := getAttr(cell, "style")
+	for _, decl := range strings.Split(style, ";") {
+		if prop, value, ok := strings.Cut(decl, ":"); ok && strings.TrimSpace(prop) == "text-align" {
+			return strings.ToLower(strings.TrimSpace(value))
+		}
+	}
+	return ""
+}
+
+func textContent(n *html.Node) string {
+	var b strings.Builder
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		switch {
+		case n.Type == html.TextNode:
+			b.WriteString(n.Data)
+		case n.Type == html.ElementNode && n.Data == "br":
+			b.WriteString("\n")
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(n)
+	return b.String()
+}
+
+func findElement(n *html.Node, name string) *html.Node {
+	if n.Type == html.ElementNode && n.Data == name {
+		return n
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if found := findElement(c, name); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+func getAttr(n *html.Node, key string) (string, bool) {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val, true
+		}
+	}
+	return "", false
+}
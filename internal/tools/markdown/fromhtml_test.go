@@ -0,0 +1,84 @@
+package markdown
+
+import "testing"
+
+func TestFromHTML(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "headings and inline markup",
+			input: "<h1>Title</h1><p>Some <em>emphasis</em>, <b>bold</b>, <del>gone</del> and <code>x `y`</code>.</p>",
+			want:  "# Title\n\nSome *emphasis*, **bold**, ~~gone~~ and `` x `y` ``.",
+		},
+		{
+			name:  "whitespace collapsed and specials escaped",
+			input: "<p>\n  a_b  *c*\n  [d]\n</p><p>1. not a list</p><p># not a heading</p>",
+			want:  "a\\_b \\*c\\* \\[d\\]\n\n1\\. not a list\n\n\\# not a heading",
+		},
+		{
+			name:  "links and images",
+			input: `<p><a href="https://example.com" title="Ex">site</a> <a href="/a b">space</a> <img src="/i.png" alt="pic"></p>`,
+			want:  "[site](https://example.com \"Ex\") [space](</a b>) ![pic](/i.png)",
+		},
+		{
+			name:  "line breaks",
+			input: "<p>one<br>two<br/></p>",
+			want:  "one\\\ntwo",
+		},
+		{
+			name:  "nested and task lists",
+			input: `<ul><li>a<ul><li>a1</li></ul></li><li><input type="checkbox" checked> done</li></ul><ol start="3"><li>three</li><li><p>four</p><p>more</p></li></ol>`,
+			want:  "- a\n  - a1\n- [x] done\n\n3. three\n4. four\n\n   more",
+		},
+		{
+			name:  "code block with language",
+			input: "<pre><code class=\"language-go\">func main() {\n\tfmt.Println(\"```\")\n}\n</code></pre>",
+			want:  "````go\nfunc main() {\n\tfmt.Println(\"```\")\n}\n````",
+		},
+		{
+			name:  "blockquote",
+			input: "<blockquote><p>quoted</p><p>twice</p></blockquote>",
+			want:  "> quoted\n>\n> twice",
+		},
+		{
+			name:  "table",
+			input: `<table><thead><tr><th align="left">a</th><th style="text-align: right">b</th></tr></thead><tbody><tr><td>1|2</td><td>3</td></tr><tr><td>4</td></tr></tbody></table>`,
+			want:  "| a | b |\n| :-- | --: |\n| 1\\|2 | 3 |\n| 4 |  |",
+		},
+		{
+			name:  "full document with scripts",
+			input: "<!doctype html><html><head><title>T</title><style>p{}</style></head><body><p>hi</p><script>alert(1)</script><hr></body></html>",
+			want:  "hi\n\n---",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FromHTML(tt.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("FromHTML\n got %q\nwant %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFromHTMLRoundTrip(t *testing.T) {
+	src := "# Notes\n\nA *small* **test** with `code` and a [link](https://example.com).\n\n- one\n- [ ] two\n\n| a | b |\n| --- | --- |\n| 1 | 2 |"
+	rendered, err := ToHTML(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	back, err := FromHTML(rendered)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if back != src {
+		t.Errorf("round trip\n got %q\nwant %q", back, src)
+	}
+}
@@ -0,0 +1,67 @@
+// Package markdown renders CommonMark with GitHub-flavoured extensions to
+// sanitized HTML and converts simple HTML back to Markdown.
+package markdown
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	gmhtml "github.com/yuin/goldmark/renderer/html"
+)
+
+// renderer parses CommonMark plus GFM tables, strikethrough, autolinks and
+// task lists. Raw HTML passes through goldmark so that harmless tags such
+// as <br> and <details> survive; ToHTML sanitizes the result.
+var renderer = goldmark.New(
+	goldmark.WithExtensions(
+		extension.NewTable(extension.WithTableCellAlignMethod(extension.TableCellAlignAttribute)),
+		extension.Strikethrough,
+		extension.Linkify,
+		extension.TaskList,
+	),
+	goldmark.WithRendererOptions(gmhtml.WithUnsafe()),
+)
+
+// ToHTML renders src to an HTML fragment with scripts, event handlers and
+// unsafe URLs removed.
+func ToHTML(src string) (string, error) {
+	var buf bytes.Buffer
+	if err := renderer.Convert([]byte(src), &buf); err != nil {
+		return "", fmt.Errorf("error rendering Markdown: %w", err)
+	}
+	return Sanitize(buf.String())
+}
+
+// Document wraps an HTML fragment in a standalone page with a readable
+// stylesheet. An empty title becomes "Document".
+func Document(title, body string) string {
+	if strings.TrimSpace(title) == "" {
+		title = "Document"
+	}
+	return `<!doctype html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>` + html.EscapeString(title) + `</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; line-height: 1.6; max-width: 800px; margin: 2rem auto; padding: 0 1rem; color: #222; }
+pre, code { font-family: "Courier New", Consolas, monospace; font-size: 14px; }
+pre { background: #f5f5f5; padding: 1rem; border: 1px solid #ddd; border-radius: 4px; overflow-x: auto; }
+:not(pre) > code { background: #f5f5f5; padding: 0.1rem 0.3rem; border-radius: 3px; }
+blockquote { margin: 0; padding-left: 1rem; border-left: 4px solid #ddd; color: #555; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ddd; padding: 0.4rem 0.75rem; }
+img { max-width: 100%; }
+li > input[type="checkbox"] { margin-right: 0.4rem; }
+</style>
+</head>
+<body>
+` + body + `</body>
+</html>
+`
+}
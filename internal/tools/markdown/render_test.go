@@ -0,0 +1,84 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestToHTML(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		contains []string
+		excludes []string
+	}{
+		{
+			name:     "CommonMark basics",
+			input:    "# Title\n\nSome *emphasis*, **strong** and `code`.\n\n> quoted\n\n1. one\n2. two\n",
+			contains: []string{"<h1>Title</h1>", "<em>emphasis</em>", "<strong>strong</strong>", "<code>code</code>", "<blockquote>", "<ol>", "<li>two</li>"},
+		},
+		{
+			name:     "GFM table with alignment",
+			input:    "| a | b |\n|:--|--:|\n| 1 | 2 |\n",
+			contains: []string{"<table>", `<th align="left">a</th>`, `<td align="right">2</td>`},
+		},
+		{
+			name:     "stray closing tags in raw HTML",
+			input:    "</div></div></main><h1>x\n",
+			contains: []string{"<h1>x\n</h1>"},
+			excludes: []string{"</div>", "</main>"},
+		},
+		{
+			name:     "task list",
+			input:    "- [x] done\n- [ ] todo\n",
+			contains: []string{`<input checked="" disabled="" type="checkbox"`, `<input disabled="" type="checkbox"`},
+		},
+		{
+			name:     "fenced code keeps its language",
+			input:    "```go\nfmt.Println(\"<hi>\")\n```\n",
+			contains: []string{`<pre><code class="language-go">fmt.Println(&#34;&lt;hi&gt;&#34;)`},
+		},
+		{
+			name:     "strikethrough and autolinks",
+			input:    "~~old~~ see https://example.com",
+			contains: []string{"<del>old</del>", `<a href="https://example.com">https://example.com</a>`},
+		},
+		{
+			name:     "raw HTML is sanitized",
+			input:    "<details><summary>More</summary>ok</details>\n\n<script>alert(1)</script>\n\n<img src=x onerror=alert(1)>\n\n[click](javascript:alert(1))\n",
+			contains: []string{"<details><summary>More</summary>ok</details>", `<img src="x">`, "<a>click</a>"},
+			excludes: []string{"script", "alert", "onerror", "javascript"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ToHTML(tt.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range tt.contains {
+				if !strings.Contains(got, want) {
+					t.Errorf("output does not contain %q:\n%s", want, got)
+				}
+			}
+			for _, unwanted := range tt.excludes {
+				if strings.Contains(got, unwanted) {
+					t.Errorf("output contains %q:\n%s", unwanted, got)
+				}
+			}
+		})
+	}
+}
+
+func TestDocument(t *testing.T) {
+	doc := Document("Notes <draft>", "<p>hi</p>\n")
+	for _, want := range []string{"<!doctype html>", "<title>Notes &lt;draft&gt;</title>", "<body>\n<p>hi</p>\n</body>"} {
+		if !strings.Contains(doc, want) {
+			t.Errorf("document does not contain %q", want)
+		}
+	}
+	if !strings.Contains(Document("  ", ""), "<title>Document</title>") {
+		t.Error("empty title not defaulted")
+	}
+}
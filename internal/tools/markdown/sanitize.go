@@ -0,0 +1,211 @@
+package markdown
+
+import (
+	"io"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// allowedTags maps each element Sanitize keeps to the attributes it keeps
+// on it. Other elements are dropped but their text is kept.
+var allowedTags = map[string][]string{
+	"a": {"href", "title"}, "img": {"src", "alt", "title", "width", "height"},
+	"p": nil, "br": nil, "hr": nil, "div": nil, "span": nil,
+	"h1": nil, "h2": nil, "h3": nil, "h4": nil, "h5": nil, "h6": nil,
+	"strong": nil, "b": nil, "em": nil, "i": nil, "u": nil, "s": nil, "del": nil, "ins": nil,
+	"mark": nil, "sub": nil, "sup": nil, "small": nil, "kbd": nil, "abbr": {"title"},
+	"code": {"class"}, "pre": nil, "blockquote": nil,
+	"ul": nil, "ol": {"start"}, "li": nil, "dl": nil, "dt": nil, "dd": nil,
+	"table": nil, "thead": nil, "tbody": nil, "tfoot": nil, "tr": nil, "caption": nil,
+	"th": {"align", "colspan", "rowspan"}, "td": {"align", "colspan", "rowspan"},
+	"input":   {"type", "checked", "disabled"},
+	"details": {"open"}, "summary": nil, "figure": nil, "figcaption": nil,
+}
+
+// droppedTags are removed together with everything inside them.
+var droppedTags = map[string]bool{
+	"script": true, "style": true, "iframe": true, "frame": true, "frameset": true,
+	"object": true, "embed": true, "applet": true, "noscript": true, "template": true,
+	"svg": true, "math": true, "textarea": true, "select": true, "head": true, "title": true,
+}
+
+var (
+	languageClass = regexp.MustCompile(`^language-[\w+#.-]+$`)
+	alignValue    = regexp.MustCompile(`^(left|right|center)$`)
+	// safeImageData are the inline image types allowed in img src; SVG is
+	// excluded because it can carry script.
+	safeImageData = regexp.MustCompile(`^data:image/(png|gif|jpeg|webp)[;,]`)
+)
+
+// Sanitize keeps only allowlisted elements and attributes of an HTML
+// fragment. Scripts and other active content are removed with their
+// contents, event handler attributes are never kept, and links and images
+// may only point at http, https, mailto or relative URLs. End tags are only
+// written for elements that are open, and open elements are closed at the
+// end, so the output cannot close markup around it.
+func Sanitize(fragment string) (string, error) {
+	z := html.NewTokenizer(strings.NewReader(fragment))
+	var b strings.Builder
+	// skip counts the dropped elements the tokenizer is inside.
+	skip := 0
+	// open holds the elements written but not yet closed.
+	var open []string
+
+	for {
+		tt := z.Next()
+		switch tt {
+		case html.ErrorToken:
+			if z.Err() == io.EOF {
+				closeTo(&b, &open, 0)
+				return b.String(), nil
+			}
+			return "", z.Err()
+
+		case html.TextToken:
+			if skip == 0 {
+				b.WriteString(html.EscapeString(string(z.Text())))
+			}
+
+		case html.StartTagToken, html.SelfClosingTagToken:
+			tok := z.Token()
+			if droppedTags[tok.Data] {
+				if tt == html.StartTagToken {
+					skip++
+				}
+				continue
+			}
+			if skip > 0 {
+				continue
+			}
+			if tag, ok := sanitizeTag(tok); ok {
+				b.WriteString(tag)
+				if !voidTag(tok.Data) {
+					open = append(open, tok.Data)
+				}
+			}
+
+		case html.EndTagToken:
+			tok := z.Token()
+			if droppedTags[tok.Data] {
+				skip = max(skip-1, 0)
+				continue
+			}
+			if skip > 0 {
+				continue
+			}
+			// Closing an element also closes any left open inside it; an
+			// end tag with no open element is dropped.
+			for i := len(open) - 1; i >= 0; i-- {
+				if open[i] == tok.Data {
+					closeTo(&b, &open, i)
+					break
+				}
+			}
+		}
+		// Comments and doctypes are dropped.
+	}
+}
+
+// closeTo writes end tags for the open elements from the innermost down to
+// open[i] and removes them from open.
+func closeTo(b *strings.Builder, open *[]string, i int) {
+	for j := len(*open) - 1; j >= i; j-- {
+		b.WriteString("</" + (*open)[j] + ">")
+	}
+	*open = (*open)[:i]
+}
+
+// sanitizeTag renders an allowed start tag with its permitted attributes.
+func sanitizeTag(tok html.Token) (string, bool) {
+	allowed, ok := allowedTags[tok.Data]
+	if !ok {
+		return "", false
+	}
+
+	var b strings.Builder
+	b.WriteString("<" + tok.Data)
+	for _, a := range tok.Attr {
+		if a.Namespace != "" || !slices.Contains(allowed, a.Key) || !safeAttr(tok.Data, a.Key, a.Val) {
+			continue
+		}
+		b.WriteString(" " + a.Key + `="` + html.EscapeString(a.Val) + `"`)
+	}
+
+	if tok.Data == "input" {
+		// Only the disabled checkboxes of task lists are kept.
+		if typ, _ := attr(tok, "type"); strings.ToLower(typ) != "checkbox" {
+			return "", false
+		}
+		if _, disabled := attr(tok, "disabled"); !disabled {
+			b.WriteString(` disabled=""`)
+		}
+	}
+	b.WriteString(">")
+	return b.String(), true
+}
+
+func safeAttr(tag, key, val string) bool {
+	switch key {
+	case "href":
+		return safeURL(val, false)
+	case "src":
+		return safeURL(val, tag == "img")
+	case "class":
+		return languageClass.MatchString(val)
+	case "align":
+		return alignValue.MatchString(val)
+	case "colspan", "rowspan", "start", "width", "height":
+		_, err := strconv.ParseUint(val, 10, 16)
+		return err == nil
+	default:
+		return true
+	}
+}
+
+// safeURL accepts relative URLs and http, https and mailto URLs, plus
+// inline raster images when image is set. Browsers ignore whitespace and
+// control characters inside a scheme, so those are removed before it is
+// checked.
+func safeURL(raw string, image bool) bool {
+	cleaned := strings.Map(func(r rune) rune {
+		if r <= ' ' || r == 0x7f {
+			return -1
+		}
+		return r
+	}, raw)
+	lower := strings.ToLower(cleaned)
+
+	i := strings.IndexAny(lower, ":/?#")
+	if i < 0 || lower[i] != ':' {
+		return true
+	}
+	switch lower[:i] {
+	case "http", "https", "mailto":
+		return true
+	case "data":
+		return image && safeImageData.MatchString(lower)
+	default:
+		return false
+	}
+}
+
+func voidTag(name string) bool {
+	switch name {
+	case "br", "hr", "img", "input":
+		return true
+	}
+	return false
+}
+
+func attr(tok html.Token, key string) (string, bool) {
+	for _, a := range tok.Attr {
+		if a.Key == key {
+			return a.Val, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,46 @@
+package markdown
+
+import "testing"
+
+func TestSanitize(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"allowed markup kept", `<p>a <strong>b</strong> <a href="https://x.test/?q=1&amp;r=2" title="t">c</a></p>`, `<p>a <strong>b</strong> <a href="https://x.test/?q=1&amp;r=2" title="t">c</a></p>`},
+		{"script removed with contents", `a<script>alert("x")</script>b`, `ab`},
+		{"nested dropped elements", `<svg><script>x</script><text>y</text></svg>z`, `z`},
+		{"style and iframe removed", `<style>p{}</style><iframe src="https://x.test">f</iframe>ok`, `ok`},
+		{"event handlers removed", `<p onclick="x()" class="c">t</p>`, `<p>t</p>`},
+		{"unknown tags unwrapped", `<blink><font color=red>t</font></blink>`, `t`},
+		{"javascript URL", `<a href="javascript:alert(1)">x</a>`, `<a>x</a>`},
+		{"obfuscated javascript URL", "<a href=\" JaVa\tScRiPt:alert(1)\">x</a>", `<a>x</a>`},
+		{"entity-encoded javascript URL", `<a href="&#106;avascript:alert(1)">x</a>`, `<a>x</a>`},
+		{"vbscript and data links", `<a href="vbscript:x">a</a><a href="data:text/html,x">b</a>`, `<a>a</a><a>b</a>`},
+		{"relative and mailto links", `<a href="/docs#top">a</a><a href="mailto:a@b.test">b</a>`, `<a href="/docs#top">a</a><a href="mailto:a@b.test">b</a>`},
+		{"raster data image kept", `<img src="data:image/png;base64,AAAA" alt="p">`, `<img src="data:image/png;base64,AAAA" alt="p">`},
+		{"SVG data image dropped", `<img src="data:image/svg+xml,<svg onload=x>">`, `<img>`},
+		{"code language class only", `<code class="language-go">x</code><code class="evil">y</code>`, `<code class="language-go">x</code><code>y</code>`},
+		{"text inputs dropped", `<input type="text" value="x"><input type="checkbox" checked>`, `<input type="checkbox" checked="" disabled="">`},
+		{"comments dropped", `a<!-- <script>x</script> -->b`, `ab`},
+		{"text escaped", `1 &lt; 2 &amp; "q"`, `1 &lt; 2 &amp; &#34;q&#34;`},
+		{"stray end tags dropped", `</div></div></main><h1>x</h1>`, `<h1>x</h1>`},
+		{"unclosed elements closed", `<div><p><em>x`, `<div><p><em>x</em></p></div>`},
+		{"end tag closes elements inside it", `<div><span>a</div>b</span>`, `<div><span>a</span></div>b`},
+		{"end tag of dropped element ignored", `<p>a</font></p>`, `<p>a</p>`},
+		{"non-numeric span rejected", `<td colspan="2" rowspan="x">c</td>`, `<td colspan="2">c</td>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Sanitize(tt.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("Sanitize(%q)\n got %q\nwant %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,247 @@
+package regexutil
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+	"strings"
+	"unicode/utf8"
+)
+
+// MaxMatches caps the number of matches reported for one input.
+const MaxMatches = 1000
+
+// Flags are the RE2 flags that can be toggled without editing the pattern.
+type Flags struct {
+	CaseInsensitive bool `json:"i"`
+	Multiline       bool `json:"m"`
+	DotAll          bool `json:"s"`
+	Ungreedy        bool `json:"U"`
+}
+
+// prefix returns the inline flag group for f, such as "(?im)".
+func (f Flags) prefix() string {
+	var flags string
+	if f.CaseInsensitive {
+		flags += "i"
+	}
+	if f.Multiline {
+		flags += "m"
+	}
+	if f.DotAll {
+		flags += "s"
+	}
+	if f.Ungreedy {
+		flags += "U"
+	}
+	if flags == "" {
+		return ""
+	}
+	return "(?" + flags + ")"
+}
+
+// CompileError describes why a pattern failed to compile. Offset is a byte
+// offset into the pattern and Column a one-based rune column; both are -1
+// when the problem cannot be located.
+type CompileError struct {
+	Pattern  string `json:"pattern"`
+	Message  string `json:"message"`
+	Fragment string `json:"fragment,omitempty"`
+	Offset   int    `json:"offset"`
+	Column   int    `json:"column"`
+}
+
+func (e *CompileError) Error() string {
+	if e.Column < 0 {
+		return e.Message
+	}
+	return fmt.Sprintf("column %d: %s", e.Column, e.Message)
+}
+
+// Group is one capture group of a match. Unmatched optional groups have
+// Matched set to false and offsets of -1.
+type Group struct {
+	Index   int    `json:"index"`
+	Name    string `json:"name,omitempty"`
+	Text    string `json:"text"`
+	Start   int    `json:"start"`
+	End     int    `json:"end"`
+	Matched bool   `json:"matched"`
+}
+
+// Match is one match of the whole pattern. Offsets are byte offsets into the
+// input.
+type Match struct {
+	Index  int     `json:"index"`
+	Text   string  `json:"text"`
+	Start  int     `json:"start"`
+	End    int     `json:"end"`
+	Groups []Group `json:"groups"`
+}
+
+// Segment is a run of input text, either between matches (Match is -1) or
+// the text of the match with that index.
+type Segment struct {
+	Text  string `json:"text"`
+	Match int    `json:"match"`
+}
+
+// Result is the outcome of running a pattern against an input.
+type Result struct {
+	Pattern    string    `json:"pattern"`
+	GroupNames []string  `json:"groupNames"`
+	Matches    []Match   `json:"matches"`
+	Truncated  bool      `json:"truncated"`
+	Segments   []Segment `json:"-"`
+	Replaced   *string   `json:"replaced,omitempty"`
+}
+
+// Compile compiles pattern with flags, returning a *CompileError that points
+// at the offending part of the pattern on failure.
+func Compile(pattern string, flags Flags) (*regexp.Regexp, error) {
+	re, err := regexp.Compile(flags.prefix() + pattern)
+	if err == nil {
+		return re, nil
+	}
+
+	compileErr := &CompileError{Pattern: pattern, Message: err.Error(), Offset: -1, Column: -1}
+
+	var syntaxErr *syntax.Error
+	if errors.As(err, &syntaxErr) {
+		compileErr.Message = syntaxErr.Code.String()
+		compileErr.Fragment = syntaxErr.Expr
+
+		// Paren errors report the whole pattern as the fragment, so find
+		// the offending paren directly.
+		offset := -1
+		switch syntaxErr.Code {
+		case syntax.ErrMissingParen:
+			offset, _ = unbalancedParens(pattern)
+		case syntax.ErrUnexpectedParen:
+			_, offset = unbalancedParens(pattern)
+		case syntax.ErrTrailingBackslash:
+			offset = len(pattern) - 1
+		default:
+			// The parser does not say which occurrence of the fragment
+			// failed, so it is only located when it appears once.
+			if syntaxErr.Expr != "" && strings.Count(pattern, syntaxErr.Expr) == 1 {
+				offset = strings.Index(pattern, syntaxErr.Expr)
+			}
+		}
+
+		if offset >= 0 {
+			compileErr.Offset = offset
+			compileErr.Column = utf8.RuneCountInString(pattern[:offset]) + 1
+			switch syntaxErr.Code {
+			case syntax.ErrMissingParen, syntax.ErrUnexpectedParen, syntax.ErrTrailingBackslash:
+				compileErr.Fragment = pattern[offset : offset+1]
+			}
+		}
+	}
+
+	return nil, compileErr
+}
+
+// unbalancedParens returns the byte offsets of the last unclosed '(' and the
+// first ')' without a matching '(', or -1 for either. Escaped parens and
+// parens inside character classes are ignored.
+func unbalancedParens(pattern string) (open, close int) {
+	open, close = -1, -1
+
+	var stack []int
+	inClass := false
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; {
+		case c == '\\':
+			i++
+		case inClass:
+			if c == ']' {
+				inClass = false
+			}
+		case c == '[':
+			inClass = true
+			// A ']' straight after '[' or '[^' is a literal.
+			if i+1 < len(pattern) && pattern[i+1] == '^' {
+				i++
+			}
+			if i+1 < len(pattern) && pattern[i+1] == ']' {
+				i++
+			}
+		case c == '(':
+			stack = append(stack, i)
+		case c == ')':
+			if len(stack) == 0 {
+				if close < 0 {
+					close = i
+				}
+				continue
+			}
+			stack = stack[:len(stack)-1]
+		}
+	}
+
+	if len(stack) > 0 {
+		open = stack[len(stack)-1]
+	}
+	return open, close
+}
+
+// Run finds up to MaxMatches matches of pattern in input. When replace is
+// non-nil every match is also replaced using regexp template syntax, so $1
+// and ${name} refer to capture groups.
+func Run(pattern string, flags Flags, input string, replace *string) (*Result, error) {
+	re, err := Compile(pattern, flags)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{
+		Pattern:    pattern,
+		GroupNames: re.SubexpNames()[1:],
+		Matches:    []Match{},
+	}
+
+	locs := re.FindAllStringSubmatchIndex(input, MaxMatches+1)
+	if len(locs) > MaxMatches {
+		locs = locs[:MaxMatches]
+		result.Truncated = true
+	}
+
+	names := re.SubexpNames()
+	last := 0
+	for i, loc := range locs {
+		m := Match{
+			Index:  i,
+			Text:   input[loc[0]:loc[1]],
+			Start:  loc[0],
+			End:    loc[1],
+			Groups: make([]Group, 0, len(names)-1),
+		}
+		for g := 1; g < len(names); g++ {
+			start, end := loc[2*g], loc[2*g+1]
+			group := Group{Index: g, Name: names[g], Start: start, End: end, Matched: start >= 0}
+			if group.Matched {
+				group.Text = input[start:end]
+			}
+			m.Groups = append(m.Groups, group)
+		}
+		result.Matches = append(result.Matches, m)
+
+		if loc[0] > last {
+			result.Segments = append(result.Segments, Segment{Text: input[last:loc[0]], Match: -1})
+		}
+		result.Segments = append(result.Segments, Segment{Text: m.Text, Match: i})
+		last = loc[1]
+	}
+	if last < len(input) {
+		result.Segments = append(result.Segments, Segment{Text: input[last:], Match: -1})
+	}
+
+	if replace != nil {
+		replaced := re.ReplaceAllString(input, *replace)
+		result.Replaced = &replaced
+	}
+
+	return result, nil
+}
@@ -0,0 +1,150 @@
+package regexutil
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestCompileError(t *testing.T) {
+	tests := []struct {
+		name     string
+		pattern  string
+		message  string
+		fragment string
+		column   int
+	}{
+		{name: "missing paren", pattern: "a(b", message: "missing closing )", fragment: "(", column: 2},
+		{name: "nested missing paren", pattern: "(a|(b)", message: "missing closing )", fragment: "(", column: 1},
+		{name: "unexpected paren", pattern: "ab)", message: "unexpected )", fragment: ")", column: 3},
+		{name: "escaped paren ignored", pattern: `\(a)`, message: "unexpected )", fragment: ")", column: 4},
+		{name: "paren in class ignored", pattern: "[(]a)", message: "unexpected )", fragment: ")", column: 5},
+		{name: "nested repetition", pattern: "a**", message: "invalid nested repetition operator", fragment: "**", column: 2},
+		{name: "repeated fragment not located", pattern: "[a**]b**", message: "invalid nested repetition operator", fragment: "**", column: -1},
+		{name: "second group missing paren", pattern: "a(b)(b", message: "missing closing )", fragment: "(", column: 5},
+		{name: "bad range", pattern: "[z-a]", message: "invalid character class range", fragment: "z-a", column: 2},
+		{name: "bad repeat", pattern: "x{2,1}", message: "invalid repeat count", fragment: "{2,1}", column: 2},
+		{name: "trailing backslash", pattern: `a\`, message: "trailing backslash at end of expression", fragment: `\`, column: 2},
+		{name: "column counts runes", pattern: "é(?<x", message: "invalid named capture", fragment: "(?<x", column: 2},
+		{name: "lookahead unsupported", pattern: "a(?=b)", message: "invalid or unsupported Perl syntax", fragment: "(?=", column: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Compile(tt.pattern, Flags{})
+			var compileErr *CompileError
+			if !errors.As(err, &compileErr) {
+				t.Fatalf("Compile(%q) error = %v, want *CompileError", tt.pattern, err)
+			}
+			if compileErr.Message != tt.message || compileErr.Fragment != tt.fragment || compileErr.Column != tt.column {
+				t.Errorf("Compile(%q) = {%q %q column %d}, want {%q %q column %d}",
+					tt.pattern, compileErr.Message, compileErr.Fragment, compileErr.Column, tt.message, tt.fragment, tt.column)
+			}
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	result, err := Run(`(?P<key>\w+)=(\d+)?`, Flags{}, "a=1, b=, c=33", nil)
+	if err != nil {
+		t.Fatalf("Run unexpected error: %v", err)
+	}
+
+	if strings.Join(result.GroupNames, ",") != "key," {
+		t.Errorf("GroupNames = %q, want [key \"\"]", result.GroupNames)
+	}
+
+	var got []string
+	for _, m := range result.Matches {
+		desc := fmt.Sprintf("%d:%q@%d-%d", m.Index, m.Text, m.Start, m.End)
+		for _, g := range m.Groups {
+			desc += fmt.Sprintf(" %d/%s=%q/%v", g.Index, g.Name, g.Text, g.Matched)
+		}
+		got = append(got, desc)
+	}
+	expected := []string{
+		`0:"a=1"@0-3 1/key="a"/true 2/="1"/true`,
+		`1:"b="@5-7 1/key="b"/true 2/=""/false`,
+		`2:"c=33"@9-13 1/key="c"/true 2/="33"/true`,
+	}
+	if strings.Join(got, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("matches\ngot:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(expected, "\n"))
+	}
+
+	var segments []string
+	for _, s := range result.Segments {
+		segments = append(segments, fmt.Sprintf("%d:%s", s.Match, s.Text))
+	}
+	if strings.Join(segments, "|") != "0:a=1|-1:, |1:b=|-1:, |2:c=33" {
+		t.Errorf("segments = %q", segments)
+	}
+}
+
+func TestRunFlags(t *testing.T) {
+	tests := []struct {
+		name     string
+		pattern  string
+		flags    Flags
+		input    string
+		expected []string
+	}{
+		{name: "case sensitive", pattern: "go", input: "Go go GO", expected: []string{"go"}},
+		{name: "case insensitive", pattern: "go", flags: Flags{CaseInsensitive: true}, input: "Go go GO", expected: []string{"Go", "go", "GO"}},
+		{name: "multiline anchors", pattern: "^\\w+", flags: Flags{Multiline: true}, input: "one\ntwo", expected: []string{"one", "two"}},
+		{name: "dot matches newline", pattern: "a.b", flags: Flags{DotAll: true}, input: "a\nb", expected: []string{"a\nb"}},
+		{name: "ungreedy", pattern: "<.+>", flags: Flags{Ungreedy: true}, input: "<a><b>", expected: []string{"<a>", "<b>"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Run(tt.pattern, tt.flags, tt.input, nil)
+			if err != nil {
+				t.Fatalf("Run(%q) unexpected error: %v", tt.pattern, err)
+			}
+			var got []string
+			for _, m := range result.Matches {
+				got = append(got, m.Text)
+			}
+			if strings.Join(got, "|") != strings.Join(tt.expected, "|") {
+				t.Errorf("Run(%q) matches = %q, want %q", tt.pattern, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRunReplace(t *testing.T) {
+	tests := []struct {
+		name     string
+		pattern  string
+		template string
+		input    string
+		expected string
+	}{
+		{name: "numbered groups", pattern: `(\w+)@(\w+)`, template: "$2 at $1", input: "ann@home", expected: "home at ann"},
+		{name: "named groups", pattern: `(?P<y>\d{4})-(?P<m>\d{2})`, template: "${m}/${y}", input: "2024-05", expected: "05/2024"},
+		{name: "braces separate group from text", pattern: `(\d)`, template: "${1}x", input: "a1b2", expected: "a1xb2x"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Run(tt.pattern, Flags{}, tt.input, &tt.template)
+			if err != nil {
+				t.Fatalf("Run(%q) unexpected error: %v", tt.pattern, err)
+			}
+			if result.Replaced == nil || *result.Replaced != tt.expected {
+				t.Errorf("Run(%q) replaced = %v, want %q", tt.pattern, result.Replaced, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRunTruncates(t *testing.T) {
+	result, err := Run("a", Flags{}, strings.Repeat("a", MaxMatches+5), nil)
+	if err != nil {
+		t.Fatalf("Run unexpected error: %v", err)
+	}
+	if len(result.Matches) != MaxMatches || !result.Truncated {
+		t.Errorf("got %d matches (truncated %v), want %d truncated", len(result.Matches), result.Truncated, MaxMatches)
+	}
+}
@@ -0,0 +1,156 @@
+package textutil
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Case names an identifier naming convention.
+type Case string
+
+const (
+	CaseCamel          Case = "camel"
+	CasePascal         Case = "pascal"
+	CaseSnake          Case = "snake"
+	CaseScreamingSnake Case = "screaming-snake"
+	CaseKebab          Case = "kebab"
+	CaseTrain          Case = "train"
+	CaseDot            Case = "dot"
+	CaseTitle          Case = "title"
+)
+
+// CaseOption describes a supported case for the UI, with an example.
+type CaseOption struct {
+	Case    Case
+	Name    string
+	Example string
+}
+
+// Cases lists the supported cases in display order.
+var Cases = []CaseOption{
+	{CaseCamel, "camelCase", "httpServerPort"},
+	{CasePascal, "PascalCase", "HttpServerPort"},
+	{CaseSnake, "snake_case", "http_server_port"},
+	{CaseScreamingSnake, "SCREAMING_SNAKE", "HTTP_SERVER_PORT"},
+	{CaseKebab, "kebab-case", "http-server-port"},
+	{CaseTrain, "Train-Case", "Http-Server-Port"},
+	{CaseDot, "dot.case", "http.server.port"},
+	{CaseTitle, "Title Case", "Http Server Port"},
+}
+
+// ParseCase validates a case name.
+func ParseCase(s string) (Case, error) {
+	for _, c := range Cases {
+		if string(c.Case) == s {
+			return c.Case, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported case %q", s)
+}
+
+// SplitWords breaks an identifier or phrase into words. Any character that is
+// not a letter or digit separates words, and so do case changes: "fooBar"
+// splits before "B", and an acronym ends before its last capital when a
+// lowercase letter follows ("HTTPServer" is "HTTP", "Server"). Digits stay
+// attached to the word they follow ("base64Encode" is "base64", "Encode"),
+// and a capital after a digit starts a new word.
+func SplitWords(s string) []string {
+	runes := []rune(s)
+
+	var words []string
+	start := -1
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			if start >= 0 {
+				words = append(words, string(runes[start:i]))
+				start = -1
+			}
+			continue
+		}
+		if start < 0 {
+			start = i
+			continue
+		}
+
+		prev := runes[i-1]
+		if unicode.IsUpper(r) {
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				words = append(words, string(runes[start:i]))
+				start = i
+			}
+		}
+	}
+	if start >= 0 {
+		words = append(words, string(runes[start:]))
+	}
+
+	return words
+}
+
+// ConvertCase rewrites s in the given case. Acronyms are treated as ordinary
+// words, so "HTTPServer" becomes "httpServer", "http_server" or "HttpServer".
+func ConvertCase(s string, c Case) (string, error) {
+	words := SplitWords(s)
+
+	switch c {
+	case CaseCamel:
+		for i, w := range words {
+			if i == 0 {
+				words[i] = strings.ToLower(w)
+			} else {
+				words[i] = capitalize(w)
+			}
+		}
+		return strings.Join(words, ""), nil
+	case CasePascal:
+		return joinMapped(words, "", capitalize), nil
+	case CaseSnake:
+		return joinMapped(words, "_", strings.ToLower), nil
+	case CaseScreamingSnake:
+		return joinMapped(words, "_", strings.ToUpper), nil
+	case CaseKebab:
+		return joinMapped(words, "-", strings.ToLower), nil
+	case CaseTrain:
+		return joinMapped(words, "-", capitalize), nil
+	case CaseDot:
+		return joinMapped(words, ".", strings.ToLower), nil
+	case CaseTitle:
+		return joinMapped(words, " ", capitalize), nil
+	default:
+		return "", fmt.Errorf("unsupported case %q", c)
+	}
+}
+
+// ConvertCaseLines converts every line of s independently, keeping blank
+// lines so the output lines up with the input.
+func ConvertCaseLines(s string, c Case) (string, error) {
+	lines := strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		converted, err := ConvertCase(line, c)
+		if err != nil {
+			return "", err
+		}
+		lines[i] = converted
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// joinMapped applies f to each word and joins the results with sep.
+func joinMapped(words []string, sep string, f func(string) string) string {
+	mapped := make([]string, len(words))
+	for i, w := range words {
+		mapped[i] = f(w)
+	}
+	return strings.Join(mapped, sep)
+}
+
+// capitalize uppercases the first rune of w and lowercases the rest.
+func capitalize(w string) string {
+	runes := []rune(strings.ToLower(w))
+	if len(runes) > 0 {
+		runes[0] = unicode.ToUpper(runes[0])
+	}
+	return string(runes)
+}
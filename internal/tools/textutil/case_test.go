@@ -0,0 +1,103 @@
+package textutil
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSplitWords(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []string
+	}{
+		{name: "camel", input: "httpServerPort", expected: []string{"http", "Server", "Port"}},
+		{name: "leading acronym", input: "HTTPServer", expected: []string{"HTTP", "Server"}},
+		{name: "trailing acronym", input: "userID", expected: []string{"user", "ID"}},
+		{name: "acronym in middle", input: "parseJSONBody", expected: []string{"parse", "JSON", "Body"}},
+		{name: "snake", input: "http_server_port", expected: []string{"http", "server", "port"}},
+		{name: "screaming snake", input: "MAX_RETRY_COUNT", expected: []string{"MAX", "RETRY", "COUNT"}},
+		{name: "mixed separators", input: "  foo-bar.baz qux  ", expected: []string{"foo", "bar", "baz", "qux"}},
+		{name: "digits stay with previous word", input: "base64Encode", expected: []string{"base64", "Encode"}},
+		{name: "acronym with digits", input: "HTTP2Server", expected: []string{"HTTP2", "Server"}},
+		{name: "lowercase after digits", input: "utf8mb4", expected: []string{"utf8mb4"}},
+		{name: "leading digits", input: "3dModel", expected: []string{"3d", "Model"}},
+		{name: "unicode letters", input: "naïveÜber", expected: []string{"naïve", "Über"}},
+		{name: "empty", input: "", expected: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := SplitWords(tt.input)
+			if strings.Join(result, "|") != strings.Join(tt.expected, "|") {
+				t.Errorf("SplitWords(%q) = %q, want %q", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestConvertCase(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		to          Case
+		expected    string
+		expectError bool
+	}{
+		{name: "to camel", input: "HTTPServer", to: CaseCamel, expected: "httpServer"},
+		{name: "to pascal", input: "http_server", to: CasePascal, expected: "HttpServer"},
+		{name: "to snake", input: "HTTPServer", to: CaseSnake, expected: "http_server"},
+		{name: "to screaming snake", input: "maxRetryCount", to: CaseScreamingSnake, expected: "MAX_RETRY_COUNT"},
+		{name: "to kebab", input: "userID", to: CaseKebab, expected: "user-id"},
+		{name: "to train", input: "content_type", to: CaseTrain, expected: "Content-Type"},
+		{name: "to dot", input: "AppConfigPath", to: CaseDot, expected: "app.config.path"},
+		{name: "to title", input: "the-quick_brown fox", to: CaseTitle, expected: "The Quick Brown Fox"},
+		{name: "digits", input: "oauth2ClientID", to: CaseSnake, expected: "oauth2_client_id"},
+		{name: "round trip", input: "http_server_port", to: CaseCamel, expected: "httpServerPort"},
+		{name: "empty", input: "", to: CaseSnake, expected: ""},
+		{name: "unsupported", input: "foo", to: "shouty", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ConvertCase(tt.input, tt.to)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("ConvertCase(%q, %q) expected error but got nil", tt.input, tt.to)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ConvertCase(%q, %q) unexpected error: %v", tt.input, tt.to, err)
+			}
+			if result != tt.expected {
+				t.Errorf("ConvertCase(%q, %q) = %q, want %q", tt.input, tt.to, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestConvertCaseLines(t *testing.T) {
+	input := "firstName\r\nlast_name\n\nHTTPStatusCode"
+	expected := "first_name\nlast_name\n\nhttp_status_code"
+
+	result, err := ConvertCaseLines(input, CaseSnake)
+	if err != nil {
+		t.Fatalf("ConvertCaseLines unexpected error: %v", err)
+	}
+	if result != expected {
+		t.Errorf("ConvertCaseLines(%q) = %q, want %q", input, result, expected)
+	}
+}
+
+func TestCasesExamples(t *testing.T) {
+	for _, c := range Cases {
+		result, err := ConvertCase("HTTPServerPort", c.Case)
+		if err != nil {
+			t.Fatalf("ConvertCase(%q) unexpected error: %v", c.Case, err)
+		}
+		if result != c.Example {
+			t.Errorf("ConvertCase(%q) = %q, want example %q", c.Case, result, c.Example)
+		}
+	}
+}
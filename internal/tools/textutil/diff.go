@@ -0,0 +1,367 @@
+package textutil
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// DiffMode selects the unit that Diff compares.
+type DiffMode string
+
+const (
+	DiffLines DiffMode = "line"
+	DiffWords DiffMode = "word"
+	DiffChars DiffMode = "char"
+)
+
+// ParseDiffMode validates a diff mode name.
+func ParseDiffMode(s string) (DiffMode, error) {
+	switch DiffMode(s) {
+	case DiffLines, DiffWords, DiffChars:
+		return DiffMode(s), nil
+	default:
+		return "", fmt.Errorf("unsupported diff mode %q", s)
+	}
+}
+
+// DiffOptions controls tokenization and comparison. The zero value compares
+// lines exactly.
+type DiffOptions struct {
+	Mode DiffMode
+
+	// IgnoreWhitespace treats runs of whitespace as equal regardless of
+	// their length, and ignores leading and trailing whitespace on lines.
+	IgnoreWhitespace bool
+
+	// IgnoreCase compares tokens case-insensitively.
+	IgnoreCase bool
+}
+
+// DiffOp is the kind of an Edit.
+type DiffOp int
+
+const (
+	OpEqual DiffOp = iota
+	OpDelete
+	OpInsert
+)
+
+// String returns "equal", "delete" or "insert".
+func (op DiffOp) String() string {
+	switch op {
+	case OpDelete:
+		return "delete"
+	case OpInsert:
+		return "insert"
+	default:
+		return "equal"
+	}
+}
+
+// Edit is one token of a diff. Equal edits carry both versions, which can
+// differ when whitespace or case is ignored; deletes only carry Old and
+// inserts only carry New. In line mode each token includes its newline.
+type Edit struct {
+	Op  DiffOp
+	Old string
+	New string
+}
+
+// Text returns the token as it appears in the side it belongs to, preferring
+// the new version for equal tokens.
+func (e Edit) Text() string {
+	if e.Op == OpDelete {
+		return e.Old
+	}
+	return e.New
+}
+
+// MaxDiffTokens is the most tokens, counting both sides, that callers should
+// pass to Diff. Myers' algorithm takes time proportional to the number of
+// tokens times the number of differences, so the worst case grows with the
+// square of the input: two unrelated texts at the limit take under a second.
+const MaxDiffTokens = 20000
+
+// DiffSize returns how many tokens Diff compares for a and b with opts.
+func DiffSize(a, b string, opts DiffOptions) int {
+	mode := opts.Mode
+	if mode == "" {
+		mode = DiffLines
+	}
+	return len(tokenize(a, mode, opts.IgnoreWhitespace)) + len(tokenize(b, mode, opts.IgnoreWhitespace))
+}
+
+// Diff computes a minimal edit script from a to b using Myers' algorithm in
+// linear space. See MaxDiffTokens for how long it can take.
+func Diff(a, b string, opts DiffOptions) []Edit {
+	mode := opts.Mode
+	if mode == "" {
+		mode = DiffLines
+	}
+
+	oldTokens := tokenize(a, mode, opts.IgnoreWhitespace)
+	newTokens := tokenize(b, mode, opts.IgnoreWhitespace)
+
+	// Compare small integer IDs instead of strings.
+	ids := make(map[string]int)
+	idOf := func(tok string) int {
+		key := diffKey(tok, mode, opts)
+		id, ok := ids[key]
+		if !ok {
+			id = len(ids)
+			ids[key] = id
+		}
+		return id
+	}
+
+	d := &differ{
+		a:       make([]int, len(oldTokens)),
+		b:       make([]int, len(newTokens)),
+		removed: make([]bool, len(oldTokens)),
+		added:   make([]bool, len(newTokens)),
+	}
+	for i, tok := range oldTokens {
+		d.a[i] = idOf(tok)
+	}
+	for i, tok := range newTokens {
+		d.b[i] = idOf(tok)
+	}
+	d.compare(0, len(d.a), 0, len(d.b))
+
+	var edits []Edit
+	i, j := 0, 0
+	for i < len(oldTokens) || j < len(newTokens) {
+		switch {
+		case i < len(oldTokens) && d.removed[i]:
+			edits = append(edits, Edit{Op: OpDelete, Old: oldTokens[i]})
+			i++
+		case j < len(newTokens) && d.added[j]:
+			edits = append(edits, Edit{Op: OpInsert, New: newTokens[j]})
+			j++
+		default:
+			edits = append(edits, Edit{Op: OpEqual, Old: oldTokens[i], New: newTokens[j]})
+			i++
+			j++
+		}
+	}
+
+	return edits
+}
+
+// DiffStats counts inserted and deleted tokens.
+func DiffStats(edits []Edit) (inserted, deleted int) {
+	for _, e := range edits {
+		switch e.Op {
+		case OpInsert:
+			inserted++
+		case OpDelete:
+			deleted++
+		}
+	}
+	return inserted, deleted
+}
+
+// tokenize splits s into the units compared in mode. Concatenating the
+// tokens always reproduces s.
+func tokenize(s string, mode DiffMode, ignoreWhitespace bool) []string {
+	switch mode {
+	case DiffWords:
+		return splitTokens(s, func(r rune) int {
+			switch {
+			case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_':
+				return 1
+			case unicode.IsSpace(r):
+				return 2
+			default:
+				return 0
+			}
+		})
+	case DiffChars:
+		return splitTokens(s, func(r rune) int {
+			// Whitespace runs form one token so that ignoring whitespace
+			// can treat " " and "\t\t" as equal.
+			if ignoreWhitespace && unicode.IsSpace(r) {
+				return 2
+			}
+			return 0
+		})
+	default:
+		lines := strings.SplitAfter(s, "\n")
+		if lines[len(lines)-1] == "" {
+			lines = lines[:len(lines)-1]
+		}
+		return lines
+	}
+}
+
+// splitTokens groups consecutive runes with the same non-zero class into one
+// token; runes of class zero are tokens on their own.
+func splitTokens(s string, class func(rune) int) []string {
+	var tokens []string
+	start, prev := 0, -1
+	for i, r := range s {
+		c := class(r)
+		if i > 0 && (c == 0 || c != prev) {
+			tokens = append(tokens, s[start:i])
+			start = i
+		}
+		prev = c
+	}
+	if start < len(s) {
+		tokens = append(tokens, s[start:])
+	}
+	return tokens
+}
+
+// diffKey normalizes a token for comparison according to opts.
+func diffKey(tok string, mode DiffMode, opts DiffOptions) string {
+	if opts.IgnoreWhitespace {
+		if mode == DiffLines {
+			tok = strings.Join(strings.Fields(tok), " ")
+		} else if strings.TrimSpace(tok) == "" {
+			tok = " "
+		}
+	}
+	if opts.IgnoreCase {
+		tok = strings.ToLower(tok)
+	}
+	return tok
+}
+
+// differ marks which tokens of a are removed and which tokens of b are added.
+type differ struct {
+	a, b    []int
+	removed []bool
+	added   []bool
+}
+
+// compare marks the differences between a[aLo:aHi] and b[bLo:bHi], splitting
+// the problem at a point on an optimal path found by bisect.
+func (d *differ) compare(aLo, aHi, bLo, bHi int) {
+	for aLo < aHi && bLo < bHi && d.a[aLo] == d.b[bLo] {
+		aLo++
+		bLo++
+	}
+	for aLo < aHi && bLo < bHi && d.a[aHi-1] == d.b[bHi-1] {
+		aHi--
+		bHi--
+	}
+
+	switch {
+	case aLo == aHi:
+		for j := bLo; j < bHi; j++ {
+			d.added[j] = true
+		}
+	case bLo == bHi:
+		for i := aLo; i < aHi; i++ {
+			d.removed[i] = true
+		}
+	default:
+		x, y, ok := d.bisect(aLo, aHi, bLo, bHi)
+		if !ok {
+			for i := aLo; i < aHi; i++ {
+				d.removed[i] = true
+			}
+			for j := bLo; j < bHi; j++ {
+				d.added[j] = true
+			}
+			return
+		}
+		d.compare(aLo, aLo+x, bLo, bLo+y)
+		d.compare(aLo+x, aHi, bLo+y, bHi)
+	}
+}
+
+// bisect runs the forward and reverse Myers searches simultaneously and
+// returns, relative to aLo and bLo, the point where they first overlap. The
+// point lies on a shortest edit path, so each half can be solved on its own.
+// ok is false when the ranges share no tokens.
+func (d *differ) bisect(aLo, aHi, bLo, bHi int) (x, y int, ok bool) {
+	n, m := aHi-aLo, bHi-bLo
+	a, b := d.a[aLo:aHi], d.b[bLo:bHi]
+
+	maxD := (n + m + 1) / 2
+	offset := maxD
+	// Two extra slots keep k+1 in range when maxD is small.
+	size := 2*maxD + 2
+	forward := make([]int, size)
+	reverse := make([]int, size)
+	for i := range forward {
+		forward[i] = -1
+		reverse[i] = -1
+	}
+	forward[offset+1] = 0
+	reverse[offset+1] = 0
+
+	delta := n - m
+	// With an odd delta the paths can only meet during a forward step.
+	checkForward := delta%2 != 0
+
+	// Diagonals that run off the grid are trimmed from later rounds.
+	var k1start, k1end, k2start, k2end int
+
+	for step := 0; step < maxD; step++ {
+		for k1 := -step + k1start; k1 <= step-k1end; k1 += 2 {
+			i := offset + k1
+			var x1 int
+			if k1 == -step || (k1 != step && forward[i-1] < forward[i+1]) {
+				x1 = forward[i+1]
+			} else {
+				x1 = forward[i-1] + 1
+			}
+			y1 := x1 - k1
+			for x1 < n && y1 < m && a[x1] == b[y1] {
+				x1++
+				y1++
+			}
+			forward[i] = x1
+
+			switch {
+			case x1 > n:
+				k1end += 2
+			case y1 > m:
+				k1start += 2
+			case checkForward:
+				j := offset + delta - k1
+				if j >= 0 && j < size && reverse[j] != -1 && x1 >= n-reverse[j] {
+					return x1, y1, true
+				}
+			}
+		}
+
+		for k2 := -step + k2start; k2 <= step-k2end; k2 += 2 {
+			j := offset + k2
+			var x2 int
+			if k2 == -step || (k2 != step && reverse[j-1] < reverse[j+1]) {
+				x2 = reverse[j+1]
+			} else {
+				x2 = reverse[j-1] + 1
+			}
+			y2 := x2 - k2
+			for x2 < n && y2 < m && a[n-x2-1] == b[m-y2-1] {
+				x2++
+				y2++
+			}
+			reverse[j] = x2
+
+			switch {
+			case x2 > n:
+				k2end += 2
+			case y2 > m:
+				k2start += 2
+			case !checkForward:
+				i := offset + delta - k2
+				if i >= 0 && i < size && forward[i] != -1 {
+					x1 := forward[i]
+					y1 := offset + x1 - i
+					if x1 >= n-x2 {
+						return x1, y1, true
+					}
+				}
+			}
+		}
+	}
+
+	return 0, 0, false
+}
@@ -0,0 +1,187 @@
+package textutil
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// render formats edits compactly: "=a" for equal, "-a" for deleted and "+a"
+// for inserted tokens.
+func render(edits []Edit) string {
+	var parts []string
+	for _, e := range edits {
+		switch e.Op {
+		case OpEqual:
+			parts = append(parts, "="+e.Old)
+		case OpDelete:
+			parts = append(parts, "-"+e.Old)
+		case OpInsert:
+			parts = append(parts, "+"+e.New)
+		}
+	}
+	return strings.Join(parts, "|")
+}
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		a        string
+		b        string
+		opts     DiffOptions
+		expected string
+	}{
+		{
+			name:     "identical lines",
+			a:        "a\nb\n",
+			b:        "a\nb\n",
+			expected: "=a\n|=b\n",
+		},
+		{
+			name:     "changed line",
+			a:        "a\nb\nc\n",
+			b:        "a\nB\nc\n",
+			expected: "=a\n|-b\n|+B\n|=c\n",
+		},
+		{
+			name:     "insert and delete",
+			a:        "a\nb\nc",
+			b:        "b\nc\nd",
+			expected: "-a\n|=b\n|-c|+c\n|+d",
+		},
+		{
+			name:     "empty old",
+			a:        "",
+			b:        "x\n",
+			expected: "+x\n",
+		},
+		{
+			name:     "words",
+			a:        "the quick brown fox",
+			b:        "the slow brown fox!",
+			opts:     DiffOptions{Mode: DiffWords},
+			expected: "=the|= |-quick|+slow|= |=brown|= |=fox|+!",
+		},
+		{
+			name:     "chars",
+			a:        "kitten",
+			b:        "sitting",
+			opts:     DiffOptions{Mode: DiffChars},
+			expected: "-k|+s|=i|=t|=t|-e|+i|=n|+g",
+		},
+		{
+			name:     "ignore whitespace in lines",
+			a:        "if x {\n\treturn  1\n}\n",
+			b:        "if x {\n    return 1\n}  \n",
+			opts:     DiffOptions{IgnoreWhitespace: true},
+			expected: "=if x {\n|=\treturn  1\n|=}\n",
+		},
+		{
+			name:     "ignore case in words",
+			a:        "Hello World",
+			b:        "hello WORLD again",
+			opts:     DiffOptions{Mode: DiffWords, IgnoreCase: true},
+			expected: "=Hello|= |=World|+ |+again",
+		},
+		{
+			name:     "ignore whitespace in chars",
+			a:        "a  b",
+			b:        "a\tb",
+			opts:     DiffOptions{Mode: DiffChars, IgnoreWhitespace: true},
+			expected: "=a|=  |=b",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := render(Diff(tt.a, tt.b, tt.opts))
+			if result != tt.expected {
+				t.Errorf("Diff(%q, %q)\ngot:  %q\nwant: %q", tt.a, tt.b, result, tt.expected)
+			}
+		})
+	}
+}
+
+// lcsLength is the textbook dynamic programming solution used to check that
+// Diff finds a minimal edit script.
+func lcsLength(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for i := range a {
+		for j := range b {
+			switch {
+			case a[i] == b[j]:
+				cur[j+1] = prev[j] + 1
+			case prev[j+1] > cur[j]:
+				cur[j+1] = prev[j+1]
+			default:
+				cur[j+1] = cur[j]
+			}
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+func TestDiffMinimal(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	randText := func() string {
+		runes := make([]rune, rng.Intn(40))
+		for i := range runes {
+			runes[i] = rune('a' + rng.Intn(4))
+		}
+		return string(runes)
+	}
+
+	for i := 0; i < 500; i++ {
+		a, b := randText(), randText()
+		edits := Diff(a, b, DiffOptions{Mode: DiffChars})
+
+		var oldText, newText strings.Builder
+		for _, e := range edits {
+			if e.Op != OpInsert {
+				oldText.WriteString(e.Old)
+			}
+			if e.Op != OpDelete {
+				newText.WriteString(e.New)
+			}
+		}
+		if oldText.String() != a || newText.String() != b {
+			t.Fatalf("Diff(%q, %q) does not reproduce its inputs", a, b)
+		}
+
+		inserted, deleted := DiffStats(edits)
+		want := len(a) + len(b) - 2*lcsLength([]rune(a), []rune(b))
+		if inserted+deleted != want {
+			t.Fatalf("Diff(%q, %q) made %d edits, want %d", a, b, inserted+deleted, want)
+		}
+	}
+}
+
+func TestParseDiffMode(t *testing.T) {
+	for _, s := range []string{"line", "word", "char"} {
+		if _, err := ParseDiffMode(s); err != nil {
+			t.Errorf("ParseDiffMode(%q) unexpected error: %v", s, err)
+		}
+	}
+	if _, err := ParseDiffMode("byte"); err == nil {
+		t.Error("ParseDiffMode(\"byte\") expected error but got nil")
+	}
+}
+
+func TestDiffSize(t *testing.T) {
+	tests := []struct {
+		opts     DiffOptions
+		expected int
+	}{
+		{DiffOptions{}, 3},
+		{DiffOptions{Mode: DiffWords}, 10},
+		{DiffOptions{Mode: DiffChars}, 11},
+		{DiffOptions{Mode: DiffChars, IgnoreWhitespace: true}, 10},
+	}
+	for _, tt := range tests {
+		if got := DiffSize("a b\nc\n", "a  d\n", tt.opts); got != tt.expected {
+			t.Errorf("DiffSize(%+v) = %d, want %d", tt.opts, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,208 @@
+package textutil
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultDiffContext is the number of unchanged lines shown around each hunk
+// of a unified diff, matching diff -u.
+const DefaultDiffContext = 3
+
+// noNewline marks a final line without a trailing newline in unified diffs.
+const noNewline = "\\ No newline at end of file\n"
+
+// Unified renders line-mode edits as a unified diff suitable for patch(1).
+// Context lines come from the old text so the patch applies to it even when
+// whitespace or case was ignored. It returns "" when there are no changes.
+func Unified(oldName, newName string, edits []Edit, context int) string {
+	if context < 0 {
+		context = 0
+	}
+
+	// Line numbers (zero-based) of each edit in the old and new text.
+	oldLine := make([]int, len(edits)+1)
+	newLine := make([]int, len(edits)+1)
+	var changes []int
+	for i, e := range edits {
+		oldLine[i+1], newLine[i+1] = oldLine[i], newLine[i]
+		if e.Op != OpInsert {
+			oldLine[i+1]++
+		}
+		if e.Op != OpDelete {
+			newLine[i+1]++
+		}
+		if e.Op != OpEqual {
+			changes = append(changes, i)
+		}
+	}
+	if len(changes) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- %s\n+++ %s\n", oldName, newName)
+
+	for c := 0; c < len(changes); {
+		start := max(changes[c]-context, 0)
+
+		// Extend the hunk while the next change is close enough that the
+		// context around both would touch.
+		last := changes[c]
+		for c++; c < len(changes) && changes[c]-last <= 2*context+1; c++ {
+			last = changes[c]
+		}
+		end := min(last+context+1, len(edits))
+
+		oldCount := oldLine[end] - oldLine[start]
+		newCount := newLine[end] - newLine[start]
+		fmt.Fprintf(&b, "@@ -%s +%s @@\n", hunkRange(oldLine[start], oldCount), hunkRange(newLine[start], newCount))
+
+		for _, e := range edits[start:end] {
+			switch e.Op {
+			case OpEqual:
+				writePatchLine(&b, ' ', e.Old)
+			case OpDelete:
+				writePatchLine(&b, '-', e.Old)
+			case OpInsert:
+				writePatchLine(&b, '+', e.New)
+			}
+		}
+	}
+
+	return b.String()
+}
+
+// hunkRange formats the start,count pair of a hunk header. An empty range
+// names the line before it, as diff -u does.
+func hunkRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
+
+// writePatchLine writes one prefixed line, adding the no-newline marker when
+// the line is the unterminated last line of its file.
+func writePatchLine(b *strings.Builder, prefix byte, line string) {
+	b.WriteByte(prefix)
+	b.WriteString(line)
+	if !strings.HasSuffix(line, "\n") {
+		b.WriteString("\n")
+		b.WriteString(noNewline)
+	}
+}
+
+// Segment is a run of text within a side-by-side row, marked when it differs
+// from the other side.
+type Segment struct {
+	Text    string
+	Changed bool
+}
+
+// DiffRow is one row of a side-by-side view. Line numbers are one-based and
+// zero when the side is empty. Kind is "equal", "change", "delete" or
+// "insert".
+type DiffRow struct {
+	Kind    string
+	OldLine int
+	NewLine int
+	Old     []Segment
+	New     []Segment
+}
+
+// SideBySide pairs line-mode edits into rows. Adjacent runs of deleted and
+// inserted lines are matched up as changed rows, with a word diff between
+// each pair so the changed parts can be highlighted.
+func SideBySide(edits []Edit, opts DiffOptions) []DiffRow {
+	wordOpts := opts
+	wordOpts.Mode = DiffWords
+
+	var rows []DiffRow
+	oldNum, newNum := 0, 0
+	for i := 0; i < len(edits); {
+		if edits[i].Op == OpEqual {
+			oldNum++
+			newNum++
+			rows = append(rows, DiffRow{
+				Kind:    "equal",
+				OldLine: oldNum,
+				NewLine: newNum,
+				Old:     []Segment{{Text: trimNewline(edits[i].Old)}},
+				New:     []Segment{{Text: trimNewline(edits[i].New)}},
+			})
+			i++
+			continue
+		}
+
+		var deleted, inserted []string
+		for ; i < len(edits) && edits[i].Op != OpEqual; i++ {
+			if edits[i].Op == OpDelete {
+				deleted = append(deleted, trimNewline(edits[i].Old))
+			} else {
+				inserted = append(inserted, trimNewline(edits[i].New))
+			}
+		}
+
+		for k := 0; k < max(len(deleted), len(inserted)); k++ {
+			var row DiffRow
+			switch {
+			case k < len(deleted) && k < len(inserted):
+				oldNum++
+				newNum++
+				row = DiffRow{Kind: "change", OldLine: oldNum, NewLine: newNum}
+				row.Old, row.New = wordSegments(deleted[k], inserted[k], wordOpts)
+			case k < len(deleted):
+				oldNum++
+				row = DiffRow{Kind: "delete", OldLine: oldNum, Old: []Segment{{Text: deleted[k], Changed: true}}}
+			default:
+				newNum++
+				row = DiffRow{Kind: "insert", NewLine: newNum, New: []Segment{{Text: inserted[k], Changed: true}}}
+			}
+			rows = append(rows, row)
+		}
+	}
+
+	return rows
+}
+
+// Segments splits edits into the old and new sides, merging adjacent runs so
+// each side alternates between changed and unchanged text.
+func Segments(edits []Edit) (oldSide, newSide []Segment) {
+	for _, e := range edits {
+		switch e.Op {
+		case OpEqual:
+			oldSide = appendSegment(oldSide, e.Old, false)
+			newSide = appendSegment(newSide, e.New, false)
+		case OpDelete:
+			oldSide = appendSegment(oldSide, e.Old, true)
+		case OpInsert:
+			newSide = appendSegment(newSide, e.New, true)
+		}
+	}
+	return oldSide, newSide
+}
+
+// wordSegments diffs two lines word by word.
+func wordSegments(oldText, newText string, opts DiffOptions) (oldSide, newSide []Segment) {
+	return Segments(Diff(oldText, newText, opts))
+}
+
+// appendSegment adds text to segs, extending the last segment when it has the
+// same Changed state.
+func appendSegment(segs []Segment, text string, changed bool) []Segment {
+	if n := len(segs); n > 0 && segs[n-1].Changed == changed {
+		segs[n-1].Text += text
+		return segs
+	}
+	return append(segs, Segment{Text: text, Changed: changed})
+}
+
+// trimNewline removes a trailing "\n" or "\r\n".
+func trimNewline(s string) string {
+	s = strings.TrimSuffix(s, "\n")
+	return strings.TrimSuffix(s, "\r")
+}
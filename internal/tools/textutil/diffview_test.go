@@ -0,0 +1,103 @@
+package textutil
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestUnified(t *testing.T) {
+	tests := []struct {
+		name     string
+		a        string
+		b        string
+		context  int
+		expected string
+	}{
+		{
+			name:     "no changes",
+			a:        "a\nb\n",
+			b:        "a\nb\n",
+			context:  3,
+			expected: "",
+		},
+		{
+			name:    "separate hunks and missing newline",
+			a:       "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12",
+			b:       "1\n2\nthree\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13\n",
+			context: 3,
+			expected: "--- old\n+++ new\n" +
+				"@@ -1,6 +1,6 @@\n 1\n 2\n-3\n+three\n 4\n 5\n 6\n" +
+				"@@ -9,4 +9,5 @@\n 9\n 10\n 11\n-12\n\\ No newline at end of file\n+12\n+13\n",
+		},
+		{
+			name:     "close changes share a hunk",
+			a:        "a\nb\nc\nd\n",
+			b:        "A\nb\nc\nD\n",
+			context:  1,
+			expected: "--- old\n+++ new\n@@ -1,4 +1,4 @@\n-a\n+A\n b\n c\n-d\n+D\n",
+		},
+		{
+			name:     "zero context",
+			a:        "a\nb\n",
+			b:        "a\nx\nb\n",
+			context:  0,
+			expected: "--- old\n+++ new\n@@ -1,0 +2 @@\n+x\n",
+		},
+		{
+			name:     "new file",
+			a:        "",
+			b:        "x\ny\n",
+			context:  3,
+			expected: "--- old\n+++ new\n@@ -0,0 +1,2 @@\n+x\n+y\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Unified("old", "new", Diff(tt.a, tt.b, DiffOptions{}), tt.context)
+			if result != tt.expected {
+				t.Errorf("Unified(%q, %q)\ngot:\n%s\nwant:\n%s", tt.a, tt.b, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSideBySide(t *testing.T) {
+	a := "keep\nold line here\ngone\nsame\n"
+	b := "keep\nnew line here\nsame\nadded\n"
+
+	rows := SideBySide(Diff(a, b, DiffOptions{}), DiffOptions{})
+
+	var got []string
+	for _, r := range rows {
+		got = append(got, fmt.Sprintf("%s %d:%v %d:%v", r.Kind, r.OldLine, r.Old, r.NewLine, r.New))
+	}
+
+	expected := []string{
+		"equal 1:[{keep false}] 1:[{keep false}]",
+		"change 2:[{old true} { line here false}] 2:[{new true} { line here false}]",
+		"delete 3:[{gone true}] 0:[]",
+		"equal 4:[{same false}] 3:[{same false}]",
+		"insert 0:[] 4:[{added true}]",
+	}
+
+	if len(got) != len(expected) {
+		t.Fatalf("SideBySide returned %d rows, want %d:\n%q", len(got), len(expected), got)
+	}
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Errorf("row %d = %q, want %q", i, got[i], expected[i])
+		}
+	}
+}
+
+func TestSegments(t *testing.T) {
+	oldSide, newSide := Segments(Diff("the quick fox", "the slow red fox", DiffOptions{Mode: DiffWords}))
+
+	if fmt.Sprint(oldSide) != "[{the  false} {quick true} { fox false}]" {
+		t.Errorf("old side = %v", oldSide)
+	}
+	if fmt.Sprint(newSide) != "[{the  false} {slow red true} { fox false}]" {
+		t.Errorf("new side = %v", newSide)
+	}
+}
@@ -0,0 +1,148 @@
+package textutil
+
+import (
+	"bytes"
+	"encoding/binary"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+// Encoding names a text encoding recognised by DetectEncoding.
+type Encoding string
+
+const (
+	EncodingASCII       Encoding = "ASCII"
+	EncodingUTF8        Encoding = "UTF-8"
+	EncodingUTF16LE     Encoding = "UTF-16LE"
+	EncodingUTF16BE     Encoding = "UTF-16BE"
+	EncodingUTF32LE     Encoding = "UTF-32LE"
+	EncodingUTF32BE     Encoding = "UTF-32BE"
+	EncodingWindows1252 Encoding = "Windows-1252"
+)
+
+// boms lists byte order marks, longest first so UTF-32LE is not mistaken for
+// UTF-16LE.
+var boms = []struct {
+	bom      []byte
+	encoding Encoding
+}{
+	{[]byte{0xFF, 0xFE, 0x00, 0x00}, EncodingUTF32LE},
+	{[]byte{0x00, 0x00, 0xFE, 0xFF}, EncodingUTF32BE},
+	{[]byte{0xEF, 0xBB, 0xBF}, EncodingUTF8},
+	{[]byte{0xFF, 0xFE}, EncodingUTF16LE},
+	{[]byte{0xFE, 0xFF}, EncodingUTF16BE},
+}
+
+// sniffLen is how much of the input DetectEncoding inspects for UTF-16
+// without a BOM.
+const sniffLen = 4096
+
+// DetectEncoding guesses the encoding of data. A byte order mark is
+// authoritative and its length is returned as bomLen. Without one, valid
+// UTF-8 is reported as ASCII or UTF-8, text with NUL bytes in alternating
+// positions as UTF-16, and anything else as Windows-1252, which can decode
+// any byte sequence.
+func DetectEncoding(data []byte) (enc Encoding, bomLen int) {
+	for _, b := range boms {
+		if bytes.HasPrefix(data, b.bom) {
+			return b.encoding, len(b.bom)
+		}
+	}
+
+	if wide := sniffUTF16(data); wide != "" {
+		return wide, 0
+	}
+
+	if utf8.Valid(data) {
+		for _, c := range data {
+			if c >= utf8.RuneSelf {
+				return EncodingUTF8, 0
+			}
+		}
+		return EncodingASCII, 0
+	}
+
+	return EncodingWindows1252, 0
+}
+
+// sniffUTF16 recognises BOM-less UTF-16 text that is mostly Latin script:
+// in that case most code units have a zero high byte.
+func sniffUTF16(data []byte) Encoding {
+	sample := data[:min(len(data), sniffLen)]
+	if len(sample) < 4 || len(sample)%2 != 0 {
+		return ""
+	}
+
+	var evenZeros, oddZeros int
+	for i := 0; i < len(sample); i += 2 {
+		if sample[i] == 0 {
+			evenZeros++
+		}
+		if sample[i+1] == 0 {
+			oddZeros++
+		}
+	}
+
+	units := len(sample) / 2
+	switch {
+	case oddZeros*10 >= units*7 && evenZeros*10 < units:
+		return EncodingUTF16LE
+	case evenZeros*10 >= units*7 && oddZeros*10 < units:
+		return EncodingUTF16BE
+	default:
+		return ""
+	}
+}
+
+// DecodeText converts data to a UTF-8 string using the detected encoding,
+// dropping any byte order mark. Invalid sequences become U+FFFD.
+func DecodeText(data []byte) (text string, enc Encoding, hasBOM bool) {
+	enc, bomLen := DetectEncoding(data)
+	body := data[bomLen:]
+
+	switch enc {
+	case EncodingUTF16LE, EncodingUTF16BE:
+		var order binary.ByteOrder = binary.LittleEndian
+		if enc == EncodingUTF16BE {
+			order = binary.BigEndian
+		}
+		units := make([]uint16, len(body)/2)
+		for i := range units {
+			units[i] = order.Uint16(body[2*i:])
+		}
+		text = string(utf16.Decode(units))
+		if len(body)%2 != 0 {
+			text += string(utf8.RuneError)
+		}
+
+	case EncodingUTF32LE, EncodingUTF32BE:
+		var order binary.ByteOrder = binary.LittleEndian
+		if enc == EncodingUTF32BE {
+			order = binary.BigEndian
+		}
+		runes := make([]rune, 0, len(body)/4)
+		for i := 0; i+4 <= len(body); i += 4 {
+			r := rune(order.Uint32(body[i:]))
+			if !utf8.ValidRune(r) {
+				r = utf8.RuneError
+			}
+			runes = append(runes, r)
+		}
+		text = string(runes)
+		if len(body)%4 != 0 {
+			text += string(utf8.RuneError)
+		}
+
+	case EncodingWindows1252:
+		// The charmap decoder maps every byte, so this cannot fail.
+		decoded, _ := charmap.Windows1252.NewDecoder().Bytes(body)
+		text = string(decoded)
+
+	default:
+		text = string(body)
+	}
+
+	return text, enc, bomLen > 0
+}
@@ -0,0 +1,35 @@
+package textutil
+
+import "testing"
+
+func TestDecodeText(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []byte
+		text     string
+		encoding Encoding
+		bom      bool
+	}{
+		{name: "ascii", input: []byte("hello"), text: "hello", encoding: EncodingASCII},
+		{name: "utf-8", input: []byte("café"), text: "café", encoding: EncodingUTF8},
+		{name: "utf-8 bom", input: []byte("\xEF\xBB\xBFhi"), text: "hi", encoding: EncodingUTF8, bom: true},
+		{name: "utf-16le bom", input: []byte{0xFF, 0xFE, 'h', 0, 'i', 0}, text: "hi", encoding: EncodingUTF16LE, bom: true},
+		{name: "utf-16be bom", input: []byte{0xFE, 0xFF, 0, 'h', 0, 'i'}, text: "hi", encoding: EncodingUTF16BE, bom: true},
+		{name: "utf-16le surrogate pair", input: []byte{0xFF, 0xFE, 0x3D, 0xD8, 0x00, 0xDE}, text: "😀", encoding: EncodingUTF16LE, bom: true},
+		{name: "utf-16le without bom", input: []byte{'a', 0, 'b', 0, 'c', 0, 'd', 0}, text: "abcd", encoding: EncodingUTF16LE},
+		{name: "utf-32le bom", input: []byte{0xFF, 0xFE, 0, 0, 'A', 0, 0, 0}, text: "A", encoding: EncodingUTF32LE, bom: true},
+		{name: "utf-32be bom", input: []byte{0, 0, 0xFE, 0xFF, 0, 0, 0, 'A'}, text: "A", encoding: EncodingUTF32BE, bom: true},
+		{name: "windows-1252", input: []byte("caf\xE9 \x80"), text: "café €", encoding: EncodingWindows1252},
+		{name: "empty", input: nil, text: "", encoding: EncodingASCII},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, enc, bom := DecodeText(tt.input)
+			if text != tt.text || enc != tt.encoding || bom != tt.bom {
+				t.Errorf("DecodeText(%q) = (%q, %s, %v), want (%q, %s, %v)",
+					tt.input, text, enc, bom, tt.text, tt.encoding, tt.bom)
+			}
+		})
+	}
+}
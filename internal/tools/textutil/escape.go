@@ -0,0 +1,386 @@
+package textutil
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// EscapeStyle names a string literal escaping convention.
+type EscapeStyle string
+
+const (
+	EscapeGo      EscapeStyle = "go"
+	EscapeJSON    EscapeStyle = "json"
+	EscapeJS      EscapeStyle = "js"
+	EscapePython  EscapeStyle = "python"
+	EscapeUnicode EscapeStyle = "unicode"
+)
+
+// EscapeStyleOption describes a selectable escape style for the UI.
+type EscapeStyleOption struct {
+	Style EscapeStyle
+	Name  string
+}
+
+// EscapeStyles lists the supported styles in display order.
+var EscapeStyles = []EscapeStyleOption{
+	{EscapeGo, "Go"},
+	{EscapeJSON, "JSON"},
+	{EscapeJS, "JavaScript"},
+	{EscapePython, "Python"},
+	{EscapeUnicode, `\uXXXX only`},
+}
+
+// Escape rewrites s as the body of an ASCII-only string literal in style,
+// without the surrounding quotes. The unicode style only escapes non-ASCII
+// characters, as \uXXXX with surrogate pairs.
+func Escape(s string, style EscapeStyle) (string, error) {
+	var b strings.Builder
+	for i, w := 0, 0; i < len(s); i += w {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		w = size
+
+		if r == utf8.RuneError && size == 1 {
+			// Only Go and Python can represent arbitrary bytes.
+			switch style {
+			case EscapeGo, EscapePython:
+				fmt.Fprintf(&b, `\x%02x`, s[i])
+				continue
+			default:
+				r = utf8.RuneError
+			}
+		}
+
+		switch style {
+		case EscapeGo:
+			b.WriteString(escapeGo(r))
+		case EscapeJSON:
+			b.WriteString(escapeJSON(r))
+		case EscapeJS:
+			b.WriteString(escapeJS(r))
+		case EscapePython:
+			b.WriteString(escapePython(r))
+		case EscapeUnicode:
+			if r < utf8.RuneSelf {
+				b.WriteRune(r)
+			} else {
+				b.WriteString(utf16Escape(r))
+			}
+		default:
+			return "", fmt.Errorf("unsupported escape style %q", style)
+		}
+	}
+	return b.String(), nil
+}
+
+func escapeGo(r rune) string {
+	quoted := strconv.QuoteRuneToASCII(r)
+	inner := quoted[1 : len(quoted)-1]
+	switch inner {
+	case `\'`:
+		return "'"
+	case `"`:
+		return `\"`
+	}
+	return inner
+}
+
+// jsonShort are the two-character escapes shared by JSON and JavaScript.
+var jsonShort = map[rune]string{
+	'"': `\"`, '\\': `\\`, '\b': `\b`, '\f': `\f`, '\n': `\n`, '\r': `\r`, '\t': `\t`,
+}
+
+func escapeJSON(r rune) string {
+	if e, ok := jsonShort[r]; ok {
+		return e
+	}
+	if r < 0x20 || r >= 0x7F {
+		return utf16Escape(r)
+	}
+	return string(r)
+}
+
+func escapeJS(r rune) string {
+	switch r {
+	case '\'':
+		return `\'`
+	case '\v':
+		return `\v`
+	}
+	if r > 0xFFFF {
+		return fmt.Sprintf(`\u{%X}`, r)
+	}
+	return escapeJSON(r)
+}
+
+func escapePython(r rune) string {
+	switch r {
+	case '\\':
+		return `\\`
+	case '\'':
+		return `\'`
+	case '"':
+		return `\"`
+	case '\n':
+		return `\n`
+	case '\r':
+		return `\r`
+	case '\t':
+		return `\t`
+	}
+	switch {
+	case r < 0x20 || (r >= 0x7F && r <= 0xFF):
+		return fmt.Sprintf(`\x%02x`, r)
+	case r > 0xFFFF:
+		return fmt.Sprintf(`\U%08x`, r)
+	case r >= 0x100:
+		return fmt.Sprintf(`\u%04x`, r)
+	default:
+		return string(r)
+	}
+}
+
+// utf16Escape writes r as one \uXXXX escape, or two for a surrogate pair.
+func utf16Escape(r rune) string {
+	if r > 0xFFFF {
+		hi, lo := utf16.EncodeRune(r)
+		return fmt.Sprintf(`\u%04X\u%04X`, hi, lo)
+	}
+	return fmt.Sprintf(`\u%04X`, r)
+}
+
+// UnescapeError reports a malformed escape sequence at a byte offset.
+type UnescapeError struct {
+	Offset  int
+	Message string
+}
+
+func (e *UnescapeError) Error() string {
+	return fmt.Sprintf("offset %d: %s", e.Offset, e.Message)
+}
+
+// Unescape interprets the escape sequences of style in s, which should not
+// include the surrounding quotes. In the unicode style only \uXXXX sequences
+// are decoded and everything else is left as is.
+func Unescape(s string, style EscapeStyle) (string, error) {
+	switch style {
+	case EscapeGo, EscapeJSON, EscapeJS, EscapePython, EscapeUnicode:
+	default:
+		return "", fmt.Errorf("unsupported escape style %q", style)
+	}
+
+	u := unescaper{s: s, style: style}
+	return u.run()
+}
+
+// simpleEscapes are the single-character escapes of each style.
+var simpleEscapes = map[EscapeStyle]map[byte]string{
+	EscapeGo: {
+		'\\': `\`, '"': `"`, '\'': "'", 'a': "\a", 'b': "\b", 'f': "\f",
+		'n': "\n", 'r': "\r", 't': "\t", 'v': "\v",
+	},
+	EscapeJSON: {
+		'\\': `\`, '"': `"`, '/': "/", 'b': "\b", 'f': "\f", 'n': "\n", 'r': "\r", 't': "\t",
+	},
+	EscapeJS: {
+		'\\': `\`, '"': `"`, '\'': "'", '/': "/", 'b': "\b", 'f': "\f",
+		'n': "\n", 'r': "\r", 't': "\t", 'v': "\v",
+	},
+	EscapePython: {
+		'\\': `\`, '"': `"`, '\'': "'", 'a': "\a", 'b': "\b", 'f': "\f",
+		'n': "\n", 'r': "\r", 't': "\t", 'v': "\v",
+	},
+}
+
+type unescaper struct {
+	s     string
+	style EscapeStyle
+	b     strings.Builder
+
+	// pendingHigh holds a high surrogate waiting for its low half.
+	pendingHigh rune
+}
+
+func (u *unescaper) run() (string, error) {
+	for i := 0; i < len(u.s); {
+		if u.s[i] != '\\' || i+1 >= len(u.s) {
+			if u.s[i] == '\\' && u.style != EscapeUnicode {
+				return "", &UnescapeError{Offset: i, Message: "trailing backslash"}
+			}
+			u.flushSurrogate()
+			r, size := utf8.DecodeRuneInString(u.s[i:])
+			u.b.WriteRune(r)
+			i += size
+			continue
+		}
+
+		n, err := u.escape(i)
+		if err != nil {
+			return "", err
+		}
+		i += n
+	}
+	u.flushSurrogate()
+	return u.b.String(), nil
+}
+
+// escape decodes the sequence starting at the backslash at offset i and
+// returns its length.
+func (u *unescaper) escape(i int) (int, error) {
+	c := u.s[i+1]
+
+	if u.style == EscapeUnicode {
+		if c == 'u' {
+			if r, ok := u.hex(i+2, 4); ok {
+				u.writeUnit(r)
+				return 6, nil
+			}
+		}
+		u.flushSurrogate()
+		u.b.WriteByte('\\')
+		return 1, nil
+	}
+
+	if c != 'u' {
+		u.flushSurrogate()
+	}
+
+	if v, ok := simpleEscapes[u.style][c]; ok {
+		u.b.WriteString(v)
+		return 2, nil
+	}
+
+	switch {
+	case c == 'u' && u.style == EscapeJS && i+2 < len(u.s) && u.s[i+2] == '{':
+		end := strings.IndexByte(u.s[i+3:], '}')
+		if end < 1 || end > 6 {
+			return 0, &UnescapeError{Offset: i, Message: `malformed \u{...} escape`}
+		}
+		r, ok := u.hex(i+3, end)
+		if !ok || !utf8.ValidRune(r) {
+			return 0, &UnescapeError{Offset: i, Message: `invalid code point in \u{...} escape`}
+		}
+		u.flushSurrogate()
+		u.b.WriteRune(r)
+		return end + 4, nil
+
+	case c == 'u':
+		r, ok := u.hex(i+2, 4)
+		if !ok {
+			return 0, &UnescapeError{Offset: i, Message: `\u must be followed by 4 hex digits`}
+		}
+		if u.style == EscapeJSON || u.style == EscapeJS {
+			u.writeUnit(r)
+		} else {
+			u.flushSurrogate()
+			if !utf8.ValidRune(r) {
+				return 0, &UnescapeError{Offset: i, Message: "escape is a surrogate half"}
+			}
+			u.b.WriteRune(r)
+		}
+		return 6, nil
+
+	case c == 'U' && (u.style == EscapeGo || u.style == EscapePython):
+		r, ok := u.hex(i+2, 8)
+		if !ok || !utf8.ValidRune(r) {
+			return 0, &UnescapeError{Offset: i, Message: `\U must be followed by 8 hex digits of a valid code point`}
+		}
+		u.b.WriteRune(r)
+		return 10, nil
+
+	case c == 'x' && u.style != EscapeJSON:
+		v, ok := u.hex(i+2, 2)
+		if !ok {
+			return 0, &UnescapeError{Offset: i, Message: `\x must be followed by 2 hex digits`}
+		}
+		if u.style == EscapeGo {
+			// Go \x escapes are raw bytes.
+			u.b.WriteByte(byte(v))
+		} else {
+			u.b.WriteRune(v)
+		}
+		return 4, nil
+
+	case c >= '0' && c <= '7' && (u.style == EscapeGo || u.style == EscapePython):
+		n := 1
+		for n < 3 && i+1+n < len(u.s) && u.s[i+1+n] >= '0' && u.s[i+1+n] <= '7' {
+			n++
+		}
+		if u.style == EscapeGo && n != 3 {
+			return 0, &UnescapeError{Offset: i, Message: "octal escapes need 3 digits in Go"}
+		}
+		v, _ := strconv.ParseUint(u.s[i+1:i+1+n], 8, 32)
+		if u.style == EscapeGo {
+			if v > 0xFF {
+				return 0, &UnescapeError{Offset: i, Message: "octal escape out of range"}
+			}
+			u.b.WriteByte(byte(v))
+		} else {
+			u.b.WriteRune(rune(v))
+		}
+		return n + 1, nil
+
+	case c == '0' && u.style == EscapeJS:
+		u.b.WriteByte(0)
+		return 2, nil
+
+	case c == '\n' && (u.style == EscapeJS || u.style == EscapePython):
+		// Line continuation.
+		return 2, nil
+	}
+
+	switch u.style {
+	case EscapePython:
+		// Python keeps unknown escapes, backslash included.
+		u.b.WriteByte('\\')
+		return 1, nil
+	case EscapeJS:
+		// JavaScript drops the backslash from unknown escapes.
+		return 1, nil
+	}
+
+	r, _ := utf8.DecodeRuneInString(u.s[i+1:])
+	return 0, &UnescapeError{Offset: i, Message: fmt.Sprintf(`unknown escape \%c`, r)}
+}
+
+// hex parses n hex digits starting at offset i.
+func (u *unescaper) hex(i, n int) (rune, bool) {
+	if i+n > len(u.s) {
+		return 0, false
+	}
+	v, err := strconv.ParseUint(u.s[i:i+n], 16, 32)
+	if err != nil {
+		return 0, false
+	}
+	return rune(v), true
+}
+
+// writeUnit writes a UTF-16 code unit, pairing surrogates. An unpaired
+// surrogate becomes U+FFFD.
+func (u *unescaper) writeUnit(r rune) {
+	switch {
+	case utf16.IsSurrogate(r) && r < 0xDC00:
+		u.flushSurrogate()
+		u.pendingHigh = r
+	case utf16.IsSurrogate(r):
+		if u.pendingHigh != 0 {
+			u.b.WriteRune(utf16.DecodeRune(u.pendingHigh, r))
+			u.pendingHigh = 0
+		} else {
+			u.b.WriteRune(utf8.RuneError)
+		}
+	default:
+		u.flushSurrogate()
+		u.b.WriteRune(r)
+	}
+}
+
+func (u *unescaper) flushSurrogate() {
+	if u.pendingHigh != 0 {
+		u.b.WriteRune(utf8.RuneError)
+		u.pendingHigh = 0
+	}
+}
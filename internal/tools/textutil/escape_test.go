@@ -0,0 +1,105 @@
+package textutil
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestEscape(t *testing.T) {
+	input := "Tab\there \"q\" 'a' é 😀\x01\\"
+
+	tests := []struct {
+		style    EscapeStyle
+		expected string
+	}{
+		{EscapeGo, `Tab\there \"q\" 'a' \u00e9 \U0001f600\x01\\`},
+		{EscapeJSON, `Tab\there \"q\" 'a' \u00E9 \uD83D\uDE00\u0001\\`},
+		{EscapeJS, `Tab\there \"q\" \'a\' \u00E9 \u{1F600}\u0001\\`},
+		{EscapePython, `Tab\there \"q\" \'a\' \xe9 \U0001f600\x01\\`},
+		{EscapeUnicode, "Tab\there \"q\" 'a' \\u00E9 \\uD83D\\uDE00\x01\\"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.style), func(t *testing.T) {
+			result, err := Escape(input, tt.style)
+			if err != nil {
+				t.Fatalf("Escape(%s) unexpected error: %v", tt.style, err)
+			}
+			if result != tt.expected {
+				t.Errorf("Escape(%s)\ngot:  %s\nwant: %s", tt.style, result, tt.expected)
+			}
+
+			back, err := Unescape(result, tt.style)
+			if err != nil {
+				t.Fatalf("Unescape(%s) unexpected error: %v", tt.style, err)
+			}
+			if back != input {
+				t.Errorf("Unescape(Escape(%s)) = %q, want %q", tt.style, back, input)
+			}
+		})
+	}
+}
+
+func TestEscapeInvalidUTF8(t *testing.T) {
+	result, err := Escape("a\xffb", EscapeGo)
+	if err != nil || result != `a\xffb` {
+		t.Errorf("Escape(go) = %q, %v, want %q", result, err, `a\xffb`)
+	}
+	result, err = Escape("a\xffb", EscapeJSON)
+	if err != nil || result != `a\uFFFDb` {
+		t.Errorf("Escape(json) = %q, %v, want %q", result, err, `a\uFFFDb`)
+	}
+}
+
+func TestUnescape(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		style       EscapeStyle
+		expected    string
+		expectError bool
+	}{
+		{name: "go octal and bytes", input: `\101\x42\u00e9`, style: EscapeGo, expected: "ABé"},
+		{name: "go unknown escape", input: `\q`, style: EscapeGo, expectError: true},
+		{name: "json slash", input: `a\/b`, style: EscapeJSON, expected: "a/b"},
+		{name: "json surrogate pair", input: `\ud83d\ude00`, style: EscapeJSON, expected: "😀"},
+		{name: "json lone surrogate", input: `\ud83dx`, style: EscapeJSON, expected: "\uFFFDx"},
+		{name: "json no x escapes", input: `\x41`, style: EscapeJSON, expectError: true},
+		{name: "json short unicode", input: `\u12`, style: EscapeJSON, expectError: true},
+		{name: "js code point escape", input: `\u{1F600}\x41\0`, style: EscapeJS, expected: "😀A\x00"},
+		{name: "js unknown escape drops backslash", input: `\q\%`, style: EscapeJS, expected: "q%"},
+		{name: "js line continuation", input: "a\\\nb", style: EscapeJS, expected: "ab"},
+		{name: "python escapes", input: `\x41\u00e9\U0001F600\101\7`, style: EscapePython, expected: "Aé😀A\a"},
+		{name: "python unknown escape kept", input: `\d+`, style: EscapePython, expected: `\d+`},
+		{name: "unicode only decodes \\u", input: `\u00e9\n\t`, style: EscapeUnicode, expected: `é\n\t`},
+		{name: "unicode surrogates", input: `\uD83D\uDE00!`, style: EscapeUnicode, expected: "😀!"},
+		{name: "trailing backslash", input: `abc\`, style: EscapeGo, expectError: true},
+		{name: "unsupported style", input: "x", style: "rust", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Unescape(tt.input, tt.style)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Unescape(%q, %s) expected error but got %q", tt.input, tt.style, result)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unescape(%q, %s) unexpected error: %v", tt.input, tt.style, err)
+			}
+			if result != tt.expected {
+				t.Errorf("Unescape(%q, %s) = %q, want %q", tt.input, tt.style, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestUnescapeErrorOffset(t *testing.T) {
+	_, err := Unescape(`ok \q`, EscapeGo)
+	var unescapeErr *UnescapeError
+	if !errors.As(err, &unescapeErr) || unescapeErr.Offset != 3 {
+		t.Errorf("Unescape error = %v, want *UnescapeError at offset 3", err)
+	}
+}
@@ -0,0 +1,289 @@
+package textutil
+
+import (
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// SortMode selects how SortLines compares lines.
+type SortMode string
+
+const (
+	SortLexical SortMode = "lexical"
+	SortNatural SortMode = "natural"
+	SortNumeric SortMode = "numeric"
+)
+
+// SortOptions controls SortLines. The zero value sorts lexically.
+type SortOptions struct {
+	Mode       SortMode
+	Reverse    bool
+	IgnoreCase bool
+}
+
+// LineCount is a distinct line and the number of times it occurs.
+type LineCount struct {
+	Line  string
+	Count int
+}
+
+// SplitLines splits s into lines, accepting LF, CRLF and CR line breaks. A
+// trailing line break does not produce a final empty line.
+func SplitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	s = strings.ReplaceAll(s, "\r", "\n")
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// SortLines returns a sorted copy of lines. The sort is stable, so lines that
+// compare equal keep their order. In numeric mode lines are ordered by their
+// leading number, and lines without one sort after all numbers.
+func SortLines(lines []string, opts SortOptions) []string {
+	sorted := append([]string(nil), lines...)
+
+	key := func(s string) string {
+		if opts.IgnoreCase {
+			return strings.ToLower(s)
+		}
+		return s
+	}
+
+	var less func(a, b string) bool
+	switch opts.Mode {
+	case SortNatural:
+		less = func(a, b string) bool { return NaturalLess(key(a), key(b)) }
+	case SortNumeric:
+		less = func(a, b string) bool {
+			x, xok := leadingNumber(a)
+			y, yok := leadingNumber(b)
+			switch {
+			case xok && yok && x != y:
+				return x < y
+			case xok != yok:
+				return xok
+			default:
+				return key(a) < key(b)
+			}
+		}
+	default:
+		less = func(a, b string) bool { return key(a) < key(b) }
+	}
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if opts.Reverse {
+			return less(sorted[j], sorted[i])
+		}
+		return less(sorted[i], sorted[j])
+	})
+
+	return sorted
+}
+
+// NaturalLess compares strings so that embedded numbers are ordered by value:
+// "file2" sorts before "file10".
+func NaturalLess(a, b string) bool {
+	for a != "" && b != "" {
+		ca, restA := naturalChunk(a)
+		cb, restB := naturalChunk(b)
+
+		if isDigit(ca[0]) && isDigit(cb[0]) {
+			na, nb := strings.TrimLeft(ca, "0"), strings.TrimLeft(cb, "0")
+			if len(na) != len(nb) {
+				return len(na) < len(nb)
+			}
+			if na != nb {
+				return na < nb
+			}
+			// Equal values: fewer leading zeros first, so the order is total.
+			if len(ca) != len(cb) {
+				return len(ca) < len(cb)
+			}
+		} else if ca != cb {
+			return ca < cb
+		}
+
+		a, b = restA, restB
+	}
+	return len(a) < len(b)
+}
+
+// naturalChunk splits off the leading run of digits or non-digits.
+func naturalChunk(s string) (chunk, rest string) {
+	digits := isDigit(s[0])
+	i := 1
+	for i < len(s) && isDigit(s[i]) == digits {
+		i++
+	}
+	return s[:i], s[i:]
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+// leadingNumber parses the number at the start of s, ignoring leading
+// whitespace, as sort -n does.
+func leadingNumber(s string) (float64, bool) {
+	s = strings.TrimLeftFunc(s, unicode.IsSpace)
+	end := 0
+	for end < len(s) && (isDigit(s[end]) || s[end] == '.' || (end == 0 && (s[end] == '-' || s[end] == '+'))) {
+		end++
+	}
+	for end > 0 {
+		if n, err := strconv.ParseFloat(s[:end], 64); err == nil {
+			return n, true
+		}
+		end--
+	}
+	return 0, false
+}
+
+// DedupeLines removes repeated lines, keeping the first occurrence of each.
+func DedupeLines(lines []string, ignoreCase bool) []string {
+	seen := make(map[string]bool, len(lines))
+	var out []string
+	for _, line := range lines {
+		k := foldKey(line, ignoreCase)
+		if !seen[k] {
+			seen[k] = true
+			out = append(out, line)
+		}
+	}
+	return out
+}
+
+// CountLines counts occurrences of each distinct line, most frequent first.
+// Ties keep the order in which lines first appear.
+func CountLines(lines []string, ignoreCase bool) []LineCount {
+	index := make(map[string]int)
+	var counts []LineCount
+	for _, line := range lines {
+		k := foldKey(line, ignoreCase)
+		if i, ok := index[k]; ok {
+			counts[i].Count++
+			continue
+		}
+		index[k] = len(counts)
+		counts = append(counts, LineCount{Line: line, Count: 1})
+	}
+	sort.SliceStable(counts, func(i, j int) bool {
+		return counts[i].Count > counts[j].Count
+	})
+	return counts
+}
+
+// TrimLines removes leading and trailing whitespace from every line.
+func TrimLines(lines []string) []string {
+	out := make([]string, len(lines))
+	for i, line := range lines {
+		out[i] = strings.TrimSpace(line)
+	}
+	return out
+}
+
+// RemoveBlankLines drops lines that are empty or contain only whitespace.
+func RemoveBlankLines(lines []string) []string {
+	var out []string
+	for _, line := range lines {
+		if strings.TrimSpace(line) != "" {
+			out = append(out, line)
+		}
+	}
+	return out
+}
+
+// ShuffleLines returns lines in a random order determined by seed, so the
+// same seed always gives the same order.
+func ShuffleLines(lines []string, seed int64) []string {
+	out := append([]string(nil), lines...)
+	rng := rand.New(rand.NewSource(seed))
+	rng.Shuffle(len(out), func(i, j int) { out[i], out[j] = out[j], out[i] })
+	return out
+}
+
+// ReverseLines returns lines in reverse order.
+func ReverseLines(lines []string) []string {
+	out := make([]string, len(lines))
+	for i, line := range lines {
+		out[len(lines)-1-i] = line
+	}
+	return out
+}
+
+// WrapLines adds prefix and suffix to every line.
+func WrapLines(lines []string, prefix, suffix string) []string {
+	out := make([]string, len(lines))
+	for i, line := range lines {
+		out[i] = prefix + line + suffix
+	}
+	return out
+}
+
+// UnionLines returns the distinct lines that appear in a or b, in order of
+// first appearance.
+func UnionLines(a, b []string, ignoreCase bool) []string {
+	return DedupeLines(append(append([]string(nil), a...), b...), ignoreCase)
+}
+
+// IntersectLines returns the distinct lines of a that also appear in b.
+func IntersectLines(a, b []string, ignoreCase bool) []string {
+	inB := lineSet(b, ignoreCase)
+	var out []string
+	for _, line := range DedupeLines(a, ignoreCase) {
+		if inB[foldKey(line, ignoreCase)] {
+			out = append(out, line)
+		}
+	}
+	return out
+}
+
+// DifferenceLines returns the distinct lines of a that do not appear in b.
+func DifferenceLines(a, b []string, ignoreCase bool) []string {
+	inB := lineSet(b, ignoreCase)
+	var out []string
+	for _, line := range DedupeLines(a, ignoreCase) {
+		if !inB[foldKey(line, ignoreCase)] {
+			out = append(out, line)
+		}
+	}
+	return out
+}
+
+// SymmetricDifferenceLines returns the distinct lines that appear in exactly
+// one of a and b, those from a first.
+func SymmetricDifferenceLines(a, b []string, ignoreCase bool) []string {
+	return append(DifferenceLines(a, b, ignoreCase), DifferenceLines(b, a, ignoreCase)...)
+}
+
+func lineSet(lines []string, ignoreCase bool) map[string]bool {
+	set := make(map[string]bool, len(lines))
+	for _, line := range lines {
+		set[foldKey(line, ignoreCase)] = true
+	}
+	return set
+}
+
+func foldKey(s string, ignoreCase bool) string {
+	if ignoreCase {
+		return strings.ToLower(s)
+	}
+	return s
+}
+
+// SplitOn splits s on delim into lines, ignoring a single trailing delimiter
+// so "a,b," gives ["a" "b"]. An empty delimiter splits on whitespace.
+func SplitOn(s, delim string) []string {
+	if delim == "" {
+		return strings.Fields(s)
+	}
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, delim), delim)
+}
@@ -0,0 +1,168 @@
+package textutil
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestSplitLines(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{"", nil},
+		{"a", []string{"a"}},
+		{"a\nb\n", []string{"a", "b"}},
+		{"a\r\nb\rc", []string{"a", "b", "c"}},
+		{"a\n\nb", []string{"a", "", "b"}},
+	}
+	for _, tt := range tests {
+		if got := SplitLines(tt.input); fmt.Sprintf("%q", got) != fmt.Sprintf("%q", tt.expected) {
+			t.Errorf("SplitLines(%q) = %q, want %q", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestSortLines(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []string
+		opts     SortOptions
+		expected []string
+	}{
+		{
+			name:     "lexical",
+			input:    []string{"b", "B", "a", "file10", "file2"},
+			expected: []string{"B", "a", "b", "file10", "file2"},
+		},
+		{
+			name:     "lexical ignore case is stable",
+			input:    []string{"b", "B", "a"},
+			opts:     SortOptions{IgnoreCase: true},
+			expected: []string{"a", "b", "B"},
+		},
+		{
+			name:     "natural",
+			input:    []string{"file10.txt", "file2.txt", "file1.txt", "file02.txt"},
+			opts:     SortOptions{Mode: SortNatural},
+			expected: []string{"file1.txt", "file2.txt", "file02.txt", "file10.txt"},
+		},
+		{
+			name:     "numeric",
+			input:    []string{"10 apples", "-3", "2.5", "n/a", "  7"},
+			opts:     SortOptions{Mode: SortNumeric},
+			expected: []string{"-3", "2.5", "  7", "10 apples", "n/a"},
+		},
+		{
+			name:     "numeric reverse",
+			input:    []string{"1", "3", "2"},
+			opts:     SortOptions{Mode: SortNumeric, Reverse: true},
+			expected: []string{"3", "2", "1"},
+		},
+		{
+			name:     "natural reverse",
+			input:    []string{"v1.2", "v1.10", "v1.9"},
+			opts:     SortOptions{Mode: SortNatural, Reverse: true},
+			expected: []string{"v1.10", "v1.9", "v1.2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := SortLines(tt.input, tt.opts)
+			if strings.Join(result, "|") != strings.Join(tt.expected, "|") {
+				t.Errorf("SortLines(%q) = %q, want %q", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDedupeAndCount(t *testing.T) {
+	lines := []string{"b", "a", "B", "b", "c", "a"}
+
+	if got := DedupeLines(lines, false); strings.Join(got, "|") != "b|a|B|c" {
+		t.Errorf("DedupeLines = %q", got)
+	}
+	if got := DedupeLines(lines, true); strings.Join(got, "|") != "b|a|c" {
+		t.Errorf("DedupeLines ignore case = %q", got)
+	}
+	if got := CountLines(lines, true); fmt.Sprint(got) != "[{b 3} {a 2} {c 1}]" {
+		t.Errorf("CountLines = %v", got)
+	}
+}
+
+func TestLineTransforms(t *testing.T) {
+	lines := []string{"  one ", "", "two", "   "}
+
+	if got := TrimLines(lines); strings.Join(got, "|") != "one||two|" {
+		t.Errorf("TrimLines = %q", got)
+	}
+	if got := RemoveBlankLines(lines); strings.Join(got, "|") != "  one |two" {
+		t.Errorf("RemoveBlankLines = %q", got)
+	}
+	if got := ReverseLines([]string{"a", "b", "c"}); strings.Join(got, "|") != "c|b|a" {
+		t.Errorf("ReverseLines = %q", got)
+	}
+	if got := WrapLines([]string{"a", "b"}, "'", "',"); strings.Join(got, "|") != "'a',|'b'," {
+		t.Errorf("WrapLines = %q", got)
+	}
+}
+
+func TestShuffleLines(t *testing.T) {
+	lines := []string{"a", "b", "c", "d", "e", "f"}
+
+	first := ShuffleLines(lines, 42)
+	second := ShuffleLines(lines, 42)
+	if strings.Join(first, "") != strings.Join(second, "") {
+		t.Errorf("same seed gave %q and %q", first, second)
+	}
+	if strings.Join(SortLines(first, SortOptions{}), "") != "abcdef" {
+		t.Errorf("shuffle lost or duplicated lines: %q", first)
+	}
+	if strings.Join(lines, "") != "abcdef" {
+		t.Error("ShuffleLines modified its input")
+	}
+}
+
+func TestSetOperations(t *testing.T) {
+	a := []string{"apple", "banana", "cherry", "apple"}
+	b := []string{"Banana", "cherry", "date"}
+
+	tests := []struct {
+		name     string
+		result   []string
+		expected string
+	}{
+		{"union", UnionLines(a, b, false), "apple|banana|cherry|Banana|date"},
+		{"union ignore case", UnionLines(a, b, true), "apple|banana|cherry|date"},
+		{"intersection", IntersectLines(a, b, false), "cherry"},
+		{"intersection ignore case", IntersectLines(a, b, true), "banana|cherry"},
+		{"difference", DifferenceLines(a, b, true), "apple"},
+		{"symmetric difference", SymmetricDifferenceLines(a, b, true), "apple|date"},
+	}
+	for _, tt := range tests {
+		if got := strings.Join(tt.result, "|"); got != tt.expected {
+			t.Errorf("%s = %q, want %q", tt.name, got, tt.expected)
+		}
+	}
+}
+
+func TestSplitOn(t *testing.T) {
+	tests := []struct {
+		input    string
+		delim    string
+		expected []string
+	}{
+		{"a,b,c", ",", []string{"a", "b", "c"}},
+		{"a,b,", ",", []string{"a", "b"}},
+		{"a, b", ", ", []string{"a", "b"}},
+		{" a  b\tc ", "", []string{"a", "b", "c"}},
+		{"", ",", nil},
+	}
+	for _, tt := range tests {
+		if got := SplitOn(tt.input, tt.delim); fmt.Sprintf("%q", got) != fmt.Sprintf("%q", tt.expected) {
+			t.Errorf("SplitOn(%q, %q) = %q, want %q", tt.input, tt.delim, got, tt.expected)
+		}
+	}
+}
@@ -2,16 +2,218 @@ package textutil
 
 import (
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 )
 
 // nonAlnum matches any run of non-alphanumeric characters.
 var nonAlnum = regexp.MustCompile(`[^a-zA-Z0-9]+`)
 
-// Slugify converts a string into a URL-friendly slug.
+// DefaultSlugSeparator joins words in a slug when SlugOptions.Separator is empty.
+const DefaultSlugSeparator = "-"
+
+// EnglishStopwords is a short list of English words commonly dropped from
+// slugs. Pass it as SlugOptions.Stopwords to enable it.
+var EnglishStopwords = []string{
+	"a", "an", "and", "are", "as", "at", "be", "but", "by", "for", "from",
+	"if", "in", "into", "is", "it", "of", "on", "or", "so", "than", "that",
+	"the", "their", "then", "there", "these", "this", "to", "was", "were",
+	"will", "with",
+}
+
+// SlugOptions controls how a slug is built. The zero value produces the same
+// slugs as Slugify.
+type SlugOptions struct {
+	// Separator joins words. Defaults to DefaultSlugSeparator.
+	Separator string
+
+	// KeepCase disables lowercasing.
+	KeepCase bool
+
+	// MaxLength limits the slug length in bytes, cutting at a word boundary
+	// when possible. Zero means no limit.
+	MaxLength int
+
+	// Stopwords are removed case-insensitively unless every word in the
+	// input is a stopword.
+	Stopwords []string
+
+	// Replacements are applied to the raw input before transliteration, so
+	// "C++" can become "cpp" before the symbols are stripped. Longer keys
+	// win over shorter ones.
+	Replacements map[string]string
+
+	// Reserved slugs are never returned as-is; a numeric suffix is added
+	// instead, as if they were already taken.
+	Reserved []string
+
+	// Language selects the transliteration rule set.
+	Language Language
+}
+
+// Slugify converts a string into a URL-friendly slug using the default
+// transliteration rules.
 func Slugify(s string) string {
-	s = strings.ToLower(s)
-	s = nonAlnum.ReplaceAllString(s, "-")
-	s = strings.Trim(s, "-")
-	return s
+	return SlugifyWithOptions(s, SlugOptions{})
+}
+
+// SlugifyLang converts a string into a URL-friendly slug, transliterating
+// letters and symbols with the rule set for lang before stripping anything
+// that is not ASCII alphanumeric.
+func SlugifyLang(s string, lang Language) string {
+	return SlugifyWithOptions(s, SlugOptions{Language: lang})
+}
+
+// SlugifyWithOptions converts a string into a slug according to opts. A slug
+// that matches a reserved word gets a numeric suffix.
+func SlugifyWithOptions(s string, opts SlugOptions) string {
+	return SlugifyBatch([]string{s}, nil, opts)[0]
+}
+
+// SlugifyBatch slugifies each input and makes the results unique among
+// themselves, the existing slugs and opts.Reserved by appending -2, -3, ...
+// (using the configured separator). Uniqueness is case-insensitive. Inputs
+// that produce an empty slug stay empty. A suffixed slug is trimmed to
+// opts.MaxLength unless the number alone is longer.
+func SlugifyBatch(inputs, existing []string, opts SlugOptions) []string {
+	sep := opts.Separator
+	if sep == "" {
+		sep = DefaultSlugSeparator
+	}
+
+	taken := make(map[string]bool, len(existing)+len(opts.Reserved)+len(inputs))
+	for _, s := range existing {
+		taken[strings.ToLower(s)] = true
+	}
+	for _, s := range opts.Reserved {
+		taken[strings.ToLower(s)] = true
+	}
+
+	replacer := newReplacer(opts.Replacements)
+
+	slugs := make([]string, len(inputs))
+	for i, input := range inputs {
+		words := slugWords(replacer.Replace(input), opts)
+		if len(words) == 0 {
+			continue
+		}
+
+		slug := joinWords(words, sep, opts.MaxLength)
+		for n := 2; taken[strings.ToLower(slug)]; n++ {
+			num := strconv.Itoa(n)
+			suffix := sep + num
+			limit := 0
+			if opts.MaxLength > 0 {
+				limit = opts.MaxLength - len(suffix)
+			}
+			// Trim the base so base and suffix fit together. When there is
+			// no room for even one byte of the base, the number stands alone.
+			if opts.MaxLength > 0 && limit < 1 {
+				slug = num
+			} else {
+				slug = joinWords(words, sep, limit) + suffix
+			}
+		}
+
+		taken[strings.ToLower(slug)] = true
+		slugs[i] = slug
+	}
+
+	return slugs
+}
+
+// slugWords transliterates s and splits it into the ASCII alphanumeric words
+// that make up its slug, with stopwords removed.
+func slugWords(s string, opts SlugOptions) []string {
+	s = Transliterate(s, opts.Language)
+	if !opts.KeepCase {
+		s = strings.ToLower(s)
+	}
+
+	var words []string
+	for _, w := range nonAlnum.Split(s, -1) {
+		if w != "" {
+			words = append(words, w)
+		}
+	}
+
+	if len(opts.Stopwords) == 0 {
+		return words
+	}
+
+	stop := make(map[string]bool, len(opts.Stopwords))
+	for _, w := range opts.Stopwords {
+		stop[strings.ToLower(w)] = true
+	}
+
+	var kept []string
+	for _, w := range words {
+		if !stop[strings.ToLower(w)] {
+			kept = append(kept, w)
+		}
+	}
+
+	// A title made only of stopwords ("The The") keeps its words rather
+	// than producing an empty slug.
+	if len(kept) == 0 {
+		return words
+	}
+
+	return kept
+}
+
+// joinWords joins words with sep, dropping trailing words so the result fits
+// in maxLen bytes. If the first word alone is too long it is cut mid-word.
+// A maxLen of zero means no limit.
+func joinWords(words []string, sep string, maxLen int) string {
+	slug := strings.Join(words, sep)
+	if maxLen <= 0 || len(slug) <= maxLen {
+		return slug
+	}
+
+	var b strings.Builder
+	for i, w := range words {
+		extra := len(w)
+		if i > 0 {
+			extra += len(sep)
+		}
+		if b.Len()+extra > maxLen {
+			break
+		}
+		if i > 0 {
+			b.WriteString(sep)
+		}
+		b.WriteString(w)
+	}
+
+	if b.Len() == 0 {
+		return words[0][:maxLen]
+	}
+
+	return b.String()
+}
+
+// newReplacer builds a strings.Replacer that prefers longer keys, since
+// strings.Replacer resolves overlapping keys by argument order.
+func newReplacer(replacements map[string]string) *strings.Replacer {
+	keys := make([]string, 0, len(replacements))
+	for k := range replacements {
+		if k != "" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if len(keys[i]) != len(keys[j]) {
+			return len(keys[i]) > len(keys[j])
+		}
+		return keys[i] < keys[j]
+	})
+
+	pairs := make([]string, 0, 2*len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, k, replacements[k])
+	}
+
+	return strings.NewReplacer(pairs...)
 }
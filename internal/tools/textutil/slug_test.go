@@ -1,6 +1,9 @@
 package textutil
 
-import "testing"
+import (
+	"strings"
+	"testing"
+)
 
 func TestSlugify(t *testing.T) {
 	tests := []struct {
@@ -61,7 +64,7 @@ func TestSlugify(t *testing.T) {
 		{
 			name:     "only special characters",
 			input:    "!@#$%^&*()",
-			expected: "",
+			expected: "at-dollar-percent-and",
 		},
 		{
 			name:     "only spaces",
@@ -83,7 +86,32 @@ func TestSlugify(t *testing.T) {
 		{
 			name:     "unicode characters",
 			input:    "Café au lait",
-			expected: "caf-au-lait",
+			expected: "cafe-au-lait",
+		},
+		{
+			name:     "french diacritics",
+			input:    "Crème Brûlée",
+			expected: "creme-brulee",
+		},
+		{
+			name:     "cyrillic",
+			input:    "Привет мир",
+			expected: "privet-mir",
+		},
+		{
+			name:     "greek with accents",
+			input:    "Καλημέρα κόσμε",
+			expected: "kalimera-kosme",
+		},
+		{
+			name:     "german without language rules",
+			input:    "Über Straße",
+			expected: "uber-strasse",
+		},
+		{
+			name:     "symbols",
+			input:    "Salt & Pepper @ 100%",
+			expected: "salt-and-pepper-at-100-percent",
 		},
 		{
 			name:     "emoji",
@@ -128,3 +156,180 @@ func TestSlugify(t *testing.T) {
 		})
 	}
 }
+
+func TestSlugifyLang(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		lang     Language
+		expected string
+	}{
+		{name: "german umlauts", input: "Über Straße", lang: "de", expected: "ueber-strasse"},
+		{name: "german ampersand", input: "Müller & Söhne", lang: "de", expected: "mueller-und-soehne"},
+		{name: "french ampersand", input: "Pain & Fromage", lang: "fr", expected: "pain-et-fromage"},
+		{name: "danish", input: "Ærø Å", lang: "da", expected: "aeroe-aa"},
+		{name: "ukrainian", input: "Гарний їжак", lang: "uk", expected: "harnyy-yizhak"},
+		{name: "russian", input: "Щука и ёж", lang: "ru", expected: "shchuka-i-yozh"},
+		{name: "bulgarian", input: "Щъркел", lang: "bg", expected: "shtarkel"},
+		{name: "unknown language uses defaults", input: "Crème", lang: "xx", expected: "creme"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := SlugifyLang(tt.input, tt.lang)
+			if result != tt.expected {
+				t.Errorf("SlugifyLang(%q, %q) = %q, want %q", tt.input, tt.lang, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSlugifyWithOptions(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		opts     SlugOptions
+		expected string
+	}{
+		{
+			name:     "zero options match Slugify",
+			input:    "Crème Brûlée & Co.",
+			expected: "creme-brulee-and-co",
+		},
+		{
+			name:     "underscore separator",
+			input:    "Hello World",
+			opts:     SlugOptions{Separator: "_"},
+			expected: "hello_world",
+		},
+		{
+			name:     "keep case",
+			input:    "Hello World",
+			opts:     SlugOptions{KeepCase: true},
+			expected: "Hello-World",
+		},
+		{
+			name:     "max length cuts at word boundary",
+			input:    "The quick brown fox jumps",
+			opts:     SlugOptions{MaxLength: 15},
+			expected: "the-quick-brown",
+		},
+		{
+			name:     "max length drops partial word",
+			input:    "The quick brown fox jumps",
+			opts:     SlugOptions{MaxLength: 14},
+			expected: "the-quick",
+		},
+		{
+			name:     "max length cuts a single long word",
+			input:    "Supercalifragilistic",
+			opts:     SlugOptions{MaxLength: 5},
+			expected: "super",
+		},
+		{
+			name:     "stopwords removed",
+			input:    "The Art of War and Peace",
+			opts:     SlugOptions{Stopwords: EnglishStopwords},
+			expected: "art-war-peace",
+		},
+		{
+			name:     "only stopwords kept",
+			input:    "To Be or Not",
+			opts:     SlugOptions{Stopwords: []string{"to", "be", "or", "not"}},
+			expected: "to-be-or-not",
+		},
+		{
+			name:     "replacements before transliteration",
+			input:    "C++ and C# tips",
+			opts:     SlugOptions{Replacements: map[string]string{"C++": "cpp", "C#": "csharp", "C": "c-lang"}},
+			expected: "cpp-and-csharp-tips",
+		},
+		{
+			name:     "reserved word gets suffix",
+			input:    "Admin",
+			opts:     SlugOptions{Reserved: []string{"admin", "new"}},
+			expected: "admin-2",
+		},
+		{
+			name:     "cms settings",
+			input:    "The Ultimate Guide to Writing Clean, Maintainable Go Code in 2025 and Beyond",
+			opts:     SlugOptions{Separator: "_", MaxLength: 60, Stopwords: EnglishStopwords},
+			expected: "ultimate_guide_writing_clean_maintainable_go_code_2025",
+		},
+		{
+			name:     "language option",
+			input:    "Müller & Söhne",
+			opts:     SlugOptions{Language: "de"},
+			expected: "mueller-und-soehne",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := SlugifyWithOptions(tt.input, tt.opts)
+			if result != tt.expected {
+				t.Errorf("SlugifyWithOptions(%q) = %q, want %q", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSlugifyBatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		inputs   []string
+		existing []string
+		opts     SlugOptions
+		expected []string
+	}{
+		{
+			name:     "duplicates get numeric suffixes",
+			inputs:   []string{"Hello World", "hello world!", "Hello, World"},
+			expected: []string{"hello-world", "hello-world-2", "hello-world-3"},
+		},
+		{
+			name:     "existing slugs are taken",
+			inputs:   []string{"About", "Contact"},
+			existing: []string{"about", "about-2"},
+			expected: []string{"about-3", "contact"},
+		},
+		{
+			name:     "suffix uses separator",
+			inputs:   []string{"a b", "a b"},
+			opts:     SlugOptions{Separator: "_"},
+			expected: []string{"a_b", "a_b_2"},
+		},
+		{
+			name:     "suffix respects max length",
+			inputs:   []string{"alpha beta", "alpha beta"},
+			opts:     SlugOptions{MaxLength: 10},
+			expected: []string{"alpha-beta", "alpha-2"},
+		},
+		{
+			name:     "suffix trims base to one byte",
+			inputs:   []string{"abc", "abc"},
+			opts:     SlugOptions{MaxLength: 3},
+			expected: []string{"abc", "a-2"},
+		},
+		{
+			name:     "suffix longer than max length",
+			inputs:   []string{"ab", "ab", "ab"},
+			opts:     SlugOptions{MaxLength: 2},
+			expected: []string{"ab", "2", "3"},
+		},
+		{
+			name:     "empty slugs are not deduplicated",
+			inputs:   []string{"!!!", "???"},
+			expected: []string{"", ""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := SlugifyBatch(tt.inputs, tt.existing, tt.opts)
+			if strings.Join(result, "|") != strings.Join(tt.expected, "|") {
+				t.Errorf("SlugifyBatch(%q) = %q, want %q", tt.inputs, result, tt.expected)
+			}
+		})
+	}
+}
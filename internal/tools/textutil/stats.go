@@ -0,0 +1,411 @@
+package textutil
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+	"golang.org/x/text/unicode/runenames"
+)
+
+// WordsPerMinute is the average silent reading speed used for ReadingTime.
+const WordsPerMinute = 238
+
+// DefaultTopWords is the number of frequent words reported when
+// StatsOptions.TopWords is zero.
+const DefaultTopWords = 10
+
+// maxFindingsPerRune caps the positions recorded for each flagged character.
+const maxFindingsPerRune = 20
+
+// StatsOptions controls Analyze.
+type StatsOptions struct {
+	// TopWords is the number of most frequent words to report.
+	TopWords int
+
+	// SkipStopwords leaves EnglishStopwords out of the frequent words.
+	SkipStopwords bool
+}
+
+// WordCount is a word and how often it occurs, compared case-insensitively.
+type WordCount struct {
+	Word  string
+	Count int
+}
+
+// Position is a one-based line and column (in runes) within the text.
+type Position struct {
+	Line   int
+	Column int
+}
+
+// CharFinding reports a suspicious character: where it occurs and, for
+// homoglyphs, the ASCII character it imitates.
+type CharFinding struct {
+	Rune      rune
+	CodePoint string
+	Name      string
+	LooksLike string
+	Count     int
+	Positions []Position
+}
+
+// LineEndings counts each kind of line break. Style is "LF", "CRLF", "CR",
+// "mixed" or "none".
+type LineEndings struct {
+	LF    int
+	CRLF  int
+	CR    int
+	Style string
+}
+
+// TextStats is the result of Analyze.
+type TextStats struct {
+	Encoding    Encoding
+	BOM         bool
+	Bytes       int
+	Runes       int
+	Characters  int
+	Letters     int
+	Digits      int
+	Spaces      int
+	Words       int
+	UniqueWords int
+	Lines       int
+	Sentences   int
+	Paragraphs  int
+	ReadingTime time.Duration
+	TopWords    []WordCount
+	LineEndings LineEndings
+	Invisible   []CharFinding
+	Homoglyphs  []CharFinding
+}
+
+// Analyze decodes data and reports counts, frequent words, the line ending
+// style, and invisible or look-alike characters. Bytes is the size of the
+// raw input; every other count is taken from the decoded text.
+func Analyze(data []byte, opts StatsOptions) TextStats {
+	text, enc, bom := DecodeText(data)
+
+	stats := TextStats{
+		Encoding: enc,
+		BOM:      bom,
+		Bytes:    len(data),
+		Runes:    utf8.RuneCountInString(text),
+	}
+
+	for _, r := range text {
+		switch {
+		case unicode.IsLetter(r):
+			stats.Letters++
+		case unicode.IsDigit(r):
+			stats.Digits++
+		case unicode.IsSpace(r):
+			stats.Spaces++
+		}
+		if !isExtender(r) {
+			stats.Characters++
+		}
+	}
+
+	stats.LineEndings = countLineEndings(text)
+	stats.Lines = countLines(text)
+	stats.Paragraphs = countParagraphs(text)
+
+	words := extractWords(text)
+	stats.Words = len(words)
+	stats.Sentences = countSentences(text, len(words))
+	stats.ReadingTime = (time.Duration(len(words)) * time.Minute / WordsPerMinute).Round(time.Second)
+	stats.TopWords, stats.UniqueWords = topWords(words, opts)
+
+	stats.Invisible, stats.Homoglyphs = findSuspicious(text)
+
+	return stats
+}
+
+// isExtender reports whether r attaches to the preceding character rather
+// than being seen as one of its own: combining marks, variation selectors
+// and the zero-width joiner.
+func isExtender(r rune) bool {
+	return unicode.In(r, unicode.Mn, unicode.Me) ||
+		unicode.Is(unicode.Variation_Selector, r) ||
+		r == '\u200D'
+}
+
+func countLineEndings(text string) LineEndings {
+	var le LineEndings
+	for i := 0; i < len(text); i++ {
+		switch text[i] {
+		case '\r':
+			if i+1 < len(text) && text[i+1] == '\n' {
+				le.CRLF++
+				i++
+			} else {
+				le.CR++
+			}
+		case '\n':
+			le.LF++
+		}
+	}
+
+	kinds := 0
+	for _, n := range []int{le.LF, le.CRLF, le.CR} {
+		if n > 0 {
+			kinds++
+		}
+	}
+	switch {
+	case kinds == 0:
+		le.Style = "none"
+	case kinds > 1:
+		le.Style = "mixed"
+	case le.LF > 0:
+		le.Style = "LF"
+	case le.CRLF > 0:
+		le.Style = "CRLF"
+	default:
+		le.Style = "CR"
+	}
+
+	return le
+}
+
+// countLines counts lines the way an editor shows them: a final line without
+// a line break still counts, but a trailing line break does not start one.
+func countLines(text string) int {
+	if text == "" {
+		return 0
+	}
+	normalized := strings.ReplaceAll(strings.ReplaceAll(text, "\r\n", "\n"), "\r", "\n")
+	n := strings.Count(normalized, "\n")
+	if !strings.HasSuffix(normalized, "\n") {
+		n++
+	}
+	return n
+}
+
+// countParagraphs counts blocks of non-blank lines.
+func countParagraphs(text string) int {
+	normalized := strings.ReplaceAll(strings.ReplaceAll(text, "\r\n", "\n"), "\r", "\n")
+	n := 0
+	inParagraph := false
+	for _, line := range strings.Split(normalized, "\n") {
+		blank := strings.TrimSpace(line) == ""
+		if !blank && !inParagraph {
+			n++
+		}
+		inParagraph = !blank
+	}
+	return n
+}
+
+// extractWords returns runs of letters and digits. Apostrophes and hyphens
+// between letters ("don't", "well-known") stay part of the word.
+func extractWords(text string) []string {
+	var words []string
+	runes := []rune(text)
+	start := -1
+	for i, r := range runes {
+		inWord := unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.In(r, unicode.Mn, unicode.Mc)
+		if !inWord && start >= 0 && (r == '\'' || r == '’' || r == '-') &&
+			i+1 < len(runes) && unicode.IsLetter(runes[i+1]) {
+			inWord = true
+		}
+
+		switch {
+		case inWord && start < 0:
+			start = i
+		case !inWord && start >= 0:
+			words = append(words, string(runes[start:i]))
+			start = -1
+		}
+	}
+	if start >= 0 {
+		words = append(words, string(runes[start:]))
+	}
+	return words
+}
+
+// countSentences counts runs of sentence-ending punctuation that follow some
+// text, plus a final sentence that has no terminator.
+func countSentences(text string, words int) int {
+	if words == 0 {
+		return 0
+	}
+
+	n := 0
+	pending := false
+	for _, r := range text {
+		switch {
+		case strings.ContainsRune(".!?…。！？", r):
+			if pending {
+				n++
+				pending = false
+			}
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			pending = true
+		}
+	}
+	if pending {
+		n++
+	}
+	return n
+}
+
+// topWords ranks words by frequency, breaking ties alphabetically, and also
+// returns the number of distinct words.
+func topWords(words []string, opts StatsOptions) ([]WordCount, int) {
+	limit := opts.TopWords
+	if limit == 0 {
+		limit = DefaultTopWords
+	}
+
+	skip := make(map[string]bool)
+	if opts.SkipStopwords {
+		for _, w := range EnglishStopwords {
+			skip[w] = true
+		}
+	}
+
+	counts := make(map[string]int)
+	for _, w := range words {
+		counts[strings.ToLower(w)]++
+	}
+
+	var ranked []WordCount
+	for w, c := range counts {
+		if !skip[w] {
+			ranked = append(ranked, WordCount{Word: w, Count: c})
+		}
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].Count != ranked[j].Count {
+			return ranked[i].Count > ranked[j].Count
+		}
+		return ranked[i].Word < ranked[j].Word
+	})
+
+	if len(ranked) > limit {
+		ranked = ranked[:limit]
+	}
+	return ranked, len(counts)
+}
+
+// confusables maps Cyrillic and Greek letters to the Latin letters they are
+// visually indistinguishable from.
+var confusables = map[rune]string{
+	'а': "a", 'е': "e", 'о': "o", 'р': "p", 'с': "c", 'у': "y", 'х': "x",
+	'і': "i", 'ј': "j", 'ѕ': "s", 'ԁ': "d", 'ԛ': "q", 'ԝ': "w", 'һ': "h",
+	'А': "A", 'В': "B", 'Е': "E", 'К': "K", 'М': "M", 'Н': "H", 'О': "O",
+	'Р': "P", 'С': "C", 'Т': "T", 'Х': "X", 'І': "I", 'Ј': "J", 'Ѕ': "S",
+	'Α': "A", 'Β': "B", 'Ε': "E", 'Ζ': "Z", 'Η': "H", 'Ι': "I", 'Κ': "K",
+	'Μ': "M", 'Ν': "N", 'Ο': "O", 'Ρ': "P", 'Τ': "T", 'Υ': "Y", 'Χ': "X",
+	'ο': "o", 'ν': "v", 'ι': "i",
+}
+
+// isInvisible reports whether r renders as nothing or as an ordinary-looking
+// space: format characters, control characters other than tab and line
+// breaks, and spaces other than U+0020.
+func isInvisible(r rune) bool {
+	switch r {
+	case '\t', '\n', '\r', ' ':
+		return false
+	case '\u115F', '\u1160', '\u3164', '\uFFA0', '\u2800':
+		// Hangul fillers and the blank Braille pattern look like spaces.
+		return true
+	}
+	return unicode.In(r, unicode.Cf, unicode.Cc, unicode.Zs, unicode.Zl, unicode.Zp)
+}
+
+// lookAlike returns the ASCII text that r imitates, or "". Mixed-script
+// confusables only count inside words that also contain Latin letters, so
+// ordinary Russian or Greek text is not flagged; compatibility forms such as
+// fullwidth or mathematical letters are always flagged.
+func lookAlike(r rune, latinWord bool) string {
+	if latin, ok := confusables[r]; ok {
+		if latinWord {
+			return latin
+		}
+		return ""
+	}
+	if r < utf8.RuneSelf || !unicode.In(r, unicode.L, unicode.N) {
+		return ""
+	}
+	folded := norm.NFKC.String(string(r))
+	if len(folded) == 1 && folded[0] < utf8.RuneSelf && folded != string(r) {
+		return folded
+	}
+	return ""
+}
+
+// findSuspicious locates invisible characters and homoglyphs, grouped by
+// character in order of first appearance.
+func findSuspicious(text string) (invisible, homoglyphs []CharFinding) {
+	invisibleIdx := make(map[rune]int)
+	homoglyphIdx := make(map[rune]int)
+
+	record := func(list []CharFinding, idx map[rune]int, r rune, looks string, pos Position) []CharFinding {
+		i, ok := idx[r]
+		if !ok {
+			name := runenames.Name(r)
+			if name == "" || strings.HasPrefix(name, "<") {
+				name = "(unnamed)"
+			}
+			idx[r] = len(list)
+			list = append(list, CharFinding{
+				Rune:      r,
+				CodePoint: fmt.Sprintf("U+%04X", r),
+				Name:      name,
+				LooksLike: looks,
+			})
+			i = len(list) - 1
+		}
+		list[i].Count++
+		if len(list[i].Positions) < maxFindingsPerRune {
+			list[i].Positions = append(list[i].Positions, pos)
+		}
+		return list
+	}
+
+	line, col := 1, 0
+	runes := []rune(text)
+	latinWord := false
+	for i, r := range runes {
+		col++
+
+		// Decide once per word whether it contains Latin letters.
+		if unicode.IsLetter(r) && (i == 0 || !unicode.IsLetter(runes[i-1])) {
+			latinWord = false
+			for _, w := range runes[i:] {
+				if !unicode.IsLetter(w) {
+					break
+				}
+				if unicode.Is(unicode.Latin, w) {
+					latinWord = true
+					break
+				}
+			}
+		}
+
+		pos := Position{Line: line, Column: col}
+		switch {
+		case isInvisible(r):
+			invisible = record(invisible, invisibleIdx, r, "", pos)
+		default:
+			if looks := lookAlike(r, latinWord); looks != "" {
+				homoglyphs = record(homoglyphs, homoglyphIdx, r, looks, pos)
+			}
+		}
+
+		if r == '\n' || (r == '\r' && (i+1 >= len(runes) || runes[i+1] != '\n')) {
+			line++
+			col = 0
+		}
+	}
+
+	return invisible, homoglyphs
+}
@@ -0,0 +1,134 @@
+package textutil
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestAnalyzeCounts(t *testing.T) {
+	text := "Hello, world! Don't panic.\n\nIt's a well-known fact… Really?\nYes"
+	stats := Analyze([]byte(text), StatsOptions{})
+
+	checks := []struct {
+		name     string
+		got      int
+		expected int
+	}{
+		{"bytes", stats.Bytes, len(text)},
+		{"runes", stats.Runes, 63},
+		{"words", stats.Words, 10},
+		{"unique words", stats.UniqueWords, 10},
+		{"lines", stats.Lines, 4},
+		{"sentences", stats.Sentences, 5},
+		{"paragraphs", stats.Paragraphs, 2},
+		{"digits", stats.Digits, 0},
+	}
+	for _, c := range checks {
+		if c.got != c.expected {
+			t.Errorf("%s = %d, want %d", c.name, c.got, c.expected)
+		}
+	}
+
+	if stats.Encoding != EncodingUTF8 || stats.BOM {
+		t.Errorf("encoding = %s (BOM %v), want UTF-8 without BOM", stats.Encoding, stats.BOM)
+	}
+	if stats.LineEndings.Style != "LF" || stats.LineEndings.LF != 3 {
+		t.Errorf("line endings = %+v, want 3 LF", stats.LineEndings)
+	}
+}
+
+func TestAnalyzeCharacters(t *testing.T) {
+	// "e" + combining acute, and a family emoji joined with ZWJ.
+	stats := Analyze([]byte("e\u0301 👨\u200d👩"), StatsOptions{})
+	if stats.Runes != 6 {
+		t.Errorf("runes = %d, want 6", stats.Runes)
+	}
+	if stats.Characters != 4 {
+		t.Errorf("characters = %d, want 4", stats.Characters)
+	}
+}
+
+func TestAnalyzeReadingTime(t *testing.T) {
+	text := ""
+	for i := 0; i < 476; i++ {
+		text += "word "
+	}
+	stats := Analyze([]byte(text), StatsOptions{})
+	if stats.ReadingTime != 2*time.Minute {
+		t.Errorf("reading time = %v, want 2m0s", stats.ReadingTime)
+	}
+}
+
+func TestAnalyzeTopWords(t *testing.T) {
+	text := "The cat and the hat. The cat sat."
+
+	stats := Analyze([]byte(text), StatsOptions{TopWords: 2})
+	if fmt.Sprint(stats.TopWords) != "[{the 3} {cat 2}]" {
+		t.Errorf("top words = %v", stats.TopWords)
+	}
+
+	stats = Analyze([]byte(text), StatsOptions{TopWords: 3, SkipStopwords: true})
+	if fmt.Sprint(stats.TopWords) != "[{cat 2} {hat 1} {sat 1}]" {
+		t.Errorf("top words without stopwords = %v", stats.TopWords)
+	}
+}
+
+func TestAnalyzeLineEndings(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"a\nb\n", "LF"},
+		{"a\r\nb\r\n", "CRLF"},
+		{"a\rb", "CR"},
+		{"a\r\nb\n", "mixed"},
+		{"ab", "none"},
+	}
+	for _, tt := range tests {
+		if got := Analyze([]byte(tt.input), StatsOptions{}).LineEndings.Style; got != tt.expected {
+			t.Errorf("line ending style of %q = %s, want %s", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestAnalyzeInvisible(t *testing.T) {
+	stats := Analyze([]byte("pass\u200bword\nnon\u00a0breaking \u200b\tok"), StatsOptions{})
+
+	var got []string
+	for _, f := range stats.Invisible {
+		got = append(got, fmt.Sprintf("%s %s x%d %v", f.CodePoint, f.Name, f.Count, f.Positions))
+	}
+	expected := []string{
+		"U+200B ZERO WIDTH SPACE x2 [{1 5} {2 14}]",
+		"U+00A0 NO-BREAK SPACE x1 [{2 4}]",
+	}
+	if fmt.Sprint(got) != fmt.Sprint(expected) {
+		t.Errorf("invisible =\n%q\nwant\n%q", got, expected)
+	}
+}
+
+func TestAnalyzeHomoglyphs(t *testing.T) {
+	// Cyrillic "а" in "pаypal", fullwidth "Ａ", and an all-Cyrillic word that
+	// must not be flagged.
+	stats := Analyze([]byte("pаypal Ａpple привет"), StatsOptions{})
+
+	var got []string
+	for _, f := range stats.Homoglyphs {
+		got = append(got, fmt.Sprintf("%s looks like %s at %v", f.CodePoint, f.LooksLike, f.Positions))
+	}
+	expected := []string{
+		"U+0430 looks like a at [{1 2}]",
+		"U+FF21 looks like A at [{1 8}]",
+	}
+	if fmt.Sprint(got) != fmt.Sprint(expected) {
+		t.Errorf("homoglyphs =\n%q\nwant\n%q", got, expected)
+	}
+}
+
+func TestAnalyzeUTF16(t *testing.T) {
+	stats := Analyze([]byte{0xFF, 0xFE, 'h', 0, 'i', 0}, StatsOptions{})
+	if stats.Encoding != EncodingUTF16LE || !stats.BOM || stats.Runes != 2 || stats.Bytes != 6 {
+		t.Errorf("stats = %+v, want UTF-16LE with BOM, 2 runes, 6 bytes", stats)
+	}
+}
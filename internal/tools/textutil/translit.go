@@ -0,0 +1,138 @@
+package textutil
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Language is a transliteration rule set, named by its ISO 639-1 code. The
+// empty Language applies only the default rules.
+type Language string
+
+// LanguageOption describes a selectable rule set for the UI.
+type LanguageOption struct {
+	Code Language
+	Name string
+}
+
+// Languages lists the supported rule sets in display order.
+var Languages = []LanguageOption{
+	{"", "Default"},
+	{"de", "German"},
+	{"fr", "French"},
+	{"es", "Spanish"},
+	{"da", "Danish"},
+	{"no", "Norwegian"},
+	{"sv", "Swedish"},
+	{"tr", "Turkish"},
+	{"ru", "Russian"},
+	{"uk", "Ukrainian"},
+	{"bg", "Bulgarian"},
+	{"el", "Greek"},
+}
+
+// symbolRules spell out common symbols in English.
+var symbolRules = map[rune]string{
+	'&': "and", '@': "at", '%': "percent", '+': "plus",
+	'€': "euro", '£': "pound", '¥': "yen", '$': "dollar",
+	'©': "c", '®': "r", '™': "tm", '°': "deg",
+}
+
+// defaultRules transliterate letters that Unicode decomposition cannot reduce
+// to ASCII: Cyrillic, Greek, and Latin letters without a decomposition.
+// Keys are lowercase; case is restored by Transliterate.
+var defaultRules = map[rune]string{
+	// Latin
+	'ß': "ss", 'æ': "ae", 'œ': "oe", 'ø': "o", 'đ': "d", 'ð': "d",
+	'þ': "th", 'ł': "l", 'ı': "i", 'ħ': "h", 'ŋ': "ng",
+
+	// Cyrillic (Russian, with common Ukrainian letters)
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "yo",
+	'ж': "zh", 'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m",
+	'н': "n", 'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u",
+	'ф': "f", 'х': "kh", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "",
+	'ы': "y", 'ь': "", 'э': "e", 'ю': "yu", 'я': "ya",
+	'є': "ye", 'і': "i", 'ї': "yi", 'ґ': "g",
+
+	// Greek
+	'α': "a", 'β': "v", 'γ': "g", 'δ': "d", 'ε': "e", 'ζ': "z", 'η': "i",
+	'θ': "th", 'ι': "i", 'κ': "k", 'λ': "l", 'μ': "m", 'ν': "n", 'ξ': "x",
+	'ο': "o", 'π': "p", 'ρ': "r", 'σ': "s", 'ς': "s", 'τ': "t", 'υ': "y",
+	'φ': "f", 'χ': "ch", 'ψ': "ps", 'ω': "o",
+}
+
+// languageRules override the defaults for a specific language.
+var languageRules = map[Language]map[rune]string{
+	"de": {'ä': "ae", 'ö': "oe", 'ü': "ue", 'ß': "ss", '&': "und"},
+	"fr": {'&': "et"},
+	"es": {'&': "y"},
+	"da": {'æ': "ae", 'ø': "oe", 'å': "aa", '&': "og"},
+	"no": {'æ': "ae", 'ø': "oe", 'å': "aa", '&': "og"},
+	"sv": {'&': "och"},
+	"tr": {'&': "ve"},
+	"ru": {},
+	"uk": {'г': "h", 'и': "y", 'і': "i", 'ї': "yi", 'є': "ye", 'ґ': "g", '&': "i"},
+	"bg": {'щ': "sht", 'ъ': "a", 'ю': "yu", 'я': "ya", '&': "i"},
+	"el": {'&': "kai"},
+}
+
+// Transliterate rewrites s using only ASCII where possible: NFC composition
+// and language rules first, then the default rules and symbols, then NFKD
+// decomposition with combining marks removed. Characters with no mapping, such as CJK or emoji,
+// are kept unchanged so the caller can decide what to do with them.
+func Transliterate(s string, lang Language) string {
+	rules := languageRules[lang]
+
+	// Compose first so a decomposed "a" + U+0308 still matches the German
+	// rule for "ä" instead of losing its mark below.
+	mapped := applyRules(norm.NFC.String(s), rules)
+
+	// Decompose ("é" -> "e" + U+0301) and drop the combining marks. Letters
+	// that only reach a mapped form after decomposition, like Greek "ά",
+	// are handled by the second applyRules pass.
+	var b strings.Builder
+	for _, r := range norm.NFKD.String(mapped) {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		b.WriteRune(r)
+	}
+
+	return applyRules(b.String(), rules)
+}
+
+// applyRules replaces every rune that has a rule in lang, the defaults or the
+// symbol table, preserving the case of letters.
+func applyRules(s string, lang map[rune]string) string {
+	var b strings.Builder
+	for _, r := range s {
+		lower := unicode.ToLower(r)
+
+		repl, ok := lang[lower]
+		if !ok {
+			repl, ok = defaultRules[lower]
+		}
+		if !ok {
+			repl, ok = symbolRules[r]
+		}
+		if !ok {
+			b.WriteRune(r)
+			continue
+		}
+
+		if !unicode.IsLetter(r) {
+			// Surround spelled-out symbols with spaces so "R&D" becomes
+			// "R and D" rather than "RandD".
+			repl = " " + repl + " "
+		}
+
+		if unicode.IsUpper(r) && repl != "" {
+			repl = strings.ToUpper(repl[:1]) + repl[1:]
+		}
+		b.WriteString(repl)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,33 @@
+package textutil
+
+import "testing"
+
+func TestTransliterate(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		lang     Language
+		expected string
+	}{
+		{name: "ascii unchanged", input: "Hello, World!", expected: "Hello, World!"},
+		{name: "diacritics stripped", input: "Crème Brûlée", expected: "Creme Brulee"},
+		{name: "case preserved", input: "Жук ЖУК", expected: "Zhuk ZhUK"},
+		{name: "short i before decomposition", input: "Мой", expected: "Moy"},
+		{name: "greek tonos", input: "Ά έ ώ", expected: "A e o"},
+		{name: "latin without decomposition", input: "Łódź Ørsted æ œ", expected: "Lodz Orsted ae oe"},
+		{name: "compatibility forms", input: "ﬁ ①", expected: "fi 1"},
+		{name: "symbol spacing", input: "R&D", expected: "R and D"},
+		{name: "language override", input: "R&D", lang: "de", expected: "R und D"},
+		{name: "decomposed input uses language rules", input: "a\u0308 U\u0308ber", lang: "de", expected: "ae Ueber"},
+		{name: "unmapped kept", input: "你好 👋", expected: "你好 👋"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Transliterate(tt.input, tt.lang)
+			if result != tt.expected {
+				t.Errorf("Transliterate(%q, %q) = %q, want %q", tt.input, tt.lang, result, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package textutil
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+	"golang.org/x/text/unicode/runenames"
+)
+
+// MaxInspectRunes caps the number of runes Inspect describes.
+const MaxInspectRunes = 5000
+
+// NormalForm is s in one Unicode normalization form.
+type NormalForm struct {
+	Form    string
+	Text    string
+	Runes   int
+	Bytes   int
+	Changed bool
+}
+
+// NormalForms returns s in NFC, NFD, NFKC and NFKD, noting which differ from
+// the input.
+func NormalForms(s string) []NormalForm {
+	forms := []struct {
+		name string
+		form norm.Form
+	}{
+		{"NFC", norm.NFC},
+		{"NFD", norm.NFD},
+		{"NFKC", norm.NFKC},
+		{"NFKD", norm.NFKD},
+	}
+
+	out := make([]NormalForm, len(forms))
+	for i, f := range forms {
+		text := f.form.String(s)
+		out[i] = NormalForm{
+			Form:    f.name,
+			Text:    text,
+			Runes:   utf8.RuneCountInString(text),
+			Bytes:   len(text),
+			Changed: text != s,
+		}
+	}
+	return out
+}
+
+// RuneInfo describes one rune of a string.
+type RuneInfo struct {
+	Offset       int
+	Char         string
+	CodePoint    string
+	Name         string
+	Category     string
+	CategoryName string
+	UTF8         string
+	Invalid      bool
+}
+
+// categories lists the Unicode general categories with their names, in the
+// order they are checked.
+var categories = []struct {
+	code, name string
+}{
+	{"Lu", "Uppercase Letter"}, {"Ll", "Lowercase Letter"}, {"Lt", "Titlecase Letter"},
+	{"Lm", "Modifier Letter"}, {"Lo", "Other Letter"},
+	{"Mn", "Nonspacing Mark"}, {"Mc", "Spacing Mark"}, {"Me", "Enclosing Mark"},
+	{"Nd", "Decimal Number"}, {"Nl", "Letter Number"}, {"No", "Other Number"},
+	{"Pc", "Connector Punctuation"}, {"Pd", "Dash Punctuation"}, {"Ps", "Open Punctuation"},
+	{"Pe", "Close Punctuation"}, {"Pi", "Initial Punctuation"}, {"Pf", "Final Punctuation"},
+	{"Po", "Other Punctuation"},
+	{"Sm", "Math Symbol"}, {"Sc", "Currency Symbol"}, {"Sk", "Modifier Symbol"}, {"So", "Other Symbol"},
+	{"Zs", "Space Separator"}, {"Zl", "Line Separator"}, {"Zp", "Paragraph Separator"},
+	{"Cc", "Control"}, {"Cf", "Format"}, {"Co", "Private Use"}, {"Cs", "Surrogate"},
+}
+
+// Category returns the two-letter general category of r and its name, or
+// "Cn" (unassigned) when r is in no category.
+func Category(r rune) (code, name string) {
+	for _, c := range categories {
+		if unicode.Is(unicode.Categories[c.code], r) {
+			return c.code, c.name
+		}
+	}
+	return "Cn", "Unassigned"
+}
+
+// Inspect describes each rune of s, up to MaxInspectRunes. Invalid UTF-8
+// bytes are reported one at a time with Invalid set.
+func Inspect(s string) (runes []RuneInfo, truncated bool) {
+	for offset := 0; offset < len(s); {
+		if len(runes) == MaxInspectRunes {
+			return runes, true
+		}
+
+		r, size := utf8.DecodeRuneInString(s[offset:])
+		info := RuneInfo{Offset: offset, UTF8: hexBytes(s[offset : offset+size])}
+
+		if r == utf8.RuneError && size == 1 {
+			info.Invalid = true
+			info.CodePoint = "-"
+			info.Name = "invalid UTF-8 byte"
+		} else {
+			info.Char = displayRune(r)
+			info.CodePoint = fmt.Sprintf("U+%04X", r)
+			info.Name = runeName(r)
+			info.Category, info.CategoryName = Category(r)
+		}
+
+		runes = append(runes, info)
+		offset += size
+	}
+	return runes, false
+}
+
+// runeName returns the Unicode name of r. Control characters have no name in
+// the Unicode data, so their conventional abbreviation is used instead.
+func runeName(r rune) string {
+	name := runenames.Name(r)
+	if name != "" && !strings.HasPrefix(name, "<") {
+		return name
+	}
+	if abbr, ok := controlNames[r]; ok {
+		return abbr
+	}
+	if name == "" {
+		return "(unassigned)"
+	}
+	return name
+}
+
+var controlNames = map[rune]string{
+	0x00: "NULL", 0x07: "BELL", 0x08: "BACKSPACE", 0x09: "CHARACTER TABULATION",
+	0x0A: "LINE FEED", 0x0B: "LINE TABULATION", 0x0C: "FORM FEED",
+	0x0D: "CARRIAGE RETURN", 0x1B: "ESCAPE", 0x7F: "DELETE", 0x85: "NEXT LINE",
+}
+
+// displayRune returns a printable form of r: combining marks are shown on a
+// dotted circle and invisible characters as their code point.
+func displayRune(r rune) string {
+	switch {
+	case unicode.In(r, unicode.Mn, unicode.Me):
+		return "◌" + string(r)
+	case unicode.IsGraphic(r) && !unicode.IsSpace(r):
+		return string(r)
+	default:
+		return ""
+	}
+}
+
+func hexBytes(s string) string {
+	parts := make([]string, len(s))
+	for i := 0; i < len(s); i++ {
+		parts[i] = fmt.Sprintf("%02X", s[i])
+	}
+	return strings.Join(parts, " ")
+}
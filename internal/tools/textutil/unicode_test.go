@@ -0,0 +1,79 @@
+package textutil
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestNormalForms(t *testing.T) {
+	// "é" as e + combining acute, followed by the "ﬁ" ligature.
+	forms := NormalForms("e\u0301ﬁ")
+
+	var got []string
+	for _, f := range forms {
+		got = append(got, fmt.Sprintf("%s %+q %d/%d %v", f.Form, f.Text, f.Runes, f.Bytes, f.Changed))
+	}
+	expected := []string{
+		`NFC "\u00e9\ufb01" 2/5 true`,
+		`NFD "e\u0301\ufb01" 3/6 false`,
+		`NFKC "\u00e9fi" 3/4 true`,
+		`NFKD "e\u0301fi" 4/5 true`,
+	}
+	if fmt.Sprint(got) != fmt.Sprint(expected) {
+		t.Errorf("NormalForms =\n%q\nwant\n%q", got, expected)
+	}
+}
+
+func TestInspect(t *testing.T) {
+	runes, truncated := Inspect("A\u0301\u200b€\xff\n")
+	if truncated {
+		t.Fatal("unexpected truncation")
+	}
+
+	var got []string
+	for _, r := range runes {
+		got = append(got, fmt.Sprintf("%d %q %s %s %s [%s]", r.Offset, r.Char, r.CodePoint, r.Name, r.Category, r.UTF8))
+	}
+	expected := []string{
+		`0 "A" U+0041 LATIN CAPITAL LETTER A Lu [41]`,
+		`1 "◌́" U+0301 COMBINING ACUTE ACCENT Mn [CC 81]`,
+		`3 "" U+200B ZERO WIDTH SPACE Cf [E2 80 8B]`,
+		`6 "€" U+20AC EURO SIGN Sc [E2 82 AC]`,
+		`9 "" - invalid UTF-8 byte  [FF]`,
+		`10 "" U+000A LINE FEED Cc [0A]`,
+	}
+	if len(got) != len(expected) {
+		t.Fatalf("Inspect returned %d runes, want %d:\n%q", len(got), len(expected), got)
+	}
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Errorf("rune %d = %s, want %s", i, got[i], expected[i])
+		}
+	}
+}
+
+func TestInspectTruncates(t *testing.T) {
+	s := make([]byte, MaxInspectRunes+10)
+	for i := range s {
+		s[i] = 'a'
+	}
+	runes, truncated := Inspect(string(s))
+	if len(runes) != MaxInspectRunes || !truncated {
+		t.Errorf("Inspect returned %d runes (truncated %v), want %d truncated", len(runes), truncated, MaxInspectRunes)
+	}
+}
+
+func TestCategory(t *testing.T) {
+	tests := []struct {
+		r    rune
+		code string
+	}{
+		{'a', "Ll"}, {'Z', "Lu"}, {'5', "Nd"}, {'½', "No"}, {'-', "Pd"},
+		{'(', "Ps"}, {'+', "Sm"}, {'$', "Sc"}, {' ', "Zs"}, {'\u0378', "Cn"},
+	}
+	for _, tt := range tests {
+		if code, _ := Category(tt.r); code != tt.code {
+			t.Errorf("Category(%U) = %s, want %s", tt.r, code, tt.code)
+		}
+	}
+}
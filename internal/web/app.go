@@ -13,6 +13,9 @@ type Application struct {
 	infoLog       *log.Logger
 	errorLog      *log.Logger
 	templateCache map[string]*template.Template
+
+	// hexUploads keeps files open in the hex viewer between page turns.
+	hexUploads *uploadStore
 }
 
 // NewApplication wires up dependencies and builds the initial template cache.
@@ -26,6 +29,7 @@ func NewApplication(infoLog, errorLog *log.Logger) (*Application, error) {
 		infoLog:       infoLog,
 		errorLog:      errorLog,
 		templateCache: tc,
+		hexUploads:    newUploadStore(maxHexStoreSize, hexUploadTTL),
 	}
 
 	return app, nil
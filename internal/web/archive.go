@@ -0,0 +1,190 @@
+package web
+
+import (
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/NickDiPreta1/toolhub/internal/tools/fileconvert"
+)
+
+// maxArchiveUploadSize caps the request body. Uploads beyond the in-memory
+// part of the form are spooled to temporary files.
+const maxArchiveUploadSize = 256 << 20
+
+type ArchiveData struct {
+	Error   string
+	Action  string
+	Format  string
+	Formats []fileconvert.ArchiveFormat
+	Entry   string
+	// ArchiveName and Listing describe the archive that was inspected.
+	ArchiveName string
+	Listing     *fileconvert.ArchiveListing
+}
+
+// archiveTool builds zip and tar.gz archives from uploaded files, lists the
+// contents of an uploaded archive and extracts single entries from it.
+func (app *Application) archiveTool(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		data := &templateData{
+			ToolData: &ArchiveData{
+				Format:  string(fileconvert.ArchiveZip),
+				Formats: []fileconvert.ArchiveFormat{fileconvert.ArchiveZip, fileconvert.ArchiveTarGz},
+			},
+		}
+		app.render(w, http.StatusOK, "archive.tmpl.html", data)
+		return
+
+	case http.MethodPost:
+		r.Body = http.MaxBytesReader(w, r.Body, maxArchiveUploadSize)
+
+		toolData := &ArchiveData{
+			Format:  string(fileconvert.ArchiveZip),
+			Formats: []fileconvert.ArchiveFormat{fileconvert.ArchiveZip, fileconvert.ArchiveTarGz},
+		}
+
+		if err := r.ParseMultipartForm(32 << 20); err != nil {
+			toolData.Error = "Upload too large or invalid. Maximum size is 256MB."
+			app.render(w, http.StatusBadRequest, "archive.tmpl.html", &templateData{ToolData: toolData})
+			return
+		}
+		defer r.MultipartForm.RemoveAll()
+
+		toolData.Action = r.FormValue("action")
+		if f := r.FormValue("format"); f != "" {
+			toolData.Format = f
+		}
+		toolData.Entry = r.FormValue("entry")
+
+		switch toolData.Action {
+		case "create":
+			app.createArchive(w, r, toolData)
+
+		case "list", "extract":
+			file, header, err := r.FormFile("archive")
+			if err != nil {
+				toolData.Error = "Please choose an archive."
+				app.render(w, http.StatusBadRequest, "archive.tmpl.html", &templateData{ToolData: toolData})
+				return
+			}
+			defer file.Close()
+			toolData.ArchiveName = header.Filename
+
+			if toolData.Action == "extract" {
+				app.extractArchiveEntry(w, file, header.Size, toolData)
+				return
+			}
+
+			listing, err := fileconvert.ListArchive(file, header.Size)
+			if err != nil {
+				toolData.Error = err.Error()
+				app.render(w, http.StatusBadRequest, "archive.tmpl.html", &templateData{ToolData: toolData})
+				return
+			}
+			toolData.Listing = listing
+			app.render(w, http.StatusOK, "archive.tmpl.html", &templateData{ToolData: toolData})
+
+		default:
+			toolData.Error = "Unknown action: " + toolData.Action
+			app.render(w, http.StatusBadRequest, "archive.tmpl.html", &templateData{ToolData: toolData})
+		}
+		return
+
+	default:
+		w.Header().Set("Allow", http.MethodGet+", "+http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+}
+
+// createArchive streams the uploaded files into a new archive.
+func (app *Application) createArchive(w http.ResponseWriter, r *http.Request, toolData *ArchiveData) {
+	headers := r.MultipartForm.File["files"]
+	if len(headers) == 0 {
+		toolData.Error = "Please choose at least one file."
+		app.render(w, http.StatusBadRequest, "archive.tmpl.html", &templateData{ToolData: toolData})
+		return
+	}
+	if len(headers) > fileconvert.MaxArchiveEntries {
+		toolData.Error = fmt.Sprintf("At most %d files can be archived at once.", fileconvert.MaxArchiveEntries)
+		app.render(w, http.StatusBadRequest, "archive.tmpl.html", &templateData{ToolData: toolData})
+		return
+	}
+
+	format := fileconvert.ArchiveFormat(toolData.Format)
+	aw, err := fileconvert.NewArchiveWriter(w, format)
+	if err != nil {
+		toolData.Error = err.Error()
+		app.render(w, http.StatusBadRequest, "archive.tmpl.html", &templateData{ToolData: toolData})
+		return
+	}
+
+	w.Header().Set("Content-Type", format.ContentType())
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": "archive" + format.Extension()}))
+	w.WriteHeader(http.StatusOK)
+
+	// Headers are sent by now, so failures can only be logged.
+	now := time.Now()
+	for _, fh := range headers {
+		if err := addUpload(aw, fh, now); err != nil {
+			app.errorLog.Printf("error building archive: %v", err)
+			return
+		}
+	}
+	if err := aw.Close(); err != nil {
+		app.errorLog.Printf("error building archive: %v", err)
+	}
+}
+
+func addUpload(aw *fileconvert.ArchiveWriter, fh *multipart.FileHeader, modified time.Time) error {
+	f, err := fh.Open()
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = aw.Add(fh.Filename, fh.Size, modified, f)
+	return err
+}
+
+// extractArchiveEntry sends one entry of the uploaded archive as a download.
+func (app *Application) extractArchiveEntry(w http.ResponseWriter, file multipart.File, size int64, toolData *ArchiveData) {
+	if strings.TrimSpace(toolData.Entry) == "" {
+		toolData.Error = "Choose an entry to extract."
+		app.render(w, http.StatusBadRequest, "archive.tmpl.html", &templateData{ToolData: toolData})
+		return
+	}
+
+	// Check the entry before committing to a download, so problems can
+	// still be shown on the page.
+	listing, err := fileconvert.ListArchive(file, size)
+	if err == nil {
+		err = fmt.Errorf("no entry named %q", toolData.Entry)
+		for _, e := range listing.Entries {
+			if e.Name == toolData.Entry {
+				err = fileconvert.CheckEntry(e)
+				break
+			}
+		}
+	}
+	if err != nil {
+		toolData.Error = err.Error()
+		toolData.Listing = listing
+		app.render(w, http.StatusBadRequest, "archive.tmpl.html", &templateData{ToolData: toolData})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": path.Base(toolData.Entry)}))
+	w.WriteHeader(http.StatusOK)
+
+	if _, err := fileconvert.ExtractEntry(file, size, toolData.Entry, w); err != nil {
+		app.errorLog.Printf("error extracting %s: %v", toolData.Entry, err)
+	}
+}
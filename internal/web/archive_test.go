@@ -0,0 +1,162 @@
+package web
+
+import (
+	"archive/zip"
+	"bytes"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+// archiveRequest builds a multipart request; files maps a form field to
+// name and content pairs.
+func archiveRequest(t *testing.T, fields [][2]string, field string, files [][2]string) *http.Request {
+	t.Helper()
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	for _, f := range fields {
+		writer.WriteField(f[0], f[1])
+	}
+	for _, f := range files {
+		part, err := writer.CreateFormFile(field, f[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		part.Write([]byte(f[1]))
+	}
+	writer.Close()
+
+	req := httptest.NewRequest("POST", "/tools/archive", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return req
+}
+
+func TestArchiveTool(t *testing.T) {
+	originalWd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir("../.."); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(originalWd)
+
+	app, err := NewApplication(
+		log.New(os.Stdout, "TEST INFO: ", 0),
+		log.New(os.Stdout, "TEST ERROR: ", 0),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var zipBuf bytes.Buffer
+	zw := zip.NewWriter(&zipBuf)
+	for name, content := range map[string]string{"docs/readme.txt": "read me", "../evil.sh": "rm -rf /"} {
+		f, _ := zw.Create(name)
+		f.Write([]byte(content))
+	}
+	zw.Close()
+	archive := [][2]string{{"upload.zip", zipBuf.String()}}
+
+	tests := []struct {
+		name            string
+		fields          [][2]string
+		field           string
+		files           [][2]string
+		wantStatus      int
+		wantContentType string
+		wantBody        string
+	}{
+		{
+			name:            "create tar.gz",
+			fields:          [][2]string{{"action", "create"}, {"format", "tar.gz"}},
+			field:           "files",
+			files:           [][2]string{{"a.txt", "alpha"}, {"b.txt", "beta"}},
+			wantStatus:      http.StatusOK,
+			wantContentType: "application/gzip",
+		},
+		{
+			name:       "create without files",
+			fields:     [][2]string{{"action", "create"}},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   "choose at least one file",
+		},
+		{
+			name:       "create unsupported format",
+			fields:     [][2]string{{"action", "create"}, {"format", "rar"}},
+			field:      "files",
+			files:      [][2]string{{"a.txt", "alpha"}},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   "unsupported archive format",
+		},
+		{
+			name:       "list",
+			fields:     [][2]string{{"action", "list"}},
+			field:      "archive",
+			files:      archive,
+			wantStatus: http.StatusOK,
+			wantBody:   "name escapes the archive",
+		},
+		{
+			name:       "list not an archive",
+			fields:     [][2]string{{"action", "list"}},
+			field:      "archive",
+			files:      [][2]string{{"notes.txt", "hello"}},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   "not a zip, tar or tar.gz archive",
+		},
+		{
+			name:            "extract",
+			fields:          [][2]string{{"action", "extract"}, {"entry", "docs/readme.txt"}},
+			field:           "archive",
+			files:           archive,
+			wantStatus:      http.StatusOK,
+			wantContentType: "application/octet-stream",
+			wantBody:        "read me",
+		},
+		{
+			name:       "extract zip-slip entry",
+			fields:     [][2]string{{"action", "extract"}, {"entry", "../evil.sh"}},
+			field:      "archive",
+			files:      archive,
+			wantStatus: http.StatusBadRequest,
+			wantBody:   "name escapes the archive",
+		},
+		{
+			name:       "extract without entry",
+			fields:     [][2]string{{"action", "extract"}},
+			field:      "archive",
+			files:      archive,
+			wantStatus: http.StatusBadRequest,
+			wantBody:   "Choose an entry",
+		},
+		{
+			name:       "unknown action",
+			fields:     [][2]string{{"action", "shred"}},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   "Unknown action",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			app.archiveTool(rr, archiveRequest(t, tt.fields, tt.field, tt.files))
+
+			if rr.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rr.Code, tt.wantStatus, rr.Body.String())
+			}
+			if tt.wantContentType != "" && rr.Header().Get("Content-Type") != tt.wantContentType {
+				t.Errorf("Content-Type = %q, want %q", rr.Header().Get("Content-Type"), tt.wantContentType)
+			}
+			if tt.wantBody != "" && !strings.Contains(rr.Body.String(), tt.wantBody) {
+				t.Errorf("body does not contain %q", tt.wantBody)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package web
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/NickDiPreta1/toolhub/internal/tools/textutil"
+)
+
+type CaseData struct {
+	Error       string
+	Input       string
+	Output      string
+	Target      string
+	Batch       bool
+	Cases       []textutil.CaseOption
+	Conversions []CaseConversion
+}
+
+// CaseConversion is one row of the all-cases table shown for a single input.
+type CaseConversion struct {
+	Name   string
+	Output string
+}
+
+// caseTool converts identifiers between naming conventions. A single input is
+// shown in every case; batch mode converts each line to the selected case.
+func (app *Application) caseTool(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		data := &templateData{
+			ToolData: &CaseData{
+				Target: string(textutil.CaseSnake),
+				Cases:  textutil.Cases,
+			},
+		}
+		app.render(w, http.StatusOK, "case.tmpl.html", data)
+		return
+
+	case http.MethodPost:
+		toolData := &CaseData{
+			Input:  r.FormValue("input"),
+			Target: r.FormValue("target"),
+			Batch:  r.FormValue("batch") == "on",
+			Cases:  textutil.Cases,
+		}
+		if toolData.Target == "" {
+			toolData.Target = string(textutil.CaseSnake)
+		}
+
+		if strings.TrimSpace(toolData.Input) == "" {
+			toolData.Error = "Input cannot be empty."
+			app.render(w, http.StatusBadRequest, "case.tmpl.html", &templateData{ToolData: toolData})
+			return
+		}
+
+		target, err := textutil.ParseCase(toolData.Target)
+		if err != nil {
+			toolData.Error = err.Error()
+			app.render(w, http.StatusBadRequest, "case.tmpl.html", &templateData{ToolData: toolData})
+			return
+		}
+
+		if toolData.Batch {
+			output, err := textutil.ConvertCaseLines(toolData.Input, target)
+			if err != nil {
+				toolData.Error = err.Error()
+				app.render(w, http.StatusBadRequest, "case.tmpl.html", &templateData{ToolData: toolData})
+				return
+			}
+			toolData.Output = output
+			app.render(w, http.StatusOK, "case.tmpl.html", &templateData{ToolData: toolData})
+			return
+		}
+
+		for _, c := range textutil.Cases {
+			output, err := textutil.ConvertCase(toolData.Input, c.Case)
+			if err != nil {
+				app.serverError(w, err)
+				return
+			}
+			toolData.Conversions = append(toolData.Conversions, CaseConversion{Name: c.Name, Output: output})
+			if c.Case == target {
+				toolData.Output = output
+			}
+		}
+		app.render(w, http.StatusOK, "case.tmpl.html", &templateData{ToolData: toolData})
+		return
+
+	default:
+		w.Header().Set("Allow", http.MethodGet+", "+http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+}
@@ -0,0 +1,79 @@
+package web
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/NickDiPreta1/toolhub/internal/tools/codegen"
+)
+
+type CodegenData struct {
+	Error    string
+	Input    string
+	Output   string
+	RootName string
+	Target   string
+}
+
+// codegenTool generates Go structs, TypeScript interfaces or a JSON Schema
+// from JSON samples.
+func (app *Application) codegenTool(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		data := &templateData{
+			ToolData: &CodegenData{
+				RootName: codegen.DefaultRootName,
+				Target:   "go",
+			},
+		}
+		app.render(w, http.StatusOK, "codegen.tmpl.html", data)
+		return
+
+	case http.MethodPost:
+		toolData := &CodegenData{
+			Input:    r.FormValue("input"),
+			RootName: r.FormValue("root"),
+			Target:   r.FormValue("target"),
+		}
+		if toolData.Target == "" {
+			toolData.Target = "go"
+		}
+
+		if strings.TrimSpace(toolData.Input) == "" {
+			toolData.Error = "Input cannot be empty."
+			app.render(w, http.StatusBadRequest, "codegen.tmpl.html", &templateData{ToolData: toolData})
+			return
+		}
+
+		typ, err := codegen.Infer(toolData.Input)
+		if err != nil {
+			toolData.Error = err.Error()
+			app.render(w, http.StatusBadRequest, "codegen.tmpl.html", &templateData{ToolData: toolData})
+			return
+		}
+
+		var output string
+		switch toolData.Target {
+		case "typescript":
+			output, err = codegen.TypeScript(typ, toolData.RootName)
+		case "jsonschema":
+			output, err = codegen.JSONSchema(typ, toolData.RootName)
+		default:
+			output, err = codegen.GoStructs(typ, toolData.RootName)
+		}
+		if err != nil {
+			toolData.Error = err.Error()
+			app.render(w, http.StatusBadRequest, "codegen.tmpl.html", &templateData{ToolData: toolData})
+			return
+		}
+
+		toolData.Output = output
+		app.render(w, http.StatusOK, "codegen.tmpl.html", &templateData{ToolData: toolData})
+		return
+
+	default:
+		w.Header().Set("Allow", http.MethodGet+", "+http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+}
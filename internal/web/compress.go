@@ -0,0 +1,236 @@
+package web
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"io"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/NickDiPreta1/toolhub/internal/tools/encodingutil"
+)
+
+const (
+	maxCompressUploadSize = 64 << 20
+	// maxCompressOutput caps decompressed output, which is held in memory.
+	maxCompressOutput = 64 << 20
+	// maxCompressPreview is the most output shown on the page.
+	maxCompressPreview = 64 << 10
+)
+
+// compressExtensions are the conventional file extensions of each codec.
+var compressExtensions = map[encodingutil.Codec]string{
+	encodingutil.CodecGzip:    ".gz",
+	encodingutil.CodecZlib:    ".zz",
+	encodingutil.CodecDeflate: ".deflate",
+	encodingutil.CodecBzip2:   ".bz2",
+	encodingutil.CodecLZWLSB:  ".lzw",
+	encodingutil.CodecLZWMSB:  ".lzw",
+}
+
+type CompressData struct {
+	Error  string
+	Action string
+	Codec  string
+	Codecs []encodingutil.CodecOption
+	Level  string
+	// Paste says whether pasted input is "text" or "base64".
+	Paste string
+	Input string
+	// Source names the input: the uploaded file or "pasted input".
+	Source string
+	Stats  *encodingutil.CompressStats
+	// SavedPercent is Stats.Savings as a percentage, for display.
+	SavedPercent float64
+	// Output previews the result as text or, for binary data, as base64.
+	Output       string
+	OutputBase64 bool
+	Truncated    bool
+}
+
+// compressTool compresses or decompresses an upload or pasted input and
+// reports sizes, ratios and gzip header metadata.
+func (app *Application) compressTool(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		data := &templateData{
+			ToolData: &CompressData{
+				Action: "decompress",
+				Codec:  string(encodingutil.CodecAuto),
+				Codecs: encodingutil.Codecs,
+				Level:  strconv.Itoa(encodingutil.DefaultLevel),
+				Paste:  "base64",
+			},
+		}
+		app.render(w, http.StatusOK, "compress.tmpl.html", data)
+		return
+
+	case http.MethodPost:
+		r.Body = http.MaxBytesReader(w, r.Body, maxCompressUploadSize)
+
+		toolData := &CompressData{
+			Action: "decompress",
+			Codec:  string(encodingutil.CodecAuto),
+			Codecs: encodingutil.Codecs,
+			Level:  strconv.Itoa(encodingutil.DefaultLevel),
+			Paste:  "base64",
+		}
+
+		if err := r.ParseMultipartForm(32 << 20); err != nil {
+			toolData.Error = "Upload too large or invalid. Maximum size is 64MB."
+			app.render(w, http.StatusBadRequest, "compress.tmpl.html", &templateData{ToolData: toolData})
+			return
+		}
+		defer r.MultipartForm.RemoveAll()
+
+		for field, dst := range map[string]*string{
+			"action": &toolData.Action, "codec": &toolData.Codec, "level": &toolData.Level, "paste": &toolData.Paste,
+		} {
+			if v := strings.TrimSpace(r.FormValue(field)); v != "" {
+				*dst = v
+			}
+		}
+		toolData.Input = r.FormValue("input")
+		download := r.FormValue("download") != ""
+
+		input, name, err := compressInput(r, toolData)
+		if err != nil {
+			switch {
+			case errors.Is(err, errNoCompressInput):
+				toolData.Error = "Upload a file or paste some input."
+			case errors.Is(err, errInvalidPaste):
+				toolData.Error = "Pasted input is not valid base64."
+			default:
+				toolData.Error = err.Error()
+			}
+			app.render(w, http.StatusBadRequest, "compress.tmpl.html", &templateData{ToolData: toolData})
+			return
+		}
+		defer input.Close()
+		toolData.Source = name
+		if name == "" {
+			toolData.Source = "pasted input"
+		}
+
+		var out bytes.Buffer
+		var stats encodingutil.CompressStats
+		codec := encodingutil.Codec(toolData.Codec)
+
+		switch toolData.Action {
+		case "compress":
+			level, convErr := strconv.Atoi(toolData.Level)
+			if convErr != nil {
+				toolData.Error = "Level must be a whole number."
+				app.render(w, http.StatusBadRequest, "compress.tmpl.html", &templateData{ToolData: toolData})
+				return
+			}
+			stats, err = encodingutil.Compress(&out, input, encodingutil.CompressOptions{
+				Codec:   codec,
+				Level:   level,
+				Name:    name,
+				ModTime: time.Now(),
+			})
+		case "decompress":
+			stats, err = encodingutil.Decompress(&out, input, codec, maxCompressOutput)
+		default:
+			toolData.Error = "Unknown action: " + toolData.Action
+			app.render(w, http.StatusBadRequest, "compress.tmpl.html", &templateData{ToolData: toolData})
+			return
+		}
+		if err != nil {
+			toolData.Error = err.Error()
+			app.render(w, http.StatusBadRequest, "compress.tmpl.html", &templateData{ToolData: toolData})
+			return
+		}
+
+		if download {
+			sendDownload(w, "application/octet-stream", compressedName(toolData.Action, stats, name), out.Bytes())
+			return
+		}
+
+		toolData.Stats = &stats
+		toolData.SavedPercent = stats.Savings() * 100
+		toolData.Output, toolData.OutputBase64, toolData.Truncated = previewOutput(out.Bytes(), toolData.Action == "compress")
+		app.render(w, http.StatusOK, "compress.tmpl.html", &templateData{ToolData: toolData})
+		return
+
+	default:
+		w.Header().Set("Allow", http.MethodGet+", "+http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+}
+
+var (
+	errNoCompressInput = errors.New("no file uploaded or input pasted")
+	errInvalidPaste    = errors.New("pasted input is not valid base64")
+)
+
+// compressInput returns the uploaded file and its name, or the pasted
+// input decoded as toolData.Paste says with an empty name.
+func compressInput(r *http.Request, toolData *CompressData) (io.ReadCloser, string, error) {
+	file, header, err := r.FormFile("file")
+	if err == nil {
+		return file, header.Filename, nil
+	}
+
+	if strings.TrimSpace(toolData.Input) == "" {
+		return nil, "", errNoCompressInput
+	}
+	if toolData.Paste == "text" {
+		return io.NopCloser(strings.NewReader(toolData.Input)), "", nil
+	}
+	data, err := encodingutil.DecodeBytes(toolData.Input)
+	if err != nil {
+		return nil, "", errInvalidPaste
+	}
+	return io.NopCloser(bytes.NewReader(data)), "", nil
+}
+
+// compressedName picks a download name for the output of action.
+func compressedName(action string, stats encodingutil.CompressStats, source string) string {
+	base := path.Base(strings.ReplaceAll(source, `\`, "/"))
+	if source == "" {
+		base = "data"
+	}
+	ext := compressExtensions[stats.Codec]
+
+	if action == "compress" {
+		return base + ext
+	}
+	if stats.Gzip != nil && stats.Gzip.Name != "" {
+		return path.Base(strings.ReplaceAll(stats.Gzip.Name, `\`, "/"))
+	}
+	if trimmed := strings.TrimSuffix(base, ext); ext != "" && trimmed != base && trimmed != "" {
+		return trimmed
+	}
+	return base + ".out"
+}
+
+// previewOutput returns the start of data for display: as text when it is
+// readable UTF-8, otherwise as base64. Compressed output is always base64.
+func previewOutput(data []byte, binary bool) (preview string, isBase64, truncated bool) {
+	truncated = len(data) > maxCompressPreview
+	if truncated {
+		data = data[:maxCompressPreview]
+	}
+
+	if !binary && !bytes.ContainsRune(data, 0) {
+		text := data
+		if truncated {
+			// Drop a rune cut in half by the preview limit.
+			for i := 0; i < utf8.UTFMax && len(text) > 0 && !utf8.Valid(text); i++ {
+				text = text[:len(text)-1]
+			}
+		}
+		if utf8.Valid(text) {
+			return string(text), false, truncated
+		}
+	}
+	return base64.StdEncoding.EncodeToString(data), true, truncated
+}
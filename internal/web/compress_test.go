@@ -0,0 +1,150 @@
+package web
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCompressTool(t *testing.T) {
+	originalWd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir("../.."); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(originalWd)
+
+	app, err := NewApplication(
+		log.New(os.Stdout, "TEST INFO: ", 0),
+		log.New(os.Stdout, "TEST ERROR: ", 0),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var gz bytes.Buffer
+	zw := gzip.NewWriter(&gz)
+	zw.Name = "notes.txt"
+	zw.ModTime = time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC)
+	zw.Write([]byte("hello, world\n"))
+	zw.Close()
+	pasted := base64.StdEncoding.EncodeToString(gz.Bytes())
+
+	tests := []struct {
+		name            string
+		fields          [][2]string
+		file            [2]string
+		wantStatus      int
+		wantContentType string
+		wantDisposition string
+		wantBody        []string
+	}{
+		{
+			name:       "decompress pasted base64",
+			fields:     [][2]string{{"action", "decompress"}, {"codec", "auto"}, {"input", pasted}},
+			wantStatus: http.StatusOK,
+			wantBody:   []string{"hello, world", "notes.txt", "2024-03-01 10:30:00 UTC", "Members"},
+		},
+		{
+			name:            "decompress upload as download",
+			fields:          [][2]string{{"action", "decompress"}, {"download", "1"}},
+			file:            [2]string{"notes.txt.gz", gz.String()},
+			wantStatus:      http.StatusOK,
+			wantContentType: "application/octet-stream",
+			wantDisposition: `attachment; filename=notes.txt`,
+			wantBody:        []string{"hello, world"},
+		},
+		{
+			name:       "compress pasted text",
+			fields:     [][2]string{{"action", "compress"}, {"codec", "zlib"}, {"level", "9"}, {"paste", "text"}, {"input", strings.Repeat("abc", 100)}},
+			wantStatus: http.StatusOK,
+			wantBody:   []string{"Uncompressed</td><td style=\"padding: 0.25rem 1rem 0.25rem 0;\">300 bytes", "(base64)"},
+		},
+		{
+			name:            "compress upload as download",
+			fields:          [][2]string{{"action", "compress"}, {"codec", "gzip"}, {"download", "1"}},
+			file:            [2]string{"report.csv", "a,b\n1,2\n"},
+			wantStatus:      http.StatusOK,
+			wantContentType: "application/octet-stream",
+			wantDisposition: `attachment; filename=report.csv.gz`,
+		},
+		{
+			name:       "bzip2 cannot be compressed",
+			fields:     [][2]string{{"action", "compress"}, {"codec", "bzip2"}, {"paste", "text"}, {"input", "x"}},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   []string{"bzip2 can only be decompressed"},
+		},
+		{
+			name:       "undetectable format",
+			fields:     [][2]string{{"action", "decompress"}, {"paste", "text"}, {"input", "plain text"}},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   []string{"could not detect the compression format"},
+		},
+		{
+			name:       "invalid base64",
+			fields:     [][2]string{{"action", "decompress"}, {"input", "not base64!"}},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   []string{"not valid base64"},
+		},
+		{
+			name:       "no input",
+			fields:     [][2]string{{"action", "decompress"}},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   []string{"Upload a file or paste some input"},
+		},
+		{
+			name:       "unknown action",
+			fields:     [][2]string{{"action", "shred"}, {"paste", "text"}, {"input", "x"}},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   []string{"Unknown action"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body bytes.Buffer
+			writer := multipart.NewWriter(&body)
+			for _, f := range tt.fields {
+				writer.WriteField(f[0], f[1])
+			}
+			if tt.file[0] != "" {
+				part, err := writer.CreateFormFile("file", tt.file[0])
+				if err != nil {
+					t.Fatal(err)
+				}
+				part.Write([]byte(tt.file[1]))
+			}
+			writer.Close()
+
+			req := httptest.NewRequest("POST", "/tools/compress", &body)
+			req.Header.Set("Content-Type", writer.FormDataContentType())
+			rr := httptest.NewRecorder()
+			app.compressTool(rr, req)
+
+			if rr.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rr.Code, tt.wantStatus, rr.Body.String())
+			}
+			if tt.wantContentType != "" && rr.Header().Get("Content-Type") != tt.wantContentType {
+				t.Errorf("Content-Type = %q, want %q", rr.Header().Get("Content-Type"), tt.wantContentType)
+			}
+			if tt.wantDisposition != "" && rr.Header().Get("Content-Disposition") != tt.wantDisposition {
+				t.Errorf("Content-Disposition = %q, want %q", rr.Header().Get("Content-Disposition"), tt.wantDisposition)
+			}
+			for _, want := range tt.wantBody {
+				if !strings.Contains(rr.Body.String(), want) {
+					t.Errorf("body does not contain %q", want)
+				}
+			}
+		})
+	}
+}
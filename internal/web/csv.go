@@ -0,0 +1,295 @@
+package web
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/NickDiPreta1/toolhub/internal/tools/csvutil"
+)
+
+const (
+	csvPageSize  = 50
+	maxCSVIssues = 500
+)
+
+// csvDelimiters maps the delimiter options to their characters.
+var csvDelimiters = map[string]rune{",": ',', ";": ';', "tab": '\t', "|": '|', ":": ':'}
+
+// csvDelimiterNames describes delimiters in the detected dialect summary.
+var csvDelimiterNames = map[rune]string{',': "comma", ';': "semicolon", '\t': "tab", '|': "pipe", ':': "colon"}
+
+type CSVRow struct {
+	Line  int
+	Cells []string
+}
+
+type CSVColumn struct {
+	Name string
+	Type csvutil.ColumnType
+}
+
+type CSVData struct {
+	Error     string
+	Input     string
+	Filename  string
+	Action    string
+	Delimiter string
+	Quote     string
+	Header    string
+	Columns   string
+	Rules     string
+	// Output options for reformat and JSON.
+	OutDelimiter string
+	OutQuote     string
+	QuoteMode    string
+	QuoteModes   []csvutil.QuoteMode
+	Typed        bool
+
+	// Dialect summarizes how the input was read.
+	Dialect  string
+	RowCount int
+	Table    []CSVColumn
+	Rows     []CSVRow
+	Page     int
+	Pages    int
+	// PrevPage and NextPage are zero on the first and last pages.
+	PrevPage   int
+	NextPage   int
+	Validated  bool
+	Issues     []csvutil.Issue
+	IssueTotal int
+	Output     string
+}
+
+// csvTool previews, validates, reshapes and converts delimited data.
+func (app *Application) csvTool(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		data := &templateData{
+			ToolData: &CSVData{
+				Action:       "preview",
+				Delimiter:    "auto",
+				Quote:        "auto",
+				Header:       "auto",
+				OutDelimiter: ",",
+				OutQuote:     `"`,
+				QuoteMode:    string(csvutil.QuoteMinimal),
+				QuoteModes:   csvutil.QuoteModes,
+				Typed:        true,
+			},
+		}
+		app.render(w, http.StatusOK, "csv.tmpl.html", data)
+		return
+
+	case http.MethodPost:
+		const maxUploadSize = 10 * 1024 * 1024
+		r.Body = http.MaxBytesReader(w, r.Body, maxUploadSize)
+
+		toolData := &CSVData{QuoteModes: csvutil.QuoteModes}
+
+		if err := r.ParseMultipartForm(maxUploadSize); err != nil {
+			toolData.Error = "File too large or invalid upload. Maximum size is 10MB."
+			app.render(w, http.StatusBadRequest, "csv.tmpl.html", &templateData{ToolData: toolData})
+			return
+		}
+		defer r.MultipartForm.RemoveAll()
+
+		toolData.Input = r.FormValue("input")
+		toolData.Action = r.FormValue("action")
+		toolData.Delimiter = r.FormValue("delimiter")
+		toolData.Quote = r.FormValue("quote")
+		toolData.Header = r.FormValue("header")
+		toolData.Columns = strings.TrimSpace(r.FormValue("columns"))
+		toolData.Rules = strings.TrimSpace(r.FormValue("rules"))
+		toolData.OutDelimiter = r.FormValue("out_delimiter")
+		toolData.OutQuote = r.FormValue("out_quote")
+		toolData.QuoteMode = r.FormValue("quote_mode")
+		toolData.Typed = r.FormValue("typed") == "on"
+		if toolData.Action == "" {
+			toolData.Action = "preview"
+		}
+		if toolData.OutDelimiter == "" {
+			toolData.OutDelimiter = ","
+		}
+		if toolData.OutQuote == "" {
+			toolData.OutQuote = `"`
+		}
+		if toolData.QuoteMode == "" {
+			toolData.QuoteMode = string(csvutil.QuoteMinimal)
+		}
+
+		// An upload replaces the pasted input, and is echoed back into the
+		// form so paging and later actions work without choosing it again.
+		file, header, err := r.FormFile("file")
+		if err == nil {
+			defer file.Close()
+			data, err := io.ReadAll(file)
+			if err != nil {
+				app.serverError(w, err)
+				return
+			}
+			if !utf8.Valid(data) {
+				toolData.Error = "The file is not UTF-8 text. Convert it with File Convert first."
+				app.render(w, http.StatusBadRequest, "csv.tmpl.html", &templateData{ToolData: toolData})
+				return
+			}
+			toolData.Input = string(data)
+			toolData.Filename = header.Filename
+		}
+
+		if strings.TrimSpace(toolData.Input) == "" {
+			toolData.Error = "Please upload a file or paste some CSV."
+			app.render(w, http.StatusBadRequest, "csv.tmpl.html", &templateData{ToolData: toolData})
+			return
+		}
+
+		if err := runCSVAction(toolData, r.FormValue("page")); err != nil {
+			toolData.Error = err.Error()
+			app.render(w, http.StatusBadRequest, "csv.tmpl.html", &templateData{ToolData: toolData})
+			return
+		}
+
+		app.render(w, http.StatusOK, "csv.tmpl.html", &templateData{ToolData: toolData})
+		return
+
+	default:
+		w.Header().Set("Allow", http.MethodGet+", "+http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+}
+
+// runCSVAction parses toolData.Input, applies the column selection and
+// fills in the result of toolData.Action.
+func runCSVAction(toolData *CSVData, page string) error {
+	dialect, err := csvDialect(toolData)
+	if err != nil {
+		return err
+	}
+	table, err := csvutil.Parse(toolData.Input, dialect)
+	if err != nil {
+		return err
+	}
+	table, err = csvutil.Select(table, toolData.Columns)
+	if err != nil {
+		return err
+	}
+	toolData.RowCount = len(table.Rows)
+
+	switch toolData.Action {
+	case "preview":
+		types := csvutil.InferTypes(table)
+		for i, name := range table.Header {
+			toolData.Table = append(toolData.Table, CSVColumn{Name: name, Type: types[i]})
+		}
+
+		toolData.Pages = max(1, (len(table.Rows)+csvPageSize-1)/csvPageSize)
+		toolData.Page = 1
+		if n, err := strconv.Atoi(page); err == nil {
+			toolData.Page = min(max(n, 1), toolData.Pages)
+		}
+		if toolData.Page > 1 {
+			toolData.PrevPage = toolData.Page - 1
+		}
+		if toolData.Page < toolData.Pages {
+			toolData.NextPage = toolData.Page + 1
+		}
+		start := (toolData.Page - 1) * csvPageSize
+		end := min(start+csvPageSize, len(table.Rows))
+		for i := start; i < end; i++ {
+			toolData.Rows = append(toolData.Rows, CSVRow{Line: table.Lines[i], Cells: table.Rows[i]})
+		}
+
+	case "validate":
+		rules, err := csvutil.ParseRules(table, toolData.Rules)
+		if err != nil {
+			return err
+		}
+		toolData.Issues, toolData.IssueTotal = csvutil.Validate(table, rules, maxCSVIssues)
+		toolData.Validated = true
+
+	case "reformat":
+		delim, ok := csvDelimiters[toolData.OutDelimiter]
+		if !ok {
+			return fmt.Errorf("unsupported output delimiter %q", toolData.OutDelimiter)
+		}
+		quote, _ := utf8.DecodeRuneInString(toolData.OutQuote)
+		var b strings.Builder
+		err := csvutil.Write(&b, table, csvutil.WriteOptions{
+			Delimiter: delim,
+			Quote:     quote,
+			Mode:      csvutil.QuoteMode(toolData.QuoteMode),
+		})
+		if err != nil {
+			return err
+		}
+		toolData.Output = b.String()
+
+	case "json":
+		toolData.Output, err = csvutil.ToJSON(table, toolData.Typed)
+		if err != nil {
+			return err
+		}
+
+	case "markdown":
+		toolData.Output = csvutil.ToMarkdown(table)
+
+	default:
+		return fmt.Errorf("unknown action %q", toolData.Action)
+	}
+	return nil
+}
+
+// csvDialect sniffs the input and overrides whatever the form fixes
+// explicitly, then records a summary of the result.
+func csvDialect(toolData *CSVData) (csvutil.Dialect, error) {
+	d := csvutil.Sniff(toolData.Input)
+
+	switch toolData.Delimiter {
+	case "", "auto":
+		toolData.Delimiter = "auto"
+	default:
+		delim, ok := csvDelimiters[toolData.Delimiter]
+		if !ok {
+			return d, fmt.Errorf("unsupported delimiter %q", toolData.Delimiter)
+		}
+		d.Delimiter = delim
+	}
+
+	switch toolData.Quote {
+	case "", "auto":
+		toolData.Quote = "auto"
+	case "none":
+		d.Quote = 0
+	case `"`, "'":
+		d.Quote = rune(toolData.Quote[0])
+	default:
+		return d, fmt.Errorf("unsupported quote character %q", toolData.Quote)
+	}
+
+	switch toolData.Header {
+	case "", "auto":
+		toolData.Header = "auto"
+	case "yes":
+		d.Header = true
+	case "no":
+		d.Header = false
+	default:
+		return d, fmt.Errorf("unsupported header option %q", toolData.Header)
+	}
+
+	quote := "no quoting"
+	if d.Quote != 0 {
+		quote = "quoted with " + string(d.Quote)
+	}
+	header := "no header row"
+	if d.Header {
+		header = "header row"
+	}
+	toolData.Dialect = fmt.Sprintf("%s-delimited, %s, %s", csvDelimiterNames[d.Delimiter], quote, header)
+	return d, nil
+}
@@ -0,0 +1,147 @@
+package web
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestCSVTool(t *testing.T) {
+	originalWd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir("../.."); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(originalWd)
+
+	app, err := NewApplication(
+		log.New(os.Stdout, "TEST INFO: ", 0),
+		log.New(os.Stdout, "TEST ERROR: ", 0),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	people := "id;name;score\n1;Ada;9.5\n2;\"Grace; H\";x\n"
+	var long strings.Builder
+	long.WriteString("n\n")
+	for i := 1; i <= 120; i++ {
+		fmt.Fprintf(&long, "row%d\n", i)
+	}
+
+	tests := []struct {
+		name       string
+		fields     [][2]string
+		file       [2]string
+		wantStatus int
+		wantBody   []string
+		notBody    []string
+	}{
+		{
+			name:       "preview sniffs the dialect",
+			fields:     [][2]string{{"input", people}},
+			wantStatus: http.StatusOK,
+			wantBody:   []string{"semicolon-delimited, quoted with &#34;, header row", "2 rows", "Grace; H", "float"},
+		},
+		{
+			name:       "preview pages",
+			fields:     [][2]string{{"input", long.String()}, {"page", "3"}},
+			wantStatus: http.StatusOK,
+			wantBody:   []string{"Page 3 of 3", "row101", "row120"},
+			notBody:    []string{"row100<", "Next"},
+		},
+		{
+			name:       "validate reports line numbers",
+			fields:     [][2]string{{"action", "validate"}, {"input", people}, {"rules", "id:int!, score:float"}},
+			wantStatus: http.StatusOK,
+			wantBody:   []string{"Problems found: 1", `line 3, column &#34;score&#34;: &#34;x&#34; is not a valid float`},
+		},
+		{
+			name:       "reformat selected columns from an upload",
+			fields:     [][2]string{{"action", "reformat"}, {"columns", "name=who, id"}, {"out_delimiter", "tab"}, {"quote_mode", "all"}},
+			file:       [2]string{"people.csv", people},
+			wantStatus: http.StatusOK,
+			wantBody:   []string{"loaded from people.csv", "&#34;who&#34;\t&#34;id&#34;", "&#34;Grace; H&#34;\t&#34;2&#34;"},
+		},
+		{
+			name:       "to JSON",
+			fields:     [][2]string{{"action", "json"}, {"input", people}, {"typed", "on"}},
+			wantStatus: http.StatusOK,
+			wantBody:   []string{"&#34;score&#34;: 9.5", "&#34;score&#34;: &#34;x&#34;"},
+		},
+		{
+			name:       "to Markdown",
+			fields:     [][2]string{{"action", "markdown"}, {"input", "a,b\n1,2\n"}},
+			wantStatus: http.StatusOK,
+			wantBody:   []string{"|   a |   b |", "| --: | --: |"},
+		},
+		{
+			name:       "malformed quoting",
+			fields:     [][2]string{{"input", "a,b\n\"open,c\n"}, {"delimiter", ","}},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   []string{"line 2, column 1: quoted field is never closed"},
+		},
+		{
+			name:       "unknown column",
+			fields:     [][2]string{{"input", people}, {"columns", "email"}},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   []string{"unknown column &#34;email&#34;"},
+		},
+		{
+			name:       "not UTF-8",
+			file:       [2]string{"latin1.csv", "caf\xe9,1\n"},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   []string{"not UTF-8 text"},
+		},
+		{
+			name:       "empty input",
+			wantStatus: http.StatusBadRequest,
+			wantBody:   []string{"Please upload a file or paste some CSV."},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body bytes.Buffer
+			writer := multipart.NewWriter(&body)
+			for _, f := range tt.fields {
+				writer.WriteField(f[0], f[1])
+			}
+			if tt.file[0] != "" {
+				part, err := writer.CreateFormFile("file", tt.file[0])
+				if err != nil {
+					t.Fatal(err)
+				}
+				part.Write([]byte(tt.file[1]))
+			}
+			writer.Close()
+
+			req := httptest.NewRequest("POST", "/tools/csv", &body)
+			req.Header.Set("Content-Type", writer.FormDataContentType())
+			rr := httptest.NewRecorder()
+			app.csvTool(rr, req)
+
+			if rr.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rr.Code, tt.wantStatus, rr.Body.String())
+			}
+			for _, want := range tt.wantBody {
+				if !strings.Contains(rr.Body.String(), want) {
+					t.Errorf("body does not contain %q", want)
+				}
+			}
+			for _, unwanted := range tt.notBody {
+				if strings.Contains(rr.Body.String(), unwanted) {
+					t.Errorf("body contains %q", unwanted)
+				}
+			}
+		})
+	}
+}
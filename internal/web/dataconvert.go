@@ -0,0 +1,78 @@
+package web
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/NickDiPreta1/toolhub/internal/tools/dataconvert"
+)
+
+type DataConvertData struct {
+	Error   string
+	Input   string
+	Output  string
+	From    string
+	To      string
+	Formats []dataconvert.Format
+}
+
+// dataConvert converts structured data between JSON, YAML, TOML, XML and CSV.
+func (app *Application) dataConvert(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		data := &templateData{
+			ToolData: &DataConvertData{
+				From:    string(dataconvert.JSON),
+				To:      string(dataconvert.YAML),
+				Formats: dataconvert.Formats,
+			},
+		}
+		app.render(w, http.StatusOK, "dataconvert.tmpl.html", data)
+		return
+
+	case http.MethodPost:
+		input := r.FormValue("input")
+		toolData := &DataConvertData{
+			Input:   input,
+			From:    r.FormValue("from"),
+			To:      r.FormValue("to"),
+			Formats: dataconvert.Formats,
+		}
+
+		if strings.TrimSpace(input) == "" {
+			toolData.Error = "Input cannot be empty."
+			app.render(w, http.StatusBadRequest, "dataconvert.tmpl.html", &templateData{ToolData: toolData})
+			return
+		}
+
+		from, err := dataconvert.ParseFormat(toolData.From)
+		if err != nil {
+			toolData.Error = err.Error()
+			app.render(w, http.StatusBadRequest, "dataconvert.tmpl.html", &templateData{ToolData: toolData})
+			return
+		}
+
+		to, err := dataconvert.ParseFormat(toolData.To)
+		if err != nil {
+			toolData.Error = err.Error()
+			app.render(w, http.StatusBadRequest, "dataconvert.tmpl.html", &templateData{ToolData: toolData})
+			return
+		}
+
+		output, err := dataconvert.Convert(input, from, to)
+		if err != nil {
+			toolData.Error = err.Error()
+			app.render(w, http.StatusBadRequest, "dataconvert.tmpl.html", &templateData{ToolData: toolData})
+			return
+		}
+
+		toolData.Output = output
+		app.render(w, http.StatusOK, "dataconvert.tmpl.html", &templateData{ToolData: toolData})
+		return
+
+	default:
+		w.Header().Set("Allow", http.MethodGet+", "+http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+}
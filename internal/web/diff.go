@@ -0,0 +1,173 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/NickDiPreta1/toolhub/internal/tools/textutil"
+)
+
+// maxDiffRequestBytes limits the size of a diff form submission.
+const maxDiffRequestBytes = 2 << 20
+
+type DiffData struct {
+	Error            string
+	Old              string
+	New              string
+	Mode             string
+	View             string
+	IgnoreWhitespace bool
+	IgnoreCase       bool
+	Compared         bool
+	Inserted         int
+	Deleted          int
+	Edits            []textutil.Edit
+	Lines            []DiffLine
+	Rows             []textutil.DiffRow
+	OldSegments      []textutil.Segment
+	NewSegments      []textutil.Segment
+	Patch            string
+}
+
+// diffTool compares two texts and shows the result side by side, inline or
+// as a unified diff that can be downloaded as a .patch file.
+func (app *Application) diffTool(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		data := &templateData{
+			ToolData: &DiffData{
+				Mode: string(textutil.DiffLines),
+				View: "side-by-side",
+			},
+		}
+		app.render(w, http.StatusOK, "diff.tmpl.html", data)
+		return
+
+	case http.MethodPost:
+		r.Body = http.MaxBytesReader(w, r.Body, maxDiffRequestBytes)
+		if err := r.ParseForm(); err != nil {
+			toolData := &DiffData{
+				Mode:  string(textutil.DiffLines),
+				View:  "side-by-side",
+				Error: "Input too large or invalid. Maximum size is 2MB.",
+			}
+			app.render(w, http.StatusBadRequest, "diff.tmpl.html", &templateData{ToolData: toolData})
+			return
+		}
+
+		// Browsers submit textarea contents with CRLF line endings.
+		toolData := &DiffData{
+			Old:              strings.ReplaceAll(r.FormValue("old"), "\r\n", "\n"),
+			New:              strings.ReplaceAll(r.FormValue("new"), "\r\n", "\n"),
+			Mode:             r.FormValue("mode"),
+			View:             r.FormValue("view"),
+			IgnoreWhitespace: r.FormValue("ignore_whitespace") == "on",
+			IgnoreCase:       r.FormValue("ignore_case") == "on",
+		}
+		if toolData.Mode == "" {
+			toolData.Mode = string(textutil.DiffLines)
+		}
+		if toolData.View == "" {
+			toolData.View = "side-by-side"
+		}
+
+		if toolData.Old == "" && toolData.New == "" {
+			toolData.Error = "Enter text on at least one side to compare."
+			app.render(w, http.StatusBadRequest, "diff.tmpl.html", &templateData{ToolData: toolData})
+			return
+		}
+
+		mode, err := textutil.ParseDiffMode(toolData.Mode)
+		if err != nil {
+			toolData.Error = err.Error()
+			app.render(w, http.StatusBadRequest, "diff.tmpl.html", &templateData{ToolData: toolData})
+			return
+		}
+
+		opts := textutil.DiffOptions{
+			Mode:             mode,
+			IgnoreWhitespace: toolData.IgnoreWhitespace,
+			IgnoreCase:       toolData.IgnoreCase,
+		}
+
+		// Patches are always line based, whatever mode is used for display.
+		lineOpts := opts
+		lineOpts.Mode = textutil.DiffLines
+
+		// Refuse inputs that could keep the request busy for a long time.
+		for _, o := range []textutil.DiffOptions{opts, lineOpts} {
+			if n := textutil.DiffSize(toolData.Old, toolData.New, o); n > textutil.MaxDiffTokens {
+				toolData.Error = fmt.Sprintf("The texts are too long to compare: %d tokens in %s mode, and the limit is %d.", n, o.Mode, textutil.MaxDiffTokens)
+				app.render(w, http.StatusBadRequest, "diff.tmpl.html", &templateData{ToolData: toolData})
+				return
+			}
+		}
+
+		lineEdits := textutil.Diff(toolData.Old, toolData.New, lineOpts)
+		toolData.Patch = textutil.Unified("a/text", "b/text", lineEdits, textutil.DefaultDiffContext)
+
+		if r.FormValue("action") == "download" {
+			if toolData.Patch == "" {
+				toolData.Error = "The texts are identical, so there is no patch to download."
+				app.render(w, http.StatusBadRequest, "diff.tmpl.html", &templateData{ToolData: toolData})
+				return
+			}
+			w.Header().Set("Content-Type", "text/x-patch; charset=utf-8")
+			w.Header().Set("Content-Disposition", `attachment; filename="diff.patch"`)
+			w.Write([]byte(toolData.Patch))
+			return
+		}
+
+		edits := lineEdits
+		if mode != textutil.DiffLines {
+			edits = textutil.Diff(toolData.Old, toolData.New, opts)
+		}
+		toolData.Compared = true
+		toolData.Edits = edits
+		toolData.Inserted, toolData.Deleted = textutil.DiffStats(edits)
+
+		switch {
+		case toolData.View == "side-by-side" && mode == textutil.DiffLines:
+			toolData.Rows = textutil.SideBySide(edits, opts)
+		case toolData.View == "side-by-side":
+			toolData.OldSegments, toolData.NewSegments = textutil.Segments(edits)
+		case toolData.View == "inline" && mode == textutil.DiffLines:
+			toolData.Lines = inlineLines(edits)
+		}
+
+		app.render(w, http.StatusOK, "diff.tmpl.html", &templateData{ToolData: toolData})
+		return
+
+	default:
+		w.Header().Set("Allow", http.MethodGet+", "+http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+}
+
+// DiffLine is one line of the inline view in line mode.
+type DiffLine struct {
+	Kind   string
+	Prefix string
+	Text   string
+}
+
+// inlineLines prefixes each line edit like a unified diff without hunks.
+func inlineLines(edits []textutil.Edit) []DiffLine {
+	prefixes := map[textutil.DiffOp]string{
+		textutil.OpEqual:  " ",
+		textutil.OpDelete: "-",
+		textutil.OpInsert: "+",
+	}
+
+	lines := make([]DiffLine, len(edits))
+	for i, e := range edits {
+		lines[i] = DiffLine{
+			Kind:   e.Op.String(),
+			Prefix: prefixes[e.Op],
+			Text:   strings.TrimRight(e.Text(), "\r\n"),
+		}
+	}
+	return lines
+}
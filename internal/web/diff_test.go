@@ -0,0 +1,78 @@
+package web
+
+import (
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestDiffTool(t *testing.T) {
+	originalWd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir("../.."); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(originalWd)
+
+	app, err := NewApplication(
+		log.New(os.Stdout, "TEST INFO: ", 0),
+		log.New(os.Stdout, "TEST ERROR: ", 0),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		form       url.Values
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "line diff",
+			form:       url.Values{"old": {"a\nb\n"}, "new": {"a\nc\n"}},
+			wantStatus: http.StatusOK,
+			wantBody:   ">+1</span>",
+		},
+		{
+			name:       "too many characters",
+			form:       url.Values{"old": {strings.Repeat("ab", 6000)}, "new": {strings.Repeat("ba", 6000)}, "mode": {"char"}},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   "24000 tokens in char mode",
+		},
+		{
+			name:       "too many lines for the patch",
+			form:       url.Values{"old": {strings.Repeat("\n", 15000)}, "new": {strings.Repeat("\n", 15000)}, "mode": {"word"}},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   "30000 tokens in line mode",
+		},
+		{
+			name:       "body over the limit",
+			form:       url.Values{"old": {strings.Repeat("x", maxDiffRequestBytes)}, "new": {"y"}},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   "Maximum size is 2MB",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/tools/diff", strings.NewReader(tt.form.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			rr := httptest.NewRecorder()
+			app.diffTool(rr, req)
+
+			if rr.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rr.Code, tt.wantStatus)
+			}
+			if !strings.Contains(rr.Body.String(), tt.wantBody) {
+				t.Errorf("body does not contain %q", tt.wantBody)
+			}
+		})
+	}
+}
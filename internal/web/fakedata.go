@@ -0,0 +1,161 @@
+package web
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/NickDiPreta1/toolhub/internal/tools/fakedata"
+)
+
+// maxPreviewRecords is the largest record batch shown on the page; bigger
+// batches are only available as a download.
+const maxPreviewRecords = 1000
+
+const defaultFakeTemplate = `id: seq
+uuid: uuid
+name: name
+email: email
+address: address
+signed_up: timestamp
+last_ip: ipv4
+plan: oneof(free, pro, team)`
+
+type FakeDataData struct {
+	Error          string
+	Kind           string
+	Unit           string
+	Count          string
+	StartWithLorem bool
+	Template       string
+	Format         string
+	Seed           string
+	FieldTypes     []fakedata.FieldType
+	Output         string
+}
+
+// fakeDataTool generates lorem ipsum text or fake records from a field
+// template. A blank seed picks a random one, which is shown with the result
+// so the output can be reproduced.
+func (app *Application) fakeDataTool(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		data := &templateData{
+			ToolData: &FakeDataData{
+				Kind:           "records",
+				Unit:           string(fakedata.LoremParagraphs),
+				Count:          "10",
+				StartWithLorem: true,
+				Template:       defaultFakeTemplate,
+				Format:         string(fakedata.FormatJSON),
+				FieldTypes:     fakedata.FieldTypes,
+			},
+		}
+		app.render(w, http.StatusOK, "fakedata.tmpl.html", data)
+		return
+
+	case http.MethodPost:
+		toolData := &FakeDataData{
+			Kind:           r.FormValue("kind"),
+			Unit:           r.FormValue("unit"),
+			Count:          strings.TrimSpace(r.FormValue("count")),
+			StartWithLorem: r.FormValue("start_lorem") == "on",
+			Template:       strings.ReplaceAll(r.FormValue("template"), "\r\n", "\n"),
+			Format:         r.FormValue("format"),
+			Seed:           strings.TrimSpace(r.FormValue("seed")),
+			FieldTypes:     fakedata.FieldTypes,
+		}
+		download := r.FormValue("action") == "download"
+
+		count, err := strconv.Atoi(toolData.Count)
+		if err != nil {
+			toolData.Error = "Count must be a whole number."
+			app.render(w, http.StatusBadRequest, "fakedata.tmpl.html", &templateData{ToolData: toolData})
+			return
+		}
+
+		seed := time.Now().UnixNano()
+		if toolData.Seed != "" {
+			if seed, err = strconv.ParseInt(toolData.Seed, 10, 64); err != nil {
+				toolData.Error = "Seed must be a whole number."
+				app.render(w, http.StatusBadRequest, "fakedata.tmpl.html", &templateData{ToolData: toolData})
+				return
+			}
+		}
+		toolData.Seed = strconv.FormatInt(seed, 10)
+
+		var output, filename, contentType string
+		switch toolData.Kind {
+		case "lorem":
+			output, err = fakedata.Lorem(fakedata.LoremOptions{
+				Unit:           fakedata.LoremUnit(toolData.Unit),
+				Count:          count,
+				Seed:           seed,
+				StartWithLorem: toolData.StartWithLorem,
+			})
+			filename, contentType = "lorem.txt", "text/plain; charset=utf-8"
+
+		case "records":
+			if count > maxPreviewRecords && !download {
+				err = fmt.Errorf("only %d records can be shown on the page; use Download for larger batches", maxPreviewRecords)
+				break
+			}
+			output, err = generateRecords(r, toolData, count, seed)
+			switch fakedata.Format(toolData.Format) {
+			case fakedata.FormatCSV:
+				filename, contentType = "data.csv", "text/csv; charset=utf-8"
+			case fakedata.FormatNDJSON:
+				filename, contentType = "data.ndjson", "application/x-ndjson"
+			default:
+				filename, contentType = "data.json", "application/json"
+			}
+
+		default:
+			err = fmt.Errorf("unknown generator %q", toolData.Kind)
+		}
+		if err != nil {
+			toolData.Error = err.Error()
+			app.render(w, http.StatusBadRequest, "fakedata.tmpl.html", &templateData{ToolData: toolData})
+			return
+		}
+
+		if download {
+			w.Header().Set("Content-Type", contentType)
+			w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+			w.Write([]byte(output))
+			return
+		}
+
+		toolData.Output = output
+		app.render(w, http.StatusOK, "fakedata.tmpl.html", &templateData{ToolData: toolData})
+		return
+
+	default:
+		w.Header().Set("Allow", http.MethodGet+", "+http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+}
+
+func generateRecords(r *http.Request, d *FakeDataData, count int, seed int64) (string, error) {
+	fields, err := fakedata.ParseTemplate(d.Template)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	err = fakedata.Generate(r.Context(), &buf, fields, fakedata.Options{
+		Count:   count,
+		Seed:    seed,
+		Format:  fakedata.Format(d.Format),
+		Workers: runtime.NumCPU(),
+	})
+	if err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
@@ -1,15 +1,28 @@
 package web
 
 import (
+	"bufio"
 	"io"
+	"mime"
+	"mime/multipart"
 	"net/http"
 	"path/filepath"
+	"strconv"
+	"strings"
 
 	"github.com/NickDiPreta1/toolhub/internal/tools/fileconvert"
 )
 
+// maxConvertUploadSize caps the request body. Conversions stream, so memory
+// use does not grow with the upload.
+const maxConvertUploadSize = 512 << 20
+
 type FileConvertData struct {
-	Error string
+	Error    string
+	Mode     string
+	TabWidth string
+	Charset  string
+	Modes    []fileconvert.ModeOption
 }
 
 // fileConvert handles file upload, conversion, and download.
@@ -17,62 +30,118 @@ func (app *Application) fileConvert(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
 	case http.MethodGet:
 		data := &templateData{
-			ToolData: &FileConvertData{},
+			ToolData: &FileConvertData{
+				Mode:     string(fileconvert.ModeUpper),
+				TabWidth: strconv.Itoa(fileconvert.DefaultTabWidth),
+				Modes:    fileconvert.Modes,
+			},
 		}
 		app.render(w, http.StatusOK, "fileconvert.tmpl.html", data)
 	case http.MethodPost:
-		const maxUploadSize = 2 * 1024 * 1024
-		r.Body = http.MaxBytesReader(w, r.Body, maxUploadSize)
-
-		// Limit total form data to keep memory usage predictable.
-		if err := r.ParseMultipartForm(maxUploadSize); err != nil {
-			data := &templateData{
-				ToolData: &FileConvertData{
-					Error: "File too large or invalid upload. Maximum size is 2MB.",
-				},
-			}
-			app.render(w, http.StatusBadRequest, "fileconvert.tmpl.html", data)
-			return
+		r.Body = http.MaxBytesReader(w, r.Body, maxConvertUploadSize)
+
+		toolData := &FileConvertData{
+			Mode:     string(fileconvert.ModeUpper),
+			TabWidth: strconv.Itoa(fileconvert.DefaultTabWidth),
+			Modes:    fileconvert.Modes,
 		}
 
-		file, header, err := r.FormFile("file")
+		// Read the multipart body part by part instead of parsing the whole
+		// form, so the file is converted as it arrives and never buffered.
+		// The options must come before the file, as they do in the form.
+		mr, err := r.MultipartReader()
 		if err != nil {
-			data := &templateData{
-				ToolData: &FileConvertData{
-					Error: "Please choose a file to convert.",
-				},
-			}
-			app.render(w, http.StatusBadRequest, "fileconvert.tmpl.html", data)
+			toolData.Error = "Invalid upload."
+			app.render(w, http.StatusBadRequest, "fileconvert.tmpl.html", &templateData{ToolData: toolData})
 			return
 		}
-		defer file.Close()
-
-		ext := filepath.Ext(header.Filename)
-		if ext != ".txt" {
-			data := &templateData{
-				ToolData: &FileConvertData{
-					Error: "Only .txt files are supported right now.",
-				},
+
+		var file *multipart.Part
+		for file == nil {
+			part, err := mr.NextPart()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				toolData.Error = "File too large or invalid upload. Maximum size is 512MB."
+				app.render(w, http.StatusBadRequest, "fileconvert.tmpl.html", &templateData{ToolData: toolData})
+				return
+			}
+
+			if part.FormName() == "file" {
+				file = part
+				continue
+			}
+
+			value, err := io.ReadAll(io.LimitReader(part, 1024))
+			if err != nil {
+				toolData.Error = "Invalid upload."
+				app.render(w, http.StatusBadRequest, "fileconvert.tmpl.html", &templateData{ToolData: toolData})
+				return
 			}
-			app.render(w, http.StatusBadRequest, "fileconvert.tmpl.html", data)
+			switch v := strings.TrimSpace(string(value)); part.FormName() {
+			case "mode":
+				if v != "" {
+					toolData.Mode = v
+				}
+			case "tab_width":
+				if v != "" {
+					toolData.TabWidth = v
+				}
+			case "charset":
+				toolData.Charset = v
+			}
+		}
+
+		if file == nil || file.FileName() == "" {
+			toolData.Error = "Please choose a file to convert."
+			app.render(w, http.StatusBadRequest, "fileconvert.tmpl.html", &templateData{ToolData: toolData})
+			return
+		}
+
+		tabWidth, err := strconv.Atoi(toolData.TabWidth)
+		if err != nil {
+			toolData.Error = "Tab width must be a whole number."
+			app.render(w, http.StatusBadRequest, "fileconvert.tmpl.html", &templateData{ToolData: toolData})
 			return
 		}
 
-		mode := r.FormValue("mode")
-		if mode == "" {
-			mode = "uppercase"
+		// Sniff the content rather than trusting the extension, so any
+		// text file can be converted but binaries are refused.
+		br := bufio.NewReader(file)
+		sample, err := br.Peek(512)
+		if err != nil && err != io.EOF {
+			toolData.Error = "File too large or invalid upload. Maximum size is 512MB."
+			app.render(w, http.StatusBadRequest, "fileconvert.tmpl.html", &templateData{ToolData: toolData})
+			return
+		}
+		if !fileconvert.IsText(sample) {
+			toolData.Error = "This does not look like a text file."
+			app.render(w, http.StatusBadRequest, "fileconvert.tmpl.html", &templateData{ToolData: toolData})
+			return
 		}
+		// Only transcoding produces UTF-8; the other modes keep the source
+		// charset. The sample is only valid until br is read again.
+		charset := fileconvert.OutputCharset(sample, err == io.EOF, fileconvert.Mode(toolData.Mode))
 
-		converted, err := fileconvert.ToUpperText(file)
+		converted, err := fileconvert.Convert(br, fileconvert.Options{
+			Mode:     fileconvert.Mode(toolData.Mode),
+			TabWidth: tabWidth,
+			Charset:  toolData.Charset,
+		})
 		if err != nil {
-			app.serverError(w, err)
+			toolData.Error = err.Error()
+			app.render(w, http.StatusBadRequest, "fileconvert.tmpl.html", &templateData{ToolData: toolData})
 			return
 		}
 
-		w.Header().Set("Content-Type", "text/plain")
-		w.Header().Set("Content-Disposition", `attachment; filename="converted.txt"`)
+		filename := "converted-" + filepath.Base(file.FileName())
+		w.Header().Set("Content-Type", mime.FormatMediaType("text/plain", map[string]string{"charset": charset}))
+		w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
 		w.WriteHeader(http.StatusOK)
 
+		// Headers are sent by now, so a failure part way through (such as
+		// the upload exceeding the limit) can only be logged.
 		_, err = io.Copy(w, converted)
 		if err != nil {
 			app.errorLog.Printf("error sending converted file: %v", err)
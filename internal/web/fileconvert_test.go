@@ -0,0 +1,195 @@
+package web
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+// fileConvertRequest streams a multipart body with the given fields in order
+// followed by the file, as a browser sends the form.
+func fileConvertRequest(t *testing.T, fields [][2]string, filename string, content io.Reader) *http.Request {
+	t.Helper()
+
+	pr, pw := io.Pipe()
+	writer := multipart.NewWriter(pw)
+	go func() {
+		for _, f := range fields {
+			writer.WriteField(f[0], f[1])
+		}
+		if filename != "" {
+			part, err := writer.CreateFormFile("file", filename)
+			if err != nil {
+				pw.CloseWithError(err)
+				return
+			}
+			if _, err := io.Copy(part, content); err != nil {
+				pw.CloseWithError(err)
+				return
+			}
+		}
+		pw.CloseWithError(writer.Close())
+	}()
+
+	req := httptest.NewRequest("POST", "/tools/fileconvert", pr)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return req
+}
+
+func TestFileConvert(t *testing.T) {
+	originalWd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir("../.."); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(originalWd)
+
+	app, err := NewApplication(
+		log.New(os.Stdout, "TEST INFO: ", 0),
+		log.New(os.Stdout, "TEST ERROR: ", 0),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		fields     [][2]string
+		filename   string
+		content    string
+		wantStatus int
+		wantBody   string
+		wantType   string
+	}{
+		{
+			name:       "uppercase by default",
+			filename:   "notes.md",
+			content:    "hello",
+			wantStatus: http.StatusOK,
+			wantBody:   "HELLO",
+			wantType:   "text/plain; charset=utf-8",
+		},
+		{
+			name:       "utf-16 keeps its charset",
+			fields:     [][2]string{{"mode", "crlf-to-lf"}},
+			filename:   "utf16.txt",
+			content:    "\xff\xfea\x00\r\x00\n\x00b\x00",
+			wantStatus: http.StatusOK,
+			wantBody:   "\xff\xfea\x00\n\x00b\x00",
+			wantType:   "text/plain; charset=utf-16le",
+		},
+		{
+			name:       "windows-1252 keeps its charset",
+			fields:     [][2]string{{"mode", "upper"}},
+			filename:   "cp1252.txt",
+			content:    "caf\xe9",
+			wantStatus: http.StatusOK,
+			wantBody:   "CAF\xe9",
+			wantType:   "text/plain; charset=windows-1252",
+		},
+		{
+			name:       "selected mode",
+			fields:     [][2]string{{"mode", "crlf-to-lf"}},
+			filename:   "data.csv",
+			content:    "a,b\r\nc,d\r\n",
+			wantStatus: http.StatusOK,
+			wantBody:   "a,b\nc,d\n",
+		},
+		{
+			name:       "transcode",
+			fields:     [][2]string{{"mode", "to-utf8"}, {"charset", "iso-8859-1"}},
+			filename:   "latin1.txt",
+			content:    "caf\xe9",
+			wantStatus: http.StatusOK,
+			wantBody:   "café",
+			wantType:   "text/plain; charset=utf-8",
+		},
+		{
+			name:       "binary rejected",
+			filename:   "image.png",
+			content:    "\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR",
+			wantStatus: http.StatusBadRequest,
+			wantBody:   "does not look like a text file",
+		},
+		{
+			name:       "unknown mode",
+			fields:     [][2]string{{"mode", "rot13"}},
+			filename:   "a.txt",
+			content:    "x",
+			wantStatus: http.StatusBadRequest,
+			wantBody:   "unsupported mode",
+		},
+		{
+			name:       "no file",
+			fields:     [][2]string{{"mode", "upper"}},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   "Please choose a file",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := fileConvertRequest(t, tt.fields, tt.filename, strings.NewReader(tt.content))
+			rr := httptest.NewRecorder()
+			app.fileConvert(rr, req)
+
+			if rr.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d; body: %s", rr.Code, tt.wantStatus, rr.Body.String())
+			}
+			if tt.wantStatus == http.StatusOK {
+				if rr.Body.String() != tt.wantBody {
+					t.Errorf("body = %q, want %q", rr.Body.String(), tt.wantBody)
+				}
+				if cd := rr.Header().Get("Content-Disposition"); !strings.Contains(cd, "converted-"+tt.filename) {
+					t.Errorf("Content-Disposition = %q", cd)
+				}
+				if ct := rr.Header().Get("Content-Type"); tt.wantType != "" && ct != tt.wantType {
+					t.Errorf("Content-Type = %q, want %q", ct, tt.wantType)
+				}
+			} else if !strings.Contains(rr.Body.String(), tt.wantBody) {
+				t.Errorf("body does not contain %q", tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestFileConvertLargeUpload(t *testing.T) {
+	originalWd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir("../.."); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(originalWd)
+
+	app, err := NewApplication(
+		log.New(os.Stdout, "TEST INFO: ", 0),
+		log.New(os.Stdout, "TEST ERROR: ", 0),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Well past the old 2MB limit.
+	line := "the quick brown fox\n"
+	const lines = 1 << 20
+	req := fileConvertRequest(t, [][2]string{{"mode", "upper"}}, "big.txt", strings.NewReader(strings.Repeat(line, lines)))
+	rr := httptest.NewRecorder()
+	app.fileConvert(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rr.Code)
+	}
+	if want := bytes.Repeat([]byte("THE QUICK BROWN FOX\n"), lines); !bytes.Equal(rr.Body.Bytes(), want) {
+		t.Errorf("body has %d bytes, want %d", rr.Body.Len(), len(want))
+	}
+}
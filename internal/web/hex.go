@@ -0,0 +1,261 @@
+package web
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/NickDiPreta1/toolhub/internal/tools/fileinfo"
+)
+
+const (
+	maxHexFileSize = 64 << 20
+	// maxHexRequestSize leaves room for two uploads and the other fields.
+	maxHexRequestSize = 2*maxHexFileSize + 1<<20
+	// Uploads are kept on the server between page turns, up to
+	// maxHexStoreSize in total and for hexUploadTTL after their last use.
+	maxHexStoreSize = 256 << 20
+	hexUploadTTL    = 30 * time.Minute
+	hexPageSize     = 1024
+	maxHexResults   = 200
+)
+
+type HexData struct {
+	Error string
+	// Name and Token identify the first file; Token refers to the copy kept
+	// on the server, so paging works without uploading it again.
+	Name   string
+	Token  string
+	Size   int64
+	NameB  string
+	TokenB string
+	SizeB  int64
+	Offset string
+	Search string
+	Mode   string
+
+	Compare bool
+	Start   int64
+	Page    int64
+	Pages   int64
+	// PrevStart and NextStart are only meaningful with HasPrev and HasNext.
+	HasPrev   bool
+	PrevStart int64
+	HasNext   bool
+	NextStart int64
+	LastStart int64
+	Rows      []fileinfo.HexRow
+	RowsB     []fileinfo.HexRow
+
+	Searched   bool
+	Matches    []fileinfo.Range
+	MatchTotal int
+	Diffs      []fileinfo.Range
+	DiffTotal  int
+	DiffBytes  int64
+}
+
+// hexTool shows uploaded files as a paged hexdump with offset jumps, byte
+// pattern search and, given two files, highlighted differences.
+func (app *Application) hexTool(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		data := &templateData{
+			ToolData: &HexData{Mode: string(fileinfo.PatternText)},
+		}
+		app.render(w, http.StatusOK, "hex.tmpl.html", data)
+		return
+
+	case http.MethodPost:
+		r.Body = http.MaxBytesReader(w, r.Body, maxHexRequestSize)
+
+		toolData := &HexData{Mode: string(fileinfo.PatternText)}
+
+		if err := r.ParseMultipartForm(32 << 20); err != nil {
+			toolData.Error = "File too large or invalid upload. Maximum size is 64MB per file."
+			app.render(w, http.StatusBadRequest, "hex.tmpl.html", &templateData{ToolData: toolData})
+			return
+		}
+		defer r.MultipartForm.RemoveAll()
+
+		toolData.Offset = strings.TrimSpace(r.FormValue("offset"))
+		toolData.Search = r.FormValue("search")
+		if mode := r.FormValue("mode"); mode != "" {
+			toolData.Mode = mode
+		}
+
+		a, name, token, err := app.hexFile(r, "file", "token")
+		if err != nil {
+			toolData.Error = err.Error()
+			app.render(w, http.StatusBadRequest, "hex.tmpl.html", &templateData{ToolData: toolData})
+			return
+		}
+		b, nameB, tokenB, err := app.hexFile(r, "file_b", "token_b")
+		if err != nil {
+			toolData.Error = err.Error()
+			app.render(w, http.StatusBadRequest, "hex.tmpl.html", &templateData{ToolData: toolData})
+			return
+		}
+		if r.FormValue("clear_b") != "" {
+			b, nameB, tokenB = nil, "", ""
+		}
+		if a == nil {
+			toolData.Error = "Please choose a file."
+			app.render(w, http.StatusBadRequest, "hex.tmpl.html", &templateData{ToolData: toolData})
+			return
+		}
+
+		toolData.Name, toolData.Token, toolData.Size = name, token, int64(len(a))
+		if b != nil {
+			toolData.Compare = true
+			toolData.NameB, toolData.TokenB, toolData.SizeB = nameB, tokenB, int64(len(b))
+		}
+
+		if err := runHexView(toolData, a, b, r.FormValue("action"), r.FormValue("start"), r.FormValue("goto")); err != nil {
+			toolData.Error = err.Error()
+			app.render(w, http.StatusBadRequest, "hex.tmpl.html", &templateData{ToolData: toolData})
+			return
+		}
+
+		app.render(w, http.StatusOK, "hex.tmpl.html", &templateData{ToolData: toolData})
+		return
+
+	default:
+		w.Header().Set("Allow", http.MethodGet+", "+http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+}
+
+// hexFile returns the file uploaded as field, keeping it in app.hexUploads,
+// or else the stored file named by the token in tokenField. It returns nil
+// when neither was sent.
+func (app *Application) hexFile(r *http.Request, field, tokenField string) (data []byte, name, token string, err error) {
+	file, header, err := r.FormFile(field)
+	if err == nil {
+		defer file.Close()
+		if header.Size > maxHexFileSize {
+			return nil, "", "", fmt.Errorf("%s is larger than 64MB", header.Filename)
+		}
+		data, err = io.ReadAll(file)
+		if err != nil {
+			return nil, "", "", err
+		}
+		name = filepath.Base(header.Filename)
+		return data, name, app.hexUploads.put(name, data), nil
+	}
+
+	token = r.FormValue(tokenField)
+	if token == "" {
+		return nil, "", "", nil
+	}
+	name, data, ok := app.hexUploads.get(token)
+	if !ok {
+		return nil, "", "", errors.New("the previous file is no longer available; please upload it again")
+	}
+	return data, name, token, nil
+}
+
+// runHexView searches and compares the files and lays out the page chosen
+// by a paging button (start), a result link (target), or the action: an
+// offset jump or the first search match or difference. Jump is the first
+// button in the form, so pressing Enter with no offset just shows the file.
+func runHexView(toolData *HexData, a, b []byte, action, start, target string) error {
+	var pattern []byte
+	if toolData.Search != "" {
+		var err error
+		pattern, err = fileinfo.ParsePattern(toolData.Search, fileinfo.PatternMode(toolData.Mode))
+		if err != nil {
+			return err
+		}
+		toolData.Matches, toolData.MatchTotal = fileinfo.FindAll(a, pattern, maxHexResults)
+		toolData.Searched = true
+	}
+	if toolData.Compare {
+		toolData.Diffs, toolData.DiffTotal, toolData.DiffBytes = fileinfo.DiffRanges(a, b, maxHexResults)
+	}
+
+	size := max(toolData.Size, toolData.SizeB)
+	cursor := int64(-1)
+	switch {
+	case start != "":
+		off, err := fileinfo.ParseOffset(start)
+		if err != nil {
+			return err
+		}
+		toolData.Start = off
+	case target != "":
+		off, err := fileinfo.ParseOffset(target)
+		if err != nil {
+			return err
+		}
+		cursor = off
+	case action == "jump" && toolData.Offset != "":
+		off, err := fileinfo.ParseOffset(toolData.Offset)
+		if err != nil {
+			return err
+		}
+		if off >= size {
+			return fmt.Errorf("offset %d is past the end of the file (%d bytes)", off, size)
+		}
+		cursor = off
+	case action == "search" && len(toolData.Matches) > 0:
+		cursor = toolData.Matches[0].Start
+	case action == "diff" && len(toolData.Diffs) > 0:
+		cursor = toolData.Diffs[0].Start
+	}
+	if cursor >= 0 {
+		toolData.Start = cursor
+	}
+
+	// Pages start on a row boundary, and a start past the end shows the
+	// last page.
+	toolData.LastStart = max(size-1, 0) / hexPageSize * hexPageSize
+	toolData.Start = fileinfo.RowStart(min(toolData.Start, max(size-1, 0)))
+	end := min(toolData.Start+hexPageSize, size)
+	toolData.Page = toolData.Start/hexPageSize + 1
+	toolData.Pages = max((size+hexPageSize-1)/hexPageSize, 1)
+	if toolData.Start > 0 {
+		toolData.HasPrev, toolData.PrevStart = true, max(toolData.Start-hexPageSize, 0)
+	}
+	if end < size {
+		toolData.HasNext, toolData.NextStart = true, end
+	}
+
+	pageA := window(a, toolData.Start, end)
+	marks := fileinfo.Marks{Cursor: cursor}
+	if len(pattern) > 0 {
+		// Search just around the page so matches beyond the listed ones
+		// are still highlighted.
+		lo := max(toolData.Start-int64(len(pattern))+1, 0)
+		around := window(a, lo, end+int64(len(pattern))-1)
+		found, _ := fileinfo.FindAll(around, pattern, hexPageSize)
+		marks.Matches = shiftRanges(found, lo)
+	}
+	if toolData.Compare {
+		pageB := window(b, toolData.Start, end)
+		diffs, _, _ := fileinfo.DiffRanges(pageA, pageB, hexPageSize)
+		marks.Diffs = shiftRanges(diffs, toolData.Start)
+		toolData.RowsB = fileinfo.HexRows(pageB, toolData.Start, fileinfo.Marks{Diffs: marks.Diffs, Cursor: cursor})
+	}
+	toolData.Rows = fileinfo.HexRows(pageA, toolData.Start, marks)
+	return nil
+}
+
+// window returns data[start:end], clamped to the length of data.
+func window(data []byte, start, end int64) []byte {
+	n := int64(len(data))
+	return data[min(start, n):min(end, n)]
+}
+
+func shiftRanges(ranges []fileinfo.Range, by int64) []fileinfo.Range {
+	for i := range ranges {
+		ranges[i].Start += by
+		ranges[i].End += by
+	}
+	return ranges
+}
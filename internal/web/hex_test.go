@@ -0,0 +1,245 @@
+package web
+
+import (
+	"bytes"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHexTool(t *testing.T) {
+	originalWd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir("../.."); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(originalWd)
+
+	app, err := NewApplication(
+		log.New(os.Stdout, "TEST INFO: ", 0),
+		log.New(os.Stdout, "TEST ERROR: ", 0),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Three pages, with a marker near the end of the last one.
+	large := bytes.Repeat([]byte("0123456789abcdef"), 3*hexPageSize/16)
+	copy(large[len(large)-32:], "NEEDLE")
+	modified := bytes.Clone(large)
+	modified[5] = 'X'
+	modified = append(modified, "tail"...)
+
+	big := app.hexUploads.put("big.bin", large)
+	fileA := app.hexUploads.put("a.bin", large)
+	fileB := app.hexUploads.put("b.bin", modified)
+	hello := app.hexUploads.put("hello.txt", []byte("hello\n"))
+
+	tests := []struct {
+		name       string
+		fields     [][2]string
+		files      [][2]string
+		wantStatus int
+		wantBody   []string
+		notInBody  []string
+	}{
+		{
+			name:       "first page of an upload",
+			files:      [][2]string{{"file", "hello.txt"}},
+			wantStatus: http.StatusOK,
+			wantBody: []string{
+				"00000000  68 65 6c 6c 6f 0a                                 |hello.|",
+				"Showing hello.txt (6 bytes)",
+				`name="token" value="`,
+				"Page 1 of 1",
+			},
+		},
+		{
+			name:       "paging an echoed file",
+			fields:     [][2]string{{"token", big}, {"start", "1024"}},
+			wantStatus: http.StatusOK,
+			wantBody:   []string{"Offset 0x00000400", "Page 2 of 3", `name="start" value="0"`, `name="start" value="2048"`, "00000400  30 31"},
+			notInBody:  []string{"000003f0"},
+		},
+		{
+			name:       "jump to a hex offset",
+			fields:     [][2]string{{"token", big}, {"action", "jump"}, {"offset", "0x805"}},
+			wantStatus: http.StatusOK,
+			wantBody:   []string{"Offset 0x00000800", `<span style="background: #222; color: white;">35</span>`},
+		},
+		{
+			name:       "jump past the end",
+			fields:     [][2]string{{"token", fileA}, {"action", "jump"}, {"offset", "999999"}},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   []string{"past the end of the file"},
+		},
+		{
+			name:       "search for text",
+			fields:     [][2]string{{"token", big}, {"action", "search"}, {"search", "NEEDLE"}},
+			wantStatus: http.StatusOK,
+			wantBody:   []string{"1 matches in big.bin", "0x00000be0", "Offset 0x00000be0", "Page 3 of 3", `<mark style="background: #ffe58f;">45</mark>`},
+		},
+		{
+			name:       "search for hex bytes",
+			fields:     [][2]string{{"token", fileA}, {"action", "search"}, {"search", "65 66 30"}, {"mode", "hex"}},
+			wantStatus: http.StatusOK,
+			wantBody:   []string{"190 matches", `<mark style="background: #ffe58f;">30</mark>`},
+			notInBody:  []string{"are listed"},
+		},
+		{
+			name:       "invalid hex pattern",
+			fields:     [][2]string{{"token", fileA}, {"search", "zz"}, {"mode", "hex"}},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   []string{"invalid hex pattern"},
+		},
+		{
+			name:       "compare two files",
+			fields:     [][2]string{{"token", fileA}, {"token_b", fileB}},
+			wantStatus: http.StatusOK,
+			wantBody:   []string{"5 bytes differ in 2 ranges", "0x00000005", "0x00000c00-0x00000c03 (4 bytes)", `<span style="background: #ffc1bd;">58</span>`, "Comparing with b.bin"},
+		},
+		{
+			name:       "jump to a difference",
+			fields:     [][2]string{{"token", fileA}, {"token_b", fileB}, {"goto", "3072"}},
+			wantStatus: http.StatusOK,
+			wantBody:   []string{"Offset 0x00000c00", `00000c00  <span style="background: #222; color: white;">74</span>`, "(end of file)"},
+		},
+		{
+			name:       "stop comparing",
+			fields:     [][2]string{{"token", fileA}, {"token_b", fileB}, {"clear_b", "on"}},
+			wantStatus: http.StatusOK,
+			notInBody:  []string{"Differences", "Comparing with"},
+		},
+		{
+			name:       "identical files",
+			fields:     [][2]string{{"token", hello}, {"token_b", hello}},
+			wantStatus: http.StatusOK,
+			wantBody:   []string{"The files are identical."},
+		},
+		{
+			name:       "unknown token",
+			fields:     [][2]string{{"token", "expired"}},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   []string{"please upload it again"},
+		},
+		{
+			name:       "no file",
+			fields:     [][2]string{{"action", "view"}},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   []string{"Please choose a file."},
+		},
+	}
+
+	post := func(t *testing.T, fields, files [][2]string) *httptest.ResponseRecorder {
+		t.Helper()
+		var body bytes.Buffer
+		writer := multipart.NewWriter(&body)
+		for _, f := range fields {
+			writer.WriteField(f[0], f[1])
+		}
+		for _, f := range files {
+			part, err := writer.CreateFormFile(f[0], f[1])
+			if err != nil {
+				t.Fatal(err)
+			}
+			part.Write([]byte("hello\n"))
+		}
+		writer.Close()
+
+		req := httptest.NewRequest("POST", "/tools/hex", &body)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		rr := httptest.NewRecorder()
+		app.hexTool(rr, req)
+		return rr
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := post(t, tt.fields, tt.files)
+
+			if rr.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rr.Code, tt.wantStatus, rr.Body.String())
+			}
+			for _, want := range tt.wantBody {
+				if !strings.Contains(rr.Body.String(), want) {
+					t.Errorf("body does not contain %q", want)
+				}
+			}
+			for _, unwanted := range tt.notInBody {
+				if strings.Contains(rr.Body.String(), unwanted) {
+					t.Errorf("body contains %q", unwanted)
+				}
+			}
+		})
+	}
+
+	t.Run("token from an upload", func(t *testing.T) {
+		// The page refers back to the upload by token instead of
+		// carrying its content.
+		rr := post(t, nil, [][2]string{{"file", "hello.txt"}})
+		m := regexp.MustCompile(`name="token" value="([^"]+)"`).FindStringSubmatch(rr.Body.String())
+		if m == nil {
+			t.Fatal("no token in the page")
+		}
+		if strings.Contains(rr.Body.String(), "aGVsbG8K") {
+			t.Error("page contains the file content")
+		}
+
+		rr = post(t, [][2]string{{"token", m[1]}, {"action", "view"}}, nil)
+		if rr.Code != http.StatusOK || !strings.Contains(rr.Body.String(), "Showing hello.txt (6 bytes)") {
+			t.Errorf("status = %d, want the stored file shown", rr.Code)
+		}
+	})
+
+	t.Run("method not allowed", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		app.hexTool(rr, httptest.NewRequest("PUT", "/tools/hex", nil))
+		if rr.Code != http.StatusMethodNotAllowed {
+			t.Errorf("status = %d, want 405", rr.Code)
+		}
+	})
+}
+
+func TestUploadStore(t *testing.T) {
+	s := newUploadStore(10, time.Hour)
+
+	a := s.put("a", []byte("aaaa"))
+	b := s.put("b", []byte("bbbb"))
+	if name, data, ok := s.get(a); !ok || name != "a" || string(data) != "aaaa" {
+		t.Fatalf("get(a) = %q, %q, %v", name, data, ok)
+	}
+
+	// a was used more recently than b, so b goes when c needs the room.
+	c := s.put("c", []byte("cccc"))
+	if _, _, ok := s.get(b); ok {
+		t.Error("b was kept past the size limit")
+	}
+	for _, token := range []string{a, c} {
+		if _, _, ok := s.get(token); !ok {
+			t.Errorf("%s was evicted", token)
+		}
+	}
+
+	// A file larger than the limit is still kept until the next put.
+	big := s.put("big", []byte("0123456789abcdef"))
+	if _, _, ok := s.get(big); !ok {
+		t.Error("the newest upload was evicted")
+	}
+	if s.size != 16 {
+		t.Errorf("size = %d, want 16", s.size)
+	}
+
+	s.ttl = 0
+	time.Sleep(time.Millisecond)
+	if _, _, ok := s.get(big); ok {
+		t.Error("expired upload was returned")
+	}
+}
@@ -0,0 +1,120 @@
+package web
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/NickDiPreta1/toolhub/internal/tools/idgen"
+)
+
+type IDsData struct {
+	Error    string
+	Action   string
+	Kind     string
+	Count    string
+	Size     string
+	Alphabet string
+	Kinds    []idgen.KindOption
+	Output   string
+	Input    string
+	Decoded  []DecodedID
+}
+
+// DecodedID is the result of decoding one input line.
+type DecodedID struct {
+	Input string
+	ID    *idgen.Decoded
+	Error string
+}
+
+// idsTool generates identifiers in bulk or decodes existing ones, one per
+// line.
+func (app *Application) idsTool(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		data := &templateData{
+			ToolData: &IDsData{
+				Action: "generate",
+				Kind:   string(idgen.KindUUIDv7),
+				Count:  "10",
+				Size:   strconv.Itoa(idgen.DefaultNanoIDSize),
+				Kinds:  idgen.Kinds,
+			},
+		}
+		app.render(w, http.StatusOK, "ids.tmpl.html", data)
+		return
+
+	case http.MethodPost:
+		toolData := &IDsData{
+			Action:   r.FormValue("action"),
+			Kind:     r.FormValue("kind"),
+			Count:    strings.TrimSpace(r.FormValue("count")),
+			Size:     strings.TrimSpace(r.FormValue("size")),
+			Alphabet: r.FormValue("alphabet"),
+			Kinds:    idgen.Kinds,
+			Input:    r.FormValue("input"),
+		}
+
+		switch toolData.Action {
+		case "generate":
+			count, err := strconv.Atoi(toolData.Count)
+			if err != nil {
+				toolData.Error = "Count must be a whole number."
+				app.render(w, http.StatusBadRequest, "ids.tmpl.html", &templateData{ToolData: toolData})
+				return
+			}
+			size := 0
+			if toolData.Size != "" {
+				if size, err = strconv.Atoi(toolData.Size); err != nil {
+					toolData.Error = "Size must be a whole number."
+					app.render(w, http.StatusBadRequest, "ids.tmpl.html", &templateData{ToolData: toolData})
+					return
+				}
+			}
+
+			ids, err := idgen.Generate(idgen.Options{
+				Kind:     idgen.Kind(toolData.Kind),
+				Count:    count,
+				Size:     size,
+				Alphabet: toolData.Alphabet,
+			})
+			if err != nil {
+				toolData.Error = err.Error()
+				app.render(w, http.StatusBadRequest, "ids.tmpl.html", &templateData{ToolData: toolData})
+				return
+			}
+			toolData.Output = strings.Join(ids, "\n")
+
+		case "decode":
+			lines := splitLines(toolData.Input)
+			if len(lines) == 0 {
+				toolData.Error = "Enter at least one identifier to decode."
+				app.render(w, http.StatusBadRequest, "ids.tmpl.html", &templateData{ToolData: toolData})
+				return
+			}
+			for _, line := range lines {
+				d := DecodedID{Input: line}
+				if id, err := idgen.Decode(line); err != nil {
+					d.Error = err.Error()
+				} else {
+					d.ID = id
+				}
+				toolData.Decoded = append(toolData.Decoded, d)
+			}
+
+		default:
+			toolData.Error = "Unknown action: " + toolData.Action
+			app.render(w, http.StatusBadRequest, "ids.tmpl.html", &templateData{ToolData: toolData})
+			return
+		}
+
+		app.render(w, http.StatusOK, "ids.tmpl.html", &templateData{ToolData: toolData})
+		return
+
+	default:
+		w.Header().Set("Allow", http.MethodGet+", "+http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+}
@@ -0,0 +1,224 @@
+package web
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/NickDiPreta1/toolhub/internal/tools/fileconvert"
+)
+
+// Batch uploads are decoded in memory, so they have a lower limit than the
+// streaming text converter.
+const (
+	maxImageUploadSize = 64 << 20
+	maxImageFiles      = 50
+)
+
+type ImagesData struct {
+	Error     string
+	Format    string
+	Formats   []fileconvert.ImageFormat
+	Quality   string
+	Resize    string
+	Width     string
+	Height    string
+	CropX     string
+	CropY     string
+	CropW     string
+	CropH     string
+	Rotate    string
+	Grayscale bool
+	Thumbnail string
+	// Failures lists the files that could not be converted when none were.
+	Failures []fileconvert.BatchResult
+}
+
+// imagesTool converts, resizes and edits uploaded images. A single image is
+// returned as is; several images, or any with thumbnails, come back as a zip.
+func (app *Application) imagesTool(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		data := &templateData{
+			ToolData: &ImagesData{
+				Formats: fileconvert.ImageFormats,
+				Quality: strconv.Itoa(fileconvert.DefaultJPEGQuality),
+				Rotate:  "0",
+			},
+		}
+		app.render(w, http.StatusOK, "images.tmpl.html", data)
+		return
+
+	case http.MethodPost:
+		r.Body = http.MaxBytesReader(w, r.Body, maxImageUploadSize)
+
+		toolData := &ImagesData{
+			Formats: fileconvert.ImageFormats,
+			Quality: strconv.Itoa(fileconvert.DefaultJPEGQuality),
+			Rotate:  "0",
+		}
+
+		if err := r.ParseMultipartForm(maxImageUploadSize); err != nil {
+			toolData.Error = "Upload too large or invalid. Maximum total size is 64MB."
+			app.render(w, http.StatusBadRequest, "images.tmpl.html", &templateData{ToolData: toolData})
+			return
+		}
+		defer r.MultipartForm.RemoveAll()
+
+		field := func(name string) string { return strings.TrimSpace(r.FormValue(name)) }
+		toolData.Format = field("format")
+		toolData.Quality = field("quality")
+		toolData.Resize = field("resize")
+		toolData.Width = field("width")
+		toolData.Height = field("height")
+		toolData.CropX = field("crop_x")
+		toolData.CropY = field("crop_y")
+		toolData.CropW = field("crop_w")
+		toolData.CropH = field("crop_h")
+		toolData.Rotate = field("rotate")
+		toolData.Grayscale = field("grayscale") != ""
+		toolData.Thumbnail = field("thumbnail")
+
+		opts, err := imageOptions(toolData)
+		if err != nil {
+			toolData.Error = err.Error()
+			app.render(w, http.StatusBadRequest, "images.tmpl.html", &templateData{ToolData: toolData})
+			return
+		}
+
+		headers := r.MultipartForm.File["files"]
+		if len(headers) == 0 {
+			toolData.Error = "Please choose at least one image."
+			app.render(w, http.StatusBadRequest, "images.tmpl.html", &templateData{ToolData: toolData})
+			return
+		}
+		if len(headers) > maxImageFiles {
+			toolData.Error = fmt.Sprintf("At most %d images can be converted at once.", maxImageFiles)
+			app.render(w, http.StatusBadRequest, "images.tmpl.html", &templateData{ToolData: toolData})
+			return
+		}
+
+		files := make([]fileconvert.ImageFile, 0, len(headers))
+		for _, fh := range headers {
+			f, err := fh.Open()
+			if err != nil {
+				app.serverError(w, err)
+				return
+			}
+			data, err := io.ReadAll(f)
+			f.Close()
+			if err != nil {
+				app.serverError(w, err)
+				return
+			}
+			files = append(files, fileconvert.ImageFile{Name: fh.Filename, Data: data})
+		}
+
+		if len(files) == 1 && opts.ThumbnailSize == 0 {
+			var buf bytes.Buffer
+			format, err := fileconvert.ProcessImage(bytes.NewReader(files[0].Data), &buf, opts.ImageOptions)
+			if err != nil {
+				toolData.Error = err.Error()
+				app.render(w, http.StatusBadRequest, "images.tmpl.html", &templateData{ToolData: toolData})
+				return
+			}
+
+			// Multipart file names are already reduced to their base name.
+			name := strings.TrimSuffix(files[0].Name, filepath.Ext(files[0].Name)) + format.Extension()
+			sendDownload(w, "image/"+string(format), name, buf.Bytes())
+			return
+		}
+
+		var buf bytes.Buffer
+		results, err := fileconvert.ProcessImages(r.Context(), files, opts, &buf)
+		if err != nil {
+			toolData.Error = err.Error()
+			app.render(w, http.StatusBadRequest, "images.tmpl.html", &templateData{ToolData: toolData})
+			return
+		}
+		failed := 0
+		for _, res := range results {
+			if res.Err != nil {
+				failed++
+			}
+		}
+		if failed == len(results) {
+			toolData.Error = "None of the images could be converted."
+			toolData.Failures = results
+			app.render(w, http.StatusBadRequest, "images.tmpl.html", &templateData{ToolData: toolData})
+			return
+		}
+
+		sendDownload(w, "application/zip", "images.zip", buf.Bytes())
+		return
+
+	default:
+		w.Header().Set("Allow", http.MethodGet+", "+http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+}
+
+// imageOptions parses the numeric form fields into batch options.
+func imageOptions(d *ImagesData) (fileconvert.BatchOptions, error) {
+	var opts fileconvert.BatchOptions
+	var crop [4]int
+	fields := []struct {
+		label string
+		value string
+		dst   *int
+	}{
+		{"quality", d.Quality, &opts.Quality},
+		{"width", d.Width, &opts.Width},
+		{"height", d.Height, &opts.Height},
+		{"crop x", d.CropX, &crop[0]},
+		{"crop y", d.CropY, &crop[1]},
+		{"crop width", d.CropW, &crop[2]},
+		{"crop height", d.CropH, &crop[3]},
+		{"rotation", d.Rotate, &opts.Rotate},
+		{"thumbnail size", d.Thumbnail, &opts.ThumbnailSize},
+	}
+	for _, f := range fields {
+		if f.value == "" {
+			continue
+		}
+		n, err := strconv.Atoi(f.value)
+		if err != nil || n < 0 {
+			return opts, fmt.Errorf("%s must be a whole number", f.label)
+		}
+		*f.dst = n
+	}
+
+	if crop[2] > 0 || crop[3] > 0 {
+		if crop[2] == 0 || crop[3] == 0 {
+			return opts, errors.New("crop needs both a width and a height")
+		}
+		opts.Crop = image.Rect(crop[0], crop[1], crop[0]+crop[2], crop[1]+crop[3])
+	}
+
+	if d.Format != "" {
+		format, err := fileconvert.ParseImageFormat(d.Format)
+		if err != nil {
+			return opts, err
+		}
+		opts.Format = format
+	}
+	opts.Resize = fileconvert.ResizeMode(d.Resize)
+	opts.Grayscale = d.Grayscale
+	return opts, nil
+}
+
+// sendDownload writes body as an attachment named filename.
+func sendDownload(w http.ResponseWriter, contentType, filename string, body []byte) {
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	w.Write(body)
+}
@@ -0,0 +1,193 @@
+package web
+
+import (
+	"archive/zip"
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+func imagesRequest(t *testing.T, fields [][2]string, files map[string][]byte) *http.Request {
+	t.Helper()
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	for _, f := range fields {
+		writer.WriteField(f[0], f[1])
+	}
+	for name, data := range files {
+		part, err := writer.CreateFormFile("files", name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		part.Write(data)
+	}
+	writer.Close()
+
+	req := httptest.NewRequest("POST", "/tools/images", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return req
+}
+
+func TestImagesTool(t *testing.T) {
+	originalWd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir("../.."); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(originalWd)
+
+	app, err := NewApplication(
+		log.New(os.Stdout, "TEST INFO: ", 0),
+		log.New(os.Stdout, "TEST ERROR: ", 0),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	src := image.NewNRGBA(image.Rect(0, 0, 40, 20))
+	for i := range src.Pix {
+		src.Pix[i] = 0xff
+	}
+	src.SetNRGBA(0, 0, color.NRGBA{0xff, 0, 0, 0xff})
+	var pngData bytes.Buffer
+	if err := png.Encode(&pngData, src); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name            string
+		fields          [][2]string
+		files           map[string][]byte
+		wantStatus      int
+		wantContentType string
+		wantBody        string
+	}{
+		{
+			name:            "single image converted directly",
+			fields:          [][2]string{{"format", "bmp"}, {"resize", "fit"}, {"width", "10"}},
+			files:           map[string][]byte{"photo.png": pngData.Bytes()},
+			wantStatus:      http.StatusOK,
+			wantContentType: "image/bmp",
+		},
+		{
+			name:            "several images zipped",
+			fields:          [][2]string{{"format", "jpeg"}, {"rotate", "90"}},
+			files:           map[string][]byte{"a.png": pngData.Bytes(), "b.png": pngData.Bytes()},
+			wantStatus:      http.StatusOK,
+			wantContentType: "application/zip",
+		},
+		{
+			name:            "thumbnail zipped",
+			fields:          [][2]string{{"thumbnail", "16"}},
+			files:           map[string][]byte{"a.png": pngData.Bytes()},
+			wantStatus:      http.StatusOK,
+			wantContentType: "application/zip",
+		},
+		{
+			name:       "no files",
+			wantStatus: http.StatusBadRequest,
+			wantBody:   "choose at least one image",
+		},
+		{
+			name:       "not an image",
+			files:      map[string][]byte{"notes.txt": []byte("hello")},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   "unrecognised image",
+		},
+		{
+			name:       "bad number",
+			fields:     [][2]string{{"width", "wide"}},
+			files:      map[string][]byte{"a.png": pngData.Bytes()},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   "width must be a whole number",
+		},
+		{
+			name:       "half a crop",
+			fields:     [][2]string{{"crop_w", "10"}},
+			files:      map[string][]byte{"a.png": pngData.Bytes()},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   "crop needs both",
+		},
+		{
+			name:       "every image fails",
+			files:      map[string][]byte{"x.png": []byte("x"), "y.png": []byte("y")},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   "None of the images",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			app.imagesTool(rr, imagesRequest(t, tt.fields, tt.files))
+
+			if rr.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rr.Code, tt.wantStatus, rr.Body.String())
+			}
+			if tt.wantContentType != "" && rr.Header().Get("Content-Type") != tt.wantContentType {
+				t.Errorf("Content-Type = %q, want %q", rr.Header().Get("Content-Type"), tt.wantContentType)
+			}
+			if tt.wantBody != "" && !strings.Contains(rr.Body.String(), tt.wantBody) {
+				t.Errorf("body does not contain %q", tt.wantBody)
+			}
+		})
+	}
+
+	t.Run("single image name and size", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		req := imagesRequest(t, [][2]string{{"format", "jpeg"}, {"resize", "exact"}, {"width", "8"}, {"height", "8"}},
+			map[string][]byte{"holiday.png": pngData.Bytes()})
+		app.imagesTool(rr, req)
+
+		if got := rr.Header().Get("Content-Disposition"); got != `attachment; filename=holiday.jpg` {
+			t.Errorf("Content-Disposition = %q", got)
+		}
+		cfg, format, err := image.DecodeConfig(rr.Body)
+		if err != nil || format != "jpeg" || cfg.Width != 8 || cfg.Height != 8 {
+			t.Errorf("output is %s %dx%d, %v; want jpeg 8x8", format, cfg.Width, cfg.Height, err)
+		}
+	})
+
+	t.Run("zip contents", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		req := imagesRequest(t, [][2]string{{"format", "gif"}, {"thumbnail", "10"}},
+			map[string][]byte{"a.png": pngData.Bytes()})
+		app.imagesTool(rr, req)
+
+		zr, err := zip.NewReader(bytes.NewReader(rr.Body.Bytes()), int64(rr.Body.Len()))
+		if err != nil {
+			t.Fatalf("reading zip: %v", err)
+		}
+		var names []string
+		for _, f := range zr.File {
+			names = append(names, f.Name)
+		}
+		if strings.Join(names, ",") != "a.gif,thumbs/a.gif" {
+			t.Errorf("entries = %v", names)
+		}
+	})
+}
+
+func TestImagesToolMethodNotAllowed(t *testing.T) {
+	app := &Application{}
+	rr := httptest.NewRecorder()
+	app.imagesTool(rr, httptest.NewRequest(http.MethodDelete, "/tools/images", nil))
+
+	if rr.Code != http.StatusMethodNotAllowed {
+		t.Errorf("status = %d, want 405", rr.Code)
+	}
+	if allow := rr.Header().Get("Allow"); allow != "GET, POST" {
+		t.Errorf("Allow = %q", allow)
+	}
+}
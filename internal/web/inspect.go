@@ -0,0 +1,135 @@
+package web
+
+import (
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/NickDiPreta1/toolhub/internal/tools/fileinfo"
+)
+
+// maxInspectUploadSize caps the request body. Files are hashed as they
+// arrive and only the first fileinfo.HeadSize bytes are kept.
+const maxInspectUploadSize = 512 << 20
+
+// inspectHexBytes is how much of the file the hexdump shows.
+const inspectHexBytes = 512
+
+type InspectData struct {
+	Error    string
+	Filename string
+	Report   *fileinfo.Report
+	// ExtensionMismatch is set when the file name's extension does not fit
+	// the detected binary format.
+	ExtensionMismatch bool
+	EntropyNote       string
+	Hexdump           string
+	HexBytes          int
+}
+
+// inspectTool identifies an uploaded file by its content and reports its
+// digests, entropy, encoding, image metadata and first bytes.
+func (app *Application) inspectTool(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		data := &templateData{
+			ToolData: &InspectData{},
+		}
+		app.render(w, http.StatusOK, "inspect.tmpl.html", data)
+		return
+
+	case http.MethodPost:
+		r.Body = http.MaxBytesReader(w, r.Body, maxInspectUploadSize)
+
+		toolData := &InspectData{}
+
+		// Stream the file part rather than parsing the whole form, so large
+		// uploads are never buffered.
+		mr, err := r.MultipartReader()
+		if err != nil {
+			toolData.Error = "Invalid upload."
+			app.render(w, http.StatusBadRequest, "inspect.tmpl.html", &templateData{ToolData: toolData})
+			return
+		}
+
+		var report *fileinfo.Report
+		for report == nil {
+			part, err := mr.NextPart()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				toolData.Error = "File too large or invalid upload. Maximum size is 512MB."
+				app.render(w, http.StatusBadRequest, "inspect.tmpl.html", &templateData{ToolData: toolData})
+				return
+			}
+			if part.FormName() != "file" || part.FileName() == "" {
+				continue
+			}
+
+			toolData.Filename = filepath.Base(part.FileName())
+			report, err = fileinfo.Inspect(part)
+			if err != nil {
+				toolData.Error = "File too large or invalid upload. Maximum size is 512MB."
+				app.render(w, http.StatusBadRequest, "inspect.tmpl.html", &templateData{ToolData: toolData})
+				return
+			}
+		}
+
+		if report == nil {
+			toolData.Error = "Please choose a file to inspect."
+			app.render(w, http.StatusBadRequest, "inspect.tmpl.html", &templateData{ToolData: toolData})
+			return
+		}
+
+		toolData.Report = report
+		toolData.EntropyNote = fileinfo.DescribeEntropy(report.Entropy)
+		toolData.HexBytes = min(len(report.Head), inspectHexBytes)
+		toolData.Hexdump = fileinfo.Hexdump(report.Head[:toolData.HexBytes], 0)
+		// Text has too many extensions to judge.
+		if ext := report.Type.Extension; ext != "" && !strings.HasPrefix(report.Type.MIME, "text/") {
+			toolData.ExtensionMismatch = !extensionMatches(filepath.Ext(toolData.Filename), ext)
+		}
+
+		app.render(w, http.StatusOK, "inspect.tmpl.html", &templateData{ToolData: toolData})
+		return
+
+	default:
+		w.Header().Set("Allow", http.MethodGet+", "+http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+}
+
+// extensionAliases lists other common extensions for a detected type's
+// usual one.
+var extensionAliases = map[string][]string{
+	".jpg":    {".jpeg", ".jpe", ".jfif"},
+	".tif":    {".tiff"},
+	".mid":    {".midi"},
+	".aiff":   {".aif", ".aifc"},
+	".sqlite": {".db", ".sqlite3"},
+	".gz":     {".tgz"},
+	".mp4":    {".m4v"},
+	".m4a":    {".m4b"},
+	".ogg":    {".oga"},
+	".zip":    {".ipa", ".xpi", ".whl", ".nupkg", ".vsix"},
+	".exe":    {".com", ".scr", ".sys"},
+	".dll":    {".ocx", ".cpl"},
+}
+
+// extensionMatches reports whether a file name's extension fits the
+// detected type's usual extension.
+func extensionMatches(ext, want string) bool {
+	ext = strings.ToLower(ext)
+	if ext == want {
+		return true
+	}
+	for _, alias := range extensionAliases[want] {
+		if ext == alias {
+			return true
+		}
+	}
+	return false
+}
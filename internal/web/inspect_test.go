@@ -0,0 +1,136 @@
+package web
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestInspectTool(t *testing.T) {
+	originalWd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir("../.."); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(originalWd)
+
+	app, err := NewApplication(
+		log.New(os.Stdout, "TEST INFO: ", 0),
+		log.New(os.Stdout, "TEST ERROR: ", 0),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var pngData bytes.Buffer
+	if err := png.Encode(&pngData, image.NewGray(image.Rect(0, 0, 12, 5))); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		file       [2]string
+		wantStatus int
+		wantBody   []string
+		notInBody  []string
+	}{
+		{
+			name:       "PNG image",
+			file:       [2]string{"pixel.png", pngData.String()},
+			wantStatus: http.StatusOK,
+			wantBody:   []string{"PNG image", "image/png", "12 × 5 pixels", "Binary", "|.PNG........IHDR|"},
+			notInBody:  []string{"usually has the extension"},
+		},
+		{
+			name:       "misnamed image",
+			file:       [2]string{"report.pdf", pngData.String()},
+			wantStatus: http.StatusOK,
+			wantBody:   []string{"usually has the extension <code>.png</code>"},
+		},
+		{
+			name:       "text file",
+			file:       [2]string{"notes.md", "hello\n"},
+			wantStatus: http.StatusOK,
+			wantBody: []string{
+				"Plain text", "ASCII", "6 bytes",
+				"5891b5b522d5df086d0ff0b110fbd9d21bb4fc7163af34d08286a2e846f6be03",
+				"b1946ac92492d2347c6235b4d2611184",
+			},
+			notInBody: []string{"usually has the extension"},
+		},
+		{
+			name:       "no file",
+			wantStatus: http.StatusBadRequest,
+			wantBody:   []string{"Please choose a file to inspect."},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body bytes.Buffer
+			writer := multipart.NewWriter(&body)
+			if tt.file[0] != "" {
+				part, err := writer.CreateFormFile("file", tt.file[0])
+				if err != nil {
+					t.Fatal(err)
+				}
+				part.Write([]byte(tt.file[1]))
+			}
+			writer.Close()
+
+			req := httptest.NewRequest("POST", "/tools/inspect", &body)
+			req.Header.Set("Content-Type", writer.FormDataContentType())
+			rr := httptest.NewRecorder()
+			app.inspectTool(rr, req)
+
+			if rr.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rr.Code, tt.wantStatus, rr.Body.String())
+			}
+			for _, want := range tt.wantBody {
+				if !strings.Contains(rr.Body.String(), want) {
+					t.Errorf("body does not contain %q", want)
+				}
+			}
+			for _, unwanted := range tt.notInBody {
+				if strings.Contains(rr.Body.String(), unwanted) {
+					t.Errorf("body contains %q", unwanted)
+				}
+			}
+		})
+	}
+
+	t.Run("GET shows form", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		app.inspectTool(rr, httptest.NewRequest("GET", "/tools/inspect", nil))
+		if rr.Code != http.StatusOK || !strings.Contains(rr.Body.String(), "File Inspector") {
+			t.Errorf("status = %d", rr.Code)
+		}
+	})
+
+	t.Run("invalid upload", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/tools/inspect", strings.NewReader("x"))
+		req.Header.Set("Content-Type", "text/plain")
+		rr := httptest.NewRecorder()
+		app.inspectTool(rr, req)
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("status = %d, want 400", rr.Code)
+		}
+	})
+
+	t.Run("method not allowed", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		app.inspectTool(rr, httptest.NewRequest("DELETE", "/tools/inspect", nil))
+		if rr.Code != http.StatusMethodNotAllowed {
+			t.Errorf("status = %d, want 405", rr.Code)
+		}
+	})
+}
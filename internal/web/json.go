@@ -12,6 +12,9 @@ type JSONFormatterData struct {
 	Input  string
 	Output string
 	Mode   string
+	Hash   string
+	Parse  string
+	Fixes  []jsonutil.Fix
 }
 
 // jsonFormatter formats or minifies JSON submitted by the user.
@@ -25,65 +28,70 @@ func (app *Application) jsonFormatter(w http.ResponseWriter, r *http.Request) {
 		return
 
 	case http.MethodPost:
-		input := r.FormValue("input")
-		mode := r.FormValue("mode")
-		if mode == "" {
-			mode = "pretty"
+		// Every response carries the chosen options and any repairs, so
+		// the form keeps its state when an error is shown.
+		toolData := &JSONFormatterData{
+			Input: r.FormValue("input"),
+			Mode:  r.FormValue("mode"),
+			Parse: r.FormValue("parse"),
+		}
+		if toolData.Mode == "" {
+			toolData.Mode = "pretty"
+		}
+		if toolData.Parse == "" {
+			toolData.Parse = "strict"
 		}
 
-		if strings.TrimSpace(input) == "" {
-			data := &templateData{
-				ToolData: &JSONFormatterData{
-					Input: input,
-					Error: "Input cannot be empty.",
-				},
-			}
-			app.render(w, http.StatusBadRequest, "json.tmpl.html", data)
+		if strings.TrimSpace(toolData.Input) == "" {
+			toolData.Error = "Input cannot be empty."
+			app.render(w, http.StatusBadRequest, "json.tmpl.html", &templateData{ToolData: toolData})
 			return
 		}
 
-		if mode == "minify" {
-			minified, err := jsonutil.Minify(input)
+		// Lenient and repair parsing convert the input to strict JSON before
+		// formatting; the original text is still echoed back in the form.
+		source := toolData.Input
+		switch toolData.Parse {
+		case "lenient":
+			strict, err := jsonutil.ParseLenient(toolData.Input)
 			if err != nil {
-				data := &templateData{
-					ToolData: &JSONFormatterData{
-						Input: input,
-						Error: err.Error(),
-					},
-				}
-				app.render(w, http.StatusBadRequest, "json.tmpl.html", data)
+				toolData.Error = err.Error()
+				app.render(w, http.StatusBadRequest, "json.tmpl.html", &templateData{ToolData: toolData})
 				return
 			}
-
-			data := &templateData{
-				ToolData: &JSONFormatterData{
-					Input:  input,
-					Output: minified,
-				},
+			source = strict
+		case "repair":
+			strict, fixes, err := jsonutil.Repair(toolData.Input)
+			toolData.Fixes = fixes
+			if err != nil {
+				toolData.Error = err.Error()
+				app.render(w, http.StatusBadRequest, "json.tmpl.html", &templateData{ToolData: toolData})
+				return
 			}
-			app.render(w, http.StatusOK, "json.tmpl.html", data)
-			return
+			source = strict
 		}
 
-		pretty, err := jsonutil.PrettyPrint(input)
+		var output, hash string
+		var err error
+		switch toolData.Mode {
+		case "minify":
+			output, err = jsonutil.Minify(source)
+		case "canonical", "canonical-hash":
+			output, hash, err = jsonutil.CanonicalHash(source)
+		default:
+			output, err = jsonutil.PrettyPrint(source)
+		}
 		if err != nil {
-			data := &templateData{
-				ToolData: &JSONFormatterData{
-					Input: input,
-					Error: err.Error(),
-				},
-			}
-			app.render(w, http.StatusBadRequest, "json.tmpl.html", data)
+			toolData.Error = err.Error()
+			app.render(w, http.StatusBadRequest, "json.tmpl.html", &templateData{ToolData: toolData})
 			return
 		}
 
-		data := &templateData{
-			ToolData: &JSONFormatterData{
-				Input:  input,
-				Output: pretty,
-			},
+		toolData.Output = output
+		if toolData.Mode == "canonical-hash" {
+			toolData.Hash = hash
 		}
-		app.render(w, http.StatusOK, "json.tmpl.html", data)
+		app.render(w, http.StatusOK, "json.tmpl.html", &templateData{ToolData: toolData})
 		return
 
 	default:
@@ -0,0 +1,118 @@
+package web
+
+import (
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestJSONFormatter_CanonicalHash(t *testing.T) {
+	originalWd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir("../.."); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(originalWd)
+
+	app, err := NewApplication(
+		log.New(os.Stdout, "TEST INFO: ", 0),
+		log.New(os.Stdout, "TEST ERROR: ", 0),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hashFor := func(input string) string {
+		t.Helper()
+
+		form := url.Values{"input": {input}, "mode": {"canonical-hash"}}
+		req := httptest.NewRequest("POST", "/tools/json", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		recorder := httptest.NewRecorder()
+
+		app.jsonFormatter(recorder, req)
+
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d", recorder.Code)
+		}
+
+		body := recorder.Body.String()
+		if !strings.Contains(body, "SHA-256 of Canonical JSON") {
+			t.Fatal("expected hash section in response")
+		}
+
+		return body[strings.Index(body, "SHA-256 of Canonical JSON"):]
+	}
+
+	a := hashFor(`{"b":2,"a":1}`)
+	b := hashFor("{\n  \"a\": 1,\n  \"b\": 2.0\n}")
+	if a != b {
+		t.Error("expected identical hashes for semantically equal documents")
+	}
+}
+
+func TestJSONFormatter_ErrorKeepsOptions(t *testing.T) {
+	originalWd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir("../.."); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(originalWd)
+
+	app, err := NewApplication(
+		log.New(os.Stdout, "TEST INFO: ", 0),
+		log.New(os.Stdout, "TEST ERROR: ", 0),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		form     url.Values
+		wantBody []string
+	}{
+		{
+			name:     "minify error",
+			form:     url.Values{"input": {`{"a": }`}, "mode": {"minify"}},
+			wantBody: []string{`value="minify"\s+checked`, `<option value="strict" selected>`},
+		},
+		{
+			name:     "canonical error",
+			form:     url.Values{"input": {`[1,`}, "mode": {"canonical"}, "parse": {"strict"}},
+			wantBody: []string{`value="canonical"\s+checked`, `<option value="strict" selected>`},
+		},
+		{
+			name:     "repair error keeps repairs",
+			form:     url.Values{"input": {`{'a': NaN}`}, "mode": {"minify"}, "parse": {"repair"}},
+			wantBody: []string{`value="minify"\s+checked`, `<option value="repair" selected>`, `Repairs Applied \(1\)`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/tools/json", strings.NewReader(tt.form.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			rr := httptest.NewRecorder()
+			app.jsonFormatter(rr, req)
+
+			if rr.Code != http.StatusBadRequest {
+				t.Fatalf("status = %d, want 400", rr.Code)
+			}
+			for _, want := range tt.wantBody {
+				if !regexp.MustCompile(want).MatchString(rr.Body.String()) {
+					t.Errorf("body does not match %q", want)
+				}
+			}
+		})
+	}
+}
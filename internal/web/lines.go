@@ -0,0 +1,154 @@
+package web
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/NickDiPreta1/toolhub/internal/tools/textutil"
+)
+
+type LinesData struct {
+	Error      string
+	Input      string
+	Other      string
+	Operation  string
+	SortMode   string
+	Reverse    bool
+	IgnoreCase bool
+	Prefix     string
+	Suffix     string
+	Delimiter  string
+	Seed       string
+	Output     string
+	Count      int
+}
+
+// linesTool applies sort/uniq style operations to a list of lines, including
+// set operations against a second list.
+func (app *Application) linesTool(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		data := &templateData{
+			ToolData: &LinesData{
+				Operation: "sort",
+				SortMode:  string(textutil.SortLexical),
+				Delimiter: ",",
+			},
+		}
+		app.render(w, http.StatusOK, "lines.tmpl.html", data)
+		return
+
+	case http.MethodPost:
+		toolData := &LinesData{
+			Input:      r.FormValue("input"),
+			Other:      r.FormValue("other"),
+			Operation:  r.FormValue("op"),
+			SortMode:   r.FormValue("sort"),
+			Reverse:    r.FormValue("reverse") == "on",
+			IgnoreCase: r.FormValue("ignore_case") == "on",
+			Prefix:     r.FormValue("prefix"),
+			Suffix:     r.FormValue("suffix"),
+			Delimiter:  r.FormValue("delimiter"),
+			Seed:       strings.TrimSpace(r.FormValue("seed")),
+		}
+
+		if toolData.Input == "" {
+			toolData.Error = "Input cannot be empty."
+			app.render(w, http.StatusBadRequest, "lines.tmpl.html", &templateData{ToolData: toolData})
+			return
+		}
+
+		output, count, err := applyLineOperation(toolData)
+		if err != nil {
+			toolData.Error = err.Error()
+			app.render(w, http.StatusBadRequest, "lines.tmpl.html", &templateData{ToolData: toolData})
+			return
+		}
+
+		toolData.Output, toolData.Count = output, count
+		app.render(w, http.StatusOK, "lines.tmpl.html", &templateData{ToolData: toolData})
+		return
+
+	default:
+		w.Header().Set("Allow", http.MethodGet+", "+http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+}
+
+// applyLineOperation runs the selected operation and returns the output text
+// and the number of resulting lines.
+func applyLineOperation(d *LinesData) (string, int, error) {
+	lines := textutil.SplitLines(d.Input)
+	other := textutil.SplitLines(d.Other)
+	delimiter := unescapeDelimiter(d.Delimiter)
+
+	var result []string
+	switch d.Operation {
+	case "sort":
+		mode := textutil.SortMode(d.SortMode)
+		switch mode {
+		case "":
+			mode = textutil.SortLexical
+		case textutil.SortLexical, textutil.SortNatural, textutil.SortNumeric:
+		default:
+			return "", 0, fmt.Errorf("unknown sort mode %q", d.SortMode)
+		}
+		result = textutil.SortLines(lines, textutil.SortOptions{
+			Mode:       mode,
+			Reverse:    d.Reverse,
+			IgnoreCase: d.IgnoreCase,
+		})
+	case "dedupe":
+		result = textutil.DedupeLines(lines, d.IgnoreCase)
+	case "count":
+		for _, c := range textutil.CountLines(lines, d.IgnoreCase) {
+			result = append(result, fmt.Sprintf("%7d %s", c.Count, c.Line))
+		}
+	case "trim":
+		result = textutil.TrimLines(lines)
+	case "remove-blank":
+		result = textutil.RemoveBlankLines(lines)
+	case "shuffle":
+		seed := time.Now().UnixNano()
+		if d.Seed != "" {
+			n, err := strconv.ParseInt(d.Seed, 10, 64)
+			if err != nil {
+				return "", 0, errors.New("seed must be a whole number")
+			}
+			seed = n
+		}
+		result = textutil.ShuffleLines(lines, seed)
+	case "reverse":
+		result = textutil.ReverseLines(lines)
+	case "wrap":
+		result = textutil.WrapLines(lines, d.Prefix, d.Suffix)
+	case "join":
+		joined := strings.Join(lines, delimiter)
+		return joined, 1, nil
+	case "split":
+		result = textutil.SplitOn(strings.ReplaceAll(d.Input, "\r\n", "\n"), delimiter)
+	case "union":
+		result = textutil.UnionLines(lines, other, d.IgnoreCase)
+	case "intersection":
+		result = textutil.IntersectLines(lines, other, d.IgnoreCase)
+	case "difference":
+		result = textutil.DifferenceLines(lines, other, d.IgnoreCase)
+	case "symmetric-difference":
+		result = textutil.SymmetricDifferenceLines(lines, other, d.IgnoreCase)
+	default:
+		return "", 0, fmt.Errorf("unknown operation %q", d.Operation)
+	}
+
+	return strings.Join(result, "\n"), len(result), nil
+}
+
+// unescapeDelimiter lets delimiters such as a tab or newline be typed as
+// \t and \n.
+func unescapeDelimiter(s string) string {
+	return strings.NewReplacer(`\t`, "\t", `\n`, "\n", `\r`, "\r", `\\`, `\`).Replace(s)
+}
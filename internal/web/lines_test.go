@@ -0,0 +1,58 @@
+package web
+
+import (
+	"testing"
+)
+
+func TestApplyLineOperation(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    LinesData
+		want    string
+		wantErr string
+	}{
+		{
+			name: "natural sort",
+			data: LinesData{Input: "a10\na2\na1", Operation: "sort", SortMode: "natural"},
+			want: "a1\na2\na10",
+		},
+		{
+			name: "empty sort mode is lexical",
+			data: LinesData{Input: "a10\na2\na1", Operation: "sort"},
+			want: "a1\na10\na2",
+		},
+		{
+			name:    "unknown sort mode",
+			data:    LinesData{Input: "b\na", Operation: "sort", SortMode: "random"},
+			wantErr: `unknown sort mode "random"`,
+		},
+		{
+			name:    "unknown operation",
+			data:    LinesData{Input: "a", Operation: "explode"},
+			wantErr: `unknown operation "explode"`,
+		},
+		{
+			name:    "bad seed",
+			data:    LinesData{Input: "a", Operation: "shuffle", Seed: "1.5"},
+			wantErr: "seed must be a whole number",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, err := applyLineOperation(&tt.data)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("output = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
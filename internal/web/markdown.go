@@ -0,0 +1,127 @@
+package web
+
+import (
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"strings"
+
+	"github.com/NickDiPreta1/toolhub/internal/tools/markdown"
+	"github.com/NickDiPreta1/toolhub/internal/tools/textutil"
+)
+
+// maxMarkdownRequestBytes limits the size of a Markdown API request body.
+const maxMarkdownRequestBytes = 1 << 20
+
+type MarkdownData struct {
+	Error string
+	// Direction is "to-html" or "to-markdown".
+	Direction string
+	Input     string
+	Title     string
+	Output    string
+	// Preview is the sanitized HTML output, rendered as-is.
+	Preview template.HTML
+}
+
+// MarkdownRequest is the JSON body accepted by the Markdown API.
+type MarkdownRequest struct {
+	Markdown string `json:"markdown"`
+}
+
+// markdownTool renders Markdown to sanitized HTML with a preview, or
+// converts simple HTML back to Markdown. Either result can be downloaded.
+func (app *Application) markdownTool(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		data := &templateData{
+			ToolData: &MarkdownData{Direction: "to-html"},
+		}
+		app.render(w, http.StatusOK, "markdown.tmpl.html", data)
+		return
+
+	case http.MethodPost:
+		toolData := &MarkdownData{
+			Direction: r.FormValue("direction"),
+			Input:     strings.ReplaceAll(r.FormValue("input"), "\r\n", "\n"),
+			Title:     strings.TrimSpace(r.FormValue("title")),
+		}
+		if toolData.Direction == "" {
+			toolData.Direction = "to-html"
+		}
+
+		if strings.TrimSpace(toolData.Input) == "" {
+			toolData.Error = "Please enter some text to convert."
+			app.render(w, http.StatusBadRequest, "markdown.tmpl.html", &templateData{ToolData: toolData})
+			return
+		}
+
+		var err error
+		switch toolData.Direction {
+		case "to-html":
+			toolData.Output, err = markdown.ToHTML(toolData.Input)
+			toolData.Preview = template.HTML(toolData.Output)
+		case "to-markdown":
+			toolData.Output, err = markdown.FromHTML(toolData.Input)
+		default:
+			toolData.Error = "Unknown conversion direction."
+			app.render(w, http.StatusBadRequest, "markdown.tmpl.html", &templateData{ToolData: toolData})
+			return
+		}
+		if err != nil {
+			toolData.Error = err.Error()
+			app.render(w, http.StatusBadRequest, "markdown.tmpl.html", &templateData{ToolData: toolData})
+			return
+		}
+
+		if r.FormValue("download") != "" {
+			name := textutil.Slugify(toolData.Title)
+			if name == "" {
+				name = "document"
+			}
+			if toolData.Direction == "to-html" {
+				doc := markdown.Document(toolData.Title, toolData.Output)
+				sendDownload(w, "text/html; charset=utf-8", name+".html", []byte(doc))
+			} else {
+				sendDownload(w, "text/markdown; charset=utf-8", name+".md", []byte(toolData.Output+"\n"))
+			}
+			return
+		}
+
+		app.render(w, http.StatusOK, "markdown.tmpl.html", &templateData{ToolData: toolData})
+		return
+
+	default:
+		w.Header().Set("Allow", http.MethodGet+", "+http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+}
+
+// markdownAPI renders Markdown to sanitized HTML for the live preview.
+func (app *Application) markdownAPI(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		app.writeJSON(w, http.StatusMethodNotAllowed, map[string]any{
+			"error": map[string]string{"message": "method not allowed"},
+		})
+		return
+	}
+
+	var req MarkdownRequest
+	r.Body = http.MaxBytesReader(w, r.Body, maxMarkdownRequestBytes)
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		app.writeJSON(w, http.StatusBadRequest, map[string]any{
+			"error": map[string]string{"message": "invalid JSON body: " + err.Error()},
+		})
+		return
+	}
+
+	out, err := markdown.ToHTML(req.Markdown)
+	if err != nil {
+		app.serverError(w, err)
+		return
+	}
+
+	app.writeJSON(w, http.StatusOK, map[string]string{"html": out})
+}
@@ -0,0 +1,198 @@
+package web
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestMarkdownTool(t *testing.T) {
+	originalWd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir("../.."); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(originalWd)
+
+	app, err := NewApplication(
+		log.New(os.Stdout, "TEST INFO: ", 0),
+		log.New(os.Stdout, "TEST ERROR: ", 0),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name            string
+		method          string
+		form            url.Values
+		expectedStatus  int
+		expectedType    string
+		expectedContent []string
+		unexpected      []string
+	}{
+		{
+			name:            "GET shows form",
+			method:          "GET",
+			expectedStatus:  http.StatusOK,
+			expectedContent: []string{"Markdown to HTML", "/api/markdown"},
+		},
+		{
+			name:            "render with preview",
+			method:          "POST",
+			form:            url.Values{"direction": {"to-html"}, "input": {"# Hi\n\n- [x] done\n\n<script>alert(1)</script>"}},
+			expectedStatus:  http.StatusOK,
+			expectedContent: []string{"<h1>Hi</h1>", "&lt;h1&gt;Hi&lt;/h1&gt;", `type="checkbox"`},
+			unexpected:      []string{"<script>alert(1)"},
+		},
+		{
+			name:            "HTML to Markdown",
+			method:          "POST",
+			form:            url.Values{"direction": {"to-markdown"}, "input": {"<h2>Title</h2><p>a <b>b</b></p>"}},
+			expectedStatus:  http.StatusOK,
+			expectedContent: []string{"## Title\n\na **b**"},
+		},
+		{
+			name:            "download HTML document",
+			method:          "POST",
+			form:            url.Values{"direction": {"to-html"}, "input": {"text"}, "title": {"My Notes"}, "download": {"1"}},
+			expectedStatus:  http.StatusOK,
+			expectedType:    "text/html; charset=utf-8",
+			expectedContent: []string{"<!doctype html>", "<title>My Notes</title>", "<p>text</p>"},
+		},
+		{
+			name:            "download Markdown",
+			method:          "POST",
+			form:            url.Values{"direction": {"to-markdown"}, "input": {"<p>x</p>"}, "download": {"1"}},
+			expectedStatus:  http.StatusOK,
+			expectedType:    "text/markdown; charset=utf-8",
+			expectedContent: []string{"x\n"},
+		},
+		{
+			name:            "empty input",
+			method:          "POST",
+			form:            url.Values{"direction": {"to-html"}, "input": {"  "}},
+			expectedStatus:  http.StatusBadRequest,
+			expectedContent: []string{"Please enter some text to convert."},
+		},
+		{
+			name:            "unknown direction",
+			method:          "POST",
+			form:            url.Values{"direction": {"sideways"}, "input": {"x"}},
+			expectedStatus:  http.StatusBadRequest,
+			expectedContent: []string{"Unknown conversion direction."},
+		},
+		{
+			name:           "method not allowed",
+			method:         "PUT",
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/tools/markdown", strings.NewReader(tt.form.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			recorder := httptest.NewRecorder()
+
+			app.markdownTool(recorder, req)
+
+			if recorder.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tt.expectedStatus, recorder.Code)
+			}
+			if tt.expectedType != "" {
+				if ct := recorder.Header().Get("Content-Type"); ct != tt.expectedType {
+					t.Errorf("expected content type %q, got %q", tt.expectedType, ct)
+				}
+				if cd := recorder.Header().Get("Content-Disposition"); !strings.HasPrefix(cd, "attachment") {
+					t.Errorf("expected attachment, got %q", cd)
+				}
+			}
+			body := recorder.Body.String()
+			for _, want := range tt.expectedContent {
+				if !strings.Contains(body, want) {
+					t.Errorf("expected body to contain %q", want)
+				}
+			}
+			for _, unwanted := range tt.unexpected {
+				if strings.Contains(body, unwanted) {
+					t.Errorf("expected body not to contain %q", unwanted)
+				}
+			}
+		})
+	}
+}
+
+func TestMarkdownAPI(t *testing.T) {
+	originalWd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir("../.."); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(originalWd)
+
+	app, err := NewApplication(
+		log.New(os.Stdout, "TEST INFO: ", 0),
+		log.New(os.Stdout, "TEST ERROR: ", 0),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name           string
+		method         string
+		body           string
+		expectedStatus int
+		expectedHTML   string
+	}{
+		{
+			name:           "renders sanitized HTML",
+			method:         "POST",
+			body:           `{"markdown": "**hi** [x](javascript:alert(1))"}`,
+			expectedStatus: http.StatusOK,
+			expectedHTML:   "<p><strong>hi</strong> <a>x</a></p>\n",
+		},
+		{
+			name:           "invalid JSON",
+			method:         "POST",
+			body:           `{`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "method not allowed",
+			method:         "GET",
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/api/markdown", strings.NewReader(tt.body))
+			recorder := httptest.NewRecorder()
+
+			app.markdownAPI(recorder, req)
+
+			if recorder.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, recorder.Code, recorder.Body.String())
+			}
+
+			var body map[string]any
+			if err := json.Unmarshal(recorder.Body.Bytes(), &body); err != nil {
+				t.Fatalf("invalid JSON response: %v", err)
+			}
+			if tt.expectedHTML != "" && body["html"] != tt.expectedHTML {
+				t.Errorf("expected html %q, got %v", tt.expectedHTML, body["html"])
+			}
+		})
+	}
+}
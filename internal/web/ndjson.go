@@ -0,0 +1,164 @@
+package web
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/NickDiPreta1/toolhub/internal/tools/jsonutil"
+)
+
+type NDJSONData struct {
+	Error      string
+	Input      string
+	Mode       string
+	Query      string
+	Processed  bool
+	Lines      int
+	Records    int
+	ErrorCount int
+	LineErrors []jsonutil.LineError
+}
+
+// ndjsonTool validates and transforms newline-delimited JSON. Uploads are
+// streamed through a worker pool into a temporary file, so large exports are
+// processed with bounded memory.
+func (app *Application) ndjsonTool(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		data := &templateData{
+			ToolData: &NDJSONData{Mode: "validate"},
+		}
+		app.render(w, http.StatusOK, "ndjson.tmpl.html", data)
+		return
+
+	case http.MethodPost:
+		const maxUploadSize = 64 * 1024 * 1024
+		// Only the first megabyte of the form is kept in memory; larger
+		// uploads are spooled to disk by the multipart reader.
+		const maxMemory = 1024 * 1024
+		r.Body = http.MaxBytesReader(w, r.Body, maxUploadSize)
+
+		toolData := &NDJSONData{Mode: "validate"}
+		if err := r.ParseMultipartForm(maxMemory); err != nil {
+			toolData.Error = "File too large or invalid upload. Maximum size is 64MB."
+			app.render(w, http.StatusBadRequest, "ndjson.tmpl.html", &templateData{ToolData: toolData})
+			return
+		}
+		defer r.MultipartForm.RemoveAll()
+
+		toolData.Input = r.FormValue("input")
+		toolData.Query = r.FormValue("query")
+		if mode := r.FormValue("mode"); mode != "" {
+			toolData.Mode = mode
+		}
+
+		var in io.Reader
+		file, _, err := r.FormFile("file")
+		switch {
+		case err == nil:
+			defer file.Close()
+			in = file
+		case strings.TrimSpace(toolData.Input) != "":
+			in = strings.NewReader(toolData.Input)
+		default:
+			toolData.Error = "Please upload a file or paste some NDJSON."
+			app.render(w, http.StatusBadRequest, "ndjson.tmpl.html", &templateData{ToolData: toolData})
+			return
+		}
+
+		out, err := os.CreateTemp("", "toolhub-ndjson-*")
+		if err != nil {
+			app.serverError(w, err)
+			return
+		}
+		defer os.Remove(out.Name())
+		defer out.Close()
+
+		filename := "output.ndjson"
+		contentType := "application/x-ndjson"
+		opts := jsonutil.NDJSONOptions{}
+
+		switch toolData.Mode {
+		case "from-array":
+			if _, err := jsonutil.ArrayToNDJSON(in, out); err != nil {
+				toolData.Error = err.Error()
+				app.render(w, http.StatusBadRequest, "ndjson.tmpl.html", &templateData{ToolData: toolData})
+				return
+			}
+			app.sendTempFile(w, out, filename, contentType)
+			return
+		case "minify":
+			opts.Transform = jsonutil.ValidateLine
+		case "pretty":
+			opts.Transform = jsonutil.PrettyLine
+			filename, contentType = "output.txt", "text/plain"
+		case "to-array":
+			opts.Transform = jsonutil.ValidateLine
+			opts.AsArray = true
+			filename, contentType = "output.json", "application/json"
+		case "filter":
+			q, err := jsonutil.CompileQuery(toolData.Query)
+			if err != nil {
+				toolData.Error = err.Error()
+				app.render(w, http.StatusBadRequest, "ndjson.tmpl.html", &templateData{ToolData: toolData})
+				return
+			}
+			opts.Transform = jsonutil.FilterLines(q)
+		default:
+			toolData.Mode = "validate"
+			opts.Transform = jsonutil.DiscardLines
+		}
+
+		stats, err := jsonutil.StreamNDJSON(r.Context(), in, out, opts)
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				toolData.Error = "File too large. Maximum size is 64MB."
+			} else {
+				toolData.Error = err.Error()
+			}
+			app.render(w, http.StatusBadRequest, "ndjson.tmpl.html", &templateData{ToolData: toolData})
+			return
+		}
+
+		toolData.Processed = true
+		toolData.Lines = stats.Lines
+		toolData.Records = stats.Records
+		toolData.ErrorCount = stats.ErrorCount
+		toolData.LineErrors = stats.Errors
+
+		if toolData.Mode == "validate" || stats.ErrorCount > 0 {
+			status := http.StatusOK
+			if stats.ErrorCount > 0 {
+				status = http.StatusBadRequest
+			}
+			app.render(w, status, "ndjson.tmpl.html", &templateData{ToolData: toolData})
+			return
+		}
+
+		app.sendTempFile(w, out, filename, contentType)
+
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// sendTempFile rewinds f and streams it to the client as a download.
+func (app *Application) sendTempFile(w http.ResponseWriter, f *os.File, filename, contentType string) {
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		app.serverError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+	w.WriteHeader(http.StatusOK)
+
+	if _, err := io.Copy(w, f); err != nil {
+		app.errorLog.Printf("error sending %s: %v", filename, err)
+	}
+}
@@ -0,0 +1,134 @@
+package web
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/NickDiPreta1/toolhub/internal/tools/regexutil"
+)
+
+// maxRegexRequestBytes limits the size of a regex API request body.
+const maxRegexRequestBytes = 10 << 20
+
+type RegexData struct {
+	Error          string
+	ErrorPattern   string
+	ErrorCaret     string
+	Pattern        string
+	Input          string
+	Replace        string
+	ReplaceEnabled bool
+	Flags          regexutil.Flags
+	Result         *regexutil.Result
+}
+
+// RegexRequest is the JSON body accepted by the regex API. Replace is
+// optional; when present every match is replaced using $1 or ${name}
+// templates.
+type RegexRequest struct {
+	Pattern string          `json:"pattern"`
+	Flags   regexutil.Flags `json:"flags"`
+	Input   string          `json:"input"`
+	Replace *string         `json:"replace"`
+}
+
+// regexTool runs an RE2 pattern against input text, highlighting matches and
+// listing capture groups.
+func (app *Application) regexTool(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		data := &templateData{
+			ToolData: &RegexData{},
+		}
+		app.render(w, http.StatusOK, "regex.tmpl.html", data)
+		return
+
+	case http.MethodPost:
+		toolData := &RegexData{
+			Pattern:        r.FormValue("pattern"),
+			Input:          strings.ReplaceAll(r.FormValue("input"), "\r\n", "\n"),
+			Replace:        r.FormValue("replace"),
+			ReplaceEnabled: r.FormValue("replace_enabled") == "on",
+			Flags: regexutil.Flags{
+				CaseInsensitive: r.FormValue("flag_i") == "on",
+				Multiline:       r.FormValue("flag_m") == "on",
+				DotAll:          r.FormValue("flag_s") == "on",
+				Ungreedy:        r.FormValue("flag_U") == "on",
+			},
+		}
+
+		if toolData.Pattern == "" {
+			toolData.Error = "Pattern cannot be empty."
+			app.render(w, http.StatusBadRequest, "regex.tmpl.html", &templateData{ToolData: toolData})
+			return
+		}
+
+		var replace *string
+		if toolData.ReplaceEnabled {
+			replace = &toolData.Replace
+		}
+
+		result, err := regexutil.Run(toolData.Pattern, toolData.Flags, toolData.Input, replace)
+		if err != nil {
+			toolData.Error = err.Error()
+			var compileErr *regexutil.CompileError
+			if errors.As(err, &compileErr) && compileErr.Column > 0 {
+				toolData.ErrorPattern = compileErr.Pattern
+				toolData.ErrorCaret = strings.Repeat(" ", compileErr.Column-1) + "^"
+			}
+			app.render(w, http.StatusBadRequest, "regex.tmpl.html", &templateData{ToolData: toolData})
+			return
+		}
+
+		toolData.Result = result
+		app.render(w, http.StatusOK, "regex.tmpl.html", &templateData{ToolData: toolData})
+		return
+
+	default:
+		w.Header().Set("Allow", http.MethodGet+", "+http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+}
+
+// regexAPI is the JSON counterpart of regexTool. Compile errors are returned
+// with status 400 and their position in the pattern.
+func (app *Application) regexAPI(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		app.writeJSON(w, http.StatusMethodNotAllowed, map[string]any{
+			"error": map[string]string{"message": "method not allowed"},
+		})
+		return
+	}
+
+	var req RegexRequest
+	r.Body = http.MaxBytesReader(w, r.Body, maxRegexRequestBytes)
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		app.writeJSON(w, http.StatusBadRequest, map[string]any{
+			"error": map[string]string{"message": "invalid JSON body: " + err.Error()},
+		})
+		return
+	}
+	if req.Pattern == "" {
+		app.writeJSON(w, http.StatusBadRequest, map[string]any{
+			"error": map[string]string{"message": "pattern cannot be empty"},
+		})
+		return
+	}
+
+	result, err := regexutil.Run(req.Pattern, req.Flags, req.Input, req.Replace)
+	if err != nil {
+		var compileErr *regexutil.CompileError
+		if errors.As(err, &compileErr) {
+			app.writeJSON(w, http.StatusBadRequest, map[string]any{"error": compileErr})
+			return
+		}
+		app.serverError(w, err)
+		return
+	}
+
+	app.writeJSON(w, http.StatusOK, result)
+}
@@ -0,0 +1,100 @@
+package web
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestRegexAPI(t *testing.T) {
+	originalWd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir("../.."); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(originalWd)
+
+	app, err := NewApplication(
+		log.New(os.Stdout, "TEST INFO: ", 0),
+		log.New(os.Stdout, "TEST ERROR: ", 0),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name           string
+		method         string
+		body           string
+		expectedStatus int
+		check          func(t *testing.T, body map[string]any)
+	}{
+		{
+			name:           "matches and replace",
+			method:         "POST",
+			body:           `{"pattern": "(?P<n>\\d+)", "flags": {"i": true}, "input": "a1 b22", "replace": "<${n}>"}`,
+			expectedStatus: http.StatusOK,
+			check: func(t *testing.T, body map[string]any) {
+				if matches := body["matches"].([]any); len(matches) != 2 {
+					t.Errorf("expected 2 matches, got %d", len(matches))
+				}
+				if body["replaced"] != "a<1> b<22>" {
+					t.Errorf("unexpected replaced text %v", body["replaced"])
+				}
+			},
+		},
+		{
+			name:           "compile error with position",
+			method:         "POST",
+			body:           `{"pattern": "ab)", "input": "ab"}`,
+			expectedStatus: http.StatusBadRequest,
+			check: func(t *testing.T, body map[string]any) {
+				e := body["error"].(map[string]any)
+				if e["message"] != "unexpected )" || e["column"] != float64(3) {
+					t.Errorf("unexpected error body %v", e)
+				}
+			},
+		},
+		{
+			name:           "invalid JSON",
+			method:         "POST",
+			body:           `{`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "method not allowed",
+			method:         "GET",
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/api/regex", strings.NewReader(tt.body))
+			recorder := httptest.NewRecorder()
+
+			app.regexAPI(recorder, req)
+
+			if recorder.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, recorder.Code, recorder.Body.String())
+			}
+			if ct := recorder.Header().Get("Content-Type"); ct != "application/json" {
+				t.Errorf("expected JSON content type, got %q", ct)
+			}
+
+			var body map[string]any
+			if err := json.Unmarshal(recorder.Body.Bytes(), &body); err != nil {
+				t.Fatalf("invalid JSON response: %v", err)
+			}
+			if tt.check != nil {
+				tt.check(t, body)
+			}
+		})
+	}
+}
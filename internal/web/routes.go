@@ -9,13 +9,35 @@ func (app *Application) Routes() http.Handler {
 	mux.HandleFunc("/ping", app.Ping)
 	mux.HandleFunc("/", app.home)
 	mux.HandleFunc("/tools/fileconvert", app.fileConvert)
+	mux.HandleFunc("/tools/inspect", app.inspectTool)
+	mux.HandleFunc("/tools/hex", app.hexTool)
+	mux.HandleFunc("/tools/split", app.splitTool)
 	mux.HandleFunc("/tools/slugify", app.slugify)
+	mux.HandleFunc("/tools/case", app.caseTool)
+	mux.HandleFunc("/tools/diff", app.diffTool)
+	mux.HandleFunc("/tools/regex", app.regexTool)
+	mux.HandleFunc("/tools/textstats", app.textStats)
+	mux.HandleFunc("/tools/lines", app.linesTool)
+	mux.HandleFunc("/tools/unicode", app.unicodeTool)
+	mux.HandleFunc("/tools/fakedata", app.fakeDataTool)
+	mux.HandleFunc("/tools/ids", app.idsTool)
+	mux.HandleFunc("/tools/images", app.imagesTool)
+	mux.HandleFunc("/tools/archive", app.archiveTool)
+	mux.HandleFunc("/tools/compress", app.compressTool)
 	mux.HandleFunc("/tools/json", app.jsonFormatter)
+	mux.HandleFunc("/tools/convert", app.dataConvert)
+	mux.HandleFunc("/tools/csv", app.csvTool)
+	mux.HandleFunc("/tools/markdown", app.markdownTool)
+	mux.HandleFunc("/tools/codegen", app.codegenTool)
+	mux.HandleFunc("/tools/ndjson", app.ndjsonTool)
 	mux.HandleFunc("/tools/base64", app.base64Tool)
 	mux.HandleFunc("/tools/concurrent-upper", app.concurrentUpper)
 	mux.HandleFunc("/tools/concurrent-hash", app.concurrentHash)
 	mux.HandleFunc("/tools/workerpool", app.workerPool)
 	mux.HandleFunc("/tools/progress", app.progressDemo)
 
+	mux.HandleFunc("/api/regex", app.regexAPI)
+	mux.HandleFunc("/api/markdown", app.markdownAPI)
+
 	return app.PanicRecover(app.LogRequest(mux))
 }
@@ -1,16 +1,35 @@
 package web
 
 import (
+	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/NickDiPreta1/toolhub/internal/tools/textutil"
 )
 
 type SlugifyData struct {
-	Error  string
-	Input  string
-	Output string
+	Error        string
+	Input        string
+	Output       string
+	Language     string
+	Languages    []textutil.LanguageOption
+	Batch        bool
+	Results      []SlugResult
+	Existing     string
+	Separator    string
+	MaxLength    string
+	KeepCase     bool
+	Stopwords    bool
+	Replacements string
+	Reserved     string
+}
+
+// SlugResult pairs a batch input line with its slug.
+type SlugResult struct {
+	Input string
+	Slug  string
 }
 
 // slugify renders the slugify tool and processes user input.
@@ -18,34 +37,108 @@ func (app *Application) slugify(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
 	case http.MethodGet:
 		data := &templateData{
-			ToolData: &SlugifyData{},
+			ToolData: &SlugifyData{
+				Languages: textutil.Languages,
+				Separator: textutil.DefaultSlugSeparator,
+			},
 		}
 		app.render(w, http.StatusOK, "slugify.tmpl.html", data)
 
 	case http.MethodPost:
-		input := r.FormValue("input")
+		toolData := &SlugifyData{
+			Input:        r.FormValue("input"),
+			Language:     r.FormValue("lang"),
+			Languages:    textutil.Languages,
+			Batch:        r.FormValue("batch") == "on",
+			Existing:     r.FormValue("existing"),
+			Separator:    r.FormValue("separator"),
+			MaxLength:    strings.TrimSpace(r.FormValue("maxlength")),
+			KeepCase:     r.FormValue("keepcase") == "on",
+			Stopwords:    r.FormValue("stopwords") == "on",
+			Replacements: r.FormValue("replacements"),
+			Reserved:     r.FormValue("reserved"),
+		}
+
+		if strings.TrimSpace(toolData.Input) == "" {
+			toolData.Error = "Please enter some text to slugify."
+			app.render(w, http.StatusBadRequest, "slugify.tmpl.html", &templateData{ToolData: toolData})
+			return
+		}
 
-		if strings.TrimSpace(input) == "" {
-			data := &templateData{
-				ToolData: &SlugifyData{
-					Error: "Please enter some text to slugify.",
-				},
+		opts := textutil.SlugOptions{
+			Separator: toolData.Separator,
+			KeepCase:  toolData.KeepCase,
+			Reserved:  splitLines(toolData.Reserved),
+			Language:  textutil.Language(toolData.Language),
+		}
+		if toolData.Stopwords {
+			opts.Stopwords = textutil.EnglishStopwords
+		}
+		if toolData.MaxLength != "" {
+			n, err := strconv.Atoi(toolData.MaxLength)
+			if err != nil || n < 0 {
+				toolData.Error = "Max length must be a non-negative whole number."
+				app.render(w, http.StatusBadRequest, "slugify.tmpl.html", &templateData{ToolData: toolData})
+				return
 			}
-			app.render(w, http.StatusBadRequest, "slugify.tmpl.html", data)
+			opts.MaxLength = n
+		}
+
+		replacements, err := parseReplacements(toolData.Replacements)
+		if err != nil {
+			toolData.Error = err.Error()
+			app.render(w, http.StatusBadRequest, "slugify.tmpl.html", &templateData{ToolData: toolData})
 			return
 		}
+		opts.Replacements = replacements
 
-		slug := textutil.Slugify(input)
-		data := &templateData{
-			ToolData: &SlugifyData{
-				Input:  input,
-				Output: slug,
-			},
+		existing := splitLines(toolData.Existing)
+		if !toolData.Batch {
+			toolData.Output = textutil.SlugifyBatch([]string{toolData.Input}, existing, opts)[0]
+			app.render(w, http.StatusOK, "slugify.tmpl.html", &templateData{ToolData: toolData})
+			return
 		}
-		app.render(w, http.StatusOK, "slugify.tmpl.html", data)
+
+		inputs := splitLines(toolData.Input)
+		slugs := textutil.SlugifyBatch(inputs, existing, opts)
+		for i, input := range inputs {
+			toolData.Results = append(toolData.Results, SlugResult{Input: input, Slug: slugs[i]})
+		}
+		toolData.Output = strings.Join(slugs, "\n")
+		app.render(w, http.StatusOK, "slugify.tmpl.html", &templateData{ToolData: toolData})
+
 	default:
 		w.Header().Set("Allow", "GET, POST")
 		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 }
+
+// splitLines returns the non-blank lines of s with surrounding whitespace
+// removed.
+func splitLines(s string) []string {
+	var lines []string
+	for _, line := range strings.Split(s, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// parseReplacements reads one "from=to" pair per line.
+func parseReplacements(s string) (map[string]string, error) {
+	replacements := make(map[string]string)
+	for i, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		from, to, ok := strings.Cut(line, "=")
+		if !ok || strings.TrimSpace(from) == "" {
+			return nil, fmt.Errorf("replacement on line %d must look like from=to", i+1)
+		}
+		replacements[strings.TrimSpace(from)] = strings.TrimSpace(to)
+	}
+	return replacements, nil
+}
@@ -0,0 +1,214 @@
+package web
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/NickDiPreta1/toolhub/internal/tools/fileconvert"
+)
+
+// maxSplitUploadSize caps the request body. Uploads beyond the in-memory
+// part of the form are spooled to temporary files.
+const maxSplitUploadSize = 512 << 20
+
+type SplitData struct {
+	Error string
+	// By is "parts" to split into Parts equal parts or "size" to cut
+	// chunks of ChunkSize.
+	By        string
+	Parts     string
+	ChunkSize string
+
+	// Manifest and Verification report on the parts uploaded to be joined.
+	Manifest     *fileconvert.Manifest
+	Verification *fileconvert.Verification
+	// Extra lists uploaded files the manifest does not mention.
+	Extra []string
+}
+
+// splitTool splits an upload into numbered parts, downloaded as a zip with
+// a manifest of their SHA-256 digests, and verifies and joins parts back
+// together using that manifest.
+func (app *Application) splitTool(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		data := &templateData{
+			ToolData: &SplitData{By: "parts", Parts: "2", ChunkSize: "10MB"},
+		}
+		app.render(w, http.StatusOK, "split.tmpl.html", data)
+		return
+
+	case http.MethodPost:
+		r.Body = http.MaxBytesReader(w, r.Body, maxSplitUploadSize)
+
+		toolData := &SplitData{By: "parts", Parts: "2", ChunkSize: "10MB"}
+
+		if err := r.ParseMultipartForm(32 << 20); err != nil {
+			toolData.Error = "Upload too large or invalid. Maximum size is 512MB."
+			app.render(w, http.StatusBadRequest, "split.tmpl.html", &templateData{ToolData: toolData})
+			return
+		}
+		defer r.MultipartForm.RemoveAll()
+
+		if by := r.FormValue("by"); by != "" {
+			toolData.By = by
+		}
+		if parts := strings.TrimSpace(r.FormValue("parts")); parts != "" {
+			toolData.Parts = parts
+		}
+		if size := strings.TrimSpace(r.FormValue("chunk_size")); size != "" {
+			toolData.ChunkSize = size
+		}
+
+		switch action := r.FormValue("action"); action {
+		case "split":
+			app.splitFile(w, r, toolData)
+		case "verify", "join":
+			app.joinParts(w, r, toolData, action == "join")
+		default:
+			toolData.Error = "Unknown action: " + action
+			app.render(w, http.StatusBadRequest, "split.tmpl.html", &templateData{ToolData: toolData})
+		}
+		return
+
+	default:
+		w.Header().Set("Allow", http.MethodGet+", "+http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+}
+
+// splitFile sends the uploaded file as a zip of parts and a manifest.
+func (app *Application) splitFile(w http.ResponseWriter, r *http.Request, toolData *SplitData) {
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		toolData.Error = "Please choose a file to split."
+		app.render(w, http.StatusBadRequest, "split.tmpl.html", &templateData{ToolData: toolData})
+		return
+	}
+	defer file.Close()
+
+	var opts fileconvert.SplitOptions
+	switch toolData.By {
+	case "parts":
+		opts.Parts, err = strconv.Atoi(toolData.Parts)
+		if err != nil {
+			err = errors.New("the number of parts must be a whole number")
+		}
+	case "size":
+		opts.ChunkSize, err = fileconvert.ParseSize(toolData.ChunkSize)
+	default:
+		err = fmt.Errorf("unknown split mode %q", toolData.By)
+	}
+	// Check the options before committing to a download, so problems can
+	// still be shown on the page.
+	if err == nil {
+		_, err = opts.PartSizes(header.Size)
+	}
+	if err != nil {
+		toolData.Error = err.Error()
+		app.render(w, http.StatusBadRequest, "split.tmpl.html", &templateData{ToolData: toolData})
+		return
+	}
+
+	filename := filepath.Base(header.Filename) + "-parts.zip"
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+	w.WriteHeader(http.StatusOK)
+
+	// Headers are sent by now, so failures can only be logged.
+	if _, err := fileconvert.Split(w, file, header.Filename, header.Size, opts); err != nil {
+		app.errorLog.Printf("error splitting %s: %v", header.Filename, err)
+	}
+}
+
+// joinParts checks the uploaded parts against the uploaded manifest. With
+// join set and every part intact, it sends the rebuilt file as a download;
+// otherwise it shows the report.
+func (app *Application) joinParts(w http.ResponseWriter, r *http.Request, toolData *SplitData, join bool) {
+	uploads := map[string]*multipart.FileHeader{}
+	for _, fh := range r.MultipartForm.File["parts"] {
+		uploads[filepath.Base(fh.Filename)] = fh
+	}
+	manifestHeader, ok := uploads[fileconvert.ManifestName]
+	if !ok {
+		toolData.Error = "Please include " + fileconvert.ManifestName + " with the parts."
+		app.render(w, http.StatusBadRequest, "split.tmpl.html", &templateData{ToolData: toolData})
+		return
+	}
+
+	m, err := readManifest(manifestHeader)
+	if err != nil {
+		toolData.Error = err.Error()
+		app.render(w, http.StatusBadRequest, "split.tmpl.html", &templateData{ToolData: toolData})
+		return
+	}
+	open := func(name string) (io.ReadCloser, error) {
+		fh, ok := uploads[name]
+		if !ok {
+			return nil, fs.ErrNotExist
+		}
+		return fh.Open()
+	}
+	v, err := fileconvert.CheckChunks(m, open)
+	if err != nil {
+		toolData.Error = err.Error()
+		app.render(w, http.StatusBadRequest, "split.tmpl.html", &templateData{ToolData: toolData})
+		return
+	}
+
+	toolData.Manifest, toolData.Verification = m, v
+
+	listed := map[string]bool{fileconvert.ManifestName: true}
+	for _, c := range v.Chunks {
+		listed[c.Name] = true
+	}
+	for _, fh := range r.MultipartForm.File["parts"] {
+		if name := filepath.Base(fh.Filename); !listed[name] {
+			toolData.Extra = append(toolData.Extra, name)
+		}
+	}
+
+	// Every check is done before the download starts, so a file that
+	// would not match the manifest is never sent.
+	if v.Problems > 0 {
+		toolData.Error = fmt.Sprintf("%d of %d parts are missing or corrupted.", v.Problems, len(v.Chunks))
+		app.render(w, http.StatusBadRequest, "split.tmpl.html", &templateData{ToolData: toolData})
+		return
+	}
+	if v.FileProblem != "" {
+		toolData.Error = "Every part matches its digest, but " + v.FileProblem + "."
+		app.render(w, http.StatusBadRequest, "split.tmpl.html", &templateData{ToolData: toolData})
+		return
+	}
+	if !join {
+		app.render(w, http.StatusOK, "split.tmpl.html", &templateData{ToolData: toolData})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": m.Name}))
+	w.WriteHeader(http.StatusOK)
+
+	// Headers are sent by now, so failures can only be logged.
+	if err := fileconvert.Join(w, m, open); err != nil {
+		app.errorLog.Printf("error joining %s: %v", m.Name, err)
+	}
+}
+
+func readManifest(fh *multipart.FileHeader) (*fileconvert.Manifest, error) {
+	f, err := fh.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return fileconvert.ParseManifest(f)
+}
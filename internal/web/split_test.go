@@ -0,0 +1,192 @@
+package web
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/NickDiPreta1/toolhub/internal/tools/fileconvert"
+)
+
+func TestSplitTool(t *testing.T) {
+	originalWd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir("../.."); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(originalWd)
+
+	app, err := NewApplication(
+		log.New(os.Stdout, "TEST INFO: ", 0),
+		log.New(os.Stdout, "TEST ERROR: ", 0),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	original := strings.Repeat("a file too large for the mail server\n", 100)
+
+	// Split the file and unpack the downloaded zip.
+	rr := httptest.NewRecorder()
+	app.splitTool(rr, archiveRequest(t, [][2]string{{"action", "split"}, {"by", "size"}, {"chunk_size", "1k"}}, "file", [][2]string{{"notes.txt", original}}))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("split status = %d: %s", rr.Code, rr.Body.String())
+	}
+	if cd := rr.Header().Get("Content-Disposition"); !strings.Contains(cd, "notes.txt-parts.zip") {
+		t.Errorf("Content-Disposition = %q", cd)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(rr.Body.Bytes()), int64(rr.Body.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var parts [][2]string
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(rc)
+		rc.Close()
+		parts = append(parts, [2]string{f.Name, string(body)})
+	}
+	// 3700 bytes in 1KB chunks is four parts, plus the manifest.
+	if len(parts) != 5 || parts[0][0] != "notes.txt.001" || parts[4][0] != "manifest.json" {
+		t.Fatalf("zip entries = %v", parts)
+	}
+
+	t.Run("join restores the file", func(t *testing.T) {
+		// Upload order does not matter, and unrelated files are ignored.
+		shuffled := [][2]string{parts[4], parts[2], parts[0], parts[3], parts[1], {"readme.txt", "hi"}}
+		rr := httptest.NewRecorder()
+		app.splitTool(rr, archiveRequest(t, [][2]string{{"action", "join"}}, "parts", shuffled))
+		if rr.Code != http.StatusOK {
+			t.Fatalf("status = %d: %s", rr.Code, rr.Body.String())
+		}
+		if rr.Body.String() != original {
+			t.Error("joined file differs from the original")
+		}
+		if cd := rr.Header().Get("Content-Disposition"); !strings.Contains(cd, `filename=notes.txt`) {
+			t.Errorf("Content-Disposition = %q", cd)
+		}
+	})
+
+	// A manifest whose whole-file digest disagrees with intact parts.
+	var m fileconvert.Manifest
+	if err := json.Unmarshal([]byte(parts[4][1]), &m); err != nil {
+		t.Fatal(err)
+	}
+	m.SHA256 = strings.Repeat("0", 64)
+	tampered, _ := json.Marshal(m)
+
+	tests := []struct {
+		name       string
+		fields     [][2]string
+		field      string
+		files      [][2]string
+		wantStatus int
+		wantBody   []string
+	}{
+		{
+			name:       "verify intact parts",
+			fields:     [][2]string{{"action", "verify"}},
+			field:      "parts",
+			files:      append(append([][2]string{}, parts...), [2]string{"readme.txt", "hi"}),
+			wantStatus: http.StatusOK,
+			wantBody:   []string{"All parts are present and match the manifest.", "3700 bytes in 4 parts", "notes.txt.004", "Ignored files not in the manifest: readme.txt"},
+		},
+		{
+			name:       "missing and corrupted parts",
+			fields:     [][2]string{{"action", "join"}},
+			field:      "parts",
+			files:      [][2]string{parts[0], {parts[1][0], strings.ToUpper(parts[1][1])}, parts[3], parts[4]},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   []string{"2 of 4 parts are missing or corrupted.", "corrupted: SHA-256 does not match", `<span style="color: red;">missing</span>`},
+		},
+		{
+			name:       "whole file does not match",
+			fields:     [][2]string{{"action", "join"}},
+			field:      "parts",
+			files:      [][2]string{parts[0], parts[1], parts[2], parts[3], {"manifest.json", string(tampered)}},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   []string{"Every part matches its digest, but the joined parts do not match the SHA-256 of the whole file."},
+		},
+		{
+			name:       "no manifest",
+			fields:     [][2]string{{"action", "join"}},
+			field:      "parts",
+			files:      parts[:4],
+			wantStatus: http.StatusBadRequest,
+			wantBody:   []string{"Please include manifest.json with the parts."},
+		},
+		{
+			name:       "invalid manifest",
+			fields:     [][2]string{{"action", "verify"}},
+			field:      "parts",
+			files:      [][2]string{{"manifest.json", `{"name": "../x"}`}},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   []string{"invalid manifest"},
+		},
+		{
+			name:       "too many parts",
+			fields:     [][2]string{{"action", "split"}, {"by", "parts"}, {"parts", "1000"}},
+			field:      "file",
+			files:      [][2]string{{"notes.txt", original}},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   []string{"the number of parts must be between 2 and 999"},
+		},
+		{
+			name:       "invalid chunk size",
+			fields:     [][2]string{{"action", "split"}, {"by", "size"}, {"chunk_size", "ten"}},
+			field:      "file",
+			files:      [][2]string{{"notes.txt", original}},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   []string{"invalid size"},
+		},
+		{
+			name:       "no file to split",
+			fields:     [][2]string{{"action", "split"}},
+			field:      "file",
+			wantStatus: http.StatusBadRequest,
+			wantBody:   []string{"Please choose a file to split."},
+		},
+		{
+			name:       "unknown action",
+			fields:     [][2]string{{"action", "explode"}},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   []string{"Unknown action: explode"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			app.splitTool(rr, archiveRequest(t, tt.fields, tt.field, tt.files))
+
+			if rr.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rr.Code, tt.wantStatus, rr.Body.String())
+			}
+			for _, want := range tt.wantBody {
+				if !strings.Contains(rr.Body.String(), want) {
+					t.Errorf("body does not contain %q", want)
+				}
+			}
+		})
+	}
+
+	t.Run("method not allowed", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		app.splitTool(rr, httptest.NewRequest("DELETE", "/tools/split", nil))
+		if rr.Code != http.StatusMethodNotAllowed {
+			t.Errorf("status = %d, want 405", rr.Code)
+		}
+	})
+}
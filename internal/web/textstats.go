@@ -0,0 +1,91 @@
+package web
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/NickDiPreta1/toolhub/internal/tools/textutil"
+)
+
+type TextStatsData struct {
+	Error         string
+	Input         string
+	Filename      string
+	TopWords      string
+	SkipStopwords bool
+	Stats         *textutil.TextStats
+}
+
+// textStats reports counts, frequent words, encoding details and suspicious
+// characters for pasted text or an uploaded file.
+func (app *Application) textStats(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		data := &templateData{
+			ToolData: &TextStatsData{
+				TopWords:      strconv.Itoa(textutil.DefaultTopWords),
+				SkipStopwords: true,
+			},
+		}
+		app.render(w, http.StatusOK, "textstats.tmpl.html", data)
+		return
+
+	case http.MethodPost:
+		const maxUploadSize = 10 * 1024 * 1024
+		r.Body = http.MaxBytesReader(w, r.Body, maxUploadSize)
+
+		toolData := &TextStatsData{}
+		if err := r.ParseMultipartForm(maxUploadSize); err != nil {
+			toolData.Error = "File too large or invalid upload. Maximum size is 10MB."
+			app.render(w, http.StatusBadRequest, "textstats.tmpl.html", &templateData{ToolData: toolData})
+			return
+		}
+		defer r.MultipartForm.RemoveAll()
+
+		toolData.Input = r.FormValue("input")
+		toolData.TopWords = strings.TrimSpace(r.FormValue("top"))
+		toolData.SkipStopwords = r.FormValue("skip_stopwords") == "on"
+
+		opts := textutil.StatsOptions{SkipStopwords: toolData.SkipStopwords}
+		if toolData.TopWords != "" {
+			n, err := strconv.Atoi(toolData.TopWords)
+			if err != nil || n < 1 || n > 100 {
+				toolData.Error = "Top words must be a number between 1 and 100."
+				app.render(w, http.StatusBadRequest, "textstats.tmpl.html", &templateData{ToolData: toolData})
+				return
+			}
+			opts.TopWords = n
+		}
+
+		var data []byte
+		file, header, err := r.FormFile("file")
+		switch {
+		case err == nil:
+			defer file.Close()
+			data, err = io.ReadAll(file)
+			if err != nil {
+				app.serverError(w, err)
+				return
+			}
+			toolData.Filename = header.Filename
+		case toolData.Input != "":
+			data = []byte(toolData.Input)
+		default:
+			toolData.Error = "Please upload a file or paste some text."
+			app.render(w, http.StatusBadRequest, "textstats.tmpl.html", &templateData{ToolData: toolData})
+			return
+		}
+
+		stats := textutil.Analyze(data, opts)
+		toolData.Stats = &stats
+		app.render(w, http.StatusOK, "textstats.tmpl.html", &templateData{ToolData: toolData})
+		return
+
+	default:
+		w.Header().Set("Allow", http.MethodGet+", "+http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+}
@@ -0,0 +1,81 @@
+package web
+
+import (
+	"net/http"
+
+	"github.com/NickDiPreta1/toolhub/internal/tools/textutil"
+)
+
+type UnicodeData struct {
+	Error       string
+	Input       string
+	Action      string
+	Style       string
+	Styles      []textutil.EscapeStyleOption
+	Output      string
+	NormalForms []textutil.NormalForm
+	Runes       []textutil.RuneInfo
+	Truncated   bool
+}
+
+// unicodeTool shows the normalization forms and a per-rune breakdown of the
+// input, and escapes or unescapes it as a string literal.
+func (app *Application) unicodeTool(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		data := &templateData{
+			ToolData: &UnicodeData{
+				Action: "inspect",
+				Style:  string(textutil.EscapeGo),
+				Styles: textutil.EscapeStyles,
+			},
+		}
+		app.render(w, http.StatusOK, "unicode.tmpl.html", data)
+		return
+
+	case http.MethodPost:
+		toolData := &UnicodeData{
+			Input:  r.FormValue("input"),
+			Action: r.FormValue("action"),
+			Style:  r.FormValue("style"),
+			Styles: textutil.EscapeStyles,
+		}
+
+		if toolData.Input == "" {
+			toolData.Error = "Input cannot be empty."
+			app.render(w, http.StatusBadRequest, "unicode.tmpl.html", &templateData{ToolData: toolData})
+			return
+		}
+
+		text := toolData.Input
+		var err error
+		switch toolData.Action {
+		case "inspect":
+		case "escape":
+			toolData.Output, err = textutil.Escape(text, textutil.EscapeStyle(toolData.Style))
+		case "unescape":
+			toolData.Output, err = textutil.Unescape(text, textutil.EscapeStyle(toolData.Style))
+			text = toolData.Output
+		default:
+			toolData.Error = "Unknown action: " + toolData.Action
+			app.render(w, http.StatusBadRequest, "unicode.tmpl.html", &templateData{ToolData: toolData})
+			return
+		}
+		if err != nil {
+			toolData.Error = err.Error()
+			app.render(w, http.StatusBadRequest, "unicode.tmpl.html", &templateData{ToolData: toolData})
+			return
+		}
+
+		// After unescaping, inspect the decoded text rather than the literal.
+		toolData.NormalForms = textutil.NormalForms(text)
+		toolData.Runes, toolData.Truncated = textutil.Inspect(text)
+		app.render(w, http.StatusOK, "unicode.tmpl.html", &templateData{ToolData: toolData})
+		return
+
+	default:
+		w.Header().Set("Allow", http.MethodGet+", "+http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+}
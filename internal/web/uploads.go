@@ -0,0 +1,89 @@
+package web
+
+import (
+	"crypto/rand"
+	"sync"
+	"time"
+)
+
+// uploadStore keeps recent uploads in memory under random tokens, so a page
+// can refer back to a file without the browser sending it again. Entries
+// expire after ttl without use, and the least recently used are dropped once
+// the total size passes limit.
+type uploadStore struct {
+	mu      sync.Mutex
+	limit   int64
+	ttl     time.Duration
+	size    int64
+	entries map[string]*storedUpload
+}
+
+type storedUpload struct {
+	name     string
+	data     []byte
+	lastUsed time.Time
+}
+
+func newUploadStore(limit int64, ttl time.Duration) *uploadStore {
+	return &uploadStore{limit: limit, ttl: ttl, entries: map[string]*storedUpload{}}
+}
+
+// put stores data and returns the token to fetch it with. The caller must
+// not modify data afterwards.
+func (s *uploadStore) put(name string, data []byte) string {
+	token := rand.Text()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.entries[token] = &storedUpload{name: name, data: data, lastUsed: now}
+	s.size += int64(len(data))
+	s.evict(now, token)
+
+	return token
+}
+
+// get returns the upload stored under token and marks it as used. The data
+// is shared and must not be modified.
+func (s *uploadStore) get(token string) (name string, data []byte, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	e, ok := s.entries[token]
+	if !ok || now.Sub(e.lastUsed) > s.ttl {
+		return "", nil, false
+	}
+	e.lastUsed = now
+
+	return e.name, e.data, true
+}
+
+// evict drops expired entries, then the least recently used ones other than
+// keep until the store fits in its limit.
+func (s *uploadStore) evict(now time.Time, keep string) {
+	for token, e := range s.entries {
+		if now.Sub(e.lastUsed) > s.ttl {
+			s.remove(token)
+		}
+	}
+
+	for s.size > s.limit {
+		oldest := ""
+		for token, e := range s.entries {
+			if token != keep && (oldest == "" || e.lastUsed.Before(s.entries[oldest].lastUsed)) {
+				oldest = token
+			}
+		}
+		if oldest == "" {
+			return
+		}
+		s.remove(oldest)
+	}
+}
+
+func (s *uploadStore) remove(token string) {
+	s.size -= int64(len(s.entries[token].data))
+	delete(s.entries, token)
+}